@@ -0,0 +1,689 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/admin"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/audit"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/config"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/configstream"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/controller"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/otlp"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/version"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/webhookcert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2/textlogger"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// webhookCertDir is the certificate directory of the webhook server, where
+// controller-runtime looks for it by default.
+var webhookCertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+
+// runController runs the controller, with its embedded proxy, until it is
+// signalled to stop. Its flags are parsed from args with the command line's
+// flag set, which controller-runtime registers --kubeconfig on.
+func runController(args []string) {
+	var metricsAddr string
+	var enableLeaderElection bool
+	var probeAddr string
+	var proxyAddr string
+	var redactHeaders string
+	var auditLog bool
+	var auditWebhookURL string
+	var adminAddr string
+	var resyncPeriod time.Duration
+	var orphanSweepPeriod time.Duration
+	var proxyUpdateDelay time.Duration
+	var shutdownDelay time.Duration
+	var traceSampler string
+	var traceSamplingPercent int
+	var otlpMetricsEndpoint string
+	var otlpMetricsHeaders string
+	var otlpMetricsInterval time.Duration
+	var backends ir.BackendOptions
+	var backendResolution string
+	var retryBaseDelay time.Duration
+	var retryMaxDelay time.Duration
+	var adminTokenFile string
+	var adminCertFile string
+	var adminKeyFile string
+	var adminClientCAFile string
+	var proxyServiceName string
+	var proxyServiceNamespace string
+	var provisionServices bool
+	var dnsEndpoints bool
+	var computedConfigMaps bool
+	var enableWebhooks bool
+	var webhookCertSecret string
+	var webhookServiceName string
+	var webhookConfigurationName string
+	var enableExperimentalAPIs bool
+	var watchNamespaces string
+	var controllerName string
+	var gatewayShard string
+	var gatewayDrainTimeout time.Duration
+	var ingressClass string
+	var acmeSolverService string
+	var configStreamAddr string
+	var configStreamTokenFile string
+	var configStreamCertFile string
+	var configStreamKeyFile string
+	var configStreamClientCAFile string
+	var configStreamConsistencyTolerance time.Duration
+	var dataPlane controller.DataPlaneOptions
+	var configFile string
+	var kubeContext string
+	flag.StringVar(&kubeContext, "context", "",
+		"The kubeconfig context to run against, from --kubeconfig or else the kubeconfig found as kubectl finds it. "+
+			"It takes precedence over the in-cluster configuration, to run the controller out of the cluster.")
+	flag.StringVar(&configFile, "config", "",
+		"A YAML file setting any of these flags by name, with apiVersion "+config.APIVersion+" and kind "+config.Kind+". "+
+			"Flags given on the command line take precedence. The file is reloaded when it changes or on SIGHUP, applying changes to v, vmodule and redact-headers without a restart.")
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&proxyAddr, "proxy-bind-address", ":8000",
+		"The address the proxy binds to. Set this to \"0\" to leave serving traffic to standalone proxies fed by the config stream.")
+	flag.StringVar(&controllerName, "controller-name", controller.DefaultControllerName,
+		"The controllerName of the GatewayClasses served, a domain-prefixed path. Instances serving different GatewayClasses must use different names.")
+	flag.StringVar(&gatewayShard, "gateway-shard", "",
+		"Serve only the Gateways whose "+controller.GatewayShardLabel+" label has this value, or those without the label if empty. "+
+			"Instances serving different shards share the controller name, and each writes the status of its own Gateways and of the routes attached to them.")
+	flag.DurationVar(&gatewayDrainTimeout, "gateway-drain-timeout", 30*time.Second,
+		"How long a deleted Gateway keeps serving the requests in flight, closing connections as they complete, before its infrastructure is torn down.")
+	flag.StringVar(&ingressClass, "ingress-class", "",
+		"Also serve the Ingresses of this IngressClass on the proxy, to ease the migration from Ingress to Gateway API. Disabled if empty.")
+	flag.StringVar(&acmeSolverService, "acme-http01-solver-service", "",
+		"Forward the ACME HTTP-01 challenges of every hostname to this Service, given as namespace/name:port, ahead of any HTTPRoute, "+
+			"so that certificates can be issued before routes are configured. Disabled if empty.")
+	flag.StringVar(&proxyServiceName, "proxy-service-name", controller.DefaultProxyServiceName,
+		"The Service exposing the proxy, whose load balancer address is published on Gateways without a Service of their own.")
+	flag.StringVar(&proxyServiceNamespace, "proxy-service-namespace", "",
+		"The namespace of the proxy Service. Defaults to the namespace the controller runs in, from the POD_NAMESPACE environment variable, or \"default\".")
+	flag.BoolVar(&provisionServices, "provision-gateway-services", false,
+		"Create a Service for each Gateway, backed by the endpoints of the proxy Service, instead of publishing the address of the proxy Service.")
+	flag.BoolVar(&dnsEndpoints, "dns-endpoints", false,
+		"Publish the hostnames of the HTTPRoutes accepted by each Gateway in a DNSEndpoint for external-dns, at the Gateway's addresses. "+
+			"Requires the DNSEndpoint CRD.")
+	flag.BoolVar(&computedConfigMaps, "computed-config-maps", false,
+		"Write the routes served for each Gateway, with their snapshot and backends, to a ConfigMap named "+
+			"\"<gateway>-computed-config\" in the Gateway's namespace whenever they change.")
+	flag.StringVar(&redactHeaders, "redact-headers", strings.Join(proxy.DefaultRedactedHeaders, ","),
+		"Comma-separated list of headers whose values are redacted in logs, debug dumps and traces.")
+	flag.BoolVar(&auditLog, "audit-log", false,
+		"Write an audit event to stdout, as a line of JSON, whenever a route is programmed, rejected or removed.")
+	flag.StringVar(&auditWebhookURL, "audit-webhook-url", "",
+		"If set, POST audit events as JSON to this URL.")
+	flag.StringVar(&adminAddr, "admin-bind-address", "0",
+		"The address the admin endpoints, and the gRPC health service reporting the readiness of the data plane, bind to. "+
+			"Set this to \"0\" to disable the admin endpoints.")
+	flag.StringVar(&adminTokenFile, "admin-token-file", "",
+		"File containing the bearer token that authenticates requests to the admin endpoints.")
+	flag.StringVar(&adminCertFile, "admin-tls-cert-file", "", "Certificate file for serving the admin endpoints over TLS.")
+	flag.StringVar(&adminKeyFile, "admin-tls-key-file", "", "Key file for serving the admin endpoints over TLS.")
+	flag.StringVar(&adminClientCAFile, "admin-client-ca-file", "",
+		"CA bundle used to verify client certificates for the admin endpoints. Requires --admin-tls-cert-file.")
+	flag.StringVar(&configStreamAddr, "config-stream-bind-address", "0",
+		"The address the config stream binds to, from which standalone proxies receive the routes to serve. Set this to \"0\" to disable the config stream.")
+	flag.StringVar(&configStreamTokenFile, "config-stream-token-file", "",
+		"File containing the bearer token that authenticates proxies to the config stream.")
+	flag.StringVar(&configStreamCertFile, "config-stream-tls-cert-file", "", "Certificate file for serving the config stream over TLS.")
+	flag.StringVar(&configStreamKeyFile, "config-stream-tls-key-file", "", "Key file for serving the config stream over TLS.")
+	flag.StringVar(&configStreamClientCAFile, "config-stream-client-ca-file", "",
+		"CA bundle used to verify the client certificates of proxies. Requires --config-stream-tls-cert-file.")
+	flag.DurationVar(&configStreamConsistencyTolerance, "config-stream-consistency-tolerance", 30*time.Second,
+		"How long a proxy may take to serve a new snapshot of the route table before the controller's readiness check fails "+
+			"and it counts in gari_configstream_out_of_sync_proxies.")
+	flag.StringVar(&dataPlane.Image, "data-plane-image", "",
+		"The image of the proxies provisioned for the GatewayClasses in DaemonSet mode without a dataPlaneImage parameter.")
+	flag.StringVar(&dataPlane.ConfigStreamAddress, "data-plane-config-stream-address", "",
+		"The address of the config stream, as reached from the proxies provisioned for the GatewayClasses in DaemonSet mode. "+
+			"DaemonSet mode is unavailable if empty.")
+	flag.StringVar(&dataPlane.ConfigStreamTokenSecret, "data-plane-config-stream-token-secret", "",
+		"The Secret, in the proxy Service's namespace, whose \"token\" key authenticates provisioned proxies to the config stream.")
+	flag.StringVar(&dataPlane.ConfigStreamCAConfigMap, "data-plane-config-stream-ca-configmap", "",
+		"The ConfigMap, in the proxy Service's namespace, whose \"ca.crt\" key verifies the config stream's certificate for provisioned proxies.")
+	flag.DurationVar(&dataPlane.ShutdownDelay, "data-plane-shutdown-delay", 0,
+		"The --shutdown-delay of the proxies provisioned for the GatewayClasses in DaemonSet mode, whose termination grace period is extended to match.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve validating admission webhooks that reject HTTPRoutes and Gateways of our GatewayClasses that cannot be served. "+
+			"Requires a serving certificate in the webhook server's certificate directory, unless --webhook-cert-secret is set.")
+	flag.StringVar(&webhookCertSecret, "webhook-cert-secret", "",
+		"Issue a self-signed serving certificate for the webhooks, kept in this Secret in the controller's namespace, renew it ahead of its expiry, "+
+			"and set its CA as the caBundle of --webhook-configuration-name. Disabled if empty, leaving the certificate to cert-manager or similar.")
+	flag.StringVar(&webhookServiceName, "webhook-service-name", "gari-webhook",
+		"The Service, in the controller's namespace, that the webhooks are called through, which the self-signed certificate is issued for.")
+	flag.StringVar(&webhookConfigurationName, "webhook-configuration-name", "gari-webhooks",
+		"The ValidatingWebhookConfiguration of the webhooks, whose caBundle is set with --webhook-cert-secret.")
+	flag.BoolVar(&enableExperimentalAPIs, "enable-experimental-apis", false,
+		"Reconcile the experimental-channel Gateway API types whose CRDs are installed: BackendTLSPolicy, TCPRoute, TLSRoute, UDPRoute and XListenerSet.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma-separated list of namespaces whose Gateways, routes and policies are reconciled, in addition to the namespace of the proxy Service. "+
+			"Objects in other namespaces are ignored, and references to them do not resolve. Defaults to all namespaces.")
+	flag.DurationVar(&resyncPeriod, "resync-period", 10*time.Minute,
+		"How often to recompute the route table and all statuses from the cluster, correcting drift. Set to 0 to disable.")
+	flag.DurationVar(&orphanSweepPeriod, "orphan-sweep-period", time.Hour,
+		"How often to delete the Services and EndpointSlices provisioned for Gateways that no longer exist. Set to 0 to disable.")
+	flag.DurationVar(&proxyUpdateDelay, "proxy-update-delay", 100*time.Millisecond,
+		"How long to coalesce route changes before applying them to the proxy as one batch. Set to 0 to apply each change right away.")
+	flag.StringVar(&backends.ClusterDomain, "cluster-domain", ir.DefaultClusterDomain,
+		"The DNS domain of the cluster, under which Services are named.")
+	flag.StringVar(&backendResolution, "backend-resolution", string(ir.BackendResolutionServiceDNS),
+		"How the proxy reaches the Services of backendRefs: ServiceDNS forwards to their DNS name, ClusterIP to their cluster IP, "+
+			"and EndpointSlice to their ready endpoints, balancing requests across them in the proxy.")
+	flag.DurationVar(&shutdownDelay, "shutdown-delay", 0,
+		"How long the proxy keeps serving once asked to stop, failing its readiness check, before it drains. "+
+			"Set this to the deregistration delay of the load balancer in front of it.")
+	flag.DurationVar(&retryBaseDelay, "retry-base-delay", time.Second,
+		"The delay before an object whose reconcile failed, or a Gateway waiting for an address, is retried. It doubles with each retry.")
+	flag.DurationVar(&retryMaxDelay, "retry-max-delay", 5*time.Minute,
+		"The longest delay between retries of an object.")
+	flag.StringVar(&traceSampler, "trace-sampler", string(proxy.TraceSamplerParentBased),
+		"Which requests are marked as sampled in their trace context, unless a TelemetryPolicy overrides it: "+
+			"Always samples every request, Ratio samples --trace-sampling-percent of the traces whatever their caller decided, "+
+			"and ParentBased keeps the decision of callers, sampling --trace-sampling-percent of the traces the proxy starts.")
+	flag.IntVar(&traceSamplingPercent, "trace-sampling-percent", 100,
+		"The percentage of traces sampled by the Ratio and ParentBased samplers.")
+	flag.StringVar(&otlpMetricsEndpoint, "otlp-metrics-endpoint", "",
+		"The OTLP/HTTP endpoint of an OpenTelemetry collector to push metrics to, such as http://otel-collector:4318/v1/metrics, "+
+			"in addition to serving them for scraping. Empty disables pushing.")
+	flag.StringVar(&otlpMetricsHeaders, "otlp-metrics-headers", "",
+		"Headers set on the requests pushing metrics, as comma-separated name=value pairs.")
+	flag.DurationVar(&otlpMetricsInterval, "otlp-metrics-interval", otlp.DefaultInterval,
+		"How often metrics are pushed to --otlp-metrics-endpoint.")
+	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
+		"Enable leader election for controller manager. "+
+			"Every replica serves traffic, but only the elected leader writes statuses and provisions infrastructure.")
+
+	var accessLog accessLogOptions
+	accessLog.AddFlags(flag.CommandLine)
+	var routeMetrics routeMetricsOptions
+	routeMetrics.AddFlags(flag.CommandLine)
+	logConfig := textlogger.NewConfig()
+	logConfig.AddFlags(flag.CommandLine)
+	flag.CommandLine.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s controller [flags]\n\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(flag.CommandLine.Output(), "Runs the controller, reconciling Gateway API resources and serving their routes.\n\n")
+		flag.CommandLine.PrintDefaults()
+	}
+	_ = flag.CommandLine.Parse(args)
+	var configWatcher *config.File
+	if configFile != "" {
+		var err error
+		if configWatcher, err = config.Load(flag.CommandLine, configFile); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	ctrl.SetLogger(textlogger.NewLogger(logConfig))
+	setupLog.Info("starting controller", version.Get().KeysAndValues()...)
+	proxy.RegisterRuntimeMetrics()
+	if err := setTraceSampling(traceSampler, traceSamplingPercent); err != nil {
+		setupLog.Error(err, "invalid trace sampling")
+		os.Exit(1)
+	}
+
+	if err := controller.ValidateControllerName(controllerName); err != nil {
+		setupLog.Error(err, "invalid --controller-name")
+		os.Exit(1)
+	}
+	backends.Resolution = ir.BackendResolution(backendResolution)
+	if !slices.Contains(ir.BackendResolutions, backends.Resolution) {
+		setupLog.Error(fmt.Errorf("must be one of %v, got %q", ir.BackendResolutions, backendResolution), "invalid --backend-resolution")
+		os.Exit(1)
+	}
+
+	if proxyServiceNamespace == "" {
+		proxyServiceNamespace = cmp.Or(os.Getenv("POD_NAMESPACE"), "default")
+	}
+	dataPlane.Namespace = proxyServiceNamespace
+	var namespaces []string
+	if watchNamespaces != "" {
+		namespaces = append(strings.Split(watchNamespaces, ","), proxyServiceNamespace)
+	}
+
+	restConfig, err := kubeConfig(kubeContext)
+	if err != nil {
+		setupLog.Error(err, "unable to load the kubeconfig")
+		os.Exit(1)
+	}
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme:    scheme,
+		Cache:     controller.CacheOptions(namespaces),
+		Client:    controller.ClientOptions(),
+		NewClient: controller.NewClientFunc(namespaces),
+		Metrics: metricsserver.Options{
+			BindAddress: metricsAddr,
+		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    9443,
+			CertDir: webhookCertDir,
+		}),
+		HealthProbeBindAddress: probeAddr,
+		LeaderElection:         enableLeaderElection,
+		LeaderElectionID:       "gateway-api-reference-implementation",
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+
+	proxyOptions := proxy.Options{RedactedHeaders: strings.Split(redactHeaders, ",")}
+	if err := accessLog.apply(&proxyOptions); err != nil {
+		setupLog.Error(err, "unable to configure access log")
+		os.Exit(1)
+	}
+	if err := routeMetrics.apply(&proxyOptions); err != nil {
+		setupLog.Error(err, "unable to configure metrics")
+		os.Exit(1)
+	}
+	dataPlane.MetricsRouteLabels, dataPlane.MaxMetricsRoutes = proxyOptions.MetricsRouteLabels, proxyOptions.MaxMetricsRoutes
+	// The embedded proxy keeps the routes even when it does not serve them,
+	// for the admin endpoints.
+	p := proxy.NewProxy(proxyOptions)
+	// The proxy and admin servers run with the manager, on every replica, and
+	// drain their requests in flight when it stops. The proxy's listener is
+	// bound here, so that the data plane is not reported ready without it.
+	if proxyAddr != "0" {
+		listener, err := net.Listen("tcp", proxyAddr)
+		if err != nil {
+			setupLog.Error(err, "unable to listen for the proxy")
+			os.Exit(1)
+		}
+		server := &http.Server{Addr: proxyAddr, Handler: p, ConnState: proxy.ConnState}
+		if err := mgr.Add(&manager.Server{Name: "proxy", Server: server, Listener: listener}); err != nil {
+			setupLog.Error(err, "unable to add proxy server")
+			os.Exit(1)
+		}
+	}
+	if otlpMetricsEndpoint != "" {
+		exporter, err := newMetricsExporter("controller", otlpMetricsEndpoint, otlpMetricsHeaders, otlpMetricsInterval)
+		if err != nil {
+			setupLog.Error(err, "unable to configure OTLP metrics export")
+			os.Exit(1)
+		}
+		if err := mgr.Add(exporter); err != nil {
+			setupLog.Error(err, "unable to add OTLP metrics export")
+			os.Exit(1)
+		}
+	}
+	if configWatcher != nil {
+		configWatcher.Reloadable = map[string]func(){
+			"v":       func() {},
+			"vmodule": func() {},
+			"redact-headers": func() {
+				p.SetRedactedHeaders(strings.Split(redactHeaders, ","))
+			},
+		}
+		if err := mgr.Add(configWatcher); err != nil {
+			setupLog.Error(err, "unable to add configuration reload")
+			os.Exit(1)
+		}
+	}
+	routeSinks := controller.RouteSinks{p}
+	if configStreamAddr != "0" {
+		configStream, err := newConfigStreamServer(configStreamAddr, configStreamTokenFile, configStreamCertFile, configStreamKeyFile, configStreamClientCAFile,
+			configStreamConsistencyTolerance)
+		if err != nil {
+			setupLog.Error(err, "unable to configure config stream")
+			os.Exit(1)
+		}
+		if err := mgr.Add(configStream); err != nil {
+			setupLog.Error(err, "unable to add config stream")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("config-consistency", configStream.ConsistencyCheck); err != nil {
+			setupLog.Error(err, "unable to set up ready check")
+			os.Exit(1)
+		}
+		routeSinks = append(routeSinks, configStream)
+	}
+	if computedConfigMaps {
+		publisher := controller.NewComputedConfigPublisher(mgr.GetClient(), mgr.GetScheme(), gatewayv1.GatewayController(controllerName), gatewayShard)
+		if err := mgr.Add(publisher); err != nil {
+			setupLog.Error(err, "unable to add computed config publisher")
+			os.Exit(1)
+		}
+		routeSinks = append(routeSinks, publisher)
+	}
+
+	var auditSinks []audit.Sink
+	if auditLog {
+		auditSinks = append(auditSinks, audit.NewLogSink(os.Stdout))
+	}
+	if auditWebhookURL != "" {
+		webhookSink := audit.NewWebhookSink(auditWebhookURL)
+		if err := mgr.Add(webhookSink); err != nil {
+			setupLog.Error(err, "unable to add audit webhook")
+			os.Exit(1)
+		}
+		auditSinks = append(auditSinks, webhookSink)
+	}
+	var auditRecorder *audit.Recorder
+	if len(auditSinks) > 0 {
+		auditRecorder = audit.NewRecorder(auditSinks...)
+	}
+
+	gatewayController := gatewayv1.GatewayController(controllerName)
+	backoff := controller.Backoff{BaseDelay: retryBaseDelay, MaxDelay: retryMaxDelay}
+	httpRouteReconciler := &controller.HTTPRouteReconciler{
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		ControllerName:   gatewayController,
+		Shard:            gatewayShard,
+		Proxy:            routeSinks,
+		Audit:            auditRecorder,
+		Recorder:         mgr.GetEventRecorderFor(controller.EventSource),
+		Backoff:          backoff,
+		ExperimentalAPIs: enableExperimentalAPIs,
+		ProxyUpdateDelay: proxyUpdateDelay,
+		Backends:         backends,
+	}
+	if acmeSolverService != "" {
+		route, err := controller.ACMESolverRoute(acmeSolverService, backends.ClusterDomain)
+		if err != nil {
+			setupLog.Error(err, "invalid --acme-http01-solver-service")
+			os.Exit(1)
+		}
+		httpRouteReconciler.BuiltinRoutes = append(httpRouteReconciler.BuiltinRoutes, route)
+	}
+	gatewayClassReconciler := &controller.GatewayClassReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorderFor(controller.EventSource),
+		ControllerName: gatewayController,
+		Backoff:        backoff,
+		DataPlane:      dataPlane,
+	}
+	gatewayReconciler := &controller.GatewayReconciler{
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		Recorder:           mgr.GetEventRecorderFor(controller.EventSource),
+		ProxyService:       types.NamespacedName{Namespace: proxyServiceNamespace, Name: proxyServiceName},
+		ProvisionServices:  provisionServices,
+		DNSEndpoints:       dnsEndpoints,
+		Shard:              gatewayShard,
+		ControllerName:     gatewayController,
+		Backoff:            backoff,
+		DrainTimeout:       gatewayDrainTimeout,
+		DataPlaneNamespace: proxyServiceNamespace,
+	}
+	if resyncPeriod > 0 {
+		resyncer := controller.NewResyncer(mgr.GetClient(), resyncPeriod, httpRouteReconciler, gatewayReconciler, gatewayClassReconciler)
+		if err := mgr.Add(resyncer); err != nil {
+			setupLog.Error(err, "unable to add periodic resync")
+			os.Exit(1)
+		}
+	}
+	if orphanSweepPeriod > 0 {
+		if err := mgr.Add(&controller.OrphanSweeper{Client: mgr.GetClient(), Period: orphanSweepPeriod}); err != nil {
+			setupLog.Error(err, "unable to add orphan sweep")
+			os.Exit(1)
+		}
+	}
+
+	shutdown := proxy.NewShutdownDelay(shutdownDelay)
+	ctx := shutdown.Context(ctrl.SetupSignalHandler())
+	go admin.ToggleVerbosityOnSignal(ctx, logConfig.Verbosity())
+	if err := controller.SetupIndexes(ctx, mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "unable to set up field indexes")
+		os.Exit(1)
+	}
+
+	if err = httpRouteReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HTTPRoute")
+		os.Exit(1)
+	}
+
+	if err = gatewayClassReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GatewayClass")
+		os.Exit(1)
+	}
+
+	if err = gatewayReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Gateway")
+		os.Exit(1)
+	}
+
+	if err = (&controller.BasicAuthPolicyReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		ControllerName: gatewayController,
+		Backoff:        backoff,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BasicAuthPolicy")
+		os.Exit(1)
+	}
+
+	if err = (&controller.SecurityHeadersPolicyReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		ControllerName: gatewayController,
+		Backoff:        backoff,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SecurityHeadersPolicy")
+		os.Exit(1)
+	}
+
+	if err = (&controller.TransformPolicyReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		ControllerName: gatewayController,
+		Backoff:        backoff,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TransformPolicy")
+		os.Exit(1)
+	}
+
+	if err = (&controller.TelemetryPolicyReconciler{
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		ControllerName: gatewayController,
+		Backoff:        backoff,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TelemetryPolicy")
+		os.Exit(1)
+	}
+
+	if enableExperimentalAPIs {
+		if err = controller.SetupExperimentalWithManager(mgr, gatewayController, backoff); err != nil {
+			setupLog.Error(err, "unable to create experimental controllers")
+			os.Exit(1)
+		}
+	}
+
+	if ingressClass != "" {
+		if err = (&controller.IngressReconciler{
+			Client:       mgr.GetClient(),
+			Scheme:       mgr.GetScheme(),
+			IngressClass: ingressClass,
+			Routes:       httpRouteReconciler,
+			ProxyService: types.NamespacedName{Namespace: proxyServiceNamespace, Name: proxyServiceName},
+			Backoff:      backoff,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Ingress")
+			os.Exit(1)
+		}
+	}
+
+	if enableWebhooks && webhookCertSecret != "" {
+		namespace := cmp.Or(os.Getenv("POD_NAMESPACE"), "default")
+		rotator := &webhookcert.Rotator{
+			Reader:               mgr.GetAPIReader(),
+			Client:               mgr.GetClient(),
+			Secret:               types.NamespacedName{Namespace: namespace, Name: webhookCertSecret},
+			DNSName:              webhookServiceName + "." + namespace + ".svc",
+			CertDir:              webhookCertDir,
+			WebhookConfiguration: webhookConfigurationName,
+		}
+		// The certificate is written before the webhook server starts, which
+		// fails without it.
+		if err := rotator.Ensure(ctx); err != nil {
+			setupLog.Error(err, "unable to issue the webhook certificate")
+			os.Exit(1)
+		}
+		if err := mgr.Add(rotator); err != nil {
+			setupLog.Error(err, "unable to add webhook certificate rotation")
+			os.Exit(1)
+		}
+	}
+	if enableWebhooks {
+		if err = (&controller.HTTPRouteValidator{Client: mgr.GetClient(), ControllerName: gatewayController}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HTTPRoute")
+			os.Exit(1)
+		}
+		if err = (&controller.GatewayValidator{Client: mgr.GetClient(), ControllerName: gatewayController}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Gateway")
+			os.Exit(1)
+		}
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	// The data plane is ready once the proxy serves the complete route table,
+	// and stays ready while the API server is unreachable, serving the routes
+	// last seen. It is probed apart, at /readyz/dataplane, to keep serving pods
+	// in the proxy Service when only checks of the control plane fail. It
+	// fails as soon as the controller is asked to stop, for the load balancer
+	// to deregister the proxy within the shutdown delay.
+	dataPlaneReady := func(req *http.Request) error {
+		if err := shutdown.ReadyCheck(req); err != nil {
+			return err
+		}
+		return httpRouteReconciler.ReadyCheck(req)
+	}
+	if err := mgr.AddReadyzCheck("dataplane", dataPlaneReady); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+
+	if adminAddr != "0" {
+		adminServer, err := admin.NewServer(p, adminAddr, adminTokenFile, adminCertFile, adminKeyFile, adminClientCAFile, logConfig.Verbosity(), dataPlaneReady)
+		if err != nil {
+			setupLog.Error(err, "unable to configure admin endpoints")
+			os.Exit(1)
+		}
+		listener, err := adminListener(adminServer, adminCertFile, adminKeyFile)
+		if err != nil {
+			setupLog.Error(err, "unable to listen for admin endpoints")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&manager.Server{Name: "admin", Server: adminServer, Listener: listener}); err != nil {
+			setupLog.Error(err, "unable to add admin server")
+			os.Exit(1)
+		}
+	}
+
+	setupLog.Info("starting manager")
+	if err := mgr.Start(ctx); err != nil {
+		setupLog.Error(err, "problem running manager")
+		os.Exit(1)
+	}
+}
+
+// adminListener returns the listener of the admin server, which serves TLS
+// with the given certificate if the server has a TLS configuration.
+func adminListener(server *http.Server, certFile, keyFile string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if server.TLSConfig == nil {
+		return listener, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	tlsConfig := server.TLSConfig.Clone()
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return tls.NewListener(listener, tlsConfig), nil
+}
+
+// kubeConfig returns the configuration of the cluster to run against. Without
+// a context, it is found as controller-runtime finds it: from --kubeconfig, or
+// else in the cluster, or else as kubectl finds it. A context is looked up in
+// --kubeconfig, or else in the kubeconfig kubectl uses, even in a cluster.
+func kubeConfig(kubeContext string) (*rest.Config, error) {
+	if kubeContext == "" {
+		return ctrl.GetConfig()
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = flag.Lookup("kubeconfig").Value.String()
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if restConfig.QPS == 0 {
+		// As with controller-runtime, API priority and fairness is relied on
+		// rather than client-side rate limiting.
+		restConfig.QPS = -1
+	}
+	return restConfig, nil
+}
+
+// newConfigStreamServer returns the server streaming routes to standalone
+// proxies, which is served over TLS when a certificate is given.
+func newConfigStreamServer(addr, tokenFile, certFile, keyFile, clientCAFile string, consistencyTolerance time.Duration) (*configstream.Server, error) {
+	opts := configstream.ServerOptions{Address: addr, ConsistencyTolerance: consistencyTolerance}
+	if tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.Token = strings.TrimSpace(string(token))
+	}
+	if clientCAFile != "" && certFile == "" {
+		return nil, errors.New("--config-stream-client-ca-file requires --config-stream-tls-cert-file")
+	}
+	if certFile != "" {
+		tlsConfig, err := configstream.ServerTLS(certFile, keyFile, clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.TLS = tlsConfig
+		opts.ClientCertificates = clientCAFile != ""
+	}
+	return configstream.NewServer(opts)
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/k8s"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/gateway-api/pkg/consts"
+	"sigs.k8s.io/yaml"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// installFieldManager is the field manager of the objects applied by the
+// install subcommand.
+const installFieldManager = "gateway-api-reference-implementation-install"
+
+// install implements the install subcommand, which applies the embedded
+// manifests to the cluster, or prints them with -dry-run.
+func install(args []string) int {
+	flags := flag.NewFlagSet("install", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s install [flags]\n\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(flags.Output(), "Installs the controller, with the CRDs of its APIs, its RBAC and the reference-class GatewayClass. "+
+			"The Gateway API CRDs must be installed first.\n\n")
+		flags.PrintDefaults()
+	}
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file.")
+	namespace := flags.String("namespace", k8s.DefaultNamespace, "The namespace to install the controller in, created if missing.")
+	image := flags.String("image", "", "The image of the controller. Defaults to the image of the manifests.")
+	dryRun := flags.Bool("dry-run", false, "Print the manifests instead of applying them.")
+	timeout := flags.Duration("wait", 0, "How long to wait for the controller to be available. Set to 0 not to wait.")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 1
+	}
+
+	objects, err := k8s.Manifests(*namespace, *image)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to load the manifests: %v\n", err)
+		return 1
+	}
+	if *dryRun {
+		for _, obj := range objects {
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "unable to print %s %s: %v\n", obj.GetKind(), obj.GetName(), err)
+				return 1
+			}
+			fmt.Printf("---\n%s", data)
+		}
+		return 0
+	}
+
+	c, err := newInstallClient(*kubeconfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to connect to the cluster: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+	gatewayClasses := schema.GroupKind{Group: gatewayv1.GroupName, Kind: "GatewayClass"}
+	if _, err := c.RESTMapper().RESTMapping(gatewayClasses); meta.IsNoMatchError(err) {
+		fmt.Fprintf(os.Stderr, "the Gateway API CRDs are not installed, install them with:\n"+
+			"  kubectl apply --server-side -f https://github.com/kubernetes-sigs/gateway-api/releases/download/%s/standard-install.yaml\n",
+			consts.BundleVersion)
+		return 1
+	}
+	for _, obj := range objects {
+		if err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(installFieldManager), client.ForceOwnership); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to apply %s %s: %v\n", obj.GetKind(), obj.GetName(), err)
+			return 1
+		}
+		fmt.Printf("%s/%s applied\n", obj.GetKind(), obj.GetName())
+	}
+
+	if *timeout > 0 {
+		key := client.ObjectKey{Namespace: *namespace, Name: "gari-controller"}
+		err := wait.PollUntilContextTimeout(ctx, time.Second, *timeout, true, func(ctx context.Context) (bool, error) {
+			var deployment appsv1.Deployment
+			if err := c.Get(ctx, key, &deployment); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			return deployment.Status.ObservedGeneration == deployment.Generation &&
+				deployment.Status.AvailableReplicas > 0 && deployment.Status.UpdatedReplicas == deployment.Status.Replicas, nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "the controller is not available after %v: %v\n", *timeout, err)
+			return 1
+		}
+		fmt.Println("controller available")
+	}
+	return 0
+}
+
+// uninstall implements the uninstall subcommand, which deletes the objects of
+// the embedded manifests from the cluster, in the reverse order of install.
+// The namespace is kept, as are the CRDs unless -crds is set.
+func uninstall(args []string) int {
+	flags := flag.NewFlagSet("uninstall", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s uninstall [flags]\n\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(flags.Output(), "Uninstalls the controller. The namespace is kept, and so are the CRDs unless -crds is set.\n\n")
+		flags.PrintDefaults()
+	}
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file.")
+	namespace := flags.String("namespace", k8s.DefaultNamespace, "The namespace the controller is installed in.")
+	crds := flags.Bool("crds", false, "Also delete the CRDs of the controller's APIs, and every policy stored in them.")
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 1
+	}
+
+	objects, err := k8s.Manifests(*namespace, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to load the manifests: %v\n", err)
+		return 1
+	}
+	c, err := newInstallClient(*kubeconfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to connect to the cluster: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+	for _, obj := range slices.Backward(objects) {
+		switch obj.GetKind() {
+		case "Namespace":
+			continue
+		case "CustomResourceDefinition":
+			if !*crds {
+				continue
+			}
+		}
+		err := c.Delete(ctx, obj)
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to delete %s %s: %v\n", obj.GetKind(), obj.GetName(), err)
+			return 1
+		}
+		fmt.Printf("%s/%s deleted\n", obj.GetKind(), obj.GetName())
+	}
+	return 0
+}
+
+// newInstallClient returns a client of the cluster of kubeconfig, or of the
+// default kubeconfig if it is empty.
+func newInstallClient(kubeconfig string) (client.Client, error) {
+	ctrl.SetLogger(logr.Discard())
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{Scheme: scheme})
+}
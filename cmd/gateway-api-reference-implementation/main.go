@@ -12,25 +12,35 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Command gateway-api-reference-implementation runs the controller, or one of
+// the tools built around it, as a subcommand.
 package main
 
 import (
+	"cmp"
+	"encoding/json"
 	"flag"
-	"net/http"
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
 	"github.com/gke-labs/gateway-api-reference-implementation/pkg/controller"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/logfile"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/otlp"
 	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/version"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/klog/v2/textlogger"
 	ctrl "sigs.k8s.io/controller-runtime"
-
-	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
-	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 var (
@@ -41,80 +51,221 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(gatewayv1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(controller.AddExperimentalToScheme(scheme))
 }
 
 func main() {
-	var metricsAddr string
-	var enableLeaderElection bool
-	var probeAddr string
-	var proxyAddr string
-	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
-	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
-	flag.StringVar(&proxyAddr, "proxy-bind-address", ":8000", "The address the proxy binds to.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
-
-	logConfig := textlogger.NewConfig()
-	logConfig.AddFlags(flag.CommandLine)
-	flag.Parse()
-
-	ctrl.SetLogger(textlogger.NewLogger(logConfig))
-
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
-		},
-		WebhookServer: webhook.NewServer(webhook.Options{
-			Port: 9443,
-		}),
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "gateway-api-reference-implementation",
-	})
-	if err != nil {
-		setupLog.Error(err, "unable to start manager")
+	// Without a subcommand the flags are the controller's, as they were
+	// before the binary had subcommands.
+	command, args := "controller", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	switch command {
+	case "controller":
+		runController(args)
+	case "proxy":
+		runProxy(args)
+	case "translate":
+		os.Exit(translate(args))
+	case "validate":
+		os.Exit(validate(args))
+	case "install":
+		os.Exit(install(args))
+	case "uninstall":
+		os.Exit(uninstall(args))
+	case "version":
+		os.Exit(printVersion(args))
+	case "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		usage()
 		os.Exit(1)
 	}
+}
 
-	p := proxy.NewProxy()
-	go func() {
-		setupLog.Info("starting proxy server", "addr", proxyAddr)
-		if err := http.ListenAndServe(proxyAddr, p); err != nil {
-			setupLog.Error(err, "proxy server failed")
-			os.Exit(1)
+// usage prints the subcommands of the binary.
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: %s COMMAND [flags]
+
+Commands:
+  controller  Run the controller, the default when the flags are given without a command
+  proxy       Run a standalone proxy fed by the controller's config stream
+  translate   Translate Gateway API manifests without a cluster
+  validate    Validate Gateway API manifests without a cluster
+  install     Install the controller in the cluster
+  uninstall   Uninstall the controller from the cluster
+  version     Print the version
+
+Run "%[1]s COMMAND -h" for the flags of a command.
+`, filepath.Base(os.Args[0]))
+}
+
+// printVersion implements the version subcommand, which prints the version
+// of the binary, with the commit and date it was built from and the Gateway
+// API version it implements.
+func printVersion(args []string) int {
+	flags := flag.NewFlagSet("version", flag.ExitOnError)
+	output := flags.String("output", "text", "The output format: text or json.")
+	_ = flags.Parse(args)
+	info := version.Get()
+	switch *output {
+	case "text":
+		fmt.Printf("version: %s\ncommit: %s\nbuild date: %s\ngo: %s\ngateway-api: %s\n",
+			info.Version, info.Commit, info.BuildDate, info.GoVersion, info.GatewayAPIVersion)
+	case "json":
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
 		}
-	}()
-
-	if err = (&controller.HTTPRouteReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Proxy:  p,
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "HTTPRoute")
-		os.Exit(1)
+		fmt.Println(string(data))
+	default:
+		flags.Usage()
+		return 1
 	}
+	return 0
+}
 
-	if err = (&controller.GatewayClassReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "GatewayClass")
-		os.Exit(1)
+// newMetricsExporter returns the exporter pushing the metrics of the
+// component to the OTLP endpoint, with the headers given as comma-separated
+// name=value pairs.
+func newMetricsExporter(component, endpoint, headers string, interval time.Duration) (*otlp.MetricsExporter, error) {
+	parsed, err := otlp.ParseHeaders(headers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --otlp-metrics-headers: %w", err)
 	}
+	hostname, _ := os.Hostname()
+	return otlp.NewMetricsExporter(otlp.MetricsExporterOptions{
+		Endpoint: endpoint,
+		Headers:  parsed,
+		Interval: interval,
+		Gatherer: ctrlmetrics.Registry,
+		Resource: map[string]string{
+			"service.name":        "gari-" + component,
+			"service.version":     version.Get().Version,
+			"service.instance.id": cmp.Or(os.Getenv("POD_NAME"), hostname),
+		},
+	}), nil
+}
 
-	if err = (&controller.GatewayReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Gateway")
-		os.Exit(1)
+// setTraceSampling sets the trace sampling of routes without a
+// TelemetryPolicy that overrides it.
+func setTraceSampling(sampler string, percent int) error {
+	if !slices.Contains(proxy.TraceSamplers, proxy.TraceSampler(sampler)) {
+		return fmt.Errorf("invalid --trace-sampler: must be one of %v, got %q", proxy.TraceSamplers, sampler)
+	}
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("invalid --trace-sampling-percent: must be between 0 and 100, got %d", percent)
 	}
+	proxy.DefaultTelemetry.TraceSampler = proxy.TraceSampler(sampler)
+	proxy.DefaultTelemetry.TraceSamplingPercent = int32(percent)
+	return nil
+}
 
-	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
-		setupLog.Error(err, "problem running manager")
-		os.Exit(1)
+// accessLogOptions holds the flags selecting where and how the proxy writes
+// its access log.
+type accessLogOptions struct {
+	output           string
+	maxSizeMB        int
+	rotationInterval time.Duration
+	maxBackups       int
+	compress         bool
+	format           string
+	fields           string
+	staticFields     string
+}
+
+func (o *accessLogOptions) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.output, "access-log-output", "stdout",
+		"Where the access log is written: stdout, or the path of a file that is rotated on size and time.")
+	fs.IntVar(&o.maxSizeMB, "access-log-max-size", 100,
+		"The size in megabytes past which the access log file is rotated. 0 disables rotation on size.")
+	fs.DurationVar(&o.rotationInterval, "access-log-rotation-interval", 24*time.Hour,
+		"How long the access log file is written to before it is rotated. 0 disables rotation on time.")
+	fs.IntVar(&o.maxBackups, "access-log-max-backups", 7,
+		"How many rotated access log files are kept. 0 keeps them all.")
+	fs.BoolVar(&o.compress, "access-log-compress", false,
+		"Compress rotated access log files with gzip.")
+	fs.StringVar(&o.format, "access-log-format", string(proxy.AccessLogFormatText),
+		"The format of access log entries: Text writes the fields of --access-log-fields, "+
+			"and Common writes them in the Common Log Format, ignoring --access-log-fields and --access-log-static-fields.")
+	fs.StringVar(&o.fields, "access-log-fields", strings.Join(proxy.DefaultAccessLogFields, ","),
+		fmt.Sprintf("Comma-separated list of the fields of access log entries, in order, from %s.", strings.Join(proxy.AccessLogFields, ", ")))
+	fs.StringVar(&o.staticFields, "access-log-static-fields", "",
+		"Fields added to every access log entry, such as the name of the cluster, as comma-separated name=value pairs.")
+}
+
+// apply sets the access log options of opts from the flags.
+func (o *accessLogOptions) apply(opts *proxy.Options) error {
+	opts.AccessLogFormat = proxy.AccessLogFormat(o.format)
+	if !slices.Contains(proxy.AccessLogFormats, opts.AccessLogFormat) {
+		return fmt.Errorf("invalid --access-log-format: must be one of %v, got %q", proxy.AccessLogFormats, o.format)
+	}
+	opts.AccessLogFields = []string{}
+	for _, field := range strings.Split(o.fields, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		if !slices.Contains(proxy.AccessLogFields, field) {
+			return fmt.Errorf("invalid --access-log-fields: must be among %v, got %q", proxy.AccessLogFields, field)
+		}
+		opts.AccessLogFields = append(opts.AccessLogFields, field)
+	}
+	opts.AccessLogStaticFields = map[string]string{}
+	for _, pair := range strings.Split(o.staticFields, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			return fmt.Errorf("invalid --access-log-static-fields: fields must be given as name=value, got %q", pair)
+		}
+		opts.AccessLogStaticFields[name] = strings.TrimSpace(value)
+	}
+
+	if o.output == "stdout" {
+		opts.AccessLog = os.Stdout
+		return nil
+	}
+	file, err := logfile.Open(o.output, logfile.Options{
+		MaxSize:          int64(o.maxSizeMB) << 20,
+		RotationInterval: o.rotationInterval,
+		MaxBackups:       o.maxBackups,
+		Compress:         o.compress,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to open --access-log-output: %w", err)
+	}
+	opts.AccessLog = file
+	return nil
+}
+
+// routeMetricsOptions holds the flags bounding the number of series of the
+// proxy's request metrics.
+type routeMetricsOptions struct {
+	routeLabels string
+	maxRoutes   int
+}
+
+func (o *routeMetricsOptions) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.routeLabels, "metrics-route-labels", string(proxy.RouteLabelModeFull),
+		"The route labels of request metrics, whatever the TelemetryPolicies of the routes: Full labels them with the route and rule names, "+
+			"Hashed with short hashes of the names, and None records them without route labels.")
+	fs.IntVar(&o.maxRoutes, "metrics-max-routes", 0,
+		"The number of routes whose request metrics carry their own labels. "+
+			"The requests of the routes past it are recorded together, under the _overflow route. 0 does not cap them.")
+}
+
+// apply sets the metrics options of opts from the flags.
+func (o *routeMetricsOptions) apply(opts *proxy.Options) error {
+	opts.MetricsRouteLabels = proxy.RouteLabelMode(o.routeLabels)
+	if !slices.Contains(proxy.RouteLabelModes, opts.MetricsRouteLabels) {
+		return fmt.Errorf("invalid --metrics-route-labels: must be one of %v, got %q", proxy.RouteLabelModes, o.routeLabels)
 	}
+	opts.MaxMetricsRoutes = o.maxRoutes
+	return nil
 }
@@ -0,0 +1,133 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/controller"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// offlineReader is the in-memory client.Reader the translate and validate
+// subcommands read the objects of manifests from. It serves the field indexes
+// registered by controller.SetupIndexes, as the manager's cache does.
+type offlineReader struct {
+	objects map[schema.GroupVersionKind][]client.Object
+	indexes map[schema.GroupVersionKind]map[string]client.IndexerFunc
+}
+
+// newOfflineReader returns an offlineReader holding objects.
+func newOfflineReader(objects []client.Object) (*offlineReader, error) {
+	r := &offlineReader{
+		objects: map[schema.GroupVersionKind][]client.Object{},
+		indexes: map[schema.GroupVersionKind]map[string]client.IndexerFunc{},
+	}
+	for _, obj := range objects {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, err
+		}
+		r.objects[gvk] = append(r.objects[gvk], obj)
+	}
+	if err := controller.SetupIndexes(context.Background(), r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *offlineReader) IndexField(_ context.Context, obj client.Object, field string, extract client.IndexerFunc) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return err
+	}
+	if r.indexes[gvk] == nil {
+		r.indexes[gvk] = map[string]client.IndexerFunc{}
+	}
+	r.indexes[gvk][field] = extract
+	return nil
+}
+
+func (r *offlineReader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return err
+	}
+	for _, stored := range r.objects[gvk] {
+		if client.ObjectKeyFromObject(stored) == key {
+			reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+			return nil
+		}
+	}
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	return apierrors.NewNotFound(resource.GroupResource(), key.Name)
+}
+
+func (r *offlineReader) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	var options client.ListOptions
+	options.ApplyOptions(opts)
+
+	var items []runtime.Object
+	for _, obj := range r.objects[gvk] {
+		match, err := r.matches(gvk, obj, &options)
+		if err != nil {
+			return err
+		}
+		if match {
+			items = append(items, obj.DeepCopyObject())
+		}
+	}
+	return meta.SetList(list, items)
+}
+
+// matches reports whether obj, of the given kind, is selected by options.
+// Field selectors may only require exact matches of indexed fields.
+func (r *offlineReader) matches(gvk schema.GroupVersionKind, obj client.Object, options *client.ListOptions) (bool, error) {
+	if options.Namespace != "" && obj.GetNamespace() != options.Namespace {
+		return false, nil
+	}
+	if options.LabelSelector != nil && !options.LabelSelector.Matches(labels.Set(obj.GetLabels())) {
+		return false, nil
+	}
+	if options.FieldSelector == nil {
+		return true, nil
+	}
+	for _, requirement := range options.FieldSelector.Requirements() {
+		extract, ok := r.indexes[gvk][requirement.Field]
+		if !ok || (requirement.Operator != selection.Equals && requirement.Operator != selection.DoubleEquals) {
+			return false, fmt.Errorf("field selector %s is not supported on %s", options.FieldSelector, gvk.Kind)
+		}
+		if !slices.Contains(extract(obj), requirement.Value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/admin"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/configstream"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/otlp"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/version"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2/textlogger"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// runProxy runs a standalone proxy serving the routes streamed by the
+// controller's config stream, so that the proxy can be scaled and deployed
+// separately from the controller.
+func runProxy(args []string) {
+	flags := flag.NewFlagSet("proxy", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s proxy [flags]\n\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(flags.Output(), "Runs a standalone proxy serving the routes streamed by the controller's config stream.\n\n")
+		flags.PrintDefaults()
+	}
+	var metricsAddr string
+	var probeAddr string
+	var proxyAddr string
+	var redactHeaders string
+	var configStreamAddr string
+	var configStreamTokenFile string
+	var configStreamCAFile string
+	var configStreamCertFile string
+	var configStreamKeyFile string
+	var adminAddr string
+	var adminTokenFile string
+	var adminCertFile string
+	var adminKeyFile string
+	var adminClientCAFile string
+	var shutdownDelay time.Duration
+	var reusePort bool
+	var traceSampler string
+	var traceSamplingPercent int
+	var otlpMetricsEndpoint string
+	var otlpMetricsHeaders string
+	var otlpMetricsInterval time.Duration
+	flags.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flags.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flags.StringVar(&proxyAddr, "proxy-bind-address", ":8000",
+		"Comma-separated list of addresses the proxy binds to. Set this to \"\" to bind none.")
+	flags.StringVar(&redactHeaders, "redact-headers", strings.Join(proxy.DefaultRedactedHeaders, ","),
+		"Comma-separated list of headers whose values are redacted in logs, debug dumps and traces.")
+	flags.StringVar(&configStreamAddr, "config-stream-address", "",
+		"The address of the controller's config stream, from which the routes to serve are received.")
+	flags.StringVar(&configStreamTokenFile, "config-stream-token-file", "",
+		"File containing the bearer token that authenticates the proxy to the config stream.")
+	flags.StringVar(&configStreamCAFile, "config-stream-ca-file", "",
+		"CA bundle used to verify the config stream's certificate. Setting it, or a client certificate, connects over TLS.")
+	flags.StringVar(&configStreamCertFile, "config-stream-tls-cert-file", "", "Client certificate file presented to the config stream.")
+	flags.StringVar(&configStreamKeyFile, "config-stream-tls-key-file", "", "Client key file presented to the config stream.")
+	flags.StringVar(&adminAddr, "admin-bind-address", "0",
+		"The address the admin endpoints, and the gRPC health service reporting the readiness of the data plane, bind to. "+
+			"Set this to \"0\" to disable the admin endpoints.")
+	flags.StringVar(&adminTokenFile, "admin-token-file", "",
+		"File containing the bearer token that authenticates requests to the admin endpoints.")
+	flags.StringVar(&adminCertFile, "admin-tls-cert-file", "", "Certificate file for serving the admin endpoints over TLS.")
+	flags.StringVar(&adminKeyFile, "admin-tls-key-file", "", "Key file for serving the admin endpoints over TLS.")
+	flags.StringVar(&adminClientCAFile, "admin-client-ca-file", "",
+		"CA bundle used to verify client certificates for the admin endpoints. Requires --admin-tls-cert-file.")
+	flags.DurationVar(&shutdownDelay, "shutdown-delay", 0,
+		"How long the proxy keeps serving once asked to stop, failing its readiness check, before it drains. "+
+			"Set this to the deregistration delay of the load balancer in front of it.")
+	flags.StringVar(&traceSampler, "trace-sampler", string(proxy.TraceSamplerParentBased),
+		"Which requests are marked as sampled in their trace context, unless a TelemetryPolicy overrides it: "+
+			"Always samples every request, Ratio samples --trace-sampling-percent of the traces whatever their caller decided, "+
+			"and ParentBased keeps the decision of callers, sampling --trace-sampling-percent of the traces the proxy starts.")
+	flags.IntVar(&traceSamplingPercent, "trace-sampling-percent", 100,
+		"The percentage of traces sampled by the Ratio and ParentBased samplers.")
+	flags.StringVar(&otlpMetricsEndpoint, "otlp-metrics-endpoint", "",
+		"The OTLP/HTTP endpoint of an OpenTelemetry collector to push metrics to, such as http://otel-collector:4318/v1/metrics, "+
+			"in addition to serving them for scraping. Empty disables pushing.")
+	flags.StringVar(&otlpMetricsHeaders, "otlp-metrics-headers", "",
+		"Headers set on the requests pushing metrics, as comma-separated name=value pairs.")
+	flags.DurationVar(&otlpMetricsInterval, "otlp-metrics-interval", otlp.DefaultInterval,
+		"How often metrics are pushed to --otlp-metrics-endpoint.")
+	flags.BoolVar(&reusePort, "reuse-port", false,
+		"Bind every address with SO_REUSEPORT, so that a replacement proxy can bind them while this one drains, "+
+			"upgrading the proxy in place without refusing connections.")
+
+	var accessLog accessLogOptions
+	accessLog.AddFlags(flags)
+	var routeMetrics routeMetricsOptions
+	routeMetrics.AddFlags(flags)
+	logConfig := textlogger.NewConfig()
+	logConfig.AddFlags(flags)
+	_ = flags.Parse(args)
+
+	ctrl.SetLogger(textlogger.NewLogger(logConfig))
+	setupLog.Info("starting proxy", version.Get().KeysAndValues()...)
+	proxy.RegisterRuntimeMetrics()
+	if err := setTraceSampling(traceSampler, traceSamplingPercent); err != nil {
+		setupLog.Error(err, "invalid trace sampling")
+		os.Exit(1)
+	}
+
+	proxyOptions := proxy.Options{RedactedHeaders: strings.Split(redactHeaders, ",")}
+	if err := accessLog.apply(&proxyOptions); err != nil {
+		setupLog.Error(err, "unable to configure access log")
+		os.Exit(1)
+	}
+	if err := routeMetrics.apply(&proxyOptions); err != nil {
+		setupLog.Error(err, "unable to configure metrics")
+		os.Exit(1)
+	}
+	p := proxy.NewProxy(proxyOptions)
+	streamClient, err := newConfigStreamClient(p, configStreamAddr, configStreamTokenFile, configStreamCAFile, configStreamCertFile, configStreamKeyFile)
+	if err != nil {
+		setupLog.Error(err, "unable to configure config stream")
+		os.Exit(1)
+	}
+
+	// Listeners are bound before the probes are served, so that the proxy is
+	// not reported ready without them.
+	var proxyServers []*http.Server
+	serve := func(name string, server *http.Server) {
+		listener, err := proxy.Listen(server.Addr, reusePort)
+		if err != nil {
+			setupLog.Error(err, "unable to listen for "+name+" server")
+			os.Exit(1)
+		}
+		go func() {
+			setupLog.Info("starting "+name+" server", "addr", server.Addr)
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				setupLog.Error(err, name+" server failed")
+				os.Exit(1)
+			}
+		}()
+	}
+	for _, addr := range strings.Split(proxyAddr, ",") {
+		if addr != "" {
+			server := &http.Server{Addr: addr, Handler: p, ConnState: proxy.ConnState}
+			proxyServers = append(proxyServers, server)
+			serve("proxy", server)
+		}
+	}
+	serve("metrics", &http.Server{Addr: metricsAddr, Handler: promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{})})
+
+	shutdown := proxy.NewShutdownDelay(shutdownDelay)
+	probes := http.NewServeMux()
+	probes.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+	ready := func(r *http.Request) error {
+		if err := shutdown.ReadyCheck(r); err != nil {
+			return err
+		}
+		return streamClient.ReadyCheck(r)
+	}
+	probes.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := ready(r); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+	})
+	serve("probe", &http.Server{Addr: probeAddr, Handler: probes})
+
+	if adminAddr != "0" {
+		adminServer, err := admin.NewServer(p, adminAddr, adminTokenFile, adminCertFile, adminKeyFile, adminClientCAFile, logConfig.Verbosity(), ready)
+		if err != nil {
+			setupLog.Error(err, "unable to configure admin endpoints")
+			os.Exit(1)
+		}
+		listener, err := proxy.Listen(adminAddr, reusePort)
+		if err != nil {
+			setupLog.Error(err, "unable to listen for admin server")
+			os.Exit(1)
+		}
+		go func() {
+			setupLog.Info("starting admin server", "addr", adminAddr)
+			var err error
+			if adminServer.TLSConfig != nil {
+				err = adminServer.ServeTLS(listener, adminCertFile, adminKeyFile)
+			} else {
+				err = adminServer.Serve(listener)
+			}
+			if err != nil {
+				setupLog.Error(err, "admin server failed")
+				os.Exit(1)
+			}
+		}()
+	}
+
+	ctx := shutdown.Context(ctrl.SetupSignalHandler())
+	go admin.ToggleVerbosityOnSignal(ctx, logConfig.Verbosity())
+	// The exporter pushes metrics once more when ctx is done, which is waited
+	// for before exiting.
+	var exported sync.WaitGroup
+	if otlpMetricsEndpoint != "" {
+		exporter, err := newMetricsExporter("proxy", otlpMetricsEndpoint, otlpMetricsHeaders, otlpMetricsInterval)
+		if err != nil {
+			setupLog.Error(err, "unable to configure OTLP metrics export")
+			os.Exit(1)
+		}
+		exported.Go(func() { _ = exporter.Start(ctx) })
+	}
+	setupLog.Info("streaming routes", "addr", configStreamAddr)
+	if err := streamClient.Start(ctx); err != nil {
+		setupLog.Error(err, "problem streaming routes")
+		os.Exit(1)
+	}
+
+	// The requests in flight are drained until they complete or the pod's
+	// termination grace period runs out.
+	setupLog.Info("draining proxy servers")
+	var drained sync.WaitGroup
+	for _, server := range proxyServers {
+		drained.Go(func() {
+			if err := server.Shutdown(context.Background()); err != nil {
+				setupLog.Error(err, "unable to drain proxy server", "addr", server.Addr)
+			}
+		})
+	}
+	drained.Wait()
+	exported.Wait()
+}
+
+// newConfigStreamClient returns the client programming p with the routes of
+// the config stream, which is connected to over TLS when a CA bundle or a
+// client certificate is given.
+func newConfigStreamClient(p *proxy.Proxy, addr, tokenFile, caFile, certFile, keyFile string) (*configstream.Client, error) {
+	if addr == "" {
+		return nil, errors.New("--config-stream-address is required")
+	}
+	opts := configstream.ClientOptions{Address: addr}
+	if tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.Token = strings.TrimSpace(string(token))
+	}
+	if caFile != "" || certFile != "" {
+		tlsConfig, err := configstream.ClientTLS(caFile, certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.TLS = tlsConfig
+	}
+	hostname, _ := os.Hostname()
+	opts.Node = cmp.Or(os.Getenv("POD_NAME"), hostname)
+	return configstream.NewClient(p, opts), nil
+}
@@ -0,0 +1,309 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/controller"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/nginx"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Exit codes of the translate subcommand.
+const (
+	translateFailed   = 1
+	translateRejected = 2
+)
+
+// translate implements the translate subcommand, which runs the controller's
+// translation over manifests read from disk, without a cluster, and prints the
+// route table and the status conditions that would be set, or the route table
+// as an nginx.conf. It exits with
+// translateRejected if any object would not be accepted, or has references
+// that do not resolve.
+func translate(args []string) int {
+	flags := flag.NewFlagSet("translate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s translate [flags] FILE_OR_DIRECTORY...\n\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(flags.Output(), "Translates Gateway API manifests, as YAML or JSON, without a cluster. \"-\" reads them from standard input.\n\n")
+		flags.PrintDefaults()
+	}
+	controllerName := flags.String("controller-name", controller.DefaultControllerName,
+		"The controllerName of the GatewayClasses served.")
+	namespace := flags.String("namespace", "default", "The namespace of the namespaced objects that do not set one.")
+	output := flags.String("output", "text", "The output format: text, json, or nginx for the route table as an nginx.conf.")
+	_ = flags.Parse(args)
+	if flags.NArg() == 0 || !slices.Contains([]string{"text", "json", "nginx"}, *output) {
+		flags.Usage()
+		return translateFailed
+	}
+	if err := controller.ValidateControllerName(*controllerName); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --controller-name: %v\n", err)
+		return translateFailed
+	}
+	ctrl.SetLogger(logr.Discard())
+
+	var objects []client.Object
+	for _, path := range flags.Args() {
+		loaded, err := loadManifests(path, *namespace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to load %s: %v\n", path, err)
+			return translateFailed
+		}
+		objects = append(objects, loaded...)
+	}
+	c, err := newOfflineReader(objects)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to load the objects: %v\n", err)
+		return translateFailed
+	}
+	translation, err := controller.Translate(context.Background(), c, gatewayv1.GatewayController(*controllerName))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to translate: %v\n", err)
+		return translateFailed
+	}
+
+	switch *output {
+	case "json":
+		err = printTranslationJSON(os.Stdout, translation)
+	case "nginx":
+		_, err = io.WriteString(os.Stdout, nginx.Render(translation.Routes, nil))
+	default:
+		err = printTranslation(os.Stdout, translation)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to print the translation: %v\n", err)
+		return translateFailed
+	}
+	if translationRejected(translation) {
+		return translateRejected
+	}
+	return 0
+}
+
+// loadManifests decodes the objects of kinds known to the scheme from the
+// manifests in path, a file, a directory of .yaml, .yml and .json files, or
+// "-" for standard input. Objects of other kinds are skipped.
+func loadManifests(path, namespace string) ([]client.Object, error) {
+	if path == "-" {
+		return decodeManifests(os.Stdin, namespace)
+	}
+	var objects []client.Object
+	err := filepath.WalkDir(path, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if name != path {
+			switch filepath.Ext(name) {
+			case ".yaml", ".yml", ".json":
+			default:
+				return nil
+			}
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		decoded, err := decodeManifests(f, namespace)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		objects = append(objects, decoded...)
+		return nil
+	})
+	return objects, err
+}
+
+// clusterScopedKinds lists the kinds known to the scheme that the translation
+// reads and that are not namespaced.
+var clusterScopedKinds = map[string]bool{"GatewayClass": true, "Namespace": true, "Node": true}
+
+// decodeManifests decodes the objects of the YAML or JSON documents read from
+// r, setting namespace on namespaced objects that have none.
+func decodeManifests(r io.Reader, namespace string) ([]client.Object, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bufio.NewReader(r), 4096)
+	var objects []client.Object
+	for {
+		var u unstructured.Unstructured
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, err
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		if u.IsList() {
+			list, err := u.ToList()
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				obj, err := typedObject(&list.Items[i], namespace)
+				if err != nil {
+					return nil, err
+				}
+				if obj != nil {
+					objects = append(objects, obj)
+				}
+			}
+			continue
+		}
+		obj, err := typedObject(&u, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if obj != nil {
+			objects = append(objects, obj)
+		}
+	}
+}
+
+// typedObject converts u to the type registered for its kind, or returns nil
+// if there is none.
+func typedObject(u *unstructured.Unstructured, namespace string) (client.Object, error) {
+	gvk := u.GroupVersionKind()
+	typed, err := scheme.New(gvk)
+	if runtime.IsNotRegisteredError(err) {
+		fmt.Fprintf(os.Stderr, "skipping %s %s: kind not translated\n", gvk.Kind, u.GetName())
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+		return nil, fmt.Errorf("%s %s: %w", gvk.Kind, u.GetName(), err)
+	}
+	obj, ok := typed.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s %s: not an object", gvk.Kind, u.GetName())
+	}
+	if obj.GetNamespace() == "" && !clusterScopedKinds[gvk.Kind] {
+		obj.SetNamespace(namespace)
+	}
+	return obj, nil
+}
+
+// printTranslation prints the route table as JSON, then the conditions of
+// every object, one per line.
+func printTranslation(w io.Writer, t *controller.Translation) error {
+	table := t.Routes
+	if table == nil {
+		table = []proxy.HTTPRoute{}
+	}
+	routes, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "# Route table\n%s\n\n# Status\n", routes)
+	printConditions := func(object string, conditions []metav1.Condition) {
+		for _, c := range conditions {
+			fmt.Fprintf(w, "%s: %s=%s (%s) %s\n", object, c.Type, c.Status, c.Reason, c.Message)
+		}
+	}
+	for _, gc := range t.GatewayClasses {
+		printConditions("GatewayClass "+gc.Name, gc.Status.Conditions)
+	}
+	for _, gw := range t.Gateways {
+		object := fmt.Sprintf("Gateway %s/%s", gw.Namespace, gw.Name)
+		printConditions(object, gw.Status.Conditions)
+		for _, listener := range gw.Status.Listeners {
+			printConditions(fmt.Sprintf("%s listener %s", object, listener.Name), listener.Conditions)
+		}
+	}
+	for _, route := range t.HTTPRoutes {
+		for _, parent := range route.Status.Parents {
+			printConditions(fmt.Sprintf("HTTPRoute %s/%s parent %s", route.Namespace, route.Name, parent.ParentRef.Name), parent.Conditions)
+		}
+	}
+	return nil
+}
+
+// printTranslationJSON prints the route table and the status of every object
+// as one JSON document.
+func printTranslationJSON(w io.Writer, t *controller.Translation) error {
+	type status struct {
+		Kind      string `json:"kind"`
+		Namespace string `json:"namespace,omitempty"`
+		Name      string `json:"name"`
+		Status    any    `json:"status"`
+	}
+	var statuses []status
+	for _, gc := range t.GatewayClasses {
+		statuses = append(statuses, status{Kind: "GatewayClass", Name: gc.Name, Status: gc.Status})
+	}
+	for _, gw := range t.Gateways {
+		statuses = append(statuses, status{Kind: "Gateway", Namespace: gw.Namespace, Name: gw.Name, Status: gw.Status})
+	}
+	for _, route := range t.HTTPRoutes {
+		statuses = append(statuses, status{Kind: "HTTPRoute", Namespace: route.Namespace, Name: route.Name, Status: route.Status})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]any{"routes": t.Routes, "statuses": statuses})
+}
+
+// translationRejected reports whether any object of the translation would not
+// be accepted, or has references that do not resolve.
+func translationRejected(t *controller.Translation) bool {
+	var conditions [][]metav1.Condition
+	for _, gc := range t.GatewayClasses {
+		conditions = append(conditions, gc.Status.Conditions)
+	}
+	for _, gw := range t.Gateways {
+		conditions = append(conditions, gw.Status.Conditions)
+		for _, listener := range gw.Status.Listeners {
+			conditions = append(conditions, listener.Conditions)
+		}
+	}
+	for _, route := range t.HTTPRoutes {
+		for _, parent := range route.Status.Parents {
+			conditions = append(conditions, parent.Conditions)
+		}
+	}
+	for _, cs := range conditions {
+		for _, c := range cs {
+			if (c.Type == "Accepted" || c.Type == "ResolvedRefs") && c.Status == metav1.ConditionFalse {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/controller"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Exit codes of the validate subcommand.
+const (
+	validateFailed   = 1
+	validateFindings = 2
+)
+
+// validate implements the validate subcommand, which applies the checks of
+// the admission webhooks to manifests read from disk, without a cluster, and
+// prints the problems found. It exits with validateFindings if there are
+// any.
+func validate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s validate [flags] FILE_OR_DIRECTORY...\n\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(flags.Output(), "Validates Gateway API manifests, as YAML or JSON, without a cluster. \"-\" reads them from standard input.\n\n")
+		flags.PrintDefaults()
+	}
+	controllerName := flags.String("controller-name", controller.DefaultControllerName,
+		"The controllerName of the GatewayClasses served.")
+	namespace := flags.String("namespace", "default", "The namespace of the namespaced objects that do not set one.")
+	output := flags.String("output", "text", "The output format: text, or json for a list of findings.")
+	_ = flags.Parse(args)
+	if flags.NArg() == 0 || (*output != "text" && *output != "json") {
+		flags.Usage()
+		return validateFailed
+	}
+	if err := controller.ValidateControllerName(*controllerName); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --controller-name: %v\n", err)
+		return validateFailed
+	}
+	ctrl.SetLogger(logr.Discard())
+
+	var objects []client.Object
+	for _, path := range flags.Args() {
+		loaded, err := loadManifests(path, *namespace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to load %s: %v\n", path, err)
+			return validateFailed
+		}
+		objects = append(objects, loaded...)
+	}
+	c, err := newOfflineReader(objects)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to load the objects: %v\n", err)
+		return validateFailed
+	}
+	findings, err := controller.Validate(context.Background(), c, gatewayv1.GatewayController(*controllerName))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to validate: %v\n", err)
+		return validateFailed
+	}
+
+	if *output == "json" {
+		if findings == nil {
+			findings = []controller.Finding{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to print the findings: %v\n", err)
+			return validateFailed
+		}
+	} else {
+		for _, finding := range findings {
+			fmt.Println(finding)
+		}
+	}
+	if len(findings) > 0 {
+		return validateFindings
+	}
+	return 0
+}
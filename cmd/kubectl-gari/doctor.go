@@ -0,0 +1,316 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/controller"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/pkg/consts"
+)
+
+// The names of the default install; see k8s/controller.yaml.
+const (
+	controllerServiceAccount = "gari-controller"
+	controllerDeployment     = "gari-controller"
+)
+
+// requiredCRDs lists the Gateway API CRDs the controller cannot run without.
+var requiredCRDs = []string{
+	"gatewayclasses.gateway.networking.k8s.io",
+	"gateways.gateway.networking.k8s.io",
+	"httproutes.gateway.networking.k8s.io",
+}
+
+// requiredPermissions lists the permissions of the controller's
+// ServiceAccount checked by doctor: those without which no Gateway or route
+// is served, and their status not written.
+var requiredPermissions = []authorizationv1.ResourceAttributes{
+	{Group: gatewayv1.GroupName, Resource: "gatewayclasses", Verb: "watch"},
+	{Group: gatewayv1.GroupName, Resource: "gateways", Verb: "watch"},
+	{Group: gatewayv1.GroupName, Resource: "httproutes", Verb: "watch"},
+	{Group: gatewayv1.GroupName, Resource: "gateways", Subresource: "status", Verb: "patch"},
+	{Group: gatewayv1.GroupName, Resource: "httproutes", Subresource: "status", Verb: "patch"},
+	{Group: gatewayv1.GroupName, Resource: "gatewayclasses", Subresource: "status", Verb: "patch"},
+	{Resource: "services", Verb: "watch"},
+	{Resource: "secrets", Verb: "watch"},
+	{Group: discoveryv1.GroupName, Resource: "endpointslices", Verb: "watch"},
+	{Resource: "events", Verb: "create"},
+}
+
+// check is the outcome of a doctor check.
+type check struct {
+	name string
+	// failed and warning are unset for a check that passed.
+	failed, warning bool
+	detail          string
+	// remediation tells how to fix a check that did not pass.
+	remediation string
+}
+
+func (c check) String() string {
+	status := "OK"
+	switch {
+	case c.failed:
+		status = "FAIL"
+	case c.warning:
+		status = "WARN"
+	}
+	s := fmt.Sprintf("[%s] %s: %s", status, c.name, c.detail)
+	if (c.failed || c.warning) && c.remediation != "" {
+		s += "\n       -> " + c.remediation
+	}
+	return s
+}
+
+// doctor implements "kubectl gari doctor", which checks the install and
+// prints how to fix what it finds. It fails if any check fails.
+func doctor(ctx context.Context, s *session, _ []string) error {
+	checks := []func(context.Context, *session) []check{
+		checkCRDs,
+		checkPermissions,
+		checkGatewayClasses,
+		checkWebhooks,
+		checkDataPlane,
+	}
+	failed := 0
+	for _, run := range checks {
+		for _, c := range run(ctx, s) {
+			fmt.Fprintln(s.out, c)
+			if c.failed {
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// checkCRDs checks that the Gateway API CRDs are installed, at the version
+// the controller is built against.
+func checkCRDs(ctx context.Context, s *session) []check {
+	var checks []check
+	for _, name := range requiredCRDs {
+		c := check{name: "CRD " + name}
+		var crd apiextensionsv1.CustomResourceDefinition
+		if err := s.Get(ctx, client.ObjectKey{Name: name}, &crd); err != nil {
+			c.failed = true
+			c.detail = err.Error()
+			c.remediation = fmt.Sprintf("install the Gateway API CRDs: kubectl apply --server-side -f "+
+				"https://github.com/kubernetes-sigs/gateway-api/releases/download/%s/standard-install.yaml", consts.BundleVersion)
+			checks = append(checks, c)
+			continue
+		}
+		version, channel := crd.Annotations[consts.BundleVersionAnnotation], crd.Annotations[consts.ChannelAnnotation]
+		c.detail = fmt.Sprintf("version %s, %s channel", orUnknown(version), orUnknown(channel))
+		if version != consts.BundleVersion {
+			c.warning = true
+			c.remediation = fmt.Sprintf("the controller is built against Gateway API %s; install the CRDs of that version", consts.BundleVersion)
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// checkPermissions checks the permissions of the controller's ServiceAccount
+// with SubjectAccessReviews.
+func checkPermissions(ctx context.Context, s *session) []check {
+	user := fmt.Sprintf("system:serviceaccount:%s:%s", s.opts.controllerNamespace, controllerServiceAccount)
+	c := check{name: "RBAC of " + user}
+	var missing []string
+	for _, attributes := range requiredPermissions {
+		review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:               user,
+			Groups:             []string{"system:serviceaccounts", "system:serviceaccounts:" + s.opts.controllerNamespace},
+			ResourceAttributes: &attributes,
+		}}
+		if err := s.Create(ctx, review); err != nil {
+			c.warning = true
+			c.detail = fmt.Sprintf("unable to check: %v", err)
+			c.remediation = "run doctor as a user allowed to create SubjectAccessReviews"
+			return []check{c}
+		}
+		if !review.Status.Allowed {
+			permission := attributes.Verb + " " + attributes.Resource
+			if attributes.Subresource != "" {
+				permission += "/" + attributes.Subresource
+			}
+			missing = append(missing, permission)
+		}
+	}
+	if len(missing) > 0 {
+		c.failed = true
+		c.detail = "missing " + strings.Join(missing, ", ")
+		c.remediation = "apply the ClusterRole and ClusterRoleBinding of k8s/controller.yaml, bound to the controller's ServiceAccount"
+		return []check{c}
+	}
+	c.detail = fmt.Sprintf("%d required permissions granted", len(requiredPermissions))
+	return []check{c}
+}
+
+// checkGatewayClasses checks that the GatewayClasses of our controller are
+// accepted.
+func checkGatewayClasses(ctx context.Context, s *session) []check {
+	var classes gatewayv1.GatewayClassList
+	if err := s.List(ctx, &classes); err != nil {
+		return []check{{name: "GatewayClasses", failed: true, detail: err.Error(), remediation: "install the Gateway API CRDs"}}
+	}
+	var checks []check
+	for _, gc := range classes.Items {
+		if string(gc.Spec.ControllerName) != s.opts.controllerName {
+			continue
+		}
+		c := check{name: "GatewayClass " + gc.Name}
+		accepted := meta.FindStatusCondition(gc.Status.Conditions, string(gatewayv1.GatewayClassConditionStatusAccepted))
+		switch {
+		case accepted == nil || accepted.ObservedGeneration < gc.Generation:
+			c.failed = true
+			c.detail = "not reconciled by the controller"
+			c.remediation = fmt.Sprintf("check that the controller runs with --controller-name %s: kubectl -n %s logs deploy/%s",
+				s.opts.controllerName, s.opts.controllerNamespace, controllerDeployment)
+		case accepted.Status != metav1.ConditionTrue:
+			c.failed = true
+			c.detail = fmt.Sprintf("not accepted (%s): %s", accepted.Reason, accepted.Message)
+			c.remediation = "fix the class's parametersRef"
+		default:
+			c.detail = "accepted"
+		}
+		checks = append(checks, c)
+	}
+	if len(checks) == 0 {
+		checks = append(checks, check{
+			name:        "GatewayClasses",
+			warning:     true,
+			detail:      fmt.Sprintf("no GatewayClass with controllerName %s", s.opts.controllerName),
+			remediation: "create a GatewayClass for the controller, as in k8s/controller.yaml",
+		})
+	}
+	return checks
+}
+
+// checkWebhooks checks that the validating webhooks, if they are installed,
+// can be reached, by creating an HTTPRoute with a dry run.
+func checkWebhooks(ctx context.Context, s *session) []check {
+	c := check{name: "Validating webhooks"}
+	var configurations admissionregistrationv1.ValidatingWebhookConfigurationList
+	if err := s.List(ctx, &configurations); err != nil {
+		c.warning = true
+		c.detail = fmt.Sprintf("unable to list: %v", err)
+		return []check{c}
+	}
+	installed := false
+	for _, configuration := range configurations.Items {
+		for _, webhook := range configuration.Webhooks {
+			if svc := webhook.ClientConfig.Service; svc != nil && svc.Path != nil && strings.Contains(*svc.Path, "gateway-networking-k8s-io-v1-httproute") {
+				installed = true
+			}
+		}
+	}
+	if !installed {
+		c.detail = "not installed; invalid routes are reported in their status instead of being rejected"
+		return []check{c}
+	}
+	route := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: s.opts.controllerNamespace, GenerateName: "gari-doctor-"}}
+	// The route has no parentRefs, so the webhook admits it; only the
+	// failures to call it matter.
+	if err := s.Create(ctx, route, client.DryRunAll); err != nil && strings.Contains(err.Error(), "failed calling webhook") {
+		c.failed = true
+		c.detail = err.Error()
+		c.remediation = fmt.Sprintf("check that the controller runs with --enable-webhooks and a serving certificate, or --webhook-cert-secret, "+
+			"and that its webhook Service has endpoints: kubectl -n %s get endpointslices", s.opts.controllerNamespace)
+		return []check{c}
+	}
+	c.detail = "reachable"
+	return []check{c}
+}
+
+// checkDataPlane checks that the controller, the proxy Service and the
+// proxies provisioned for classes in DaemonSet mode are ready.
+func checkDataPlane(ctx context.Context, s *session) []check {
+	var checks []check
+	deployment := check{name: "Controller Deployment " + s.opts.controllerNamespace + "/" + controllerDeployment}
+	var d appsv1.Deployment
+	if err := s.Get(ctx, client.ObjectKey{Namespace: s.opts.controllerNamespace, Name: controllerDeployment}, &d); err != nil {
+		deployment.failed = true
+		deployment.detail = err.Error()
+		deployment.remediation = "apply k8s/controller.yaml, or set --controller-namespace to where the controller runs"
+	} else if d.Status.ReadyReplicas == 0 {
+		deployment.failed = true
+		deployment.detail = fmt.Sprintf("0/%d replicas ready", d.Status.Replicas)
+		deployment.remediation = fmt.Sprintf("kubectl -n %s describe deploy/%s, and check its logs", s.opts.controllerNamespace, controllerDeployment)
+	} else {
+		deployment.detail = fmt.Sprintf("%d/%d replicas ready", d.Status.ReadyReplicas, d.Status.Replicas)
+	}
+	checks = append(checks, deployment)
+
+	service := check{name: "Proxy Service " + s.opts.controllerNamespace + "/" + controller.DefaultProxyServiceName}
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := s.List(ctx, &endpointSlices, client.InNamespace(s.opts.controllerNamespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: controller.DefaultProxyServiceName}); err != nil {
+		service.failed = true
+		service.detail = err.Error()
+	} else {
+		ready := 0
+		for _, slice := range endpointSlices.Items {
+			for _, endpoint := range slice.Endpoints {
+				if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+					ready++
+				}
+			}
+		}
+		service.detail = fmt.Sprintf("%d ready endpoints", ready)
+		if ready == 0 {
+			service.failed = true
+			service.remediation = "the proxy serves no traffic until the controller is ready; check its readiness probe"
+		}
+	}
+	checks = append(checks, service)
+
+	var daemonSets appsv1.DaemonSetList
+	if err := s.List(ctx, &daemonSets, client.MatchingLabels{"app.kubernetes.io/managed-by": controller.FieldManager}); err != nil {
+		return append(checks, check{name: "Provisioned proxies", warning: true, detail: fmt.Sprintf("unable to list: %v", err)})
+	}
+	for _, ds := range daemonSets.Items {
+		c := check{name: fmt.Sprintf("Provisioned proxies %s/%s", ds.Namespace, ds.Name)}
+		c.detail = fmt.Sprintf("%d/%d ready", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+		if ds.Status.NumberReady < ds.Status.DesiredNumberScheduled {
+			c.warning = true
+			c.remediation = fmt.Sprintf("kubectl -n %s describe ds/%s; the proxies must reach the config stream", ds.Namespace, ds.Name)
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,423 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// kubectl-gari is a kubectl plugin that explains how the Gateways and
+// HTTPRoutes of the reference implementation are served, from their status
+// and, when the controller's admin endpoints can be reached, from the route
+// table the proxy serves.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/admin"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/controller"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const usage = `Usage: kubectl gari COMMAND [flags]
+
+Commands:
+  routes                 List the HTTPRoutes, whether they are accepted and
+                         whether the proxy serves them
+  gateway status NAME    Show the status of a Gateway and of the routes
+                         attached to it
+  why ROUTE              Explain how an HTTPRoute is served, or why it is not
+  doctor                 Check the install: CRDs, RBAC, GatewayClasses,
+                         webhooks and data plane
+
+The route table served by the proxy is read from the controller's admin
+endpoints at --admin-url, for instance through
+"kubectl port-forward deploy/gari-controller 9090", authenticated with the
+bearer token in --admin-token-file. Without them, only the status is shown.
+`
+
+// options holds the flags common to every command.
+type options struct {
+	kubeconfig     string
+	namespace      string
+	allNamespaces  bool
+	adminURL       string
+	adminTokenFile string
+	controllerName string
+	// controllerNamespace is where the controller and the proxy Service
+	// run.
+	controllerNamespace string
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+	var err error
+	switch command, args := os.Args[1], os.Args[2:]; command {
+	case "routes":
+		err = run(args, 0, listRoutes)
+	case "gateway":
+		if len(args) == 0 || args[0] != "status" {
+			err = errors.New("usage: kubectl gari gateway status NAME")
+			break
+		}
+		err = run(args[1:], 1, gatewayStatus)
+	case "why":
+		err = run(args, 1, explainRoute)
+	case "doctor":
+		err = run(args, 0, doctor)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		err = fmt.Errorf("unknown command %q\n\n%s", command, usage)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// session holds what a command works with.
+type session struct {
+	client.Client
+	opts options
+	// live holds the routes served by the proxy, keyed by namespace/name,
+	// or is nil if the admin endpoints are not configured.
+	live map[string]admin.RouteDump
+	out  io.Writer
+}
+
+// run parses args, which must hold nargs positional arguments, and runs
+// command.
+func run(args []string, nargs int, command func(context.Context, *session, []string) error) error {
+	var opts options
+	flags := flag.NewFlagSet("kubectl gari", flag.ContinueOnError)
+	flags.StringVar(&opts.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file.")
+	flags.StringVar(&opts.namespace, "namespace", "", "The namespace of the objects. Defaults to the namespace of the current context.")
+	flags.StringVar(&opts.namespace, "n", "", "Shorthand for --namespace.")
+	flags.BoolVar(&opts.allNamespaces, "all-namespaces", false, "List the objects of every namespace.")
+	flags.BoolVar(&opts.allNamespaces, "A", false, "Shorthand for --all-namespaces.")
+	flags.StringVar(&opts.adminURL, "admin-url", os.Getenv("GARI_ADMIN_URL"),
+		"The base URL of the controller's admin endpoints. Defaults to $GARI_ADMIN_URL.")
+	flags.StringVar(&opts.adminTokenFile, "admin-token-file", os.Getenv("GARI_ADMIN_TOKEN_FILE"),
+		"File containing the bearer token of the admin endpoints. Defaults to $GARI_ADMIN_TOKEN_FILE.")
+	flags.StringVar(&opts.controllerName, "controller-name", controller.DefaultControllerName,
+		"The controllerName of the GatewayClasses served.")
+	flags.StringVar(&opts.controllerNamespace, "controller-namespace", "default",
+		"The namespace the controller and the proxy Service run in.")
+
+	// Flags may follow the positional arguments, as kubectl allows.
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if len(positional) != nargs {
+		return fmt.Errorf("expected %d argument(s), got %q", nargs, positional)
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = opts.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	if opts.namespace == "" {
+		if opts.namespace, _, err = clientConfig.Namespace(); err != nil {
+			return err
+		}
+	}
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.Install(scheme))
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	s := &session{Client: c, opts: opts, out: os.Stdout}
+	if opts.adminURL != "" {
+		adminClient := &admin.Client{URL: opts.adminURL}
+		if opts.adminTokenFile != "" {
+			token, err := os.ReadFile(opts.adminTokenFile)
+			if err != nil {
+				return err
+			}
+			adminClient.Token = strings.TrimSpace(string(token))
+		}
+		routes, err := adminClient.ConfigDump(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to read the route table from %s: %w", opts.adminURL, err)
+		}
+		s.live = map[string]admin.RouteDump{}
+		for _, route := range routes {
+			s.live[route.Namespace+"/"+route.Name] = route
+		}
+	}
+	return command(ctx, s, positional)
+}
+
+// served describes whether the proxy serves the route with the given key.
+func (s *session) served(key string) string {
+	if s.live == nil {
+		return "unknown"
+	}
+	if _, ok := s.live[key]; ok {
+		return "yes"
+	}
+	return "no"
+}
+
+// listRoutes implements "kubectl gari routes".
+func listRoutes(ctx context.Context, s *session, _ []string) error {
+	var routes gatewayv1.HTTPRouteList
+	var opts []client.ListOption
+	if !s.opts.allNamespaces {
+		opts = append(opts, client.InNamespace(s.opts.namespace))
+	}
+	if err := s.List(ctx, &routes, opts...); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(s.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tHOSTNAMES\tPARENTS\tACCEPTED\tRESOLVED\tSERVED")
+	for _, route := range routes.Items {
+		var parents, accepted, resolved []string
+		for _, ps := range s.parentStatuses(&route) {
+			parents = append(parents, string(ps.ParentRef.Name))
+			accepted = append(accepted, conditionStatus(ps.Conditions, string(gatewayv1.RouteConditionAccepted)))
+			resolved = append(resolved, conditionStatus(ps.Conditions, string(gatewayv1.RouteConditionResolvedRefs)))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", route.Namespace, route.Name, hostnames(route.Spec.Hostnames),
+			orNone(parents), orNone(accepted), orNone(resolved), s.served(route.Namespace+"/"+route.Name))
+	}
+	return w.Flush()
+}
+
+// gatewayStatus implements "kubectl gari gateway status NAME".
+func gatewayStatus(ctx context.Context, s *session, args []string) error {
+	var gw gatewayv1.Gateway
+	if err := s.Get(ctx, client.ObjectKey{Namespace: s.opts.namespace, Name: args[0]}, &gw); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Gateway %s/%s (class %s)\n", gw.Namespace, gw.Name, gw.Spec.GatewayClassName)
+	var addresses []string
+	for _, address := range gw.Status.Addresses {
+		addresses = append(addresses, address.Value)
+	}
+	fmt.Fprintf(s.out, "Addresses: %s\n", orNone(addresses))
+	printConditions(s.out, "", gw.Status.Conditions)
+	for _, listener := range gw.Spec.Listeners {
+		fmt.Fprintf(s.out, "Listener %s: %s on port %d, hostname %s\n", listener.Name, listener.Protocol, listener.Port, listenerHostname(listener.Hostname))
+		for _, ls := range gw.Status.Listeners {
+			if ls.Name == listener.Name {
+				printConditions(s.out, "  ", ls.Conditions)
+			}
+		}
+	}
+
+	var routes gatewayv1.HTTPRouteList
+	if err := s.List(ctx, &routes); err != nil {
+		return err
+	}
+	fmt.Fprintln(s.out, "Routes:")
+	w := tabwriter.NewWriter(s.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "  NAMESPACE\tNAME\tACCEPTED\tREASON\tSERVED")
+	for _, route := range routes.Items {
+		for _, ps := range s.parentStatuses(&route) {
+			if !referencesGateway(route.Namespace, ps.ParentRef, &gw) {
+				continue
+			}
+			reason := ""
+			if c := meta.FindStatusCondition(ps.Conditions, string(gatewayv1.RouteConditionAccepted)); c != nil {
+				reason = c.Reason
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", route.Namespace, route.Name,
+				conditionStatus(ps.Conditions, string(gatewayv1.RouteConditionAccepted)), reason, s.served(route.Namespace+"/"+route.Name))
+		}
+	}
+	return w.Flush()
+}
+
+// explainRoute implements "kubectl gari why ROUTE".
+func explainRoute(ctx context.Context, s *session, args []string) error {
+	var route gatewayv1.HTTPRoute
+	if err := s.Get(ctx, client.ObjectKey{Namespace: s.opts.namespace, Name: args[0]}, &route); err != nil {
+		return err
+	}
+	key := route.Namespace + "/" + route.Name
+	fmt.Fprintf(s.out, "HTTPRoute %s, hostnames %s\n", key, hostnames(route.Spec.Hostnames))
+
+	parents := s.parentStatuses(&route)
+	if len(parents) == 0 {
+		fmt.Fprintf(s.out, "No parent managed by %s has reported on the route: its parentRefs name no Gateway of our GatewayClasses, "+
+			"or the controller has not reconciled it yet.\n", s.opts.controllerName)
+	}
+	accepted := false
+	for _, ps := range parents {
+		fmt.Fprintf(s.out, "Parent %s:\n", ps.ParentRef.Name)
+		printConditions(s.out, "  ", ps.Conditions)
+		accepted = accepted || meta.IsStatusConditionTrue(ps.Conditions, string(gatewayv1.RouteConditionAccepted))
+	}
+
+	switch live, ok := s.live[key]; {
+	case s.live == nil:
+		fmt.Fprintln(s.out, "The route table served by the proxy is unknown; set --admin-url to compare with it.")
+	case !ok && accepted:
+		fmt.Fprintln(s.out, "The route is accepted but not served yet: the proxy has not received the change, "+
+			"or its Gateways belong to another shard.")
+	case !ok:
+		fmt.Fprintln(s.out, "The route is not served, since no parent accepts it.")
+	default:
+		fmt.Fprintf(s.out, "Served for hostnames %s:\n", orNone(live.Hostnames))
+		for _, rule := range live.Rules {
+			var backends []string
+			for _, backend := range rule.Backends {
+				name := backend.Backend
+				if backend.Invalid {
+					name = "invalid backend, answered with 500"
+				}
+				backends = append(backends, fmt.Sprintf("%s (weight %d)", name, backend.Weight))
+			}
+			matches := rule.Matches
+			if len(matches) == 0 {
+				matches = []string{"every request"}
+			}
+			fmt.Fprintf(s.out, "  rule %s: %s -> %s\n", rule.Name, strings.Join(matches, "; "), orNone(backends))
+		}
+		// Routes sharing a hostname compete for its requests: the most
+		// specific match wins, then the oldest route.
+		for _, otherKey := range slices.Sorted(maps.Keys(s.live)) {
+			other := s.live[otherKey]
+			if otherKey == key {
+				continue
+			}
+			if shared := sharedHostnames(live.Hostnames, other.Hostnames); len(shared) > 0 {
+				fmt.Fprintf(s.out, "  shares %s with HTTPRoute %s/%s: the most specific match wins, then the oldest route\n",
+					strings.Join(shared, ", "), other.Namespace, other.Name)
+			}
+		}
+	}
+	if route.Annotations[controller.DebugAnnotation] != "true" {
+		fmt.Fprintf(s.out, "For the details behind each condition, annotate the route with %s=true.\n", controller.DebugAnnotation)
+	}
+	return nil
+}
+
+// parentStatuses returns the entries of the route's status written by our
+// controller.
+func (s *session) parentStatuses(route *gatewayv1.HTTPRoute) []gatewayv1.RouteParentStatus {
+	var statuses []gatewayv1.RouteParentStatus
+	for _, ps := range route.Status.Parents {
+		if string(ps.ControllerName) == s.opts.controllerName {
+			statuses = append(statuses, ps)
+		}
+	}
+	return statuses
+}
+
+// referencesGateway reports whether parentRef, of a route in namespace, is gw.
+func referencesGateway(namespace string, parentRef gatewayv1.ParentReference, gw *gatewayv1.Gateway) bool {
+	if parentRef.Kind != nil && *parentRef.Kind != "Gateway" {
+		return false
+	}
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	return namespace == gw.Namespace && string(parentRef.Name) == gw.Name
+}
+
+// sharedHostnames returns the hostnames of a that are also hostnames of b,
+// where an empty list stands for every hostname.
+func sharedHostnames(a, b []string) []string {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return []string{"*"}
+	case len(a) == 0:
+		return b
+	case len(b) == 0:
+		return a
+	}
+	var shared []string
+	for _, hostname := range a {
+		if slices.Contains(b, hostname) {
+			shared = append(shared, hostname)
+		}
+	}
+	return shared
+}
+
+// conditionStatus returns the status of the condition of the given type, or
+// "-" if there is none.
+func conditionStatus(conditions []metav1.Condition, conditionType string) string {
+	if c := meta.FindStatusCondition(conditions, conditionType); c != nil {
+		return string(c.Status)
+	}
+	return "-"
+}
+
+func printConditions(w io.Writer, indent string, conditions []metav1.Condition) {
+	for _, c := range conditions {
+		fmt.Fprintf(w, "%s%s=%s (%s): %s\n", indent, c.Type, c.Status, c.Reason, c.Message)
+	}
+}
+
+func hostnames(hostnames []gatewayv1.Hostname) string {
+	if len(hostnames) == 0 {
+		return "*"
+	}
+	var names []string
+	for _, hostname := range hostnames {
+		names = append(names, string(hostname))
+	}
+	return strings.Join(names, ",")
+}
+
+func listenerHostname(hostname *gatewayv1.Hostname) string {
+	if hostname == nil || *hostname == "" {
+		return "*"
+	}
+	return string(*hostname)
+}
+
+func orNone(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
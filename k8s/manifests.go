@@ -0,0 +1,129 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package k8s embeds the manifests installing the controller, so that the
+// binary can install itself.
+package k8s
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"io"
+	"io/fs"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// DefaultNamespace is the namespace the manifests install the controller in.
+const DefaultNamespace = "default"
+
+//go:embed controller.yaml crds/*.yaml
+var manifests embed.FS
+
+// Manifests returns the objects installing the controller in namespace, with
+// image as the image of the controller if it is not empty: the CRDs of our
+// APIs first, then the namespace if it is not the default one, then the
+// controller and its RBAC.
+func Manifests(namespace, image string) ([]*unstructured.Unstructured, error) {
+	crds, err := fs.Glob(manifests, "crds/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	var objects []*unstructured.Unstructured
+	for _, file := range crds {
+		decoded, err := decode(file)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, decoded...)
+	}
+	if namespace != DefaultNamespace {
+		ns := &unstructured.Unstructured{}
+		ns.SetAPIVersion("v1")
+		ns.SetKind("Namespace")
+		ns.SetName(namespace)
+		objects = append(objects, ns)
+	}
+	controller, err := decode("controller.yaml")
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range controller {
+		if obj.GetNamespace() != "" {
+			obj.SetNamespace(namespace)
+		}
+		if err := override(obj, namespace, image); err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// override points the subjects of obj to namespace, and sets the image of
+// the controller if obj is its Deployment.
+func override(obj *unstructured.Unstructured, namespace, image string) error {
+	switch obj.GetKind() {
+	case "ClusterRoleBinding", "RoleBinding":
+		subjects, _, err := unstructured.NestedSlice(obj.Object, "subjects")
+		if err != nil {
+			return err
+		}
+		for _, subject := range subjects {
+			if s, ok := subject.(map[string]any); ok && s["kind"] == "ServiceAccount" {
+				s["namespace"] = namespace
+			}
+		}
+		return unstructured.SetNestedSlice(obj.Object, subjects, "subjects")
+	case "Deployment":
+		if image == "" {
+			return nil
+		}
+		containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		if err != nil {
+			return err
+		}
+		for _, container := range containers {
+			if c, ok := container.(map[string]any); ok && c["name"] == "controller" {
+				c["image"] = image
+			}
+		}
+		return unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+	}
+	return nil
+}
+
+// decode returns the objects of the embedded manifest file.
+func decode(file string) ([]*unstructured.Unstructured, error) {
+	data, err := manifests.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var objects []*unstructured.Unstructured
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, err
+		}
+		if len(u.Object) != 0 {
+			objects = append(objects, u)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestManifests(t *testing.T) {
+	objects, err := Manifests("gari-system", "example.com/gari:v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objects) == 0 || objects[0].GetKind() != "CustomResourceDefinition" {
+		t.Fatalf("expected the CRDs to be applied first, got %v", objects)
+	}
+
+	var namespace, image string
+	var leases bool
+	for _, obj := range objects {
+		switch obj.GetKind() {
+		case "Namespace":
+			namespace = obj.GetName()
+		case "ServiceAccount", "Service":
+			if obj.GetNamespace() != "gari-system" {
+				t.Errorf("expected %s %s to be in gari-system, got %q", obj.GetKind(), obj.GetName(), obj.GetNamespace())
+			}
+		case "Deployment":
+			if obj.GetNamespace() != "gari-system" {
+				t.Errorf("expected the Deployment to be in gari-system, got %q", obj.GetNamespace())
+			}
+			containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+			image, _, _ = unstructured.NestedString(containers[0].(map[string]any), "image")
+		case "ClusterRole":
+			rules, _, _ := unstructured.NestedSlice(obj.Object, "rules")
+			for _, rule := range rules {
+				groups, _, _ := unstructured.NestedStringSlice(rule.(map[string]any), "apiGroups")
+				resources, _, _ := unstructured.NestedStringSlice(rule.(map[string]any), "resources")
+				verbs, _, _ := unstructured.NestedStringSlice(rule.(map[string]any), "verbs")
+				if slices.Contains(groups, "coordination.k8s.io") && slices.Contains(resources, "leases") && slices.Contains(verbs, "update") {
+					leases = true
+				}
+			}
+		case "ClusterRoleBinding":
+			if subjects, _, _ := unstructured.NestedSlice(obj.Object, "subjects"); subjects[0].(map[string]any)["namespace"] != "gari-system" {
+				t.Errorf("expected the ClusterRoleBinding to bind the ServiceAccount in gari-system, got %v", subjects)
+			}
+		}
+	}
+	if !leases {
+		t.Errorf("expected the ClusterRole to allow leader election")
+	}
+	if namespace != "gari-system" {
+		t.Errorf("expected the gari-system Namespace to be created, got %q", namespace)
+	}
+	if image != "example.com/gari:v1" {
+		t.Errorf("expected the controller image to be overridden, got %q", image)
+	}
+
+	objects, err = Manifests(DefaultNamespace, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, obj := range objects {
+		if obj.GetKind() == "Namespace" {
+			t.Errorf("expected the default namespace not to be created")
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin serves the debug endpoints of the controller, which expose the
+// configuration programmed into the proxy. Because that configuration reveals
+// the cluster topology, every request must be authenticated with a bearer
+// token or a client certificate, but for gRPC health checks.
+package admin
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/nginx"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Options configures authentication for the admin endpoints. At least one
+// method must be enabled.
+type Options struct {
+	// Token, if set, authenticates requests carrying it as a bearer token.
+	Token string
+	// ClientCertificates authenticates requests that present a client
+	// certificate verified by the TLS server. The server must be configured
+	// to verify client certificates against a trusted CA.
+	ClientCertificates bool
+	// Verbosity, if set, is the log verbosity read and changed through
+	// /log_level.
+	Verbosity flag.Value
+}
+
+// NewHandler returns the handler for the admin endpoints:
+//
+//	/config_dump        the routes programmed into the proxy, as JSON
+//	/config_dump/nginx  the same routes as an nginx.conf, with the values of
+//	                    the headers the proxy redacts hidden
+//	/healthz            the number of routes served
+//	/log_level          the log verbosity, changed with PUT /log_level?v=N
+func NewHandler(p *proxy.Proxy, opts Options) (http.Handler, error) {
+	if opts.Token == "" && !opts.ClientCertificates {
+		return nil, errors.New("admin endpoints require a bearer token or client certificates")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/config_dump", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, configDump(p.Routes()))
+	})
+	mux.HandleFunc("/config_dump/nginx", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if _, err := io.WriteString(w, nginx.Render(p.Routes(), p.Redactor())); err != nil {
+			log.Log.Error(err, "unable to write admin response")
+		}
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"status": "ok", "routes": len(p.Routes())})
+	})
+	if opts.Verbosity != nil {
+		mux.HandleFunc("/log_level", func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+			case http.MethodPut, http.MethodPost:
+				v := r.URL.Query().Get("v")
+				if err := opts.Verbosity.Set(v); err != nil {
+					http.Error(w, fmt.Sprintf("invalid verbosity %q: %v", v, err), http.StatusBadRequest)
+					return
+				}
+				log.Log.Info("log verbosity changed", "verbosity", v)
+			default:
+				w.Header().Set("Allow", "GET, PUT, POST")
+				http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			writeJSON(w, map[string]string{"verbosity": opts.Verbosity.String()})
+		})
+	}
+	return authenticate(mux, opts), nil
+}
+
+// authenticate rejects requests that carry neither a valid bearer token nor a
+// verified client certificate.
+func authenticate(next http.Handler, opts Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if opts.ClientCertificates && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if opts.Token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if ok && subtle.ConstantTimeCompare([]byte(token), []byte(opts.Token)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// NewServer returns the server for the admin endpoints, authenticating with
+// the bearer token in tokenFile and the client certificates signed by the CAs
+// in clientCAFile. It is served over TLS when a certificate is given. The log
+// verbosity is changed through verbosity if it is not nil. If ready is not
+// nil, the server also serves grpc.health.v1.Health, over HTTP/2 with or
+// without TLS, reporting the data plane as serving while ready succeeds.
+func NewServer(p *proxy.Proxy, addr, tokenFile, certFile, keyFile, clientCAFile string, verbosity flag.Value, ready func(*http.Request) error) (*http.Server, error) {
+	opts := Options{Verbosity: verbosity}
+	if tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.Token = strings.TrimSpace(string(token))
+	}
+
+	server := &http.Server{Addr: addr}
+	if certFile != "" {
+		server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if clientCAFile != "" {
+		if server.TLSConfig == nil {
+			return nil, errors.New("--admin-client-ca-file requires --admin-tls-cert-file")
+		}
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in --admin-client-ca-file")
+		}
+		// Clients without a certificate may still authenticate with the
+		// bearer token.
+		server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		server.TLSConfig.ClientCAs = pool
+		opts.ClientCertificates = true
+	}
+
+	handler, err := NewHandler(p, opts)
+	if err != nil {
+		return nil, err
+	}
+	server.Handler = handler
+	if ready != nil {
+		server.Handler = withHealthService(handler, ready)
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetHTTP2(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+		if server.TLSConfig != nil {
+			// Listeners wrapped in TLS outside of the server negotiate
+			// HTTP/2 from the config.
+			server.TLSConfig.NextProtos = []string{"h2", "http/1.1"}
+		}
+	}
+	return server, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Log.Error(err, "unable to write admin response")
+	}
+}
+
+// RouteDump is the JSON representation of a proxy route served by
+// /config_dump. Secrets such as basic auth users are never included.
+type RouteDump struct {
+	Namespace       string            `json:"namespace"`
+	Name            string            `json:"name"`
+	Hostnames       []string          `json:"hostnames,omitempty"`
+	Rules           []RuleDump        `json:"rules,omitempty"`
+	BasicAuth       bool              `json:"basicAuth,omitempty"`
+	SecurityHeaders map[string]string `json:"securityHeaders,omitempty"`
+	Transform       bool              `json:"transform,omitempty"`
+	Telemetry       *proxy.Telemetry  `json:"telemetry,omitempty"`
+}
+
+// RuleDump is the JSON representation of a rule of a proxy route.
+type RuleDump struct {
+	Name     string        `json:"name,omitempty"`
+	Matches  []string      `json:"matches,omitempty"`
+	Backends []BackendDump `json:"backends"`
+	Hooks    int           `json:"hooks,omitempty"`
+}
+
+// BackendDump is the JSON representation of a backend of a rule, as host:port.
+type BackendDump struct {
+	Backend string `json:"backend"`
+	Weight  int32  `json:"weight"`
+	// Invalid is set for a backendRef that could not be resolved, whose
+	// share of the requests is answered with 500.
+	Invalid bool `json:"invalid,omitempty"`
+}
+
+func configDump(routes []proxy.HTTPRoute) []RouteDump {
+	dump := []RouteDump{}
+	for _, route := range routes {
+		rd := RouteDump{
+			Namespace:       route.Namespace,
+			Name:            route.Name,
+			Hostnames:       route.Hostnames,
+			BasicAuth:       route.BasicAuth != nil,
+			SecurityHeaders: route.SecurityHeaders,
+			Transform:       route.Transform != nil,
+			Telemetry:       route.Telemetry,
+		}
+		for _, rule := range route.Rules {
+			r := RuleDump{Name: rule.Name, Hooks: len(rule.Hooks)}
+			for _, backend := range rule.Backends {
+				dump := BackendDump{Weight: backend.Weight, Invalid: backend.Invalid}
+				if !backend.Invalid {
+					dump.Backend = fmt.Sprintf("%s:%d", backend.Host, backend.Port)
+				}
+				r.Backends = append(r.Backends, dump)
+			}
+			for _, match := range rule.Matches {
+				r.Matches = append(r.Matches, describeMatch(match))
+			}
+			rd.Rules = append(rd.Rules, r)
+		}
+		dump = append(dump, rd)
+	}
+	return dump
+}
+
+// describeMatch returns a short description of a match, such as
+// "PathPrefix /api, header X-Env Exact". Header values are omitted since they
+// may be sensitive.
+func describeMatch(match proxy.RouteMatch) string {
+	var parts []string
+	if match.Path != nil {
+		parts = append(parts, fmt.Sprintf("%s %s", match.Path.Type, match.Path.Value))
+	}
+	for _, header := range match.Headers {
+		parts = append(parts, fmt.Sprintf("header %s %s", header.Name, header.Type))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"k8s.io/klog/v2/textlogger"
+)
+
+func TestNewHandlerRequiresAuthentication(t *testing.T) {
+	if _, err := NewHandler(proxy.NewProxy(proxy.Options{}), Options{}); err == nil {
+		t.Errorf("expected an error without any authentication method")
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	p := proxy.NewProxy(proxy.Options{})
+	handler, err := NewHandler(p, Options{Token: "secret", ClientCertificates: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		tls           *tls.ConnectionState
+		expected      int
+	}{
+		{
+			name:     "no credentials",
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name:          "wrong token",
+			authorization: "Bearer wrong",
+			expected:      http.StatusUnauthorized,
+		},
+		{
+			name:          "valid token",
+			authorization: "Bearer secret",
+			expected:      http.StatusOK,
+		},
+		{
+			name:     "unverified client certificate",
+			tls:      &tls.ConnectionState{},
+			expected: http.StatusUnauthorized,
+		},
+		{
+			name:     "verified client certificate",
+			tls:      &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}},
+			expected: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/healthz", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			r.TLS = tt.tls
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, w.Code)
+			}
+		})
+	}
+}
+
+func TestConfigDump(t *testing.T) {
+	p := proxy.NewProxy(proxy.Options{})
+	p.UpdateRoutes([]proxy.HTTPRoute{
+		{
+			Namespace: "default",
+			Name:      "web",
+			Hostnames: []string{"example.com"},
+			Rules: []proxy.RouteRule{
+				{
+					Matches: []proxy.RouteMatch{
+						{
+							Path:    &proxy.PathMatch{Type: proxy.PathMatchTypePathPrefix, Value: "/api"},
+							Headers: []proxy.HeaderMatch{{Type: "Exact", Name: "X-Env", MatchExactValue: "prod"}},
+						},
+					},
+					Backends: []proxy.WeightedBackend{
+						{Backend: proxy.Backend{Host: "api.default.svc.cluster.local", Port: 8080}, Weight: 1},
+					},
+				},
+			},
+			BasicAuth: &proxy.BasicAuth{Realm: "web"},
+		},
+	})
+	handler, err := NewHandler(p, Options{Token: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/config_dump", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	var actual []RouteDump
+	if err := json.Unmarshal(w.Body.Bytes(), &actual); err != nil {
+		t.Fatalf("invalid response %q: %v", w.Body.String(), err)
+	}
+	expected := []RouteDump{
+		{
+			Namespace: "default",
+			Name:      "web",
+			Hostnames: []string{"example.com"},
+			Rules: []RuleDump{
+				{
+					Matches:  []string{"PathPrefix /api, header X-Env Exact"},
+					Backends: []BackendDump{{Backend: "api.default.svc.cluster.local:8080", Weight: 1}},
+				},
+			},
+			BasicAuth: true,
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestNginxConfigDump(t *testing.T) {
+	p := proxy.NewProxy(proxy.Options{})
+	p.UpdateRoutes([]proxy.HTTPRoute{{
+		Namespace: "default",
+		Name:      "web",
+		Hostnames: []string{"example.com"},
+		Rules: []proxy.RouteRule{{
+			Name:     "0",
+			Backends: []proxy.WeightedBackend{{Backend: proxy.Backend{Host: "web.default.svc", Port: 80}, Weight: 1}},
+		}},
+	}})
+	handler, err := NewHandler(p, Options{Token: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/config_dump/nginx", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected %v, got %v", http.StatusOK, w.Code)
+	}
+	for _, expected := range []string{"server_name example.com;", "server web.default.svc:80 weight=1;"} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("expected the configuration to contain %q, got:\n%s", expected, w.Body.String())
+		}
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	logConfig := textlogger.NewConfig(textlogger.Verbosity(2))
+	handler, err := NewHandler(proxy.NewProxy(proxy.Options{}), Options{Token: "secret", Verbosity: logConfig.Verbosity()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	request := func(method, target string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, nil)
+		r.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := request("GET", "/log_level"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"verbosity":"2"`) {
+		t.Errorf("expected the current verbosity, got %v: %s", w.Code, w.Body.String())
+	}
+	if w := request("PUT", "/log_level?v=4"); w.Code != http.StatusOK || logConfig.Verbosity().String() != "4" {
+		t.Errorf("expected the verbosity to be changed, got %v: %s", w.Code, w.Body.String())
+	}
+	if w := request("PUT", "/log_level?v=loud"); w.Code != http.StatusBadRequest || logConfig.Verbosity().String() != "4" {
+		t.Errorf("expected an invalid verbosity to be rejected, got %v: %s", w.Code, w.Body.String())
+	}
+	if w := request("DELETE", "/log_level"); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected %v, got %v", http.StatusMethodNotAllowed, w.Code)
+	}
+
+	handler, err = NewHandler(proxy.NewProxy(proxy.Options{}), Options{Token: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w := request("GET", "/log_level"); w.Code != http.StatusNotFound {
+		t.Errorf("expected no log level endpoint without a verbosity, got %v", w.Code)
+	}
+}
+
+func TestClientConfigDump(t *testing.T) {
+	p := proxy.NewProxy(proxy.Options{})
+	p.UpdateRoutes([]proxy.HTTPRoute{{Namespace: "default", Name: "web", Hostnames: []string{"example.com"}}})
+	handler, err := NewHandler(p, Options{Token: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	c := &Client{URL: server.URL + "/", Token: "secret"}
+	routes, err := c.ConfigDump(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(routes) != 1 || routes[0].Name != "web" {
+		t.Errorf("expected the route served, got %+v", routes)
+	}
+
+	c.Token = "wrong"
+	if _, err := c.ConfigDump(t.Context()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected the request to be rejected, got %v", err)
+	}
+}
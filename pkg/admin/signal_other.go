@@ -0,0 +1,32 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !unix
+
+package admin
+
+import (
+	"context"
+	"flag"
+)
+
+// DebugVerbosity is the log verbosity switched to by SIGUSR1, which is only
+// handled on Unix.
+const DebugVerbosity = "4"
+
+// ToggleVerbosityOnSignal returns when ctx is done: there is no SIGUSR1 to
+// handle on this platform.
+func ToggleVerbosityOnSignal(ctx context.Context, verbosity flag.Value) {
+	<-ctx.Done()
+}
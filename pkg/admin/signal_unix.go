@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package admin
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DebugVerbosity is the log verbosity switched to by SIGUSR1, at which the
+// proxy logs the headers of every request, redacted.
+const DebugVerbosity = "4"
+
+// ToggleVerbosityOnSignal switches verbosity to DebugVerbosity when the
+// process receives SIGUSR1, and back to the verbosity it had on the next
+// one, until ctx is done.
+func ToggleVerbosityOnSignal(ctx context.Context, verbosity flag.Value) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	defer signal.Stop(signals)
+
+	var previous string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+		}
+		next := DebugVerbosity
+		if previous != "" {
+			next, previous = previous, ""
+		} else {
+			previous = verbosity.String()
+		}
+		if err := verbosity.Set(next); err != nil {
+			log.Log.Error(err, "unable to change log verbosity")
+			continue
+		}
+		log.Log.Info("log verbosity changed on SIGUSR1", "verbosity", next)
+	}
+}
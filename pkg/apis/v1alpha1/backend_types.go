@@ -0,0 +1,75 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackendSpec defines a static destination outside the cluster's Services,
+// such as a VM or an external endpoint.
+type BackendSpec struct {
+	// Host is the hostname or IP address of the destination.
+	//
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Host string `json:"host"`
+
+	// Port is the port of the destination.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// TLS, if set, makes the proxy connect to the destination over TLS.
+	//
+	// +optional
+	TLS *BackendTLS `json:"tls,omitempty"`
+}
+
+// BackendTLS configures the TLS connection to a Backend. The certificate of
+// the destination is verified against the system roots.
+type BackendTLS struct {
+	// ServerName is sent with SNI and checked against the certificate of the
+	// destination. Defaults to the Backend's host.
+	//
+	// +optional
+	ServerName *string `json:"serverName,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=gateway-api
+
+// Backend is a static destination that HTTPRoute backendRefs can reference
+// with group gari.gke-labs.dev and kind Backend.
+type Backend struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BackendSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BackendList contains a list of Backend.
+type BackendList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Backend `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Backend{}, &BackendList{})
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// BasicAuthPolicySecretKey is the key in the referenced Secret that holds the
+// htpasswd-formatted list of users.
+const BasicAuthPolicySecretKey = "htpasswd"
+
+// BasicAuthPolicySpec defines the desired state of BasicAuthPolicy.
+type BasicAuthPolicySpec struct {
+	// TargetRefs identifies the HTTPRoutes this policy applies to.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	TargetRefs []gatewayv1.LocalPolicyTargetReferenceWithSectionName `json:"targetRefs"`
+
+	// SecretRef references a Secret in the same namespace as the policy. The
+	// Secret must contain an "htpasswd" key with one "user:hash" entry per
+	// line. Hashes are bcrypt, as produced by "htpasswd -B", or, for
+	// compatibility only, unsalted SHA-1 as produced by "htpasswd -s", which
+	// is too weak to protect anything of value.
+	SecretRef gatewayv1.SecretObjectReference `json:"secretRef"`
+
+	// Realm is the protection space advertised in the WWW-Authenticate header
+	// of 401 responses. Defaults to "gateway".
+	//
+	// +optional
+	Realm *string `json:"realm,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=gateway-api
+
+// BasicAuthPolicy requires HTTP Basic authentication on the targeted routes,
+// checking credentials against a Secret of username/password hashes.
+type BasicAuthPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BasicAuthPolicySpec    `json:"spec,omitempty"`
+	Status gatewayv1.PolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BasicAuthPolicyList contains a list of BasicAuthPolicy.
+type BasicAuthPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BasicAuthPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BasicAuthPolicy{}, &BasicAuthPolicyList{})
+}
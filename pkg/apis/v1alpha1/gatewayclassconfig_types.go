@@ -0,0 +1,183 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// LogLevel is the verbosity of the data plane logs.
+//
+// +kubebuilder:validation:Enum=error;info;debug
+type LogLevel string
+
+const (
+	LogLevelError LogLevel = "error"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelDebug LogLevel = "debug"
+)
+
+// LoadBalancingAlgorithm selects how requests are spread across the endpoints
+// of a backend.
+//
+// +kubebuilder:validation:Enum=RoundRobin;LeastRequest;Random
+type LoadBalancingAlgorithm string
+
+const (
+	LoadBalancingRoundRobin   LoadBalancingAlgorithm = "RoundRobin"
+	LoadBalancingLeastRequest LoadBalancingAlgorithm = "LeastRequest"
+	LoadBalancingRandom       LoadBalancingAlgorithm = "Random"
+)
+
+// DataPlaneMode selects how the data plane of a GatewayClass is deployed.
+//
+// +kubebuilder:validation:Enum=Shared;DaemonSet
+type DataPlaneMode string
+
+const (
+	// DataPlaneModeShared serves the Gateways of the class from the proxy
+	// shared by every class, behind a Service.
+	DataPlaneModeShared DataPlaneMode = "Shared"
+	// DataPlaneModeDaemonSet runs a proxy on every node, listening on the
+	// ports of the listeners of the class's Gateways, which are exposed at
+	// the addresses of the nodes instead of a load balancer.
+	DataPlaneModeDaemonSet DataPlaneMode = "DaemonSet"
+)
+
+// DataPlaneExposure selects how the proxies provisioned for a GatewayClass
+// in DaemonSet mode take the listener ports on their nodes.
+//
+// +kubebuilder:validation:Enum=HostPort;HostNetwork
+type DataPlaneExposure string
+
+const (
+	// DataPlaneExposureHostPort forwards each listener port of the node to
+	// the proxy's own network namespace.
+	DataPlaneExposureHostPort DataPlaneExposure = "HostPort"
+	// DataPlaneExposureHostNetwork runs the proxies in the network namespace
+	// of their nodes, listening on each listener port directly. The ports of
+	// the proxies' probe (8081) and metrics (8080) endpoints must then be
+	// left free of listeners.
+	DataPlaneExposureHostNetwork DataPlaneExposure = "HostNetwork"
+)
+
+// GatewayClassConfigSpec defines the implementation configuration of the
+// GatewayClasses that reference it. Unset fields keep the defaults.
+type GatewayClassConfigSpec struct {
+	// LogLevel is the verbosity of the data plane logs.
+	//
+	// +optional
+	LogLevel *LogLevel `json:"logLevel,omitempty"`
+
+	// RequestTimeout is the default timeout for requests on routes attached
+	// to Gateways of the class. Routes may override it with their own
+	// request timeouts.
+	//
+	// +optional
+	RequestTimeout *gatewayv1.Duration `json:"requestTimeout,omitempty"`
+
+	// LoadBalancing selects how requests are spread across backend endpoints.
+	//
+	// +optional
+	LoadBalancing *LoadBalancingAlgorithm `json:"loadBalancing,omitempty"`
+
+	// DataPlaneImage is the container image of the data plane.
+	//
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^\S+$`
+	DataPlaneImage *string `json:"dataPlaneImage,omitempty"`
+
+	// DataPlaneMode selects how the data plane is deployed. Defaults to
+	// Shared.
+	//
+	// +optional
+	DataPlaneMode *DataPlaneMode `json:"dataPlaneMode,omitempty"`
+
+	// DataPlaneExposure selects how the proxies take the listener ports on
+	// their nodes. Requires dataPlaneMode DaemonSet. Defaults to HostPort.
+	//
+	// +optional
+	DataPlaneExposure *DataPlaneExposure `json:"dataPlaneExposure,omitempty"`
+
+	// ServiceType selects how the Gateways of the class are exposed, and so
+	// the addresses published on them: the load balancer addresses of a
+	// LoadBalancer Service, the addresses of the nodes for a NodePort
+	// Service, or the cluster IP of a ClusterIP Service for Gateways only
+	// reachable from within the cluster. It is the type of the Services
+	// provisioned for Gateways without a serviceType of their own. Defaults
+	// to the type of the Service exposing the Gateway.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType *corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// Limits bounds the routes accepted by the Gateways of the class.
+	//
+	// +optional
+	Limits *ResourceLimits `json:"limits,omitempty"`
+}
+
+// ResourceLimits bounds the configuration the data plane serves for the
+// Gateways of a class. A route exceeding a limit is not accepted by the
+// Gateway, with a condition naming the limit. Unset fields set no limit.
+type ResourceLimits struct {
+	// MaxRoutesPerGateway is the maximum number of HTTPRoutes attached to
+	// each Gateway. The oldest routes are accepted first.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxRoutesPerGateway *int32 `json:"maxRoutesPerGateway,omitempty"`
+
+	// MaxRulesPerRoute is the maximum number of rules in each HTTPRoute.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxRulesPerRoute *int32 `json:"maxRulesPerRoute,omitempty"`
+
+	// MaxRegexMatchers is the maximum number of regular expression path,
+	// header and query parameter matches in each HTTPRoute.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRegexMatchers *int32 `json:"maxRegexMatchers,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories=gateway-api
+
+// GatewayClassConfig holds the parameters of a GatewayClass, referenced by its
+// spec.parametersRef. Unlike a ConfigMap, it is validated by the API server.
+type GatewayClassConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewayClassConfigSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GatewayClassConfigList contains a list of GatewayClassConfig.
+type GatewayClassConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayClassConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GatewayClassConfig{}, &GatewayClassConfigList{})
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GatewayConfigSpec defines the infrastructure of the Gateways that reference
+// it. Unset fields keep the defaults.
+type GatewayConfigSpec struct {
+	// Replicas is the number of data plane replicas serving the Gateway.
+	//
+	// Not supported yet, since Gateways share their proxies: a Gateway whose
+	// parameters set it is not accepted.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources are the compute resources of each data plane replica.
+	//
+	// Not supported yet, since Gateways share their proxies: a Gateway whose
+	// parameters set it is not accepted.
+	//
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector constrains the nodes the data plane replicas run on.
+	//
+	// Not supported yet, since Gateways share their proxies: a Gateway whose
+	// parameters set it is not accepted.
+	//
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// ServiceType is the type of the Service exposing the Gateway.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType *corev1.ServiceType `json:"serviceType,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=gateway-api
+
+// GatewayConfig holds the infrastructure parameters of a Gateway, referenced
+// by its spec.infrastructure.parametersRef.
+type GatewayConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewayConfigSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GatewayConfigList contains a list of GatewayConfig.
+type GatewayConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GatewayConfig{}, &GatewayConfigList{})
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Default values for the headers managed by SecurityHeadersPolicy.
+const (
+	DefaultStrictTransportSecurity = "max-age=31536000; includeSubDomains"
+	DefaultContentTypeOptions      = "nosniff"
+	DefaultFrameOptions            = "DENY"
+	DefaultReferrerPolicy          = "strict-origin-when-cross-origin"
+)
+
+// SecurityHeadersPolicySpec defines the desired state of SecurityHeadersPolicy.
+//
+// Each header is set to its default value unless overridden. Setting a field
+// to the empty string stops the header from being added.
+type SecurityHeadersPolicySpec struct {
+	// TargetRefs identifies the HTTPRoutes or Gateways this policy applies to.
+	// A policy targeting an HTTPRoute takes precedence over one targeting a
+	// Gateway the route is attached to.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	TargetRefs []gatewayv1.LocalPolicyTargetReferenceWithSectionName `json:"targetRefs"`
+
+	// StrictTransportSecurity is the value of the Strict-Transport-Security
+	// header. Defaults to "max-age=31536000; includeSubDomains".
+	//
+	// +optional
+	StrictTransportSecurity *string `json:"strictTransportSecurity,omitempty"`
+
+	// ContentTypeOptions is the value of the X-Content-Type-Options header.
+	// Defaults to "nosniff".
+	//
+	// +optional
+	ContentTypeOptions *string `json:"contentTypeOptions,omitempty"`
+
+	// FrameOptions is the value of the X-Frame-Options header. Defaults to
+	// "DENY".
+	//
+	// +optional
+	FrameOptions *string `json:"frameOptions,omitempty"`
+
+	// ReferrerPolicy is the value of the Referrer-Policy header. Defaults to
+	// "strict-origin-when-cross-origin".
+	//
+	// +optional
+	ReferrerPolicy *string `json:"referrerPolicy,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=gateway-api
+
+// SecurityHeadersPolicy adds standard security headers to the responses
+// served for the targeted routes or Gateways.
+type SecurityHeadersPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecurityHeadersPolicySpec `json:"spec,omitempty"`
+	Status gatewayv1.PolicyStatus    `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecurityHeadersPolicyList contains a list of SecurityHeadersPolicy.
+type SecurityHeadersPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecurityHeadersPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SecurityHeadersPolicy{}, &SecurityHeadersPolicyList{})
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// MetricsDetail controls how much detail the proxy records in its metrics.
+//
+// +kubebuilder:validation:Enum=Full;Basic;None
+type MetricsDetail string
+
+const (
+	// MetricsDetailFull records metrics labelled with the matched route.
+	MetricsDetailFull MetricsDetail = "Full"
+	// MetricsDetailBasic records metrics without route labels, so that
+	// high-volume routes do not add series of their own.
+	MetricsDetailBasic MetricsDetail = "Basic"
+	// MetricsDetailNone does not record metrics.
+	MetricsDetailNone MetricsDetail = "None"
+)
+
+// TraceSampler decides which requests the proxy marks as sampled in their
+// trace context.
+//
+// +kubebuilder:validation:Enum=Always;Ratio;ParentBased
+type TraceSampler string
+
+const (
+	// TraceSamplerAlways samples every request, including those whose caller
+	// did not sample its trace.
+	TraceSamplerAlways TraceSampler = "Always"
+	// TraceSamplerRatio samples TraceSamplingPercent of the traces, deciding
+	// from their trace ID whatever their caller decided.
+	TraceSamplerRatio TraceSampler = "Ratio"
+	// TraceSamplerParentBased keeps the sampling decision of callers, and
+	// samples TraceSamplingPercent of the traces started by the proxy.
+	TraceSamplerParentBased TraceSampler = "ParentBased"
+)
+
+// TelemetryPolicySpec defines the desired state of TelemetryPolicy. Unset
+// fields keep the proxy defaults: access logging on, every new trace sampled
+// unless the proxy is configured otherwise, and full metrics.
+type TelemetryPolicySpec struct {
+	// TargetRefs identifies the HTTPRoutes or Gateways this policy applies to.
+	// A policy targeting an HTTPRoute takes precedence over one targeting a
+	// Gateway the route is attached to.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	TargetRefs []gatewayv1.LocalPolicyTargetReferenceWithSectionName `json:"targetRefs"`
+
+	// AccessLog controls whether requests are written to the access log.
+	//
+	// +optional
+	AccessLog *bool `json:"accessLog,omitempty"`
+
+	// TraceSampler decides which requests are sampled. Defaults to the
+	// sampler the proxy is configured with, ParentBased unless changed.
+	//
+	// +optional
+	TraceSampler *TraceSampler `json:"traceSampler,omitempty"`
+
+	// TraceSamplingPercent is the percentage of traces marked as sampled by
+	// the Ratio and ParentBased samplers. With the ParentBased sampler, it
+	// only applies to traces started by the proxy: requests that already
+	// carry a trace context keep the sampling decision of their caller.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	TraceSamplingPercent *int32 `json:"traceSamplingPercent,omitempty"`
+
+	// Metrics controls the detail of the metrics recorded for requests.
+	//
+	// +optional
+	Metrics *MetricsDetail `json:"metrics,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=gateway-api
+
+// TelemetryPolicy configures access logging, trace sampling and metrics for
+// the targeted routes or Gateways.
+type TelemetryPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TelemetryPolicySpec    `json:"spec,omitempty"`
+	Status gatewayv1.PolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TelemetryPolicyList contains a list of TelemetryPolicy.
+type TelemetryPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TelemetryPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TelemetryPolicy{}, &TelemetryPolicyList{})
+}
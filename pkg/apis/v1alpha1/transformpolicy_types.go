@@ -0,0 +1,98 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// HeaderTransform sets a header to the result of a CEL expression.
+//
+// Expressions can refer to the following variables:
+//
+//   - request.method, request.host, request.path: strings
+//   - request.headers, request.query: map(string, string), with header names
+//     in lower case and only the first value of repeated entries
+//   - route.namespace, route.name: the HTTPRoute that matched the request
+//   - response.code, response.headers: only for response headers
+type HeaderTransform struct {
+	// Name is the name of the header to set.
+	Name gatewayv1.HTTPHeaderName `json:"name"`
+
+	// Value is a CEL expression that must evaluate to a string, for example
+	// `request.path.startsWith("/premium") ? "gold" : "standard"`.
+	//
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+
+	// When is an optional CEL expression that must evaluate to a bool. The
+	// header is only set when it evaluates to true.
+	//
+	// +optional
+	When *string `json:"when,omitempty"`
+}
+
+// TransformPolicySpec defines the desired state of TransformPolicy.
+type TransformPolicySpec struct {
+	// TargetRefs identifies the HTTPRoutes or Gateways this policy applies to.
+	// A policy targeting an HTTPRoute takes precedence over one targeting a
+	// Gateway the route is attached to.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	TargetRefs []gatewayv1.LocalPolicyTargetReferenceWithSectionName `json:"targetRefs"`
+
+	// RequestHeaders are set on requests before they are forwarded.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	RequestHeaders []HeaderTransform `json:"requestHeaders,omitempty"`
+
+	// ResponseHeaders are set on responses before they are returned to the
+	// client.
+	//
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	ResponseHeaders []HeaderTransform `json:"responseHeaders,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=gateway-api
+
+// TransformPolicy mutates request and response headers using CEL
+// expressions. Expressions are compiled when the policy is reconciled and a
+// policy that fails to compile is not applied.
+type TransformPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TransformPolicySpec    `json:"spec,omitempty"`
+	Status gatewayv1.PolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TransformPolicyList contains a list of TransformPolicy.
+type TransformPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TransformPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TransformPolicy{}, &TransformPolicyList{})
+}
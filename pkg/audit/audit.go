@@ -0,0 +1,193 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records configuration changes made by the controller so that
+// the history of the gateway configuration can be reconstructed.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Action describes what happened to a resource.
+type Action string
+
+const (
+	// ActionProgrammed means the resource was applied to the proxy.
+	ActionProgrammed Action = "Programmed"
+	// ActionRejected means the resource was not applied because it is invalid.
+	ActionRejected Action = "Rejected"
+	// ActionRemoved means the resource was deleted and removed from the proxy.
+	ActionRemoved Action = "Removed"
+)
+
+// Event is a structured audit record.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Action    Action    `json:"action"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid,omitempty"`
+	// Generation and ResourceVersion identify the version of the resource
+	// that triggered the event.
+	Generation      int64  `json:"generation,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// Manager is the field manager that last changed the resource, when known.
+	Manager string `json:"manager,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Sink delivers audit events.
+type Sink interface {
+	Emit(ctx context.Context, event Event) error
+}
+
+// LogSink writes each event as a line of JSON.
+type LogSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLogSink returns a LogSink writing to w.
+func NewLogSink(w io.Writer) *LogSink {
+	return &LogSink{w: w}
+}
+
+func (s *LogSink) Emit(ctx context.Context, event Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(b, '\n'))
+	return err
+}
+
+// Recorder builds audit events from Kubernetes objects and sends them to its
+// sinks. Repeated events for the same generation of an object are dropped, so
+// that reconciling an unchanged object does not flood the sinks. A nil
+// Recorder discards all events.
+type Recorder struct {
+	sinks []Sink
+
+	mu sync.Mutex
+	// last holds the last event recorded for each object, keyed by kind and
+	// name.
+	last map[string]Event
+}
+
+// NewRecorder returns a Recorder sending events to the given sinks.
+func NewRecorder(sinks ...Sink) *Recorder {
+	return &Recorder{sinks: sinks, last: map[string]Event{}}
+}
+
+// Record emits an event for obj, unless the last event for obj had the same
+// action, generation and message.
+func (r *Recorder) Record(ctx context.Context, kind string, obj client.Object, action Action, message string) {
+	if r == nil {
+		return
+	}
+	event := Event{
+		Time:            time.Now().UTC(),
+		Action:          action,
+		Kind:            kind,
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		UID:             obj.GetUID(),
+		Generation:      obj.GetGeneration(),
+		ResourceVersion: obj.GetResourceVersion(),
+		Manager:         lastManager(obj),
+		Message:         message,
+	}
+
+	k := recordKey(kind, client.ObjectKeyFromObject(obj))
+	r.mu.Lock()
+	last, ok := r.last[k]
+	if ok && last.UID == event.UID && last.Action == event.Action && last.Generation == event.Generation && last.Message == event.Message {
+		r.mu.Unlock()
+		return
+	}
+	r.last[k] = event
+	r.mu.Unlock()
+
+	r.emit(ctx, event)
+}
+
+// RecordRemoved emits a Removed event for a deleted object. Since the object
+// is gone, its identity is taken from the last event recorded for it.
+func (r *Recorder) RecordRemoved(ctx context.Context, kind string, key types.NamespacedName) {
+	if r == nil {
+		return
+	}
+	event := Event{
+		Time:      time.Now().UTC(),
+		Action:    ActionRemoved,
+		Kind:      kind,
+		Namespace: key.Namespace,
+		Name:      key.Name,
+	}
+
+	k := recordKey(kind, key)
+	r.mu.Lock()
+	if last, ok := r.last[k]; ok {
+		event.UID = last.UID
+		event.Generation = last.Generation
+		event.ResourceVersion = last.ResourceVersion
+	}
+	delete(r.last, k)
+	r.mu.Unlock()
+
+	r.emit(ctx, event)
+}
+
+// emit sends event to every sink. Delivery failures are logged and do not
+// affect the caller.
+func (r *Recorder) emit(ctx context.Context, event Event) {
+	for _, sink := range r.sinks {
+		if err := sink.Emit(ctx, event); err != nil {
+			log.FromContext(ctx).Error(err, "unable to emit audit event", "action", event.Action, "kind", event.Kind)
+		}
+	}
+}
+
+func recordKey(kind string, key types.NamespacedName) string {
+	return kind + "/" + key.String()
+}
+
+// lastManager returns the field manager of the most recent managed fields
+// entry, which identifies the client that last changed the object.
+func lastManager(obj client.Object) string {
+	var manager string
+	var latest time.Time
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Subresource != "" || entry.Time == nil {
+			continue
+		}
+		if manager == "" || entry.Time.After(latest) {
+			manager = entry.Manager
+			latest = entry.Time.Time
+		}
+	}
+	return manager
+}
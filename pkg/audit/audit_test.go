@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// recordedEvent is the subset of an Event compared by the tests.
+type recordedEvent struct {
+	Action     Action
+	Name       string
+	UID        types.UID
+	Generation int64
+	Manager    string
+}
+
+func decodeEvents(t *testing.T, out string) []recordedEvent {
+	t.Helper()
+	var events []recordedEvent
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		events = append(events, recordedEvent{Action: e.Action, Name: e.Name, UID: e.UID, Generation: e.Generation, Manager: e.Manager})
+	}
+	return events
+}
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	var out bytes.Buffer
+	r := NewRecorder(NewLogSink(&out))
+
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       "web",
+			UID:        "uid-1",
+			Generation: 1,
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl-client-side-apply", Time: &metav1.Time{Time: time.Unix(100, 0)}},
+				{Manager: "argocd", Time: &metav1.Time{Time: time.Unix(200, 0)}},
+				{Manager: "gateway-controller", Subresource: "status", Time: &metav1.Time{Time: time.Unix(300, 0)}},
+			},
+		},
+	}
+
+	r.Record(ctx, "HTTPRoute", route, ActionProgrammed, "")
+	r.Record(ctx, "HTTPRoute", route, ActionProgrammed, "")
+	route.Generation = 2
+	r.Record(ctx, "HTTPRoute", route, ActionRejected, "invalid")
+	r.RecordRemoved(ctx, "HTTPRoute", types.NamespacedName{Namespace: "default", Name: "web"})
+
+	expected := []recordedEvent{
+		{Action: ActionProgrammed, Name: "web", UID: "uid-1", Generation: 1, Manager: "argocd"},
+		{Action: ActionRejected, Name: "web", UID: "uid-1", Generation: 2, Manager: "argocd"},
+		{Action: ActionRemoved, Name: "web", UID: "uid-1", Generation: 2},
+	}
+	if actual := decodeEvents(t, out.String()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Record(context.Background(), "HTTPRoute", &gatewayv1.HTTPRoute{}, ActionProgrammed, "")
+	r.RecordRemoved(context.Background(), "HTTPRoute", types.NamespacedName{})
+}
+
+func TestWebhookSink(t *testing.T) {
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r.Header.Get("Content-Type") + " " + string(body)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sink := NewWebhookSink(server.URL)
+	go func() { _ = sink.Start(ctx) }()
+
+	if err := sink.Emit(ctx, Event{Action: ActionProgrammed, Kind: "HTTPRoute", Name: "web"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case actual := <-received:
+		if !strings.HasPrefix(actual, "application/json ") || !strings.Contains(actual, `"action":"Programmed"`) {
+			t.Errorf("expected a JSON Programmed event, got %v", actual)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the audit event")
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// webhookQueueSize bounds the number of events waiting to be delivered.
+const webhookQueueSize = 1000
+
+// WebhookSink POSTs each event as JSON to a URL. Events are delivered in the
+// background so that a slow receiver does not hold up reconciliation; when the
+// queue is full, new events are dropped and logged. WebhookSink implements
+// manager.Runnable and must be added to the manager to deliver events.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+	queue  chan Event
+}
+
+// NewWebhookSink returns a WebhookSink delivering events to url.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan Event, webhookQueueSize),
+	}
+}
+
+func (s *WebhookSink) Emit(ctx context.Context, event Event) error {
+	select {
+	case s.queue <- event:
+		return nil
+	default:
+		return errors.New("audit webhook queue is full, dropping event")
+	}
+}
+
+// Start delivers queued events until ctx is cancelled.
+func (s *WebhookSink) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("audit")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-s.queue:
+			if err := s.post(ctx, event); err != nil {
+				l.Error(err, "unable to deliver audit event", "action", event.Action, "kind", event.Kind, "namespace", event.Namespace, "name", event.Name)
+			}
+		}
+	}
+}
+
+func (s *WebhookSink) post(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
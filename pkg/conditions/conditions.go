@@ -0,0 +1,49 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conditions manages the status conditions of Gateway API and policy
+// resources, so that reconciling an unchanged object does not rewrite them.
+package conditions
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Set adds or updates the condition with the same type as condition, stamping
+// it with the object's generation. LastTransitionTime is only changed when the
+// status changes. Set reports whether the conditions changed.
+func Set(conditions *[]metav1.Condition, generation int64, condition metav1.Condition) bool {
+	condition.ObservedGeneration = generation
+	// Let meta.SetStatusCondition decide on the transition time.
+	condition.LastTransitionTime = metav1.Time{}
+	return meta.SetStatusCondition(conditions, condition)
+}
+
+// Merge returns the desired conditions, keeping the LastTransitionTime of the
+// existing conditions whose status is unchanged. Existing conditions of other
+// types are dropped. Merge reports whether the result differs from existing.
+func Merge(existing []metav1.Condition, generation int64, desired ...metav1.Condition) ([]metav1.Condition, bool) {
+	var merged []metav1.Condition
+	changed := len(existing) != len(desired)
+	for _, condition := range desired {
+		if prev := meta.FindStatusCondition(existing, condition.Type); prev != nil {
+			merged = append(merged, *prev)
+		}
+		if Set(&merged, generation, condition) {
+			changed = true
+		}
+	}
+	return merged, changed
+}
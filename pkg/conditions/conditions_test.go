@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSet(t *testing.T) {
+	transition := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	existing := []metav1.Condition{
+		{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted", ObservedGeneration: 1, LastTransitionTime: transition},
+	}
+
+	if Set(&existing, 1, metav1.Condition{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted"}) {
+		t.Errorf("expected no change for an identical condition")
+	}
+
+	if !Set(&existing, 2, metav1.Condition{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted"}) {
+		t.Errorf("expected a change for a new generation")
+	}
+	if existing[0].ObservedGeneration != 2 || !existing[0].LastTransitionTime.Equal(&transition) {
+		t.Errorf("expected generation 2 and transition %v, got %v and %v", transition, existing[0].ObservedGeneration, existing[0].LastTransitionTime)
+	}
+
+	if !Set(&existing, 2, metav1.Condition{Type: "Accepted", Status: metav1.ConditionFalse, Reason: "Invalid"}) {
+		t.Errorf("expected a change for a new status")
+	}
+	if existing[0].LastTransitionTime.Equal(&transition) {
+		t.Errorf("expected the transition time to change")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	transition := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	existing := []metav1.Condition{
+		{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted", ObservedGeneration: 1, LastTransitionTime: transition},
+		{Type: "Stale", Status: metav1.ConditionTrue, Reason: "Stale", ObservedGeneration: 1, LastTransitionTime: transition},
+	}
+
+	merged, changed := Merge(existing, 1, metav1.Condition{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted"})
+	if !changed {
+		t.Errorf("expected a change when a condition is dropped")
+	}
+	if len(merged) != 1 || !merged[0].LastTransitionTime.Equal(&transition) {
+		t.Errorf("expected the Accepted condition with transition %v, got %v", transition, merged)
+	}
+
+	if _, changed := Merge(merged, 1, metav1.Condition{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted"}); changed {
+		t.Errorf("expected no change for identical conditions")
+	}
+}
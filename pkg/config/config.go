@@ -0,0 +1,277 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config loads the options of a command from a YAML file, as an
+// alternative to passing them all as command-line flags. The file sets flags
+// by name:
+//
+//	apiVersion: gari.gke-labs.dev/v1alpha1
+//	kind: ControllerConfiguration
+//	metrics-bind-address: ":8080"
+//	resync-period: 10m
+//	watch-namespaces: [apps, tenants]
+//
+// Lists are joined with commas, for the flags taking comma-separated lists.
+// Flags set on the command line take precedence over the file.
+//
+// The file is reloaded when it changes, or on SIGHUP.
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/yaml"
+)
+
+// APIVersion and Kind identify a configuration file. Both are optional, but
+// must have these values if they are set.
+const (
+	APIVersion = "gari.gke-labs.dev/v1alpha1"
+	Kind       = "ControllerConfiguration"
+)
+
+// reloadDelay is how long after the last change to the file it is reloaded.
+const reloadDelay = 100 * time.Millisecond
+
+var (
+	lastReload = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gari_config_last_reload_success_timestamp_seconds",
+		Help: "The time the configuration file was last loaded or reloaded successfully.",
+	})
+	reloadFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gari_config_reload_failures_total",
+		Help: "The number of reloads of the configuration file that failed, leaving the previous values in place.",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(lastReload, reloadFailures)
+}
+
+// File is a configuration file whose values have been applied to a flag set.
+// As a manager Runnable, it watches the file and applies the new values of
+// the flags that can change at runtime.
+type File struct {
+	// Reloadable holds the flags whose value can change at runtime, with the
+	// function applying a new value once it is set on the flag. A flag
+	// removed from the file goes back to its default.
+	Reloadable map[string]func()
+
+	path     string
+	flags    *flag.FlagSet
+	explicit map[string]bool
+	data     []byte
+	values   map[string]string
+}
+
+// Load applies the values of the file at path to the flags of flags, which
+// must have been parsed, that were not set on the command line.
+func Load(flags *flag.FlagSet, path string) (*File, error) {
+	f := &File{path: path, flags: flags, explicit: map[string]bool{}}
+	flags.Visit(func(fl *flag.Flag) {
+		f.explicit[fl.Name] = true
+	})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values, err := parse(flags, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if f.explicit[name] {
+			continue
+		}
+		if err := flags.Set(name, values[name]); err != nil {
+			return nil, fmt.Errorf("%s: invalid value %q for %s: %w", path, values[name], name, err)
+		}
+	}
+	f.data, f.values = data, values
+	lastReload.SetToCurrentTime()
+	return f, nil
+}
+
+// parse returns the values set by a configuration file, keyed by flag name,
+// as they are given on the command line.
+func parse(flags *flag.FlagSet, data []byte) (map[string]string, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if v, ok := raw["apiVersion"]; ok && v != APIVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %v, expected %s", v, APIVersion)
+	}
+	if v, ok := raw["kind"]; ok && v != Kind {
+		return nil, fmt.Errorf("unsupported kind %v, expected %s", v, Kind)
+	}
+	delete(raw, "apiVersion")
+	delete(raw, "kind")
+
+	values := make(map[string]string, len(raw))
+	var errs []error
+	for name, v := range raw {
+		if flags.Lookup(name) == nil {
+			errs = append(errs, fmt.Errorf("unknown option %s", name))
+			continue
+		}
+		value, err := flagValue(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		values[name] = value
+	}
+	return values, errors.Join(errs...)
+}
+
+// flagValue returns v as it is given on the command line.
+func flagValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string, bool, float64:
+		return fmt.Sprint(v), nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if _, ok := item.([]any); ok {
+				return "", fmt.Errorf("unsupported value %v", v)
+			}
+			s, err := flagValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case nil:
+		return "", errors.New("no value")
+	default:
+		return "", fmt.Errorf("unsupported value %v", v)
+	}
+}
+
+// Start watches the file until ctx is done, and reloads it whenever it
+// changes or the process receives SIGHUP. Errors are logged, leaving the
+// current values in place.
+func (f *File) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("config").WithValues("path", f.path)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	// The directory is watched rather than the file, since files mounted from
+	// ConfigMaps are replaced through a symlink rather than written to.
+	if err := watcher.Add(filepath.Dir(f.path)); err != nil {
+		return err
+	}
+	// Events are coalesced for reloadDelay, so that a file being written is
+	// read once it is complete.
+	reload := time.NewTimer(0)
+	<-reload.C
+	hangup := make(chan os.Signal, 1)
+	notifyHangup(hangup)
+	defer signal.Stop(hangup)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.Errors:
+			l.Error(err, "unable to watch the configuration file")
+		case <-watcher.Events:
+			reload.Reset(reloadDelay)
+		case <-hangup:
+			l.Info("reloading the configuration file on SIGHUP")
+			reload.Reset(0)
+		case <-reload.C:
+			if err := f.reload(ctx); err != nil {
+				reloadFailures.Inc()
+				l.Error(err, "unable to reload the configuration file")
+				continue
+			}
+			lastReload.SetToCurrentTime()
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, since every
+// replica applies its configuration.
+func (f *File) NeedLeaderElection() bool {
+	return false
+}
+
+// reload applies the new values of the reloadable flags if the file changed,
+// and logs the changes of the others, which take effect on restart.
+func (f *File) reload(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("config").WithValues("path", f.path)
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, f.data) {
+		return nil
+	}
+	values, err := parse(f.flags, data)
+	if err != nil {
+		return err
+	}
+
+	// Every new value is set before any is applied, so that an invalid one
+	// leaves all the previous values in place.
+	names := append(slices.Collect(maps.Keys(values)), slices.Collect(maps.Keys(f.values))...)
+	slices.Sort(names)
+	previous := map[string]string{}
+	for _, name := range slices.Compact(names) {
+		value, set := values[name]
+		if f.explicit[name] || (set && value == f.values[name]) {
+			continue
+		}
+		if _, ok := f.Reloadable[name]; !ok {
+			l.Info("option changed, it takes effect on restart", "option", name)
+			continue
+		}
+		fl := f.flags.Lookup(name)
+		if !set {
+			value = fl.DefValue
+		}
+		previous[name] = fl.Value.String()
+		if err := f.flags.Set(name, value); err != nil {
+			for name, value := range previous {
+				_ = f.flags.Set(name, value)
+			}
+			return fmt.Errorf("invalid value %q for %s: %w", value, name, err)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(previous)) {
+		f.Reloadable[name]()
+		l.Info("option reloaded", "option", name, "value", f.flags.Lookup(name).Value.String())
+	}
+	f.data, f.values = data, values
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newFlags() *flag.FlagSet {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("address", ":8080", "")
+	flags.Duration("period", time.Minute, "")
+	flags.String("namespaces", "", "")
+	flags.String("headers", "authorization", "")
+	flags.Bool("enabled", false, "")
+	return flags
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unable to write %s: %v", path, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `apiVersion: gari.gke-labs.dev/v1alpha1
+kind: ControllerConfiguration
+address: ":9090"
+period: 10m
+namespaces: [apps, tenants]
+enabled: true
+`)
+	flags := newFlags()
+	if err := flags.Parse([]string{"--address=:7070"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Load(flags, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, expected := range map[string]string{
+		"address":    ":7070",
+		"period":     "10m0s",
+		"namespaces": "apps,tenants",
+		"enabled":    "true",
+		"headers":    "authorization",
+	} {
+		if actual := flags.Lookup(name).Value.String(); actual != expected {
+			t.Errorf("expected %s to be %q, got %q", name, expected, actual)
+		}
+	}
+
+	for content, expected := range map[string]string{
+		"unknown: 1\n":         "unknown option unknown",
+		"period: often\n":      "invalid value",
+		"kind: Deployment\n":   "unsupported kind",
+		"address: {a: b}\n":    "unsupported value",
+		"address: [1, [2]]\n":  "unsupported value",
+		"address: :1\n: bad\n": "",
+	} {
+		writeFile(t, path, content)
+		_, err := Load(newFlags(), path)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected an error containing %q for %q, got %v", expected, content, err)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "headers: cookie\nperiod: 1m\n")
+	flags := newFlags()
+	if err := flags.Parse(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f, err := Load(flags, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	applied := make(chan string, 10)
+	f.Reloadable = map[string]func(){
+		"headers": func() { applied <- flags.Lookup("headers").Value.String() },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		_ = f.Start(ctx)
+	}()
+
+	// The watch starts asynchronously, so the file is written until the
+	// change is seen.
+	expectApplied := func(content, expected string) {
+		t.Helper()
+		deadline := time.After(5 * time.Second)
+		for {
+			writeFile(t, path, content)
+			select {
+			case actual := <-applied:
+				if actual != expected {
+					t.Fatalf("expected headers to be reloaded as %q, got %q", expected, actual)
+				}
+				return
+			case <-deadline:
+				t.Fatalf("expected headers to be reloaded as %q", expected)
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
+	expectApplied("headers: cookie,x-api-key\nperiod: 1m\n", "cookie,x-api-key")
+
+	// Options that are not reloadable keep their value until a restart, and
+	// reloadable ones go back to their default when removed.
+	expectApplied("period: 5m\n", "authorization")
+	if actual := flags.Lookup("period").Value.String(); actual != "1m0s" {
+		t.Errorf("expected period to be kept until a restart, got %q", actual)
+	}
+
+	// An invalid file leaves the values in place.
+	cancel()
+	<-stopped
+	writeFile(t, path, "headers: [a\n")
+	if err := f.reload(context.Background()); err == nil {
+		t.Errorf("expected an invalid file to be rejected")
+	}
+	if actual := flags.Lookup("headers").Value.String(); actual != "authorization" {
+		t.Errorf("expected headers to be kept, got %q", actual)
+	}
+}
+
+func TestReloadKeepsPreviousValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "headers: cookie\n")
+	flags := newFlags()
+	if err := flags.Parse(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f, err := Load(flags, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if testutil.ToFloat64(lastReload) == 0 {
+		t.Errorf("expected the time of the load to be recorded")
+	}
+	applied := 0
+	f.Reloadable = map[string]func(){
+		"headers": func() { applied++ },
+		"enabled": func() { applied++ },
+	}
+
+	// An invalid value of one option leaves the others in place too.
+	writeFile(t, path, "headers: x-api-key\nenabled: maybe\n")
+	if err := f.reload(context.Background()); err == nil {
+		t.Errorf("expected an invalid value to be rejected")
+	}
+	if actual := flags.Lookup("headers").Value.String(); actual != "cookie" || applied != 0 {
+		t.Errorf("expected the previous values to be kept, got headers %q and %d applied", actual, applied)
+	}
+
+	writeFile(t, path, "headers: x-api-key\nenabled: true\n")
+	if err := f.reload(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := flags.Lookup("headers").Value.String(); actual != "x-api-key" || applied != 2 {
+		t.Errorf("expected the new values to be applied, got headers %q and %d applied", actual, applied)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configstream
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Bounds of the delay before a proxy reconnects to a broken stream. It doubles
+// with each attempt that does not get the route table.
+const (
+	reconnectBaseDelay = time.Second
+	reconnectMaxDelay  = 30 * time.Second
+)
+
+var servedSnapshot = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gari_proxy_route_table_snapshot",
+	Help: "Set to 1 for the snapshot of the route table served by this proxy. Replicas serving different snapshots for long serve different routes.",
+}, []string{"snapshot"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(servedSnapshot)
+}
+
+// ClientOptions configures a Client.
+type ClientOptions struct {
+	// Address is the address of the controller's config stream.
+	Address string
+	// TLS, if set, connects over TLS, presenting its client certificates.
+	TLS *tls.Config
+	// Token, if set, is presented as a bearer token.
+	Token string
+	// Node identifies the proxy in the logs of the controller.
+	Node string
+}
+
+// Client keeps a proxy serving the routes streamed by the controller.
+type Client struct {
+	proxy  *proxy.Proxy
+	opts   ClientOptions
+	synced atomic.Bool
+}
+
+// NewClient returns a client programming p with the routes streamed with
+// opts.
+func NewClient(p *proxy.Proxy, opts ClientOptions) *Client {
+	return &Client{proxy: p, opts: opts}
+}
+
+// Start streams the routes until ctx is done. When the stream breaks, the
+// proxy keeps serving the routes it has while the client reconnects, which
+// resyncs it with the whole route table.
+func (c *Client) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("configstream")
+	creds := insecure.NewCredentials()
+	if c.opts.TLS != nil {
+		creds = credentials.NewTLS(c.opts.TLS)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: 30 * time.Second, Timeout: 10 * time.Second, PermitWithoutStream: true}),
+	}
+	if c.opts.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken{token: c.opts.Token, secure: c.opts.TLS != nil}))
+	}
+	conn, err := grpc.NewClient(c.opts.Address, opts...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	delay := reconnectBaseDelay
+	for {
+		synced, err := c.receive(ctx, conn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if synced {
+			delay = reconnectBaseDelay
+		}
+		l.Error(err, "config stream broken, reconnecting", "delay", delay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		if !synced {
+			delay = min(2*delay, reconnectMaxDelay)
+		}
+	}
+}
+
+// receive applies the updates of one stream to the proxy until it breaks,
+// reporting whether it got the route table.
+func (c *Client) receive(ctx context.Context, conn *grpc.ClientConn) (synced bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := conn.NewStream(ctx, &streamRoutesDesc, streamRoutes, grpc.ForceCodec(jsonCodec{}))
+	if err != nil {
+		return false, err
+	}
+	if err := stream.SendMsg(&subscribeRequest{Node: c.opts.Node}); err != nil {
+		return false, err
+	}
+	for {
+		var u update
+		if err := stream.RecvMsg(&u); err != nil {
+			return synced, err
+		}
+		c.apply(ctx, &u)
+		synced = synced || u.Full
+		if err := stream.SendMsg(&acknowledgement{Snapshot: u.Snapshot}); err != nil {
+			return synced, err
+		}
+	}
+}
+
+// apply programs the proxy with an update, and records its snapshot. Routes
+// that cannot be decoded are not served.
+func (c *Client) apply(ctx context.Context, u *update) {
+	l := log.FromContext(ctx).WithName("configstream")
+	changes := make(map[proxy.RouteKey]*proxy.HTTPRoute, len(u.Routes)+len(u.Removed))
+	for _, key := range u.Removed {
+		changes[key] = nil
+	}
+	var routes []proxy.HTTPRoute
+	for _, data := range u.Routes {
+		route := &proxy.HTTPRoute{}
+		if err := json.Unmarshal(data, route); err != nil {
+			var key proxy.RouteKey
+			_ = json.Unmarshal(data, &key)
+			l.Error(err, "unable to decode route, not serving it", "namespace", key.Namespace, "name", key.Name)
+			changes[key] = nil
+			continue
+		}
+		routes = append(routes, *route)
+		changes[proxy.RouteKey{Namespace: route.Namespace, Name: route.Name}] = route
+	}
+
+	servedSnapshot.Reset()
+	servedSnapshot.WithLabelValues(u.Snapshot).Set(1)
+	if u.Full {
+		c.proxy.UpdateRoutes(routes)
+		c.synced.Store(true)
+		l.Info("Updated proxy routes", "count", len(routes), "version", u.Version, "snapshot", u.Snapshot)
+		return
+	}
+	c.proxy.ApplyRouteChanges(changes)
+	l.V(1).Info("Applied route changes", "count", len(changes), "version", u.Version, "snapshot", u.Snapshot)
+}
+
+// ReadyCheck is a readiness check that passes once the proxy serves the
+// route table streamed by the controller. It keeps passing while the stream
+// is broken, since the proxy still serves the last routes it got.
+func (c *Client) ReadyCheck(_ *http.Request) error {
+	if !c.synced.Load() {
+		return errors.New("route table not received yet")
+	}
+	return nil
+}
+
+// bearerToken presents a token with each stream.
+type bearerToken struct {
+	token  string
+	secure bool
+}
+
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool {
+	return t.secure
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configstream distributes the routes computed by the controller to
+// standalone proxies over a gRPC stream, so that one control plane can feed
+// many proxy replicas. Each stream starts with the whole route table and then
+// carries the changes to it; a proxy that loses its stream keeps serving the
+// routes it has and resyncs when it reconnects.
+//
+// Every update names the snapshot of the route table it brings the proxy to,
+// by a hash of its content, so that the snapshots served by proxies fed by
+// different controller replicas can be compared. Proxies acknowledge each
+// snapshot they serve on the same stream, which lets the controller tell when
+// one of them has fallen behind. Because routes carry
+// credentials such as basic auth hashes, proxies must authenticate with a
+// bearer token or a client certificate.
+package configstream
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"google.golang.org/grpc"
+)
+
+// The stream is a bidirectional gRPC method: the proxy sends a subscribeRequest
+// and then acknowledgements, the controller sends updates. Messages are
+// encoded as JSON, so that no generated code is needed.
+const (
+	serviceName      = "gari.configstream.v1.ConfigStream"
+	streamRoutesName = "StreamRoutes"
+	streamRoutes     = "/" + serviceName + "/" + streamRoutesName
+)
+
+var streamRoutesDesc = grpc.StreamDesc{StreamName: streamRoutesName, ServerStreams: true, ClientStreams: true}
+
+// subscribeRequest opens a stream.
+type subscribeRequest struct {
+	// Node identifies the proxy in the logs of the controller.
+	Node string `json:"node"`
+}
+
+// update is a message of a stream.
+type update struct {
+	// Version numbers the updates of a stream, from 1.
+	Version uint64 `json:"version"`
+	// Full, if set, replaces every route served by the proxy with Routes.
+	// Otherwise, Routes replace the routes with the same namespace and name,
+	// and the Removed ones stop being served.
+	Full    bool              `json:"full,omitempty"`
+	Routes  []json.RawMessage `json:"routes,omitempty"`
+	Removed []proxy.RouteKey  `json:"removed,omitempty"`
+	// Snapshot identifies the route table the proxy serves once the update is
+	// applied.
+	Snapshot string `json:"snapshot"`
+}
+
+// acknowledgement tells the controller which snapshot a proxy serves.
+type acknowledgement struct {
+	Snapshot string `json:"snapshot"`
+}
+
+// jsonCodec encodes the messages of the stream as JSON.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding %T: %w", v, err)
+	}
+	return nil
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
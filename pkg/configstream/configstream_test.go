@@ -0,0 +1,203 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configstream
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewServerRequiresAuthentication(t *testing.T) {
+	if _, err := NewServer(ServerOptions{}); err == nil {
+		t.Errorf("expected an error without any authentication method")
+	}
+	if _, err := NewServer(ServerOptions{ClientCertificates: true}); err == nil {
+		t.Errorf("expected an error for client certificates without TLS")
+	}
+}
+
+func TestStreamRoutes(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	server, err := NewServer(ServerOptions{Token: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	go func() { _ = server.serve(ctx, listener) }()
+
+	newRoute := func(name, hostname string) proxy.HTTPRoute {
+		return proxy.HTTPRoute{Namespace: "default", Name: name, Hostnames: []string{hostname}}
+	}
+	served := func(p *proxy.Proxy) []string {
+		var served []string
+		for _, route := range p.Routes() {
+			served = append(served, route.Name+"="+route.Hostnames[0])
+		}
+		return served
+	}
+	connect := func() (*proxy.Proxy, *Client) {
+		p := proxy.NewProxy(proxy.Options{})
+		client := NewClient(p, ClientOptions{Address: listener.Addr().String(), Token: "secret", Node: "test"})
+		go func() { _ = client.Start(ctx) }()
+		return p, client
+	}
+	waitFor := func(p *proxy.Proxy, expected []string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !reflect.DeepEqual(served(p), expected) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the proxy to serve %v, got %v", expected, served(p))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// Proxies get nothing until the controller has published the whole
+	// table, so that they do not serve part of it.
+	p, client := connect()
+	server.ApplyRouteChanges(map[proxy.RouteKey]*proxy.HTTPRoute{{Namespace: "default", Name: "early"}: ptr(newRoute("early", "early.example.com"))})
+	time.Sleep(100 * time.Millisecond)
+	if err := client.ReadyCheck(nil); err == nil {
+		t.Errorf("expected the proxy not to be ready before the route table is published")
+	}
+
+	server.UpdateRoutes([]proxy.HTTPRoute{newRoute("a", "a.example.com"), newRoute("b", "b.example.com")})
+	waitFor(p, []string{"a=a.example.com", "b=b.example.com"})
+	if err := client.ReadyCheck(nil); err != nil {
+		t.Errorf("expected the proxy to be ready, got %v", err)
+	}
+
+	server.ApplyRouteChanges(map[proxy.RouteKey]*proxy.HTTPRoute{
+		{Namespace: "default", Name: "a"}: ptr(newRoute("a", "updated.example.com")),
+		{Namespace: "default", Name: "b"}: nil,
+	})
+	waitFor(p, []string{"a=updated.example.com"})
+
+	// A proxy connecting later gets the table with every change applied.
+	late, _ := connect()
+	waitFor(late, []string{"a=updated.example.com"})
+}
+
+func TestStreamRoutesAuthentication(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	server, err := NewServer(ServerOptions{Token: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server.UpdateRoutes(nil)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	go func() { _ = server.serve(ctx, listener) }()
+
+	for _, token := range []string{"", "wrong"} {
+		var opts []grpc.DialOption
+		if token != "" {
+			opts = append(opts, grpc.WithPerRPCCredentials(bearerToken{token: token}))
+		}
+		conn, err := grpc.NewClient(listener.Addr().String(), append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+		if err != nil {
+			t.Fatalf("unable to connect: %v", err)
+		}
+		defer conn.Close()
+		client := NewClient(proxy.NewProxy(proxy.Options{}), ClientOptions{})
+		if synced, err := client.receive(ctx, conn); synced || status.Code(err) != codes.Unauthenticated {
+			t.Errorf("expected token %q to be rejected, got %v", token, err)
+		}
+	}
+}
+
+func TestConsistencyCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	server, err := NewServer(ServerOptions{Token: "secret", ConsistencyTolerance: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server.UpdateRoutes([]proxy.HTTPRoute{{Namespace: "default", Name: "a"}})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	go func() { _ = server.serve(ctx, listener) }()
+
+	// A server with the same routes names the same snapshot.
+	other, _ := NewServer(ServerOptions{Token: "secret"})
+	other.UpdateRoutes([]proxy.HTTPRoute{{Namespace: "default", Name: "a"}})
+	if server.snapshot == "" || server.snapshot != other.snapshot {
+		t.Errorf("expected servers with the same routes to share a snapshot, got %q and %q", server.snapshot, other.snapshot)
+	}
+
+	client := NewClient(proxy.NewProxy(proxy.Options{}), ClientOptions{Address: listener.Addr().String(), Token: "secret", Node: "in-sync"})
+	go func() { _ = client.Start(ctx) }()
+
+	// A proxy that receives the routes without acknowledging them falls
+	// out of sync once the tolerance has passed.
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithPerRPCCredentials(bearerToken{token: "secret"}))
+	if err != nil {
+		t.Fatalf("unable to connect: %v", err)
+	}
+	defer conn.Close()
+	stream, err := conn.NewStream(ctx, &streamRoutesDesc, streamRoutes, grpc.ForceCodec(jsonCodec{}))
+	if err != nil {
+		t.Fatalf("unable to open stream: %v", err)
+	}
+	if err := stream.SendMsg(&subscribeRequest{Node: "stuck"}); err != nil {
+		t.Fatalf("unable to subscribe: %v", err)
+	}
+	var u update
+	if err := stream.RecvMsg(&u); err != nil || u.Snapshot != server.snapshot {
+		t.Fatalf("expected snapshot %q, got %+v, %v", server.snapshot, u, err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if _, nodes := server.outOfSync(); !reflect.DeepEqual(nodes, []string{"stuck"}) {
+		t.Errorf("expected only the stuck proxy to be out of sync, got %v", nodes)
+	}
+	if err := server.ConsistencyCheck(nil); err == nil {
+		t.Errorf("expected the check to fail while a proxy is out of sync")
+	}
+
+	if err := stream.SendMsg(&acknowledgement{Snapshot: u.Snapshot}); err != nil {
+		t.Fatalf("unable to acknowledge: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for server.ConsistencyCheck(nil) != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the check to pass once every proxy serves the snapshot, got %v", server.ConsistencyCheck(nil))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,448 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configstream
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	connectedProxies = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gari_configstream_connected_proxies",
+		Help: "Number of standalone proxies streaming their routes from this controller.",
+	})
+	outOfSyncProxies = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gari_configstream_out_of_sync_proxies",
+		Help: "Number of connected proxies that have not served the current route table snapshot within the consistency tolerance.",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(connectedProxies, outOfSyncProxies)
+}
+
+// defaultConsistencyTolerance is how long a proxy may take to serve a new
+// snapshot of the route table before it counts as out of sync.
+const defaultConsistencyTolerance = 30 * time.Second
+
+// consistencyInterval is how often the out-of-sync gauge is refreshed.
+const consistencyInterval = 5 * time.Second
+
+// ServerOptions configures a Server. At least one authentication method must
+// be enabled.
+type ServerOptions struct {
+	// Address is the address the server binds to.
+	Address string
+	// TLS, if set, serves the stream over TLS.
+	TLS *tls.Config
+	// Token, if set, authenticates proxies presenting it as a bearer token.
+	Token string
+	// ClientCertificates authenticates proxies presenting a client
+	// certificate verified by TLS, which must be configured to verify them
+	// against a trusted CA.
+	ClientCertificates bool
+	// ConsistencyTolerance is how long a proxy may take to serve a new
+	// snapshot of the route table before it counts as out of sync. Defaults
+	// to 30 seconds.
+	ConsistencyTolerance time.Duration
+}
+
+// Server distributes routes to standalone proxies. It is handed the routes
+// the same way as a proxy is, so that the controller can publish them to both.
+type Server struct {
+	opts ServerOptions
+
+	mu sync.Mutex
+	// routes holds every route to serve, encoded, and synced is set once the
+	// whole table has been published; proxies get nothing before that, so
+	// that they do not serve a partial table.
+	routes      map[proxy.RouteKey]json.RawMessage
+	synced      bool
+	subscribers map[*subscriber]bool
+	// snapshot identifies the content of routes, and published is when it
+	// last changed.
+	snapshot  string
+	published time.Time
+}
+
+// subscriber holds the changes not yet sent on a stream, and the snapshot
+// served by its proxy.
+type subscriber struct {
+	node      string
+	connected time.Time
+	// served is the snapshot last acknowledged by the proxy.
+	served string
+	// full is set when every route must be sent, in place of the changes.
+	full bool
+	// pending holds the changed routes, with nil for the removed ones.
+	pending map[proxy.RouteKey]json.RawMessage
+	// ready is signalled when there is something to send.
+	ready chan struct{}
+}
+
+func (sub *subscriber) signal() {
+	select {
+	case sub.ready <- struct{}{}:
+	default:
+	}
+}
+
+// NewServer returns a server distributing routes with opts.
+func NewServer(opts ServerOptions) (*Server, error) {
+	if opts.Token == "" && !opts.ClientCertificates {
+		return nil, errors.New("the config stream requires a bearer token or client certificates")
+	}
+	if opts.ClientCertificates && opts.TLS == nil {
+		return nil, errors.New("client certificates require TLS")
+	}
+	if opts.ConsistencyTolerance <= 0 {
+		opts.ConsistencyTolerance = defaultConsistencyTolerance
+	}
+	return &Server{
+		opts:        opts,
+		routes:      map[proxy.RouteKey]json.RawMessage{},
+		subscribers: map[*subscriber]bool{},
+	}, nil
+}
+
+// UpdateRoutes replaces every route, which is sent whole to every proxy.
+func (s *Server) UpdateRoutes(routes []proxy.HTTPRoute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = make(map[proxy.RouteKey]json.RawMessage, len(routes))
+	for i := range routes {
+		if data := encodeRoute(&routes[i]); data != nil {
+			s.routes[proxy.RouteKey{Namespace: routes[i].Namespace, Name: routes[i].Name}] = data
+		}
+	}
+	s.synced = true
+	s.updateSnapshot()
+	for sub := range s.subscribers {
+		sub.full = true
+		sub.pending = nil
+		sub.signal()
+	}
+}
+
+// ApplyRouteChanges replaces the changed routes, and removes those whose
+// change is nil. The changes are sent to every proxy.
+func (s *Server) ApplyRouteChanges(changes map[proxy.RouteKey]*proxy.HTTPRoute) {
+	encoded := make(map[proxy.RouteKey]json.RawMessage, len(changes))
+	for key, route := range changes {
+		if route != nil {
+			// A route that cannot be sent must not be served stale either.
+			encoded[key] = encodeRoute(route)
+		} else {
+			encoded[key] = nil
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, data := range encoded {
+		if data != nil {
+			s.routes[key] = data
+		} else {
+			delete(s.routes, key)
+		}
+	}
+	s.updateSnapshot()
+	for sub := range s.subscribers {
+		if sub.full {
+			continue
+		}
+		for key, data := range encoded {
+			sub.pending[key] = data
+		}
+		sub.signal()
+	}
+}
+
+func encodeRoute(route *proxy.HTTPRoute) json.RawMessage {
+	data, err := json.Marshal(route)
+	if err != nil {
+		log.Log.WithName("configstream").Error(err, "unable to encode route, proxies will not serve it", "namespace", route.Namespace, "name", route.Name)
+		return nil
+	}
+	return data
+}
+
+// updateSnapshot identifies the content of the routes, so that servers with
+// the same routes name the same snapshot. It must be called with mu held.
+func (s *Server) updateSnapshot() {
+	h := sha256.New()
+	for _, key := range sortedKeys(s.routes) {
+		fmt.Fprintf(h, "%s/%s\x00%s\x00", key.Namespace, key.Name, s.routes[key])
+	}
+	if snapshot := hex.EncodeToString(h.Sum(nil))[:16]; snapshot != s.snapshot {
+		s.snapshot = snapshot
+		s.published = time.Now()
+	}
+}
+
+// Start serves the stream until ctx is done. Streams are served by every
+// replica, since each one keeps the route table.
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.opts.Address)
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("starting config stream server", "addr", listener.Addr())
+	return s.serve(ctx, listener)
+}
+
+func (s *Server) serve(ctx context.Context, listener net.Listener) error {
+	opts := []grpc.ServerOption{
+		grpc.ForceServerCodec(jsonCodec{}),
+		// Proxies ping idle connections to detect a controller that went away.
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{MinTime: 10 * time.Second, PermitWithoutStream: true}),
+	}
+	if s.opts.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.opts.TLS)))
+	}
+	server := grpc.NewServer(opts...)
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: serviceName,
+		HandlerType: (*any)(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    streamRoutesName,
+			Handler:       func(_ any, stream grpc.ServerStream) error { return s.stream(stream) },
+			ServerStreams: true,
+			ClientStreams: true,
+		}},
+	}, s)
+
+	go func() {
+		<-ctx.Done()
+		// Streams never end on their own, so they are not waited for.
+		server.Stop()
+	}()
+	go func() {
+		ticker := time.NewTicker(consistencyInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, nodes := s.outOfSync()
+				outOfSyncProxies.Set(float64(len(nodes)))
+			}
+		}
+	}()
+	return server.Serve(listener)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// stream sends the routes to a proxy, then their changes as they happen, and
+// records the snapshots it acknowledges.
+func (s *Server) stream(stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	if err := s.authenticate(ctx); err != nil {
+		return err
+	}
+	var req subscribeRequest
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+	l := log.Log.WithName("configstream").WithValues("node", req.Node)
+	l.Info("proxy connected")
+	defer l.Info("proxy disconnected")
+
+	sub := s.subscribe(req.Node)
+	defer s.unsubscribe(sub)
+	go func() {
+		for {
+			var ack acknowledgement
+			if err := stream.RecvMsg(&ack); err != nil {
+				return
+			}
+			s.acknowledge(sub, ack.Snapshot)
+		}
+	}()
+	var version uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.ready:
+		}
+		next, ok := s.next(sub)
+		if !ok {
+			continue
+		}
+		version++
+		next.Version = version
+		if err := stream.SendMsg(next); err != nil {
+			return err
+		}
+	}
+}
+
+// authenticate checks the bearer token or client certificate of a stream.
+func (s *Server) authenticate(ctx context.Context) error {
+	if s.opts.ClientCertificates {
+		if p, ok := peer.FromContext(ctx); ok {
+			if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+				return nil
+			}
+		}
+	}
+	if s.opts.Token != "" {
+		for _, value := range metadata.ValueFromIncomingContext(ctx, "authorization") {
+			token, ok := strings.CutPrefix(value, "Bearer ")
+			if ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1 {
+				return nil
+			}
+		}
+	}
+	return status.Error(codes.Unauthenticated, "a valid bearer token or client certificate is required")
+}
+
+func (s *Server) subscribe(node string) *subscriber {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub := &subscriber{node: node, connected: time.Now(), full: true, ready: make(chan struct{}, 1)}
+	s.subscribers[sub] = true
+	connectedProxies.Set(float64(len(s.subscribers)))
+	if s.synced {
+		sub.signal()
+	}
+	return sub
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscribers, sub)
+	connectedProxies.Set(float64(len(s.subscribers)))
+}
+
+func (s *Server) acknowledge(sub *subscriber, snapshot string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub.served = snapshot
+}
+
+// outOfSync returns the current snapshot, and the nodes of the connected
+// proxies that have not served it within the consistency tolerance, whether
+// since it was published or since they connected.
+func (s *Server) outOfSync() (snapshot string, nodes []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.synced {
+		return "", nil
+	}
+	for sub := range s.subscribers {
+		if sub.served != s.snapshot && time.Since(later(s.published, sub.connected)) > s.opts.ConsistencyTolerance {
+			nodes = append(nodes, sub.node)
+		}
+	}
+	slices.Sort(nodes)
+	return s.snapshot, nodes
+}
+
+// ConsistencyCheck is a readiness check that fails while a connected proxy
+// has not served the current snapshot of the route table within the
+// consistency tolerance, so that a rollout does not proceed while proxies
+// serve different routes.
+func (s *Server) ConsistencyCheck(_ *http.Request) error {
+	snapshot, nodes := s.outOfSync()
+	outOfSyncProxies.Set(float64(len(nodes)))
+	if len(nodes) > 0 {
+		return fmt.Errorf("proxies not serving route table snapshot %s after %v: %s", snapshot, s.opts.ConsistencyTolerance, strings.Join(nodes, ", "))
+	}
+	return nil
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// next returns the update to send to sub, and takes its pending changes. It
+// returns false if there is nothing to send.
+func (s *Server) next(sub *subscriber) (*update, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.synced {
+		return nil, false
+	}
+	var u update
+	switch {
+	case sub.full:
+		u.Full = true
+		for _, key := range sortedKeys(s.routes) {
+			u.Routes = append(u.Routes, s.routes[key])
+		}
+	case len(sub.pending) > 0:
+		for _, key := range sortedKeys(sub.pending) {
+			if data := sub.pending[key]; data != nil {
+				u.Routes = append(u.Routes, data)
+			} else {
+				u.Removed = append(u.Removed, key)
+			}
+		}
+	default:
+		return nil, false
+	}
+	u.Snapshot = s.snapshot
+	sub.full = false
+	sub.pending = map[proxy.RouteKey]json.RawMessage{}
+	return &u, true
+}
+
+func sortedKeys(routes map[proxy.RouteKey]json.RawMessage) []proxy.RouteKey {
+	keys := make([]proxy.RouteKey, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b proxy.RouteKey) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return keys
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// acmeChallengePrefix is the path prefix of ACME HTTP-01 challenges.
+const acmeChallengePrefix = "/.well-known/acme-challenge/"
+
+// acmeSolverRouteName is the name of the built-in route of the ACME HTTP-01
+// solver, which cannot collide with those of HTTPRoutes or Ingresses since it
+// has no namespace.
+const acmeSolverRouteName = "builtin/acme-http01-solver"
+
+// ACMESolverRoute returns the built-in route forwarding the ACME HTTP-01
+// challenges of every hostname to a solver Service, given as
+// namespace/name:port, by its name in clusterDomain. It takes precedence over the routes of HTTPRoutes, so
+// that certificates can be issued before any route serves the hostname, and
+// cannot be shadowed by one that does.
+func ACMESolverRoute(service, clusterDomain string) (proxy.HTTPRoute, error) {
+	ref, portValue, ok := strings.Cut(service, ":")
+	namespace, name, ok2 := strings.Cut(ref, "/")
+	port, err := strconv.ParseInt(portValue, 10, 32)
+	if !ok || !ok2 || err != nil || port <= 0 || port > 65535 ||
+		len(validation.IsDNS1123Label(namespace)) > 0 || len(validation.IsDNS1035Label(name)) > 0 {
+		return proxy.HTTPRoute{}, fmt.Errorf("ACME solver Service must be given as namespace/name:port, got %q", service)
+	}
+	return proxy.HTTPRoute{
+		Name:    acmeSolverRouteName,
+		Builtin: true,
+		Rules: []proxy.RouteRule{{
+			Name:    "challenges",
+			Matches: []proxy.RouteMatch{{Path: &proxy.PathMatch{Type: proxy.PathMatchTypePathPrefix, Value: acmeChallengePrefix}}},
+			Backends: []proxy.WeightedBackend{{
+				Backend: proxy.Backend{Host: ir.ServiceHost(namespace, name, clusterDomain), Port: int32(port)},
+				Weight:  1,
+			}},
+		}},
+	}, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestACMESolverRoute(t *testing.T) {
+	route, err := ACMESolverRoute("cert-manager/acme-solver:8089", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	backend := route.Rules[0].Backends[0].Backend
+	if !route.Builtin || backend.Host != "acme-solver.cert-manager.svc.cluster.local" || backend.Port != 8089 {
+		t.Errorf("expected a built-in route to the solver Service, got %+v", route)
+	}
+
+	for _, invalid := range []string{"acme-solver:8089", "cert-manager/acme-solver", "cert-manager/acme-solver:http", "cert-manager/Solver:8089"} {
+		if _, err := ACMESolverRoute(invalid, ""); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestBuiltinRoutesAreServed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	route, err := ACMESolverRoute("cert-manager/acme-solver:8089", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := proxy.NewProxy(proxy.Options{})
+	r := &HTTPRouteReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Scheme:        scheme,
+		Proxy:         p,
+		BuiltinRoutes: []proxy.HTTPRoute{route},
+	}
+
+	// The solver is served before any HTTPRoute exists.
+	if err := r.updateProxy(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if routes := p.Routes(); len(routes) != 1 || routes[0].Name != acmeSolverRouteName {
+		t.Errorf("expected the ACME solver route, got %v", routes)
+	}
+}
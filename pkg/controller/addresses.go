@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The addresses published on a Gateway exposed by a Service depend on how
+// the Service is reached, which the serviceType parameter of the class
+// selects, defaulting to the type of the Service:
+//
+//   - LoadBalancer: the addresses of its load balancer, published as
+//     hostnames for the load balancers that have no IP, as external-dns
+//     expects.
+//   - NodePort: the addresses of the ready nodes, on which the listener ports
+//     are reached at the Service's node ports, listed in the Programmed
+//     condition.
+//   - ClusterIP: its cluster IPs, for Gateways only reachable from within the
+//     cluster.
+
+// serviceAddresses returns the addresses at which svc exposes a Gateway, as
+// serviceType selects, in order, or why there are none yet.
+func (r *GatewayReconciler) serviceAddresses(ctx context.Context, svc *corev1.Service, serviceType corev1.ServiceType) ([]string, string, error) {
+	key := client.ObjectKeyFromObject(svc)
+	switch serviceType {
+	case corev1.ServiceTypeClusterIP:
+		var addresses []string
+		for _, ip := range svc.Spec.ClusterIPs {
+			if ip != "" && ip != corev1.ClusterIPNone {
+				addresses = append(addresses, ip)
+			}
+		}
+		return addresses, fmt.Sprintf("Waiting for Service %s to get a cluster IP", key), nil
+
+	case corev1.ServiceTypeNodePort:
+		if nodePorts(svc) == "" {
+			return nil, fmt.Sprintf("Waiting for Service %s to get node ports", key), nil
+		}
+		addresses, err := r.nodeAddresses(ctx)
+		return addresses, "Waiting for a node to be ready", err
+	}
+
+	var addresses []string
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if address := cmp.Or(ingress.IP, ingress.Hostname); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, fmt.Sprintf("Waiting for Service %s to get a LoadBalancer address", key), nil
+}
+
+// statusAddress returns the status address of a Gateway reached at address,
+// an IP or a hostname.
+func statusAddress(address string) gatewayv1.GatewayStatusAddress {
+	addressType := gatewayv1.HostnameAddressType
+	if net.ParseIP(address) != nil {
+		addressType = gatewayv1.IPAddressType
+	}
+	return gatewayv1.GatewayStatusAddress{Type: ptr(addressType), Value: address}
+}
+
+// nodePorts describes the node ports at which the ports of svc are reached,
+// or returns "" if it has none.
+func nodePorts(svc *corev1.Service) string {
+	var ports []string
+	for _, port := range svc.Spec.Ports {
+		if port.NodePort != 0 {
+			ports = append(ports, fmt.Sprintf("%d:%d", port.Port, port.NodePort))
+		}
+	}
+	return strings.Join(ports, ", ")
+}
+
+// nodeAddresses returns the address of each ready node, in order: its
+// external IP if it has one, its internal IP otherwise.
+func (r *GatewayReconciler) nodeAddresses(ctx context.Context) ([]string, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		return nil, err
+	}
+	var addresses []string
+	for i := range nodes.Items {
+		if address := nodeAddress(&nodes.Items[i]); address != "" && nodeReady(&nodes.Items[i]) {
+			addresses = append(addresses, address)
+		}
+	}
+	slices.Sort(addresses)
+	return slices.Compact(addresses), nil
+}
+
+// nodeAddress returns the external IP of node if it has one, its internal IP
+// otherwise.
+func nodeAddress(node *corev1.Node) string {
+	var internal string
+	for _, address := range node.Status.Addresses {
+		switch address.Type {
+		case corev1.NodeExternalIP:
+			return address.Address
+		case corev1.NodeInternalIP:
+			if internal == "" {
+				internal = address.Address
+			}
+		}
+	}
+	return internal
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// mapNodeToGateways enqueues every Gateway, since those exposed on node
+// ports publish the addresses of the ready nodes.
+func (r *GatewayReconciler) mapNodeToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Gateways")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(gateways.Items))
+	for i := range gateways.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateways.Items[i])})
+	}
+	return requests
+}
+
+// nodeAddressChanged filters the updates of Nodes to those that change the
+// addresses published on the Gateways exposed on node ports.
+var nodeAddressChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, ok := e.ObjectOld.(*corev1.Node)
+		if !ok {
+			return true
+		}
+		newNode, ok := e.ObjectNew.(*corev1.Node)
+		if !ok {
+			return true
+		}
+		return nodeReady(oldNode) != nodeReady(newNode) ||
+			!equality.Semantic.DeepEqual(oldNode.Status.Addresses, newNode.Status.Addresses)
+	},
+}
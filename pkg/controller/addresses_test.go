@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestServiceAddresses(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	newNode := func(name string, ready corev1.ConditionStatus, addresses ...corev1.NodeAddress) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Addresses:  addresses,
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newNode("internal", corev1.ConditionTrue, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.2"}),
+		newNode("external", corev1.ConditionTrue,
+			corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.1"},
+			corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.1"}),
+		newNode("not-ready", corev1.ConditionFalse, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.3"}),
+	).Build()
+	r := &GatewayReconciler{Client: c}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "gari-system", Name: "proxy"},
+		Spec: corev1.ServiceSpec{
+			Type:       corev1.ServiceTypeLoadBalancer,
+			ClusterIPs: []string{"10.96.0.10"},
+			Ports:      []corev1.ServicePort{{Port: 80, NodePort: 30080}, {Port: 443, NodePort: 30443}},
+		},
+		Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+			Ingress: []corev1.LoadBalancerIngress{{IP: "192.0.2.1"}, {Hostname: "lb.example.com"}},
+		}},
+	}
+	for _, tc := range []struct {
+		serviceType corev1.ServiceType
+		expected    []string
+	}{
+		{serviceType: corev1.ServiceTypeLoadBalancer, expected: []string{"192.0.2.1", "lb.example.com"}},
+		{serviceType: corev1.ServiceTypeNodePort, expected: []string{"10.0.0.2", "203.0.113.1"}},
+		{serviceType: corev1.ServiceTypeClusterIP, expected: []string{"10.96.0.10"}},
+	} {
+		t.Run(string(tc.serviceType), func(t *testing.T) {
+			addresses, _, err := r.serviceAddresses(ctx, svc, tc.serviceType)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(addresses, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, addresses)
+			}
+		})
+	}
+	if address := statusAddress("lb.example.com"); *address.Type != gatewayv1.HostnameAddressType {
+		t.Errorf("expected load balancer hostnames to be published as hostnames, got %v", *address.Type)
+	}
+	if address := statusAddress("2001:db8::1"); *address.Type != gatewayv1.IPAddressType {
+		t.Errorf("expected IPv6 addresses to be published as IP addresses, got %v", *address.Type)
+	}
+	if expected, actual := "80:30080, 443:30443", nodePorts(svc); actual != expected {
+		t.Errorf("expected node ports %q, got %q", expected, actual)
+	}
+
+	// A Service without node ports, such as a ClusterIP Service, cannot be
+	// reached on the nodes.
+	clusterIP := &corev1.Service{Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}}}
+	if addresses, waiting, _ := r.serviceAddresses(ctx, clusterIP, corev1.ServiceTypeNodePort); len(addresses) != 0 || waiting == "" {
+		t.Errorf("expected no addresses until node ports are allocated, got %v", addresses)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// listBackendTargets returns all objects backendRefs may refer to, and the
+// ReferenceGrants permitting references to other namespaces, along with the
+// BackendTLSPolicies of Services if backendTLSPolicies is set, and the
+// EndpointSlices of Services if options resolves Services to their endpoints.
+func listBackendTargets(ctx context.Context, c client.Reader, backendTLSPolicies bool, options ir.BackendOptions) (ir.Targets, error) {
+	var services corev1.ServiceList
+	if err := c.List(ctx, &services); err != nil {
+		return ir.Targets{}, err
+	}
+	var backends v1alpha1.BackendList
+	if err := c.List(ctx, &backends); err != nil {
+		return ir.Targets{}, err
+	}
+	serviceImports, err := listServiceImports(ctx, c)
+	if err != nil {
+		return ir.Targets{}, err
+	}
+	inferencePools, err := listInferencePools(ctx, c)
+	if err != nil {
+		return ir.Targets{}, err
+	}
+	referenceGrants, err := listReferenceGrants(ctx, c)
+	if err != nil {
+		return ir.Targets{}, err
+	}
+	targets := ir.Targets{
+		Options:         options,
+		Services:        make(map[types.NamespacedName]*corev1.Service, len(services.Items)),
+		Backends:        make(map[types.NamespacedName]*v1alpha1.Backend, len(backends.Items)),
+		ServiceImports:  serviceImports,
+		InferencePools:  inferencePools,
+		ReferenceGrants: referenceGrants,
+	}
+	for i := range services.Items {
+		targets.Services[client.ObjectKeyFromObject(&services.Items[i])] = &services.Items[i]
+	}
+	for i := range backends.Items {
+		targets.Backends[client.ObjectKeyFromObject(&backends.Items[i])] = &backends.Items[i]
+	}
+	if backendTLSPolicies {
+		if targets.BackendTLS, err = listBackendTLS(ctx, c); err != nil {
+			return ir.Targets{}, err
+		}
+	}
+	if options.Resolution == ir.BackendResolutionEndpointSlice {
+		var endpointSlices discoveryv1.EndpointSliceList
+		if err := c.List(ctx, &endpointSlices, client.HasLabels{discoveryv1.LabelServiceName}); err != nil {
+			return ir.Targets{}, err
+		}
+		targets.EndpointSlices = map[types.NamespacedName][]*discoveryv1.EndpointSlice{}
+		for i := range endpointSlices.Items {
+			slice := &endpointSlices.Items[i]
+			key := types.NamespacedName{Namespace: slice.Namespace, Name: slice.Labels[discoveryv1.LabelServiceName]}
+			targets.EndpointSlices[key] = append(targets.EndpointSlices[key], slice)
+		}
+	}
+	return targets, nil
+}
+
+var referenceGrantGVK = gatewayv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant")
+
+// routeBackendTargets returns the objects the backendRefs of route, and of
+// its mirror filters, refer to, as listBackendTargets does for all routes. It
+// reads only those objects, along with the ReferenceGrants, BackendTLSPolicies
+// and EndpointSlices of their namespaces and Services, so that the reconcile
+// of a single route does not list every backend of the cluster.
+func routeBackendTargets(ctx context.Context, c client.Reader, route *gatewayv1.HTTPRoute, backendTLSPolicies bool, options ir.BackendOptions) (ir.Targets, error) {
+	targets := ir.Targets{
+		Options:        options,
+		Services:       map[types.NamespacedName]*corev1.Service{},
+		Backends:       map[types.NamespacedName]*v1alpha1.Backend{},
+		ServiceImports: map[types.NamespacedName]*ir.ServiceImport{},
+		InferencePools: map[types.NamespacedName]*ir.InferencePool{},
+	}
+	getService := func(key types.NamespacedName) error {
+		if _, done := targets.Services[key]; done {
+			return nil
+		}
+		var service corev1.Service
+		if err := c.Get(ctx, key, &service); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		targets.Services[key] = &service
+		return nil
+	}
+
+	var namespaces []string
+	for _, ref := range routeBackendObjectRefs(route) {
+		key := types.NamespacedName{Namespace: route.Namespace, Name: string(ref.Name)}
+		if ref.Namespace != nil {
+			key.Namespace = string(*ref.Namespace)
+		}
+		if !slices.Contains(namespaces, key.Namespace) {
+			namespaces = append(namespaces, key.Namespace)
+		}
+		switch {
+		case ir.IsBackendRef(ref, "", "Service"):
+			if err := getService(key); err != nil {
+				return ir.Targets{}, err
+			}
+		case ir.IsBackendRef(ref, gatewayv1.Group(v1alpha1.GroupVersion.Group), ir.KindBackend):
+			var backend v1alpha1.Backend
+			if err := c.Get(ctx, key, &backend); err != nil {
+				if err := client.IgnoreNotFound(err); err != nil {
+					return ir.Targets{}, err
+				}
+				continue
+			}
+			targets.Backends[key] = &backend
+		case ir.IsBackendRef(ref, gatewayv1.Group(ir.ServiceImportGVK.Group), gatewayv1.Kind(ir.ServiceImportGVK.Kind)):
+			serviceImport, err := getServiceImport(ctx, c, key)
+			if err != nil {
+				return ir.Targets{}, err
+			}
+			if serviceImport != nil {
+				targets.ServiceImports[key] = serviceImport
+			}
+		case ir.IsBackendRef(ref, gatewayv1.Group(ir.InferencePoolGVK.Group), gatewayv1.Kind(ir.InferencePoolGVK.Kind)):
+			pool, err := getInferencePool(ctx, c, key)
+			if err != nil {
+				return ir.Targets{}, err
+			}
+			if pool == nil {
+				continue
+			}
+			targets.InferencePools[key] = pool
+			if pool.PickerService != "" {
+				if err := getService(types.NamespacedName{Namespace: key.Namespace, Name: pool.PickerService}); err != nil {
+					return ir.Targets{}, err
+				}
+			}
+		}
+	}
+
+	for _, namespace := range namespaces {
+		if namespace == route.Namespace {
+			continue
+		}
+		grants, err := listReferenceGrants(ctx, c, client.InNamespace(namespace))
+		if err != nil {
+			return ir.Targets{}, err
+		}
+		targets.ReferenceGrants = append(targets.ReferenceGrants, grants...)
+	}
+	if backendTLSPolicies {
+		targets.BackendTLS = map[ir.BackendTLSKey]*proxy.BackendTLS{}
+		for _, namespace := range namespaces {
+			settings, err := listBackendTLS(ctx, c, client.InNamespace(namespace))
+			if err != nil {
+				return ir.Targets{}, err
+			}
+			maps.Copy(targets.BackendTLS, settings)
+		}
+	}
+	if options.Resolution == ir.BackendResolutionEndpointSlice {
+		targets.EndpointSlices = map[types.NamespacedName][]*discoveryv1.EndpointSlice{}
+		for key := range targets.Services {
+			var endpointSlices discoveryv1.EndpointSliceList
+			if err := c.List(ctx, &endpointSlices, client.InNamespace(key.Namespace), client.MatchingLabels{discoveryv1.LabelServiceName: key.Name}); err != nil {
+				return ir.Targets{}, err
+			}
+			for i := range endpointSlices.Items {
+				targets.EndpointSlices[key] = append(targets.EndpointSlices[key], &endpointSlices.Items[i])
+			}
+		}
+	}
+	return targets, nil
+}
+
+// routeBackendObjectRefs returns the backendRefs of route, along with those
+// of the mirror filters of its rules and backendRefs.
+func routeBackendObjectRefs(route *gatewayv1.HTTPRoute) []gatewayv1.BackendObjectReference {
+	var refs []gatewayv1.BackendObjectReference
+	mirrors := func(filters []gatewayv1.HTTPRouteFilter) {
+		for _, filter := range filters {
+			if filter.Type == gatewayv1.HTTPRouteFilterRequestMirror && filter.RequestMirror != nil {
+				refs = append(refs, filter.RequestMirror.BackendRef)
+			}
+		}
+	}
+	for _, rule := range route.Spec.Rules {
+		mirrors(rule.Filters)
+		for _, backendRef := range rule.BackendRefs {
+			refs = append(refs, backendRef.BackendObjectReference)
+			mirrors(backendRef.Filters)
+		}
+	}
+	return refs
+}
+
+// listReferenceGrants returns the ReferenceGrants matching opts. It returns
+// none if the API is not installed in the cluster, or not registered in the
+// scheme of the client, so that references to other namespaces are refused.
+func listReferenceGrants(ctx context.Context, c client.Reader, opts ...client.ListOption) ([]gatewayv1beta1.ReferenceGrant, error) {
+	var list gatewayv1beta1.ReferenceGrantList
+	if err := c.List(ctx, &list, opts...); err != nil {
+		if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// resolvedRefsCondition returns the ResolvedRefs condition for a route. When
+// several backendRefs are unresolved, the reason of the first one is reported
+// and all messages are joined.
+func resolvedRefsCondition(route *gatewayv1.HTTPRoute, targets ir.Targets) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    string(gatewayv1.RouteConditionResolvedRefs),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(gatewayv1.RouteReasonResolvedRefs),
+		Message: "All references resolved",
+	}
+
+	var messages []string
+	check := func(rule string, ref gatewayv1.BackendObjectReference) {
+		_, err := ir.ResolveBackendRef(route.Namespace, ref, targets)
+		if err == nil {
+			return
+		}
+		if len(messages) == 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = string(err.Reason)
+		}
+		messages = append(messages, fmt.Sprintf("rule %s: %s", rule, err.Message))
+	}
+	for i, rule := range route.Spec.Rules {
+		name := ir.RuleName(rule, i)
+		for _, backendRef := range rule.BackendRefs {
+			check(name, backendRef.BackendObjectReference)
+			for _, filter := range backendRef.Filters {
+				if filter.Type == gatewayv1.HTTPRouteFilterRequestMirror && filter.RequestMirror != nil {
+					check(name, filter.RequestMirror.BackendRef)
+				}
+			}
+		}
+	}
+	if len(messages) > 0 {
+		condition.Message = strings.Join(messages, "; ")
+	}
+	return condition
+}
+
+// apiInstalled reports whether the cluster serves the API of an optional
+// backend kind, which must be the case before it can be watched.
+func apiInstalled(mapper meta.RESTMapper, gvk schema.GroupVersionKind) (bool, error) {
+	_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
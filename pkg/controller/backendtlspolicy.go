@@ -0,0 +1,327 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/conditions"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// backendTLSPolicyGVK is the BackendTLSPolicy, which is only reconciled in
+// experimental mode.
+var backendTLSPolicyGVK = gatewayv1.SchemeGroupVersion.WithKind("BackendTLSPolicy")
+
+// caCertificateKey is the key of the PEM bundle in the ConfigMaps referenced
+// by caCertificateRefs.
+const caCertificateKey = "ca.crt"
+
+// backendTLSSettings returns the TLS settings the proxy uses to connect to
+// the targets of a policy. It fails if a referenced CA bundle is missing or
+// holds no certificate.
+func backendTLSSettings(ctx context.Context, c client.Reader, policy *gatewayv1.BackendTLSPolicy) (*proxy.BackendTLS, *policyAcceptance, error) {
+	settings := &proxy.BackendTLS{ServerName: string(policy.Spec.Validation.Hostname)}
+	if policy.Spec.Validation.WellKnownCACertificates != nil {
+		if *policy.Spec.Validation.WellKnownCACertificates != gatewayv1.WellKnownCACertificatesSystem {
+			return nil, &policyAcceptance{
+				status:  metav1.ConditionFalse,
+				reason:  gatewayv1.PolicyReasonInvalid,
+				message: fmt.Sprintf("Unsupported wellKnownCACertificates %s", *policy.Spec.Validation.WellKnownCACertificates),
+			}, nil
+		}
+		return settings, nil, nil
+	}
+
+	for _, ref := range policy.Spec.Validation.CACertificateRefs {
+		if ref.Group != "" || ref.Kind != "ConfigMap" {
+			return nil, &policyAcceptance{
+				status:  metav1.ConditionFalse,
+				reason:  gatewayv1.BackendTLSPolicyReasonInvalidKind,
+				message: fmt.Sprintf("caCertificateRef %s: unsupported kind %s/%s", ref.Name, ref.Group, ref.Kind),
+			}, nil
+		}
+		var cm corev1.ConfigMap
+		if err := c.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: string(ref.Name)}, &cm); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, nil, err
+			}
+			return nil, &policyAcceptance{
+				status:  metav1.ConditionFalse,
+				reason:  gatewayv1.BackendTLSPolicyReasonNoValidCACertificate,
+				message: fmt.Sprintf("caCertificateRef %s: ConfigMap not found", ref.Name),
+			}, nil
+		}
+		bundle := []byte(cm.Data[caCertificateKey])
+		if !x509.NewCertPool().AppendCertsFromPEM(bundle) {
+			return nil, &policyAcceptance{
+				status:  metav1.ConditionFalse,
+				reason:  gatewayv1.BackendTLSPolicyReasonNoValidCACertificate,
+				message: fmt.Sprintf("caCertificateRef %s: no certificate found in %s", ref.Name, caCertificateKey),
+			}, nil
+		}
+		settings.CACertificates = append(settings.CACertificates, bundle...)
+		settings.CACertificates = append(settings.CACertificates, '\n')
+	}
+	return settings, nil, nil
+}
+
+// isServiceTarget reports whether targetRef refers to a Service.
+func isServiceTarget(targetRef gatewayv1.LocalPolicyTargetReferenceWithSectionName) bool {
+	return targetRef.Group == "" && targetRef.Kind == "Service"
+}
+
+// listBackendTLS returns the TLS settings of every Service targeted by a valid
+// BackendTLSPolicy matching opts. When several policies target the same
+// Service or port, the oldest one wins. It returns no settings if the API is
+// not installed.
+func listBackendTLS(ctx context.Context, c client.Reader, opts ...client.ListOption) (map[ir.BackendTLSKey]*proxy.BackendTLS, error) {
+	var policies gatewayv1.BackendTLSPolicyList
+	if err := c.List(ctx, &policies, opts...); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sortPoliciesByAge(policies.Items)
+
+	settings := map[ir.BackendTLSKey]*proxy.BackendTLS{}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		tls, invalid, err := backendTLSSettings(ctx, c, policy)
+		if err != nil {
+			return nil, err
+		}
+		if invalid != nil {
+			continue
+		}
+		for _, targetRef := range policy.Spec.TargetRefs {
+			if !isServiceTarget(targetRef) {
+				continue
+			}
+			key := ir.BackendTLSKey{Service: types.NamespacedName{Namespace: policy.Namespace, Name: string(targetRef.Name)}}
+			if targetRef.SectionName != nil {
+				key.Port = string(*targetRef.SectionName)
+			}
+			if _, exists := settings[key]; !exists {
+				settings[key] = tls
+			}
+		}
+	}
+	return settings, nil
+}
+
+// BackendTLSPolicyReconciler reports the status of BackendTLSPolicies. The
+// policies themselves are applied to the proxy by the HTTPRouteReconciler.
+type BackendTLSPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Backoff bounds the delays between retries of failed reconciles.
+	Backoff Backoff
+
+	// statusWrites limits how often the status of each policy is written.
+	statusWrites statusWriteLimiter
+}
+
+func (r *BackendTLSPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var policy gatewayv1.BackendTLSPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	accepted := policyAccepted
+	_, invalid, err := backendTLSSettings(ctx, r.Client, &policy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if invalid != nil {
+		accepted = *invalid
+	}
+
+	ancestors, err := r.ancestorStatuses(ctx, &policy, accepted)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := policy.DeepCopy()
+	policy.Status.Ancestors = ancestors
+	if err := patchStatus(ctx, r.Client, &r.statusWrites, &policy, original); err != nil {
+		return statusWriteFailed(l, err, "unable to update BackendTLSPolicy status")
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// ancestorStatuses reports the policy against the Gateways of the HTTPRoutes
+// that reference its target Services, or against a target itself if no route
+// references it. Entries written by other controllers are preserved.
+func (r *BackendTLSPolicyReconciler) ancestorStatuses(ctx context.Context, policy *gatewayv1.BackendTLSPolicy, accepted policyAcceptance) ([]gatewayv1.PolicyAncestorStatus, error) {
+	controllerName := controllerNameOrDefault(r.ControllerName)
+	var ancestors []gatewayv1.PolicyAncestorStatus
+	for _, ancestor := range policy.Status.Ancestors {
+		if ancestor.ControllerName != controllerName {
+			ancestors = append(ancestors, ancestor)
+		}
+	}
+
+	var routes gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &routes, client.InNamespace(policy.Namespace)); err != nil {
+		return nil, err
+	}
+
+	seen := map[types.NamespacedName]bool{}
+	for _, targetRef := range policy.Spec.TargetRefs {
+		result := accepted
+		service := types.NamespacedName{Namespace: policy.Namespace, Name: string(targetRef.Name)}
+		var ancestorRefs []gatewayv1.ParentReference
+		if !isServiceTarget(targetRef) {
+			result = policyAcceptance{
+				status:  metav1.ConditionFalse,
+				reason:  gatewayv1.PolicyReasonInvalid,
+				message: fmt.Sprintf("Unsupported target kind %s/%s", targetRef.Group, targetRef.Kind),
+			}
+		} else {
+			for i := range routes.Items {
+				if !routeReferencesService(&routes.Items[i], service) {
+					continue
+				}
+				for _, gw := range ir.RouteGateways(&routes.Items[i]) {
+					if seen[gw] {
+						continue
+					}
+					seen[gw] = true
+					ancestorRefs = append(ancestorRefs, gatewayv1.ParentReference{
+						Group:     ptr(gatewayv1.Group(gatewayv1.GroupName)),
+						Kind:      ptr(kindGateway),
+						Namespace: ptr(gatewayv1.Namespace(gw.Namespace)),
+						Name:      gatewayv1.ObjectName(gw.Name),
+					})
+				}
+			}
+		}
+		if len(ancestorRefs) == 0 {
+			ancestorRefs = append(ancestorRefs, gatewayv1.ParentReference{
+				Group:     ptr(targetRef.Group),
+				Kind:      ptr(targetRef.Kind),
+				Namespace: ptr(gatewayv1.Namespace(policy.Namespace)),
+				Name:      targetRef.Name,
+			})
+		}
+
+		for _, ancestorRef := range ancestorRefs {
+			var previous []metav1.Condition
+			for _, ancestor := range policy.Status.Ancestors {
+				if ancestor.ControllerName == controllerName && equality.Semantic.DeepEqual(ancestor.AncestorRef, ancestorRef) {
+					previous = ancestor.Conditions
+				}
+			}
+			ancestorConditions, _ := conditions.Merge(previous, policy.Generation, metav1.Condition{
+				Type:    string(gatewayv1.PolicyConditionAccepted),
+				Status:  result.status,
+				Reason:  string(result.reason),
+				Message: result.message,
+			})
+			ancestors = append(ancestors, gatewayv1.PolicyAncestorStatus{
+				AncestorRef:    ancestorRef,
+				ControllerName: controllerName,
+				Conditions:     ancestorConditions,
+			})
+		}
+	}
+	return ancestors, nil
+}
+
+func (r *BackendTLSPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.BackendTLSPolicy{}, builder.WithPredicates(specChanged)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapToPolicies)).
+		Watches(&gatewayv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.mapToPolicies), builder.WithPredicates(specChanged)).
+		WithOptions(r.Backoff.options()).
+		Complete(r)
+}
+
+// mapToPolicies enqueues every BackendTLSPolicy in the namespace of the
+// changed object, since any of them may reference it.
+func (r *BackendTLSPolicyReconciler) mapToPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
+	var policies gatewayv1.BackendTLSPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list BackendTLSPolicies")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+	}
+	return requests
+}
+
+// mapBackendTLSPolicyToRoutes enqueues the HTTPRoutes with a backendRef to a
+// Service targeted by the changed BackendTLSPolicy.
+func (r *HTTPRouteReconciler) mapBackendTLSPolicyToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*gatewayv1.BackendTLSPolicy)
+	if !ok {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, targetRef := range policy.Spec.TargetRefs {
+		if isServiceTarget(targetRef) {
+			requests = append(requests, r.routesReferencingBackend(ctx, "", "Service", types.NamespacedName{Namespace: policy.Namespace, Name: string(targetRef.Name)})...)
+		}
+	}
+	return requests
+}
+
+// mapCABundleToRoutes enqueues the HTTPRoutes affected by a change to a
+// ConfigMap holding the CA bundle of a BackendTLSPolicy.
+func (r *HTTPRouteReconciler) mapCABundleToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	var policies gatewayv1.BackendTLSPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list BackendTLSPolicies")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range policies.Items {
+		for _, ref := range policies.Items[i].Spec.Validation.CACertificateRefs {
+			if ref.Kind == "ConfigMap" && string(ref.Name) == obj.GetName() {
+				requests = append(requests, r.mapBackendTLSPolicyToRoutes(ctx, &policies.Items[i])...)
+				break
+			}
+		}
+	}
+	return requests
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// testCACertificate returns a self-signed CA certificate in PEM form.
+func testCACertificate(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestListBackendTLS(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	ca := testCACertificate(t)
+	older := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	newPolicy := func(name string, created metav1.Time, target gatewayv1.ObjectName, section *gatewayv1.SectionName, validation gatewayv1.BackendTLSPolicyValidation) *gatewayv1.BackendTLSPolicy {
+		return &gatewayv1.BackendTLSPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: created},
+			Spec: gatewayv1.BackendTLSPolicySpec{
+				TargetRefs: []gatewayv1.LocalPolicyTargetReferenceWithSectionName{{
+					LocalPolicyTargetReference: gatewayv1.LocalPolicyTargetReference{Kind: "Service", Name: target},
+					SectionName:                section,
+				}},
+				Validation: validation,
+			},
+		}
+	}
+	system := gatewayv1.BackendTLSPolicyValidation{WellKnownCACertificates: ptr(gatewayv1.WellKnownCACertificatesSystem), Hostname: "api.example.com"}
+	configMapCA := func(name gatewayv1.ObjectName) gatewayv1.BackendTLSPolicyValidation {
+		return gatewayv1.BackendTLSPolicyValidation{
+			CACertificateRefs: []gatewayv1.LocalObjectReference{{Kind: "ConfigMap", Name: name}},
+			Hostname:          "internal.example.com",
+		}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ca"}, Data: map[string]string{"ca.crt": ca}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "empty"}},
+		newPolicy("system", older, "api", nil, system),
+		newPolicy("newer", metav1.NewTime(older.Add(time.Hour)), "api", nil, configMapCA("ca")),
+		newPolicy("port", older, "api", ptr(gatewayv1.SectionName("grpc")), configMapCA("ca")),
+		newPolicy("invalid", older, "other", nil, configMapCA("empty")),
+	).Build()
+
+	settings, err := listBackendTLS(context.Background(), c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[ir.BackendTLSKey]*proxy.BackendTLS{
+		{Service: types.NamespacedName{Namespace: "default", Name: "api"}}:               {ServerName: "api.example.com"},
+		{Service: types.NamespacedName{Namespace: "default", Name: "api"}, Port: "grpc"}: {ServerName: "internal.example.com", CACertificates: []byte(ca + "\n")},
+	}
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("expected %v, got %v", expected, settings)
+	}
+
+}
+
+func TestBackendTLSPolicyStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	policy := &gatewayv1.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tls", Generation: 1},
+		Spec: gatewayv1.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1.LocalPolicyTargetReferenceWithSectionName{{
+				LocalPolicyTargetReference: gatewayv1.LocalPolicyTargetReference{Kind: "Service", Name: "api"},
+			}},
+			Validation: gatewayv1.BackendTLSPolicyValidation{
+				CACertificateRefs: []gatewayv1.LocalObjectReference{{Kind: "ConfigMap", Name: "missing"}},
+				Hostname:          "api.example.com",
+			},
+		},
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}}},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{Name: "api", Port: ptr(gatewayv1.PortNumber(443))},
+				}}},
+			}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(policy).WithObjects(policy, route).Build()
+
+	r := &BackendTLSPolicyReconciler{Client: c, Scheme: scheme}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(policy)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual gatewayv1.BackendTLSPolicy
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(policy), &actual); err != nil {
+		t.Fatalf("unable to get BackendTLSPolicy: %v", err)
+	}
+	if len(actual.Status.Ancestors) != 1 {
+		t.Fatalf("expected one ancestor, got %v", actual.Status.Ancestors)
+	}
+	ancestor := actual.Status.Ancestors[0]
+	if ancestor.AncestorRef.Name != "gw" || *ancestor.AncestorRef.Namespace != "default" {
+		t.Errorf("expected the route's Gateway as ancestor, got %v", ancestor.AncestorRef)
+	}
+	condition := ancestor.Conditions[0]
+	if condition.Status != metav1.ConditionFalse || condition.Reason != string(gatewayv1.BackendTLSPolicyReasonNoValidCACertificate) {
+		t.Errorf("expected Accepted=False with reason NoValidCACertificate, got %v", condition)
+	}
+}
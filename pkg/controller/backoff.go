@@ -0,0 +1,102 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	runtimecontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Default bounds of the delay between retries of an object, whether its
+// reconcile failed or it is waiting on another one, such as a Gateway waiting
+// for its Service to get an address.
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 5 * time.Minute
+)
+
+// Backoff bounds the delays between retries of the objects of a reconciler:
+// each retry of an object doubles its delay, from BaseDelay up to MaxDelay.
+// Zero fields take the defaults.
+type Backoff struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// delays returns the bounds of b, with the defaults filled in.
+func (b Backoff) delays() (baseDelay, maxDelay time.Duration) {
+	baseDelay, maxDelay = b.BaseDelay, b.MaxDelay
+	if baseDelay <= 0 {
+		baseDelay = retryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = retryMaxDelay
+	}
+	return baseDelay, max(baseDelay, maxDelay)
+}
+
+// options returns the options of a controller whose failed reconciles are
+// retried with b. As with the default rate limiter of controller-runtime, an
+// overall limit keeps a burst of failures from flooding the API server.
+func (b Backoff) options() runtimecontroller.Options {
+	baseDelay, maxDelay := b.delays()
+	return runtimecontroller.Options{
+		RateLimiter: workqueue.NewTypedMaxOfRateLimiter(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseDelay, maxDelay),
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+		),
+	}
+}
+
+// retryBackoff computes exponentially growing retry delays per object. The
+// zero value is ready to use.
+type retryBackoff struct {
+	mu       sync.Mutex
+	attempts map[types.NamespacedName]int
+}
+
+// next returns the delay before the next retry for key, bounded by b, and
+// records the attempt.
+func (r *retryBackoff) next(key types.NamespacedName, b Backoff) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.attempts == nil {
+		r.attempts = map[types.NamespacedName]int{}
+	}
+	attempt := r.attempts[key]
+	r.attempts[key] = attempt + 1
+
+	delay, maxDelay := b.delays()
+	for range attempt {
+		delay *= 2
+		if delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
+
+// reset forgets the attempts for key, once it no longer needs retrying.
+func (r *retryBackoff) reset(key types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, key)
+}
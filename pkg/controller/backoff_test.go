@@ -0,0 +1,68 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	var b retryBackoff
+	key := types.NamespacedName{Namespace: "default", Name: "gw"}
+	other := types.NamespacedName{Namespace: "default", Name: "other"}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+	for i, delay := range expected {
+		if actual := b.next(key, Backoff{}); actual != delay {
+			t.Errorf("attempt %d: expected %v, got %v", i, delay, actual)
+		}
+	}
+	if actual := b.next(other, Backoff{}); actual != time.Second {
+		t.Errorf("expected other keys to start at %v, got %v", time.Second, actual)
+	}
+
+	for range 20 {
+		b.next(key, Backoff{})
+	}
+	if actual := b.next(key, Backoff{}); actual != retryMaxDelay {
+		t.Errorf("expected delay capped at %v, got %v", retryMaxDelay, actual)
+	}
+
+	b.reset(key)
+	if actual := b.next(key, Backoff{}); actual != time.Second {
+		t.Errorf("expected %v after reset, got %v", time.Second, actual)
+	}
+}
+
+func TestRetryBackoffBounds(t *testing.T) {
+	var b retryBackoff
+	key := types.NamespacedName{Namespace: "default", Name: "gw"}
+	bounds := Backoff{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, delay := range expected {
+		if actual := b.next(key, bounds); actual != delay {
+			t.Errorf("attempt %d: expected %v, got %v", i, delay, actual)
+		}
+	}
+
+	// A maximum below the base delay is raised to it.
+	if base, maxDelay := (Backoff{BaseDelay: time.Minute, MaxDelay: time.Second}).delays(); base != time.Minute || maxDelay != time.Minute {
+		t.Errorf("expected delays of 1m, got %v and %v", base, maxDelay)
+	}
+}
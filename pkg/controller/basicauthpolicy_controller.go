@@ -0,0 +1,176 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const defaultBasicAuthRealm = "gateway"
+
+// BasicAuthPolicyReconciler validates BasicAuthPolicies and reports the result
+// in their status. The policies themselves are applied to the proxy by the
+// HTTPRouteReconciler.
+type BasicAuthPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Backoff bounds the delays between retries of failed reconciles.
+	Backoff Backoff
+
+	// statusWrites limits how often the status of each policy is written.
+	statusWrites statusWriteLimiter
+}
+
+func (r *BasicAuthPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var policy v1alpha1.BasicAuthPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	accepted := policyAccepted
+	if _, err := resolveBasicAuth(ctx, r.Client, &policy); err != nil {
+		accepted = policyAcceptance{
+			status:  metav1.ConditionFalse,
+			reason:  gatewayv1.PolicyReasonInvalid,
+			message: fmt.Sprintf("Invalid policy: %v", err),
+		}
+	}
+
+	ancestors, err := policyAncestorStatuses(ctx, r.Client, controllerNameOrDefault(r.ControllerName), &policy, policy.Spec.TargetRefs, []gatewayv1.Kind{kindHTTPRoute}, policy.Status.Ancestors, accepted)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := policy.DeepCopy()
+	policy.Status.Ancestors = ancestors
+	if err := patchStatus(ctx, r.Client, &r.statusWrites, &policy, original); err != nil {
+		return statusWriteFailed(l, err, "unable to update BasicAuthPolicy status")
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *BasicAuthPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.BasicAuthPolicy{}, builder.WithPredicates(specChanged)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.mapToPolicies), builder.OnlyMetadata).
+		Watches(&gatewayv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.mapToPolicies), builder.WithPredicates(specChanged)).
+		WithOptions(r.Backoff.options()).
+		Complete(r)
+}
+
+// mapToPolicies enqueues every BasicAuthPolicy in the namespace of the changed
+// object, since any of them may reference it.
+func (r *BasicAuthPolicyReconciler) mapToPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
+	var policies v1alpha1.BasicAuthPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list BasicAuthPolicies")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+	}
+	return requests
+}
+
+// resolveBasicAuth loads the credentials referenced by the policy.
+func resolveBasicAuth(ctx context.Context, c client.Reader, policy *v1alpha1.BasicAuthPolicy) (*proxy.BasicAuth, error) {
+	ref := policy.Spec.SecretRef
+	if ref.Group != nil && *ref.Group != "" {
+		return nil, fmt.Errorf("secretRef group must be empty, got %q", *ref.Group)
+	}
+	if ref.Kind != nil && *ref.Kind != "Secret" {
+		return nil, fmt.Errorf("secretRef kind must be Secret, got %q", *ref.Kind)
+	}
+	if ref.Namespace != nil && string(*ref.Namespace) != policy.Namespace {
+		return nil, fmt.Errorf("secretRef must be in the same namespace as the policy")
+	}
+
+	var secret corev1.Secret
+	if err := c.Get(ctx, client.ObjectKey{Namespace: policy.Namespace, Name: string(ref.Name)}, &secret); err != nil {
+		return nil, fmt.Errorf("unable to fetch Secret %s: %w", ref.Name, err)
+	}
+	data, ok := secret.Data[v1alpha1.BasicAuthPolicySecretKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no %q key", ref.Name, v1alpha1.BasicAuthPolicySecretKey)
+	}
+	users, err := proxy.ParseHtpasswd(data)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s: %w", ref.Name, err)
+	}
+
+	realm := defaultBasicAuthRealm
+	if policy.Spec.Realm != nil {
+		realm = *policy.Spec.Realm
+	}
+	return &proxy.BasicAuth{Realm: realm, Users: users}, nil
+}
+
+// basicAuthForRoutes computes the BasicAuth configuration for every HTTPRoute
+// targeted by a BasicAuthPolicy matching opts. When several policies target
+// the same route, the oldest one wins. A policy whose credentials cannot be
+// resolved still protects its targets, rejecting every request rather than
+// failing open.
+func basicAuthForRoutes(ctx context.Context, c client.Reader, opts ...client.ListOption) (map[types.NamespacedName]*proxy.BasicAuth, error) {
+	l := log.FromContext(ctx)
+
+	var policies v1alpha1.BasicAuthPolicyList
+	if err := c.List(ctx, &policies, opts...); err != nil {
+		return nil, err
+	}
+	sortPoliciesByAge(policies.Items)
+
+	result := map[types.NamespacedName]*proxy.BasicAuth{}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		auth, err := resolveBasicAuth(ctx, c, policy)
+		if err != nil {
+			l.Error(err, "unable to resolve BasicAuthPolicy, denying all requests", "policy", client.ObjectKeyFromObject(policy))
+			auth = &proxy.BasicAuth{Realm: defaultBasicAuthRealm}
+		}
+		for _, targetRef := range policy.Spec.TargetRefs {
+			if !isPolicyTarget(targetRef, kindHTTPRoute) {
+				continue
+			}
+			key := types.NamespacedName{Namespace: policy.Namespace, Name: string(targetRef.Name)}
+			if _, exists := result[key]; !exists {
+				result[key] = auth
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CacheOptions returns the options of the manager's cache, which keep the
+// controller's memory from growing with objects it does not serve:
+//
+//   - managedFields, which are never read, are stripped from every object.
+//   - Pods, which are only watched for InferencePool endpoints and the
+//     provisioned proxies, keep only the fields that decide whether and
+//     where they serve.
+//   - Nodes, which are only watched for the addresses of Gateways exposed on
+//     node ports, keep only their addresses and readiness.
+//
+// Services cannot be selected by the routes referencing them, so they are
+// cached whole. ConfigMaps are cached whole as well, in every namespace: they
+// are created by users, without a label to select them by, and referenced by
+// name from GatewayClasses, Gateways, ExtensionRef filters and
+// BackendTLSPolicies. Unlike the Secrets, which are only read when a route or
+// listener referencing them is reconciled, they are read whenever the route
+// table is rebuilt, for the parameters of every class, the CA bundles of
+// every BackendTLSPolicy and the Wasm module of every ExtensionRef filter, so
+// reading them from the API server would turn each rebuild into as many
+// requests. Restricting the namespaces bounds them, as it does every other
+// namespaced object. Secrets are not cached at all; see ClientOptions.
+//
+// If namespaces is not empty, only the namespaced objects in those namespaces
+// are cached, and so reconciled; see NewClientFunc.
+func CacheOptions(namespaces []string) cache.Options {
+	opts := cache.Options{
+		DefaultTransform: cache.TransformStripManagedFields(),
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}:  {Transform: stripPod},
+			&corev1.Node{}: {Transform: stripNode},
+		},
+	}
+	if len(namespaces) > 0 {
+		opts.DefaultNamespaces = make(map[string]cache.Config, len(namespaces))
+		for _, ns := range namespaces {
+			opts.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+	return opts
+}
+
+// ClientOptions returns the options of the manager's client. Secrets are read
+// from the API server instead of the cache, since only the few referenced by
+// listeners and policies are needed; the reconcilers watch them for their
+// metadata only, to be told when they change.
+func ClientOptions() client.Options {
+	return client.Options{
+		Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}}},
+	}
+}
+
+// NewClientFunc returns the function creating the manager's client. If
+// namespaces is not empty, the client treats the namespaced objects outside
+// those namespaces as missing, so that the references of the objects being
+// reconciled to objects in other namespaces fail to resolve instead of
+// erroring on the restricted cache, and Secrets, which are not cached, are
+// not read from other namespaces either.
+func NewClientFunc(namespaces []string) client.NewClientFunc {
+	return func(config *rest.Config, options client.Options) (client.Client, error) {
+		c, err := client.New(config, options)
+		if err != nil || len(namespaces) == 0 {
+			return c, err
+		}
+		return restrictToNamespaces(c, namespaces), nil
+	}
+}
+
+// namespaceRestrictedClient is a client that only reads the namespaced
+// objects of the given namespaces.
+type namespaceRestrictedClient struct {
+	client.Client
+	namespaces map[string]bool
+}
+
+func restrictToNamespaces(c client.Client, namespaces []string) client.Client {
+	r := &namespaceRestrictedClient{Client: c, namespaces: make(map[string]bool, len(namespaces))}
+	for _, ns := range namespaces {
+		r.namespaces[ns] = true
+	}
+	return r
+}
+
+func (c *namespaceRestrictedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if key.Namespace != "" && !c.namespaces[key.Namespace] {
+		var resource schema.GroupResource
+		if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+			if mapping, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+				resource = mapping.Resource.GroupResource()
+			}
+		}
+		return apierrors.NewNotFound(resource, key.Name)
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *namespaceRestrictedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	var listOpts client.ListOptions
+	listOpts.ApplyOptions(opts)
+	if listOpts.Namespace != "" && !c.namespaces[listOpts.Namespace] {
+		return nil
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+// stripPod keeps only the fields of a Pod read by podReady,
+// podEndpointChanged and dataPlaneAddresses.
+func stripPod(obj any) (any, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return obj, nil
+	}
+	stripped := &corev1.Pod{
+		TypeMeta: pod.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         pod.Namespace,
+			Name:              pod.Name,
+			UID:               pod.UID,
+			ResourceVersion:   pod.ResourceVersion,
+			Labels:            pod.Labels,
+			DeletionTimestamp: pod.DeletionTimestamp,
+		},
+		Status: corev1.PodStatus{PodIP: pod.Status.PodIP, HostIP: pod.Status.HostIP},
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			stripped.Status.Conditions = append(stripped.Status.Conditions, c)
+		}
+	}
+	return stripped, nil
+}
+
+// stripNode keeps only the fields of a Node read by nodeAddress and
+// nodeReady.
+func stripNode(obj any) (any, error) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return obj, nil
+	}
+	stripped := &corev1.Node{
+		TypeMeta: node.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            node.Name,
+			UID:             node.UID,
+			ResourceVersion: node.ResourceVersion,
+		},
+		Status: corev1.NodeStatus{Addresses: node.Status.Addresses},
+	}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			stripped.Status.Conditions = append(stripped.Status.Conditions, c)
+		}
+	}
+	return stripped, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStripPod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:     "default",
+			Name:          "model-server",
+			Labels:        map[string]string{"app": "model"},
+			Annotations:   map[string]string{"example.com/large": "value"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "server", Image: "model:latest"}}},
+		Status: corev1.PodStatus{
+			PodIP: "10.0.0.1",
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+
+	obj, err := stripPod(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stripped := obj.(*corev1.Pod)
+	if !podReady(stripped) || stripped.Status.PodIP != "10.0.0.1" || stripped.Labels["app"] != "model" {
+		t.Errorf("expected the Pod's readiness, IP and labels to be kept, got %+v", stripped)
+	}
+	if len(stripped.Spec.Containers) != 0 || stripped.Annotations != nil || stripped.ManagedFields != nil {
+		t.Errorf("expected the Pod's spec, annotations and managedFields to be dropped, got %+v", stripped)
+	}
+	if len(stripped.Status.Conditions) != 1 {
+		t.Errorf("expected only the Ready condition to be kept, got %v", stripped.Status.Conditions)
+	}
+
+	other := &corev1.Service{}
+	if obj, _ := stripPod(other); obj != other {
+		t.Errorf("expected objects other than Pods to be left as they are")
+	}
+}
+
+func TestStripNode(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"zone": "a"}},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.0.1"}},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+			Images: []corev1.ContainerImage{{Names: []string{"proxy:latest"}}},
+		},
+	}
+
+	obj, err := stripNode(node)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stripped := obj.(*corev1.Node)
+	if !nodeReady(stripped) || nodeAddress(stripped) != "10.0.0.1" {
+		t.Errorf("expected the Node's readiness and address to be kept, got %+v", stripped)
+	}
+	if stripped.Labels != nil || stripped.Status.Images != nil || len(stripped.Status.Conditions) != 1 {
+		t.Errorf("expected the Node's other fields to be dropped, got %+v", stripped)
+	}
+}
+
+func TestRestrictToNamespaces(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	c := restrictToNamespaces(fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "cert"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "cert"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+	).Build(), []string{"tenant"})
+
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "tenant", Name: "cert"}, &corev1.Secret{}); err != nil {
+		t.Errorf("expected objects in watched namespaces to be read, got %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "other", Name: "cert"}, &corev1.Secret{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected objects in other namespaces to be missing, got %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "other"}, &corev1.Namespace{}); err != nil {
+		t.Errorf("expected cluster-scoped objects to be read, got %v", err)
+	}
+
+	var secrets corev1.SecretList
+	if err := c.List(ctx, &secrets, client.InNamespace("other")); err != nil || len(secrets.Items) != 0 {
+		t.Errorf("expected no objects to be listed in other namespaces, got %v, %v", secrets.Items, err)
+	}
+	if err := c.List(ctx, &secrets, client.InNamespace("tenant")); err != nil || len(secrets.Items) != 1 {
+		t.Errorf("expected the objects of watched namespaces to be listed, got %v, %v", secrets.Items, err)
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The computed configuration of a Gateway is the part of the route table
+// served for it: the translation of the HTTPRoutes it accepted, along with the
+// built-in routes served for every Gateway. Routes translated from Ingresses
+// belong to no Gateway and are left out. ComputedConfigPublisher writes it to
+// a ConfigMap owned by each Gateway, so that what the data plane serves can be
+// read with kubectl.
+
+// computedConfigSuffix is appended to the name of a Gateway to name the
+// ConfigMap holding its computed configuration.
+const computedConfigSuffix = "-computed-config"
+
+// Keys of the computed configuration ConfigMaps.
+const (
+	// computedConfigSnapshotKey identifies the content of the routes, by a
+	// hash of their encoding.
+	computedConfigSnapshotKey = "snapshot"
+	// computedConfigRoutesKey holds the routes, encoded as they are streamed
+	// to standalone proxies, with the password digests of basic auth left
+	// out.
+	computedConfigRoutesKey = "routes.json"
+	// computedConfigBackendsKey lists the host:port of every backend the
+	// routes forward to, one per line.
+	computedConfigBackendsKey = "backends"
+)
+
+// computedConfigRetryDelay is how long the publisher waits before trying again
+// after failing to write the ConfigMaps.
+const computedConfigRetryDelay = 10 * time.Second
+
+// ComputedConfigPublisher is a RouteSink writing the routes served for each
+// Gateway to a ConfigMap in the Gateway's namespace whenever they change. It
+// runs on the leader only.
+type ComputedConfigPublisher struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Shard restricts the publisher to the Gateways of a shard; see
+	// GatewayReconciler.
+	Shard string
+
+	mu sync.Mutex
+	// routes holds every route served, and synced is set once the whole table
+	// has been received, so that partial tables are not published.
+	routes map[proxy.RouteKey]proxy.HTTPRoute
+	synced bool
+	// changed is signalled when routes changed since they were last
+	// published.
+	changed chan struct{}
+}
+
+// NewComputedConfigPublisher returns a publisher writing with c.
+func NewComputedConfigPublisher(c client.Client, scheme *runtime.Scheme, controllerName gatewayv1.GatewayController, shard string) *ComputedConfigPublisher {
+	return &ComputedConfigPublisher{
+		Client:         c,
+		Scheme:         scheme,
+		ControllerName: controllerName,
+		Shard:          shard,
+		routes:         map[proxy.RouteKey]proxy.HTTPRoute{},
+		changed:        make(chan struct{}, 1),
+	}
+}
+
+// UpdateRoutes replaces every route.
+func (p *ComputedConfigPublisher) UpdateRoutes(routes []proxy.HTTPRoute) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.routes = make(map[proxy.RouteKey]proxy.HTTPRoute, len(routes))
+	for _, route := range routes {
+		p.routes[proxy.RouteKey{Namespace: route.Namespace, Name: route.Name}] = route
+	}
+	p.synced = true
+	p.signal()
+}
+
+// ApplyRouteChanges replaces the changed routes, and removes those whose
+// change is nil.
+func (p *ComputedConfigPublisher) ApplyRouteChanges(changes map[proxy.RouteKey]*proxy.HTTPRoute) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, route := range changes {
+		if route != nil {
+			p.routes[key] = *route
+		} else {
+			delete(p.routes, key)
+		}
+	}
+	p.signal()
+}
+
+func (p *ComputedConfigPublisher) signal() {
+	select {
+	case p.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Start publishes the routes whenever they change, until ctx is cancelled.
+func (p *ComputedConfigPublisher) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("computed-config")
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.changed:
+		case <-retry:
+		}
+		retry = nil
+		if err := p.publish(ctx); err != nil {
+			l.Error(err, "unable to publish the computed configuration, retrying", "delay", computedConfigRetryDelay)
+			retry = time.After(computedConfigRetryDelay)
+		}
+	}
+}
+
+// publish writes the computed configuration of every Gateway of our classes.
+func (p *ComputedConfigPublisher) publish(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.synced {
+		p.mu.Unlock()
+		return nil
+	}
+	routes := make(map[proxy.RouteKey]proxy.HTTPRoute, len(p.routes))
+	for key, route := range p.routes {
+		routes[key] = route
+	}
+	p.mu.Unlock()
+
+	var classes gatewayv1.GatewayClassList
+	if err := p.List(ctx, &classes); err != nil {
+		return err
+	}
+	ours := map[gatewayv1.ObjectName]bool{}
+	for _, gc := range classes.Items {
+		if gc.Spec.ControllerName == controllerNameOrDefault(p.ControllerName) {
+			ours[gatewayv1.ObjectName(gc.Name)] = true
+		}
+	}
+	var gateways gatewayv1.GatewayList
+	if err := p.List(ctx, &gateways); err != nil {
+		return err
+	}
+	var errs []error
+	for i := range gateways.Items {
+		gw := &gateways.Items[i]
+		if !ours[gw.Spec.GatewayClassName] || !ir.InShard(gw, p.Shard) || !gw.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := p.publishGateway(ctx, gw, routes); err != nil {
+			errs = append(errs, fmt.Errorf("gateway %s: %w", client.ObjectKeyFromObject(gw), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// publishGateway writes the computed configuration of gw, from every route
+// served.
+func (p *ComputedConfigPublisher) publishGateway(ctx context.Context, gw *gatewayv1.Gateway, routes map[proxy.RouteKey]proxy.HTTPRoute) error {
+	served, err := p.gatewayRoutes(ctx, gw, routes)
+	if err != nil {
+		return err
+	}
+	data, err := computedConfigData(served)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{}
+	cm.Namespace = gw.Namespace
+	cm.Name = dnsLabel(gw.Name + computedConfigSuffix)
+	_, err = controllerutil.CreateOrUpdate(ctx, p.Client, cm, func() error {
+		if cm.Labels == nil {
+			cm.Labels = map[string]string{}
+		}
+		cm.Labels[gatewayNameLabel] = gw.Name
+		cm.Labels[managedByLabel] = FieldManager
+		cm.Data = data
+		return controllerutil.SetControllerReference(gw, cm, p.Scheme)
+	})
+	return err
+}
+
+// gatewayRoutes returns the routes served for gw among routes, in the order of
+// their keys: those of the HTTPRoutes accepted by gw, and the built-in ones.
+func (p *ComputedConfigPublisher) gatewayRoutes(ctx context.Context, gw *gatewayv1.Gateway, routes map[proxy.RouteKey]proxy.HTTPRoute) ([]proxy.HTTPRoute, error) {
+	var attached gatewayv1.HTTPRouteList
+	if err := p.List(ctx, &attached, client.MatchingFields{indexRouteParentGateways: client.ObjectKeyFromObject(gw).String()}); err != nil {
+		return nil, err
+	}
+	keys := map[proxy.RouteKey]bool{}
+	controllerName := controllerNameOrDefault(p.ControllerName)
+	for i := range attached.Items {
+		route := &attached.Items[i]
+		for _, ps := range route.Status.Parents {
+			if ps.ControllerName == controllerName && ir.ParentGateway(route.Namespace, ps.ParentRef) == client.ObjectKeyFromObject(gw) &&
+				meta.IsStatusConditionTrue(ps.Conditions, string(gatewayv1.RouteConditionAccepted)) {
+				keys[proxy.RouteKey{Namespace: route.Namespace, Name: route.Name}] = true
+			}
+		}
+	}
+	var served []proxy.HTTPRoute
+	for key, route := range routes {
+		if keys[key] || route.Builtin {
+			served = append(served, route)
+		}
+	}
+	slices.SortFunc(served, func(a, b proxy.HTTPRoute) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	return served, nil
+}
+
+// computedConfigData returns the content of the ConfigMap holding routes.
+func computedConfigData(routes []proxy.HTTPRoute) (map[string]string, error) {
+	var backends []string
+	for i := range routes {
+		if routes[i].BasicAuth != nil {
+			// Digests of passwords do not belong in a ConfigMap.
+			basicAuth := &proxy.BasicAuth{Realm: routes[i].BasicAuth.Realm, Users: map[string]*proxy.PasswordHash{}}
+			for user := range routes[i].BasicAuth.Users {
+				basicAuth.Users[user] = nil
+			}
+			routes[i].BasicAuth = basicAuth
+		}
+		for _, rule := range routes[i].Rules {
+			for _, backend := range rule.Backends {
+				switch {
+				case backend.Invalid:
+				case backend.EndpointPicker != nil:
+					backends = append(backends, backend.EndpointPicker.Endpoints...)
+				case len(backend.Endpoints) > 0:
+					backends = append(backends, backend.Endpoints...)
+				default:
+					backends = append(backends, net.JoinHostPort(backend.Host, strconv.Itoa(int(backend.Port))))
+				}
+			}
+		}
+	}
+	slices.Sort(backends)
+	backends = slices.Compact(backends)
+
+	encoded, err := json.Marshal(routes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode routes: %w", err)
+	}
+	if routes == nil {
+		encoded = []byte("[]")
+	}
+	h := sha256.Sum256(encoded)
+	return map[string]string{
+		computedConfigSnapshotKey: hex.EncodeToString(h[:])[:16],
+		computedConfigRoutesKey:   string(encoded),
+		computedConfigBackendsKey: strings.Join(backends, "\n"),
+	}, nil
+}
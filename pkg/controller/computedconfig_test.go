@@ -0,0 +1,123 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestPublishComputedConfig(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gc := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+		Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+	}
+	newGateway := func(name string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: name, UID: types.UID(name)},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "ours"},
+		}
+	}
+	parentRef := gatewayv1.ParentReference{Name: "gw"}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+		Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{parentRef}}},
+		Status: gatewayv1.HTTPRouteStatus{RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{{
+			ParentRef:      parentRef,
+			ControllerName: DefaultControllerName,
+			Conditions:     []metav1.Condition{{Type: string(gatewayv1.RouteConditionAccepted), Status: metav1.ConditionTrue}},
+		}}}},
+	}
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithObjects(gc, newGateway("gw"), newGateway("idle"), route).Build()
+	p := NewComputedConfigPublisher(c, scheme, "", "")
+
+	p.UpdateRoutes([]proxy.HTTPRoute{
+		{
+			Namespace: "apps",
+			Name:      "web",
+			Rules:     []proxy.RouteRule{{Name: "0", Backends: []proxy.WeightedBackend{{Backend: proxy.Backend{Host: "web.apps.svc.cluster.local", Port: 8080}}}}},
+			BasicAuth: &proxy.BasicAuth{Realm: "web", Users: map[string]*proxy.PasswordHash{"alice": {Scheme: proxy.PasswordSchemeSHA, Hash: []byte("digest")}}},
+		},
+		{Namespace: "apps", Name: "unattached"},
+		{Name: acmeSolverRouteName, Builtin: true},
+	})
+	if err := p.publish(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "apps", Name: "gw-computed-config"}, &cm); err != nil {
+		t.Fatalf("unable to get ConfigMap: %v", err)
+	}
+	if !metav1.IsControlledBy(&cm, newGateway("gw")) {
+		t.Errorf("expected the ConfigMap to be owned by the Gateway, got %v", cm.OwnerReferences)
+	}
+	var routes []proxy.HTTPRoute
+	if err := json.Unmarshal([]byte(cm.Data[computedConfigRoutesKey]), &routes); err != nil {
+		t.Fatalf("unable to decode routes: %v", err)
+	}
+	if len(routes) != 2 || routes[0].Name != acmeSolverRouteName || routes[1].Name != "web" {
+		t.Fatalf("expected the built-in route and the accepted route, got %+v", routes)
+	}
+	if digest, ok := routes[1].BasicAuth.Users["alice"]; !ok || digest != nil {
+		t.Errorf("expected the user to be listed without its digest, got %v", routes[1].BasicAuth.Users)
+	}
+	if expected := "web.apps.svc.cluster.local:8080"; cm.Data[computedConfigBackendsKey] != expected {
+		t.Errorf("expected backends %q, got %q", expected, cm.Data[computedConfigBackendsKey])
+	}
+	snapshot := cm.Data[computedConfigSnapshotKey]
+	if snapshot == "" {
+		t.Errorf("expected a snapshot")
+	}
+
+	// The Gateway without routes of its own serves the built-in ones.
+	var idle corev1.ConfigMap
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "apps", Name: "idle-computed-config"}, &idle); err != nil {
+		t.Fatalf("unable to get ConfigMap: %v", err)
+	}
+	if idle.Data[computedConfigSnapshotKey] == snapshot {
+		t.Errorf("expected Gateways serving different routes to have different snapshots")
+	}
+
+	// A removed route is no longer published.
+	p.ApplyRouteChanges(map[proxy.RouteKey]*proxy.HTTPRoute{{Namespace: "apps", Name: "web"}: nil})
+	if err := p.publish(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "apps", Name: "gw-computed-config"}, &cm); err != nil {
+		t.Fatalf("unable to get ConfigMap: %v", err)
+	}
+	if cm.Data[computedConfigSnapshotKey] != idle.Data[computedConfigSnapshotKey] || cm.Data[computedConfigBackendsKey] != "" {
+		t.Errorf("expected the route to be removed, got %v", cm.Data)
+	}
+}
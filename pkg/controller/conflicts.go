@@ -0,0 +1,239 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// routeConditionConflicted is set on a route when some of its matches are
+	// identical to those of an older route, which takes precedence. It is not
+	// a Gateway API condition.
+	routeConditionConflicted = "Conflicted"
+	routeReasonShadowed      = "ShadowedByOlderRoute"
+)
+
+// isRouteAccepted reports whether we accepted the route for any parent.
+func isRouteAccepted(route *gatewayv1.HTTPRoute, controllerName gatewayv1.GatewayController) bool {
+	for _, ps := range route.Status.Parents {
+		if ps.ControllerName != controllerName {
+			continue
+		}
+		if meta.IsStatusConditionTrue(ps.Conditions, string(gatewayv1.RouteConditionAccepted)) {
+			return true
+		}
+	}
+	return false
+}
+
+// routePrecedes reports whether a takes precedence over b for identical
+// matches: the oldest route wins, then the first in namespace/name order.
+func routePrecedes(a, b *gatewayv1.HTTPRoute) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// matchKey returns a canonical form of a match, so that matches selecting the
+// same requests compare equal. Rules without matches match every request.
+func matchKey(match gatewayv1.HTTPRouteMatch) string {
+	pathType, pathValue := gatewayv1.PathMatchPathPrefix, "/"
+	if match.Path != nil {
+		if match.Path.Type != nil {
+			pathType = *match.Path.Type
+		}
+		if match.Path.Value != nil {
+			pathValue = *match.Path.Value
+		}
+	}
+	parts := []string{fmt.Sprintf("%s %s", pathType, pathValue)}
+
+	var headers []string
+	for _, header := range match.Headers {
+		headerType := gatewayv1.HeaderMatchExact
+		if header.Type != nil {
+			headerType = *header.Type
+		}
+		headers = append(headers, fmt.Sprintf("header %s %s %s", strings.ToLower(string(header.Name)), headerType, header.Value))
+	}
+	slices.Sort(headers)
+	parts = append(parts, headers...)
+
+	var queryParams []string
+	for _, param := range match.QueryParams {
+		paramType := gatewayv1.QueryParamMatchExact
+		if param.Type != nil {
+			paramType = *param.Type
+		}
+		queryParams = append(queryParams, fmt.Sprintf("query %s %s %s", param.Name, paramType, param.Value))
+	}
+	slices.Sort(queryParams)
+	parts = append(parts, queryParams...)
+
+	if match.Method != nil {
+		parts = append(parts, fmt.Sprintf("method %s", *match.Method))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ruleMatchKeys returns the canonical form of each match of a rule.
+func ruleMatchKeys(rule gatewayv1.HTTPRouteRule) []string {
+	if len(rule.Matches) == 0 {
+		return []string{matchKey(gatewayv1.HTTPRouteMatch{})}
+	}
+	var keys []string
+	for _, match := range rule.Matches {
+		keys = append(keys, matchKey(match))
+	}
+	return keys
+}
+
+// routeConflicts describes each rule of route that has a match identical to
+// one of an accepted route among others which takes precedence over it. Such
+// matches are never served by route.
+func routeConflicts(route *gatewayv1.HTTPRoute, others []gatewayv1.HTTPRoute, controllerName gatewayv1.GatewayController) []string {
+	var conflicts []string
+	for i := range others {
+		other := &others[i]
+		if client.ObjectKeyFromObject(other) == client.ObjectKeyFromObject(route) || !isRouteAccepted(other, controllerName) || !routePrecedes(other, route) {
+			continue
+		}
+		hostname, ok := sharedHostname(route.Spec.Hostnames, other.Spec.Hostnames)
+		if !ok {
+			continue
+		}
+		otherKeys := map[string]bool{}
+		for _, rule := range other.Spec.Rules {
+			for _, key := range ruleMatchKeys(rule) {
+				otherKeys[key] = true
+			}
+		}
+		for j, rule := range route.Spec.Rules {
+			for _, key := range ruleMatchKeys(rule) {
+				if otherKeys[key] {
+					conflicts = append(conflicts, fmt.Sprintf("rule %s: match %q for hostname %s is served by older HTTPRoute %s/%s", ir.RuleName(rule, j), key, hostname, other.Namespace, other.Name))
+					break
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// routesSharingHostname returns the routes that share a hostname with route,
+// the only ones it can conflict with, sorted by namespace and name. They are
+// looked up through indexRouteHostnames rather than by listing every route.
+func (r *HTTPRouteReconciler) routesSharingHostname(ctx context.Context, route *gatewayv1.HTTPRoute) ([]gatewayv1.HTTPRoute, error) {
+	seen := map[client.ObjectKey]bool{}
+	var routes []gatewayv1.HTTPRoute
+	for _, hostname := range routeHostnamesIndex(route) {
+		var list gatewayv1.HTTPRouteList
+		if err := r.List(ctx, &list, client.MatchingFields{indexRouteHostnames: hostname}); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			if key := client.ObjectKeyFromObject(&list.Items[i]); !seen[key] {
+				seen[key] = true
+				routes = append(routes, list.Items[i])
+			}
+		}
+	}
+	slices.SortFunc(routes, func(a, b gatewayv1.HTTPRoute) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	return routes, nil
+}
+
+// sharedHostname returns a hostname listed by both routes. Routes without
+// hostnames only share the implicit "*" with each other.
+func sharedHostname(a, b []gatewayv1.Hostname) (string, bool) {
+	if len(a) == 0 || len(b) == 0 {
+		return "*", len(a) == len(b)
+	}
+	for _, hostname := range a {
+		if slices.Contains(b, hostname) {
+			return string(hostname), true
+		}
+	}
+	return "", false
+}
+
+// conflictedCondition returns the Conflicted condition for a route with the
+// given conflicts, or nil if there are none.
+func conflictedCondition(conflicts []string) *metav1.Condition {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    routeConditionConflicted,
+		Status:  metav1.ConditionTrue,
+		Reason:  routeReasonShadowed,
+		Message: strings.Join(conflicts, "; "),
+	}
+}
+
+// mapRouteToConflicts enqueues the routes whose conflicts may have changed
+// with obj: those shadowed by it, and those already reported as conflicted,
+// whose winner may have changed or been deleted.
+func (r *HTTPRouteReconciler) mapRouteToConflicts(ctx context.Context, obj client.Object) []reconcile.Request {
+	changed, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil
+	}
+	var routes gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &routes); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list HTTPRoutes")
+		return nil
+	}
+	controllerName := controllerNameOrDefault(r.ControllerName)
+	var requests []reconcile.Request
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if client.ObjectKeyFromObject(route) == client.ObjectKeyFromObject(changed) {
+			continue
+		}
+		if routeConflicted(route, controllerName) || len(routeConflicts(route, []gatewayv1.HTTPRoute{*changed}, controllerName)) > 0 {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)})
+		}
+	}
+	return requests
+}
+
+// routeConflicted reports whether we reported the route as conflicted.
+func routeConflicted(route *gatewayv1.HTTPRoute, controllerName gatewayv1.GatewayController) bool {
+	for _, ps := range route.Status.Parents {
+		if ps.ControllerName == controllerName && meta.FindStatusCondition(ps.Conditions, routeConditionConflicted) != nil {
+			return true
+		}
+	}
+	return false
+}
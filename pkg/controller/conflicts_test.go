@@ -0,0 +1,124 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestMatchKey(t *testing.T) {
+	implicit := gatewayv1.HTTPRouteMatch{}
+	explicit := gatewayv1.HTTPRouteMatch{Path: &gatewayv1.HTTPPathMatch{Type: ptr(gatewayv1.PathMatchPathPrefix), Value: ptr("/")}}
+	if matchKey(implicit) != matchKey(explicit) {
+		t.Errorf("expected defaulted matches to be equal, got %q and %q", matchKey(implicit), matchKey(explicit))
+	}
+
+	a := gatewayv1.HTTPRouteMatch{Headers: []gatewayv1.HTTPHeaderMatch{{Name: "X-A", Value: "1"}, {Name: "x-b", Value: "2"}}}
+	b := gatewayv1.HTTPRouteMatch{Headers: []gatewayv1.HTTPHeaderMatch{{Name: "x-b", Value: "2"}, {Name: "x-a", Value: "1"}}}
+	if matchKey(a) != matchKey(b) {
+		t.Errorf("expected header order and case to be ignored, got %q and %q", matchKey(a), matchKey(b))
+	}
+
+	get := gatewayv1.HTTPRouteMatch{Method: ptr(gatewayv1.HTTPMethodGet)}
+	if matchKey(get) == matchKey(implicit) {
+		t.Errorf("expected a method match to differ from an empty match")
+	}
+}
+
+func TestRouteConflicts(t *testing.T) {
+	older := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(older.Add(time.Hour))
+	accepted := gatewayv1.HTTPRouteStatus{
+		RouteStatus: gatewayv1.RouteStatus{
+			Parents: []gatewayv1.RouteParentStatus{{
+				ControllerName: DefaultControllerName,
+				Conditions:     []metav1.Condition{{Type: string(gatewayv1.RouteConditionAccepted), Status: metav1.ConditionTrue}},
+			}},
+		},
+	}
+	newRoute := func(name string, created metav1.Time, hostname gatewayv1.Hostname, path string) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: created},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Hostnames: []gatewayv1.Hostname{hostname},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					Matches: []gatewayv1.HTTPRouteMatch{{Path: &gatewayv1.HTTPPathMatch{Value: ptr(path)}}},
+				}},
+			},
+			Status: accepted,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		route    gatewayv1.HTTPRoute
+		other    gatewayv1.HTTPRoute
+		expected int
+	}{
+		{
+			name:     "shadowed by older route",
+			route:    newRoute("new", newer, "example.com", "/api"),
+			other:    newRoute("old", older, "example.com", "/api"),
+			expected: 1,
+		},
+		{
+			name:     "older route wins",
+			route:    newRoute("old", older, "example.com", "/api"),
+			other:    newRoute("new", newer, "example.com", "/api"),
+			expected: 0,
+		},
+		{
+			name:     "same age breaks ties by name",
+			route:    newRoute("b", older, "example.com", "/api"),
+			other:    newRoute("a", older, "example.com", "/api"),
+			expected: 1,
+		},
+		{
+			name:     "different hostname",
+			route:    newRoute("new", newer, "example.com", "/api"),
+			other:    newRoute("old", older, "example.org", "/api"),
+			expected: 0,
+		},
+		{
+			name:     "different path",
+			route:    newRoute("new", newer, "example.com", "/api"),
+			other:    newRoute("old", older, "example.com", "/web"),
+			expected: 0,
+		},
+		{
+			name:  "older route not accepted",
+			route: newRoute("new", newer, "example.com", "/api"),
+			other: func() gatewayv1.HTTPRoute {
+				r := newRoute("old", older, "example.com", "/api")
+				r.Status = gatewayv1.HTTPRouteStatus{}
+				return r
+			}(),
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := routeConflicts(&tt.route, []gatewayv1.HTTPRoute{tt.route, tt.other}, DefaultControllerName)
+			if len(actual) != tt.expected {
+				t.Errorf("expected %d conflicts, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -14,6 +14,38 @@
 
 package controller
 
-const (
-	ControllerName = "github.com/gke-labs/gateway-api-reference-implementation"
+import (
+	"fmt"
+	"regexp"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
+
+// DefaultControllerName is the controllerName of the GatewayClasses served by
+// the controller unless another one is configured.
+const DefaultControllerName = "github.com/gke-labs/gateway-api-reference-implementation"
+
+// controllerNamePattern is the format of a GatewayClass controllerName, a
+// domain-prefixed path, as validated by the Gateway API CRDs.
+var controllerNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/[A-Za-z0-9/\-._~%!$&'()*+,;=:]+$`)
+
+// ValidateControllerName checks that name can be the controllerName of a
+// GatewayClass, such as "example.com/gateway-controller".
+func ValidateControllerName(name string) error {
+	if len(name) > 253 {
+		return fmt.Errorf("controller name %q is longer than 253 characters", name)
+	}
+	if !controllerNamePattern.MatchString(name) {
+		return fmt.Errorf("controller name %q is not a domain-prefixed path, such as example.com/gateway-controller", name)
+	}
+	return nil
+}
+
+// controllerNameOrDefault returns name, or DefaultControllerName if it is
+// empty.
+func controllerNameOrDefault(name gatewayv1.GatewayController) gatewayv1.GatewayController {
+	if name == "" {
+		return DefaultControllerName
+	}
+	return name
+}
@@ -0,0 +1,325 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The Gateways of a class whose parameters set dataPlaneMode to DaemonSet are
+// served by a proxy on every node, provisioned as a DaemonSet owned by the
+// class. The proxies receive their routes from the config stream, and take
+// the ports of the listeners of the class's Gateways on the node, through
+// hostPorts or by running in the node's network as dataPlaneExposure selects.
+// The addresses of the nodes are published on the Gateways in place of a load
+// balancer's. Like the shared proxy, the proxies serve every route.
+
+// DataPlaneOptions configures the proxies provisioned for the classes in
+// DaemonSet mode.
+type DataPlaneOptions struct {
+	// Namespace is the namespace of the DaemonSets.
+	Namespace string
+	// Image is the image of the proxies of the classes without a
+	// dataPlaneImage parameter.
+	Image string
+	// ConfigStreamAddress is the address of the config stream, as reached
+	// from the proxies. DaemonSet mode is unavailable without it.
+	ConfigStreamAddress string
+	// ConfigStreamTokenSecret, if set, is the Secret in Namespace whose
+	// "token" key authenticates the proxies to the config stream.
+	ConfigStreamTokenSecret string
+	// ConfigStreamCAConfigMap, if set, is the ConfigMap in Namespace whose
+	// "ca.crt" key verifies the certificate of the config stream, which the
+	// proxies then connect to over TLS.
+	ConfigStreamCAConfigMap string
+	// ShutdownDelay is how long the proxies keep serving once asked to stop,
+	// failing their readiness check, before they drain. Their termination
+	// grace period covers it on top of dataPlaneDrainTimeout.
+	ShutdownDelay time.Duration
+	// MetricsRouteLabels and MaxMetricsRoutes, if set, bound the series of
+	// the request metrics of the proxies, as they do for the proxy of the
+	// controller.
+	MetricsRouteLabels proxy.RouteLabelMode
+	MaxMetricsRoutes   int
+}
+
+// Labels of the proxies provisioned for a class.
+const (
+	dataPlaneComponentLabel = "app.kubernetes.io/component"
+	dataPlaneComponent      = "gari-proxy"
+	gatewayClassLabel       = "gari.gke-labs.dev/gateway-class"
+)
+
+// Ports of the provisioned proxies, and where their config stream
+// credentials are mounted.
+const (
+	dataPlaneProxyPort   = 8000
+	dataPlaneMetricsPort = 8080
+	dataPlaneProbePort   = 8081
+	dataPlaneCredsDir    = "/var/run/secrets/gari/config-stream"
+	dataPlaneBinaryPath  = "/gateway-api-reference-implementation"
+)
+
+// dataPlaneDrainTimeout is how long the provisioned proxies are given to
+// drain the requests in flight after the shutdown delay, the default
+// termination grace period of a pod.
+const dataPlaneDrainTimeout = 30 * time.Second
+
+// dataPlaneName returns the name of the DaemonSet provisioned for a class.
+func dataPlaneName(className string) string {
+	return dnsLabel("gari-proxy-" + className)
+}
+
+// dataPlaneLabels returns the labels selecting the proxies of a class.
+func dataPlaneLabels(className string) map[string]string {
+	return map[string]string{
+		dataPlaneComponentLabel: dataPlaneComponent,
+		gatewayClassLabel:       className,
+	}
+}
+
+// dataPlaneUnavailable returns why the proxies of a class in DaemonSet mode
+// cannot be provisioned, or "" if they can.
+func (o DataPlaneOptions) dataPlaneUnavailable(params *classParameters) string {
+	switch {
+	case params == nil:
+		return ""
+	case params.DataPlaneMode != v1alpha1.DataPlaneModeDaemonSet:
+		if params.DataPlaneExposure != "" {
+			return "dataPlaneExposure requires dataPlaneMode DaemonSet"
+		}
+		return ""
+	case o.ConfigStreamAddress == "":
+		return "dataPlaneMode DaemonSet requires the controller to serve the config stream to provisioned proxies"
+	case params.DataPlaneImage == "" && o.Image == "":
+		return "dataPlaneMode DaemonSet requires a dataPlaneImage"
+	}
+	return ""
+}
+
+// provisionDataPlane creates or updates the DaemonSet of the proxies of a
+// class in DaemonSet mode, reverting any drift in the fields we set, and
+// deletes it once the class no longer is.
+func (r *GatewayClassReconciler) provisionDataPlane(ctx context.Context, gc *gatewayv1.GatewayClass, params *classParameters) error {
+	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: r.DataPlane.Namespace, Name: dataPlaneName(gc.Name)}}
+	if params == nil || params.DataPlaneMode != v1alpha1.DataPlaneModeDaemonSet {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(ds), ds); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(ds, gc) {
+			return nil
+		}
+		return client.IgnoreNotFound(r.Delete(ctx, ds))
+	}
+
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.MatchingFields{indexGatewayClassName: gc.Name}); err != nil {
+		return err
+	}
+	var ports []int32
+	for i := range gateways.Items {
+		ports = append(ports, listenerPorts(&gateways.Items[i])...)
+	}
+	slices.Sort(ports)
+	ports = slices.Compact(ports)
+
+	selector := dataPlaneLabels(gc.Name)
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, ds, func() error {
+		ds.Labels = mergeStrings(ds.Labels, selector)
+		ds.Labels[managedByLabel] = FieldManager
+		if ds.CreationTimestamp.IsZero() {
+			// The selector cannot change once created.
+			ds.Spec.Selector = &metav1.LabelSelector{MatchLabels: selector}
+		}
+		ds.Spec.Template.Labels = mergeStrings(ds.Spec.Template.Labels, selector)
+		ds.Spec.Template.Annotations = mergeStrings(ds.Spec.Template.Annotations, map[string]string{
+			"prometheus.io/scrape": "true",
+			"prometheus.io/port":   strconv.Itoa(dataPlaneMetricsPort),
+		})
+		ds.Spec.Template.Spec.AutomountServiceAccountToken = ptr(false)
+		ds.Spec.Template.Spec.HostNetwork = params.DataPlaneExposure == v1alpha1.DataPlaneExposureHostNetwork
+		if ds.Spec.Template.Spec.HostNetwork {
+			ds.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		} else {
+			ds.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+		}
+		ds.Spec.Template.Spec.TerminationGracePeriodSeconds = ptr(int64((r.DataPlane.ShutdownDelay + dataPlaneDrainTimeout).Seconds()))
+		ds.Spec.Template.Spec.Containers = []corev1.Container{r.dataPlaneContainer(params, ports)}
+		ds.Spec.Template.Spec.Volumes = r.dataPlaneVolumes()
+		return controllerutil.SetControllerReference(gc, ds, r.Scheme)
+	})
+	return err
+}
+
+// dataPlaneContainer returns the proxy container, which takes each listener
+// port on its node: in the node's network, the proxy listens on every port
+// itself; otherwise each port is forwarded to the proxy port.
+func (r *GatewayClassReconciler) dataPlaneContainer(params *classParameters, ports []int32) corev1.Container {
+	image := params.DataPlaneImage
+	if image == "" {
+		image = r.DataPlane.Image
+	}
+	hostNetwork := params.DataPlaneExposure == v1alpha1.DataPlaneExposureHostNetwork
+	bindAddresses := []string{fmt.Sprintf(":%d", dataPlaneProxyPort)}
+	if hostNetwork {
+		bindAddresses = nil
+		for _, port := range ports {
+			bindAddresses = append(bindAddresses, fmt.Sprintf(":%d", port))
+		}
+	}
+	container := corev1.Container{
+		Name:    "proxy",
+		Image:   image,
+		Command: []string{dataPlaneBinaryPath, "proxy"},
+		Args: []string{
+			"--proxy-bind-address=" + strings.Join(bindAddresses, ","),
+			fmt.Sprintf("--metrics-bind-address=:%d", dataPlaneMetricsPort),
+			fmt.Sprintf("--health-probe-bind-address=:%d", dataPlaneProbePort),
+			"--config-stream-address=" + r.DataPlane.ConfigStreamAddress,
+			"--shutdown-delay=" + r.DataPlane.ShutdownDelay.String(),
+			// Routes without a TelemetryPolicy are sampled as the controller
+			// samples them.
+			"--trace-sampler=" + string(proxy.DefaultTelemetry.TraceSampler),
+			fmt.Sprintf("--trace-sampling-percent=%d", proxy.DefaultTelemetry.TraceSamplingPercent),
+		},
+		Env: []corev1.EnvVar{{
+			Name:      "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+		}},
+		ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+			Path: "/readyz",
+			Port: intstr.FromInt32(dataPlaneProbePort),
+		}}},
+		LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+			Path: "/healthz",
+			Port: intstr.FromInt32(dataPlaneProbePort),
+		}}},
+	}
+	metrics := corev1.ContainerPort{Name: "metrics", Protocol: corev1.ProtocolTCP, ContainerPort: dataPlaneMetricsPort}
+	if hostNetwork {
+		// The API server defaults the host ports in the node's network.
+		metrics.HostPort = dataPlaneMetricsPort
+	}
+	container.Ports = append(container.Ports, metrics)
+	for _, port := range ports {
+		containerPort := int32(dataPlaneProxyPort)
+		if hostNetwork {
+			// Ports in the node's network are the node's own.
+			containerPort = port
+		}
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			Name:          fmt.Sprintf("port-%d", port),
+			Protocol:      corev1.ProtocolTCP,
+			ContainerPort: containerPort,
+			HostPort:      port,
+		})
+	}
+	if r.DataPlane.MetricsRouteLabels != "" {
+		container.Args = append(container.Args, "--metrics-route-labels="+string(r.DataPlane.MetricsRouteLabels))
+	}
+	if r.DataPlane.MaxMetricsRoutes > 0 {
+		container.Args = append(container.Args, fmt.Sprintf("--metrics-max-routes=%d", r.DataPlane.MaxMetricsRoutes))
+	}
+	if r.DataPlane.ConfigStreamTokenSecret != "" {
+		container.Args = append(container.Args, "--config-stream-token-file="+dataPlaneCredsDir+"/token/token")
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "config-stream-token", MountPath: dataPlaneCredsDir + "/token", ReadOnly: true})
+	}
+	if r.DataPlane.ConfigStreamCAConfigMap != "" {
+		container.Args = append(container.Args, "--config-stream-ca-file="+dataPlaneCredsDir+"/ca/ca.crt")
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "config-stream-ca", MountPath: dataPlaneCredsDir + "/ca", ReadOnly: true})
+	}
+	return container
+}
+
+func (r *GatewayClassReconciler) dataPlaneVolumes() []corev1.Volume {
+	var volumes []corev1.Volume
+	if r.DataPlane.ConfigStreamTokenSecret != "" {
+		volumes = append(volumes, corev1.Volume{Name: "config-stream-token", VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: r.DataPlane.ConfigStreamTokenSecret},
+		}})
+	}
+	if r.DataPlane.ConfigStreamCAConfigMap != "" {
+		volumes = append(volumes, corev1.Volume{Name: "config-stream-ca", VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: r.DataPlane.ConfigStreamCAConfigMap}},
+		}})
+	}
+	return volumes
+}
+
+// dataPlaneAddresses returns the addresses of the nodes running a ready proxy
+// of a class in DaemonSet mode, in order.
+func (r *GatewayReconciler) dataPlaneAddresses(ctx context.Context, className string) ([]string, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(r.DataPlaneNamespace), client.MatchingLabels(dataPlaneLabels(className))); err != nil {
+		return nil, err
+	}
+	var addresses []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if podReady(pod) && pod.Status.HostIP != "" {
+			addresses = append(addresses, pod.Status.HostIP)
+		}
+	}
+	slices.Sort(addresses)
+	return slices.Compact(addresses), nil
+}
+
+// mapDataPlanePodToGateways enqueues the Gateways of the class of a
+// provisioned proxy, whose addresses follow the nodes it is ready on.
+func (r *GatewayReconciler) mapDataPlanePodToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	className, ok := obj.GetLabels()[gatewayClassLabel]
+	if !ok || obj.GetNamespace() != r.DataPlaneNamespace || obj.GetLabels()[dataPlaneComponentLabel] != dataPlaneComponent {
+		return nil
+	}
+	return r.gatewaysOfClass(ctx, className)
+}
+
+// dataPlanePodChanged filters the updates of Pods to those that can change
+// the addresses published for a class in DaemonSet mode.
+var dataPlanePodChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, ok := e.ObjectOld.(*corev1.Pod)
+		if !ok {
+			return true
+		}
+		newPod, ok := e.ObjectNew.(*corev1.Pod)
+		if !ok {
+			return true
+		}
+		return podReady(oldPod) != podReady(newPod) ||
+			oldPod.Status.HostIP != newPod.Status.HostIP ||
+			!labels.Equals(oldPod.Labels, newPod.Labels)
+	},
+}
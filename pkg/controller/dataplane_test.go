@@ -0,0 +1,212 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestProvisionDataPlane(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gc := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "ours", UID: "gc-uid"},
+		Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+	}
+	newGateway := func(name string, ports ...gatewayv1.PortNumber) *gatewayv1.Gateway {
+		gw := &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: name},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "ours"},
+		}
+		for _, port := range ports {
+			gw.Spec.Listeners = append(gw.Spec.Listeners, gatewayv1.Listener{Port: port, Protocol: gatewayv1.HTTPProtocolType})
+		}
+		return gw
+	}
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).
+		WithObjects(gc, newGateway("a", 80, 8443), newGateway("b", 80, 443)).
+		Build()
+	r := &GatewayClassReconciler{
+		Client: c,
+		Scheme: scheme,
+		DataPlane: DataPlaneOptions{
+			Namespace:               "gari-system",
+			Image:                   "proxy:latest",
+			ConfigStreamAddress:     "controller.gari-system:9443",
+			ConfigStreamTokenSecret: "config-stream-token",
+			ShutdownDelay:           15 * time.Second,
+			MaxMetricsRoutes:        500,
+		},
+	}
+
+	params := &classParameters{DataPlaneMode: v1alpha1.DataPlaneModeDaemonSet}
+	if err := r.provisionDataPlane(ctx, gc, params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	key := types.NamespacedName{Namespace: "gari-system", Name: "gari-proxy-ours"}
+	var ds appsv1.DaemonSet
+	if err := c.Get(ctx, key, &ds); err != nil {
+		t.Fatalf("unable to get DaemonSet: %v", err)
+	}
+	if !metav1.IsControlledBy(&ds, gc) {
+		t.Errorf("expected DaemonSet to be owned by the GatewayClass, got %v", ds.OwnerReferences)
+	}
+	if !reflect.DeepEqual(ds.Spec.Selector.MatchLabels, dataPlaneLabels("ours")) {
+		t.Errorf("expected the proxies to be selected by class, got %v", ds.Spec.Selector)
+	}
+	container := ds.Spec.Template.Spec.Containers[0]
+	if container.Image != "proxy:latest" {
+		t.Errorf("expected the default image, got %q", container.Image)
+	}
+	var hostPorts []int32
+	for _, port := range container.Ports {
+		if port.Name != "metrics" {
+			hostPorts = append(hostPorts, port.HostPort)
+		}
+	}
+	if !reflect.DeepEqual(hostPorts, []int32{80, 443, 8443}) {
+		t.Errorf("expected host ports [80 443 8443], got %v", hostPorts)
+	}
+	if len(ds.Spec.Template.Spec.Volumes) != 1 || len(container.VolumeMounts) != 1 {
+		t.Errorf("expected the config stream token to be mounted, got %v", ds.Spec.Template.Spec.Volumes)
+	}
+
+	if ds.Spec.Template.Spec.HostNetwork || container.Args[0] != "--proxy-bind-address=:8000" {
+		t.Errorf("expected the listener ports to be forwarded to the proxy port, got %v", container.Args)
+	}
+	if !slices.Contains(container.Args, "--shutdown-delay=15s") {
+		t.Errorf("expected the proxies to be given the shutdown delay, got %v", container.Args)
+	}
+	if !slices.Contains(container.Args, "--trace-sampler=ParentBased") || !slices.Contains(container.Args, "--trace-sampling-percent=100") {
+		t.Errorf("expected the proxies to be given the default trace sampling, got %v", container.Args)
+	}
+	if !slices.Contains(container.Args, "--metrics-max-routes=500") {
+		t.Errorf("expected the proxies to be given the cap on metrics routes, got %v", container.Args)
+	}
+	if grace := ds.Spec.Template.Spec.TerminationGracePeriodSeconds; grace == nil || *grace != 45 {
+		t.Errorf("expected a termination grace period covering the shutdown delay and the drain, got %v", grace)
+	}
+	if ds.Spec.Template.Annotations["prometheus.io/port"] != "8080" {
+		t.Errorf("expected the proxies' metrics to be scraped, got %v", ds.Spec.Template.Annotations)
+	}
+
+	// In the node's network, the proxy listens on each listener port.
+	params.DataPlaneExposure = v1alpha1.DataPlaneExposureHostNetwork
+	if err := r.provisionDataPlane(ctx, gc, params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(ctx, key, &ds); err != nil {
+		t.Fatalf("unable to get DaemonSet: %v", err)
+	}
+	container = ds.Spec.Template.Spec.Containers[0]
+	if !ds.Spec.Template.Spec.HostNetwork || ds.Spec.Template.Spec.DNSPolicy != corev1.DNSClusterFirstWithHostNet {
+		t.Errorf("expected the proxies to run in the node's network, got %+v", ds.Spec.Template.Spec)
+	}
+	if container.Args[0] != "--proxy-bind-address=:80,:443,:8443" {
+		t.Errorf("expected the proxy to bind every listener port, got %v", container.Args)
+	}
+	for _, port := range container.Ports {
+		if port.ContainerPort != port.HostPort {
+			t.Errorf("expected container ports to match host ports, got %v", container.Ports)
+		}
+	}
+
+	// Leaving DaemonSet mode deletes the proxies.
+	if err := r.provisionDataPlane(ctx, gc, &classParameters{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(ctx, key, &ds); !apierrors.IsNotFound(err) {
+		t.Errorf("expected DaemonSet to be deleted, got %v", err)
+	}
+}
+
+func TestDataPlaneUnavailable(t *testing.T) {
+	daemonSet := &classParameters{DataPlaneMode: v1alpha1.DataPlaneModeDaemonSet}
+	for _, tc := range []struct {
+		name        string
+		options     DataPlaneOptions
+		params      *classParameters
+		unavailable bool
+	}{
+		{name: "shared mode", params: &classParameters{}},
+		{name: "exposure in shared mode", params: &classParameters{DataPlaneExposure: v1alpha1.DataPlaneExposureHostNetwork}, unavailable: true},
+		{name: "no config stream", options: DataPlaneOptions{Image: "proxy:latest"}, params: daemonSet, unavailable: true},
+		{name: "no image", options: DataPlaneOptions{ConfigStreamAddress: "controller:9443"}, params: daemonSet, unavailable: true},
+		{name: "class image", options: DataPlaneOptions{ConfigStreamAddress: "controller:9443"}, params: &classParameters{DataPlaneMode: v1alpha1.DataPlaneModeDaemonSet, DataPlaneImage: "proxy:v2"}},
+		{name: "available", options: DataPlaneOptions{ConfigStreamAddress: "controller:9443", Image: "proxy:latest"}, params: daemonSet},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if reason := tc.options.dataPlaneUnavailable(tc.params); (reason != "") != tc.unavailable {
+				t.Errorf("expected unavailable %v, got %q", tc.unavailable, reason)
+			}
+		})
+	}
+}
+
+func TestDataPlaneAddresses(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	newPod := func(name, hostIP string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "gari-system", Name: name, Labels: dataPlaneLabels("ours")},
+			Status: corev1.PodStatus{
+				HostIP:     hostIP,
+				PodIP:      "10.1.0.1",
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	other := newPod("other", "10.0.0.9", corev1.ConditionTrue)
+	other.Labels = dataPlaneLabels("theirs")
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newPod("b", "10.0.0.2", corev1.ConditionTrue),
+		newPod("a", "10.0.0.1", corev1.ConditionTrue),
+		newPod("starting", "10.0.0.3", corev1.ConditionFalse),
+		other,
+	).Build()
+	r := &GatewayReconciler{Client: c, DataPlaneNamespace: "gari-system"}
+
+	addresses, err := r.dataPlaneAddresses(ctx, "ours")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"10.0.0.1", "10.0.0.2"}; !reflect.DeepEqual(addresses, expected) {
+		t.Errorf("expected %v, got %v", expected, addresses)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// DebugAnnotation, set to "true" on an HTTPRoute or a Gateway, makes the
+// controller explain its decisions about that object alone: the messages of
+// its conditions are expanded with them, and each is recorded as an Event.
+const DebugAnnotation = "gari.gke-labs.dev/debug"
+
+// eventReasonDebug is the reason of the Events recorded for objects with the
+// debug annotation.
+const eventReasonDebug = "Debug"
+
+// maxConditionMessageLength is the longest condition message the API server
+// accepts.
+const maxConditionMessageLength = 32768
+
+// debugEnabled reports whether obj has the debug annotation.
+func debugEnabled(obj client.Object) bool {
+	return obj.GetAnnotations()[DebugAnnotation] == "true"
+}
+
+// debugMessage appends details to a condition message, truncated to the
+// length the API server accepts.
+func debugMessage(message string, details []string) string {
+	if len(details) == 0 {
+		return message
+	}
+	message = fmt.Sprintf("%s. Debug: %s", strings.TrimSuffix(message, "."), strings.Join(details, "; "))
+	if len(message) > maxConditionMessageLength {
+		message = message[:maxConditionMessageLength-3] + "..."
+	}
+	return message
+}
+
+// backendDiagnostics describes the destination each backendRef of route
+// resolves to. Those that do not resolve are already reported by the
+// ResolvedRefs condition.
+func backendDiagnostics(route *gatewayv1.HTTPRoute, targets ir.Targets) []string {
+	var details []string
+	describe := func(rule string, ref gatewayv1.BackendObjectReference) {
+		backend, err := ir.ResolveBackendRef(route.Namespace, ref, targets)
+		switch {
+		case err != nil:
+		case backend.EndpointPicker != nil:
+			details = append(details, fmt.Sprintf("rule %s: backendRef %s resolved to an endpoint picker", rule, ref.Name))
+		case backend.TLS != nil:
+			details = append(details, fmt.Sprintf("rule %s: backendRef %s resolved to %s:%d over TLS", rule, ref.Name, backend.Host, backend.Port))
+		default:
+			details = append(details, fmt.Sprintf("rule %s: backendRef %s resolved to %s:%d", rule, ref.Name, backend.Host, backend.Port))
+		}
+	}
+	for i, rule := range route.Spec.Rules {
+		name := ir.RuleName(rule, i)
+		for _, backendRef := range rule.BackendRefs {
+			describe(name, backendRef.BackendObjectReference)
+			for _, filter := range backendRef.Filters {
+				if filter.Type == gatewayv1.HTTPRouteFilterRequestMirror && filter.RequestMirror != nil {
+					describe(name, filter.RequestMirror.BackendRef)
+				}
+			}
+		}
+	}
+	return details
+}
+
+// listenerDiagnostics describes the hostnames a route is served for on each
+// of the listeners it may attach to.
+func listenerDiagnostics(listeners []*gatewayv1.Listener, hostnames []gatewayv1.Hostname) []string {
+	var details []string
+	for _, listener := range listeners {
+		served, ok := ir.ListenerHostnames(listener, hostnames)
+		switch {
+		case !ok:
+			details = append(details, fmt.Sprintf("listener %s (hostname %s) matches no hostname of the route", listener.Name, listenerHostname(listener)))
+		case served == nil:
+			details = append(details, fmt.Sprintf("listener %s (hostname %s) serves any hostname", listener.Name, listenerHostname(listener)))
+		default:
+			details = append(details, fmt.Sprintf("listener %s (hostname %s) serves %s", listener.Name, listenerHostname(listener), strings.Join(served, ", ")))
+		}
+	}
+	return details
+}
+
+// precedenceDiagnostics describes, for each accepted route among others that
+// shares a hostname and a match with route, which of the two serves it.
+func precedenceDiagnostics(route *gatewayv1.HTTPRoute, others []gatewayv1.HTTPRoute, controllerName gatewayv1.GatewayController) []string {
+	keys := map[string]bool{}
+	for _, rule := range route.Spec.Rules {
+		for _, key := range ruleMatchKeys(rule) {
+			keys[key] = true
+		}
+	}
+	var details []string
+	for i := range others {
+		other := &others[i]
+		if client.ObjectKeyFromObject(other) == client.ObjectKeyFromObject(route) || !isRouteAccepted(other, controllerName) {
+			continue
+		}
+		hostname, ok := sharedHostname(route.Spec.Hostnames, other.Spec.Hostnames)
+		if !ok {
+			continue
+		}
+		for _, rule := range other.Spec.Rules {
+			key := ""
+			for _, k := range ruleMatchKeys(rule) {
+				if keys[k] {
+					key = k
+					break
+				}
+			}
+			if key == "" {
+				continue
+			}
+			if routePrecedes(other, route) {
+				details = append(details, fmt.Sprintf("match %q for hostname %s is served by older HTTPRoute %s/%s", key, hostname, other.Namespace, other.Name))
+			} else {
+				details = append(details, fmt.Sprintf("match %q for hostname %s takes precedence over HTTPRoute %s/%s", key, hostname, other.Namespace, other.Name))
+			}
+			break
+		}
+	}
+	return details
+}
+
+// gatewayDiagnostics describes the Service exposing gw, or the nodes if it has
+// none, and each of its listeners, along with the other listeners on the same
+// port whose hostnames intersect with its own.
+func gatewayDiagnostics(gw *gatewayv1.Gateway, svc *corev1.Service) []string {
+	details := []string{fmt.Sprintf("exposed on the nodes running the proxies of GatewayClass %s", gw.Spec.GatewayClassName)}
+	if svc != nil {
+		details[0] = fmt.Sprintf("exposed by Service %s", client.ObjectKeyFromObject(svc))
+	}
+	for i := range gw.Spec.Listeners {
+		listener := &gw.Spec.Listeners[i]
+		detail := fmt.Sprintf("listener %s: %s on port %d for hostname %s", listener.Name, listener.Protocol, listener.Port, listenerHostname(listener))
+		var overlapping []string
+		for j := range gw.Spec.Listeners {
+			other := &gw.Spec.Listeners[j]
+			if i == j || other.Port != listener.Port {
+				continue
+			}
+			if _, ok := ir.IntersectHostnames(listenerHostname(listener), listenerHostname(other)); ok {
+				overlapping = append(overlapping, string(other.Name))
+			}
+		}
+		if len(overlapping) > 0 {
+			detail += fmt.Sprintf(", intersecting listeners %s", strings.Join(overlapping, ", "))
+		}
+		details = append(details, detail)
+	}
+	return details
+}
+
+// listenerHostname returns the hostname of listener, or "*" if it matches
+// any hostname.
+func listenerHostname(listener *gatewayv1.Listener) string {
+	if listener.Hostname == nil || *listener.Hostname == "" {
+		return "*"
+	}
+	return string(*listener.Hostname)
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcileRouteDebug(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+
+	now := metav1.Now()
+	newRoute := func(name string, created metav1.Time, annotations map[string]string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: created, Annotations: annotations},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}}},
+				Hostnames:       []gatewayv1.Hostname{"shop.example.com"},
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "shop", Port: ptr(gatewayv1.PortNumber(8080))}}},
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "missing", Port: ptr(gatewayv1.PortNumber(8080))}}},
+				}}},
+			},
+		}
+	}
+	older := newRoute("older", metav1.NewTime(now.Add(-time.Hour)), nil)
+	older.Status.Parents = []gatewayv1.RouteParentStatus{{
+		ParentRef:      gatewayv1.ParentReference{Name: "gw"},
+		ControllerName: DefaultControllerName,
+		Conditions:     []metav1.Condition{{Type: string(gatewayv1.RouteConditionAccepted), Status: metav1.ConditionTrue, Reason: string(gatewayv1.RouteReasonAccepted)}},
+	}}
+	debugged := newRoute("debugged", now, map[string]string{DebugAnnotation: "true"})
+	plain := newRoute("plain", now, nil)
+
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithStatusSubresource(debugged, plain).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners: []gatewayv1.Listener{
+					{Name: "shop", Port: 80, Protocol: gatewayv1.HTTPProtocolType, Hostname: ptr(gatewayv1.Hostname("*.example.com"))},
+					{Name: "api", Port: 80, Protocol: gatewayv1.HTTPProtocolType, Hostname: ptr(gatewayv1.Hostname("api.internal"))},
+				},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "shop"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 8080}}},
+		},
+		older, debugged, plain,
+	).Build()
+
+	recorder := record.NewFakeRecorder(20)
+	r := &HTTPRouteReconciler{Client: c, Scheme: scheme, Proxy: proxy.NewProxy(proxy.Options{}), Recorder: recorder}
+	reconcileRoute := func(route *gatewayv1.HTTPRoute) (accepted, resolvedRefs string, debugEvents []string) {
+		t.Helper()
+		for len(recorder.Events) > 0 {
+			<-recorder.Events
+		}
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for len(recorder.Events) > 0 {
+			if event := <-recorder.Events; strings.HasPrefix(event, "Normal "+eventReasonDebug+" ") {
+				debugEvents = append(debugEvents, strings.TrimPrefix(event, "Normal "+eventReasonDebug+" "))
+			}
+		}
+		var actual gatewayv1.HTTPRoute
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(route), &actual); err != nil {
+			t.Fatalf("unable to get route: %v", err)
+		}
+		conditions := existingParentConditions(&actual.Status, actual.Spec.ParentRefs[0], DefaultControllerName)
+		return meta.FindStatusCondition(conditions, string(gatewayv1.RouteConditionAccepted)).Message,
+			meta.FindStatusCondition(conditions, string(gatewayv1.RouteConditionResolvedRefs)).Message,
+			debugEvents
+	}
+
+	accepted, resolvedRefs, events := reconcileRoute(debugged)
+	expectedEvents := []string{
+		"rule 0: backendRef shop resolved to shop.default.svc.cluster.local:8080",
+		`match "PathPrefix /" for hostname shop.example.com is served by older HTTPRoute default/older`,
+		"Gateway gw: listener shop (hostname *.example.com) serves shop.example.com",
+		"Gateway gw: listener api (hostname api.internal) matches no hostname of the route",
+	}
+	if !reflect.DeepEqual(events, expectedEvents) {
+		t.Errorf("expected debug events %q, got %q", expectedEvents, events)
+	}
+	expectedAccepted := "Route accepted by reference implementation. Debug: " +
+		"listener shop (hostname *.example.com) serves shop.example.com; " +
+		"listener api (hostname api.internal) matches no hostname of the route; " +
+		`match "PathPrefix /" for hostname shop.example.com is served by older HTTPRoute default/older`
+	if accepted != expectedAccepted {
+		t.Errorf("expected Accepted message %q, got %q", expectedAccepted, accepted)
+	}
+	if !strings.Contains(resolvedRefs, "Service not found. Debug: rule 0: backendRef shop resolved to shop.default.svc.cluster.local:8080") {
+		t.Errorf("expected the ResolvedRefs message to list the resolved backends, got %q", resolvedRefs)
+	}
+
+	// Routes without the annotation keep the usual messages.
+	accepted, resolvedRefs, events = reconcileRoute(plain)
+	if strings.Contains(accepted, "Debug") || strings.Contains(resolvedRefs, "Debug") || len(events) != 0 {
+		t.Errorf("expected no debug details without the annotation, got %q, %q and %q", accepted, resolvedRefs, events)
+	}
+}
+
+func TestGatewayDiagnostics(t *testing.T) {
+	gw := &gatewayv1.Gateway{
+		Spec: gatewayv1.GatewaySpec{Listeners: []gatewayv1.Listener{
+			{Name: "any", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			{Name: "wildcard", Port: 80, Protocol: gatewayv1.HTTPProtocolType, Hostname: ptr(gatewayv1.Hostname("*.example.com"))},
+			{Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType, Hostname: ptr(gatewayv1.Hostname("shop.example.com"))},
+		}},
+	}
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "gari-system", Name: "proxy"}}
+
+	expected := []string{
+		"exposed by Service gari-system/proxy",
+		"listener any: HTTP on port 80 for hostname *, intersecting listeners wildcard",
+		"listener wildcard: HTTP on port 80 for hostname *.example.com, intersecting listeners any",
+		"listener https: HTTPS on port 443 for hostname shop.example.com",
+	}
+	if actual := gatewayDiagnostics(gw, svc); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	message := debugMessage("Gateway programmed.", []string{strings.Repeat("x", maxConditionMessageLength)})
+	if len(message) != maxConditionMessageLength || !strings.HasPrefix(message, "Gateway programmed. Debug: x") {
+		t.Errorf("expected the message to be truncated to %d bytes, got %d", maxConditionMessageLength, len(message))
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"net"
+	"slices"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// external-dns publishes the hostnames of the HTTPRoutes accepted by a
+// Gateway from the Gateway's status addresses on its own. For the setups that
+// run it with the CRD source instead, the GatewayReconciler can write the same
+// records as a DNSEndpoint owned by each Gateway, named after the Gateway.
+// The DNSEndpoint CRD is read as unstructured, so that external-dns is not a
+// dependency.
+
+// dnsEndpointGVK is the kind of external-dns's DNSEndpoints.
+var dnsEndpointGVK = schema.GroupVersionKind{Group: "externaldns.k8s.io", Version: "v1alpha1", Kind: "DNSEndpoint"}
+
+// newDNSEndpoint returns an empty DNSEndpoint.
+func newDNSEndpoint() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(dnsEndpointGVK)
+	return obj
+}
+
+// reconcileDNSEndpoint creates or updates the DNSEndpoint publishing the
+// hostnames routed by gw at its addresses, and deletes it once there are
+// none.
+func (r *GatewayReconciler) reconcileDNSEndpoint(ctx context.Context, gw *gatewayv1.Gateway, addresses []string) error {
+	hostnames, err := r.routedHostnames(ctx, gw)
+	if err != nil {
+		return err
+	}
+	endpoint := newDNSEndpoint()
+	endpoint.SetNamespace(gw.Namespace)
+	endpoint.SetName(gw.Name)
+	if len(hostnames) == 0 || len(addresses) == 0 {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(endpoint), endpoint); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(endpoint, gw) {
+			return nil
+		}
+		return client.IgnoreNotFound(r.Delete(ctx, endpoint))
+	}
+
+	records := dnsRecords(addresses)
+	var endpoints []any
+	for _, hostname := range hostnames {
+		for _, recordType := range dnsRecordTypes {
+			if targets, ok := records[recordType]; ok {
+				endpoints = append(endpoints, map[string]any{
+					"dnsName":    hostname,
+					"recordType": recordType,
+					"targets":    targets,
+				})
+			}
+		}
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, endpoint, func() error {
+		labels := endpoint.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[gatewayNameLabel] = gw.Name
+		labels[managedByLabel] = FieldManager
+		endpoint.SetLabels(labels)
+		if err := unstructured.SetNestedSlice(endpoint.Object, endpoints, "spec", "endpoints"); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(gw, endpoint, r.Scheme)
+	})
+	return err
+}
+
+// dnsRecordTypes lists the types of the records of a DNSEndpoint, in order.
+var dnsRecordTypes = []string{"A", "AAAA", "CNAME"}
+
+// dnsRecords groups addresses by the type of the DNS records pointing at
+// them: A for IPv4 addresses, AAAA for IPv6 ones, and CNAME for hostnames, of
+// which only the first can be used.
+func dnsRecords(addresses []string) map[string][]any {
+	records := map[string][]any{}
+	for _, address := range addresses {
+		recordType := "CNAME"
+		if ip := net.ParseIP(address); ip != nil {
+			recordType = "AAAA"
+			if ip.To4() != nil {
+				recordType = "A"
+			}
+		}
+		if recordType == "CNAME" && len(records[recordType]) > 0 {
+			continue
+		}
+		records[recordType] = append(records[recordType], address)
+	}
+	if len(records["A"]) > 0 || len(records["AAAA"]) > 0 {
+		// A name with a CNAME record cannot have any other.
+		delete(records, "CNAME")
+	}
+	return records
+}
+
+// routedHostnames returns the hostnames of the HTTPRoutes accepted by gw, in
+// order, as served on the listeners they are attached to. Routes served for
+// any hostname have no record.
+func (r *GatewayReconciler) routedHostnames(ctx context.Context, gw *gatewayv1.Gateway) ([]string, error) {
+	var routes gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &routes, client.MatchingFields{indexRouteParentGateways: client.ObjectKeyFromObject(gw).String()}); err != nil {
+		return nil, err
+	}
+	controllerName := controllerNameOrDefault(r.ControllerName)
+	var hostnames []string
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		for _, ps := range route.Status.Parents {
+			if ps.ControllerName != controllerName || ir.ParentGateway(route.Namespace, ps.ParentRef) != client.ObjectKeyFromObject(gw) ||
+				!meta.IsStatusConditionTrue(ps.Conditions, string(gatewayv1.RouteConditionAccepted)) {
+				continue
+			}
+			served, _ := ir.RouteHostnames(ir.ParentListeners(gw, ps.ParentRef), route.Spec.Hostnames)
+			hostnames = append(hostnames, served...)
+		}
+	}
+	slices.Sort(hostnames)
+	return slices.Compact(hostnames), nil
+}
+
+// mapRouteToGateways enqueues the Gateways in the parentRefs of a changed
+// HTTPRoute, whose DNSEndpoints publish its hostnames.
+func (r *GatewayReconciler) mapRouteToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	route, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, key := range ir.RouteGateways(route) {
+		requests = append(requests, reconcile.Request{NamespacedName: key})
+	}
+	return requests
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcileDNSEndpoint(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gw := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "gw", UID: "gw-uid"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "ours",
+			Listeners: []gatewayv1.Listener{
+				{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType, Hostname: ptr(gatewayv1.Hostname("*.example.com"))},
+			},
+		},
+	}
+	newRoute := func(name string, accepted metav1.ConditionStatus, hostnames ...gatewayv1.Hostname) *gatewayv1.HTTPRoute {
+		parentRef := gatewayv1.ParentReference{Name: "gw"}
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{parentRef}},
+				Hostnames:       hostnames,
+			},
+			Status: gatewayv1.HTTPRouteStatus{RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{{
+				ParentRef:      parentRef,
+				ControllerName: DefaultControllerName,
+				Conditions:     []metav1.Condition{{Type: string(gatewayv1.RouteConditionAccepted), Status: accepted}},
+			}}}},
+		}
+	}
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithObjects(
+		gw,
+		newRoute("web", metav1.ConditionTrue, "www.example.com", "www.other.com"),
+		newRoute("api", metav1.ConditionTrue),
+		newRoute("rejected", metav1.ConditionFalse, "rejected.example.com"),
+	).Build()
+	r := &GatewayReconciler{Client: c, Scheme: scheme, DNSEndpoints: true}
+
+	if err := r.reconcileDNSEndpoint(ctx, gw, []string{"192.0.2.1", "2001:db8::1", "lb.example.net"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	endpoint := newDNSEndpoint()
+	key := types.NamespacedName{Namespace: "apps", Name: "gw"}
+	if err := c.Get(ctx, key, endpoint); err != nil {
+		t.Fatalf("unable to get DNSEndpoint: %v", err)
+	}
+	if !metav1.IsControlledBy(endpoint, gw) {
+		t.Errorf("expected DNSEndpoint to be owned by the Gateway, got %v", endpoint.GetOwnerReferences())
+	}
+	endpoints, _, _ := unstructured.NestedSlice(endpoint.Object, "spec", "endpoints")
+	var records []string
+	for _, e := range endpoints {
+		e := e.(map[string]any)
+		records = append(records, e["dnsName"].(string)+" "+e["recordType"].(string))
+	}
+	// Routes without hostnames take the listener's, and the load balancer
+	// hostname cannot be published next to its IPs.
+	expected := []string{"*.example.com A", "*.example.com AAAA", "www.example.com A", "www.example.com AAAA"}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected records %v, got %v", expected, records)
+	}
+
+	// A Gateway without addresses has no records.
+	if err := r.reconcileDNSEndpoint(ctx, gw, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(ctx, key, newDNSEndpoint()); !apierrors.IsNotFound(err) {
+		t.Errorf("expected DNSEndpoint to be deleted, got %v", err)
+	}
+}
+
+func TestDNSRecords(t *testing.T) {
+	records := dnsRecords([]string{"lb-1.example.net", "lb-2.example.net"})
+	if expected := map[string][]any{"CNAME": {"lb-1.example.net"}}; !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected %v, got %v", expected, records)
+	}
+}
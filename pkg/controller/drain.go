@@ -0,0 +1,165 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/conditions"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// A managed Gateway holds gatewayFinalizer, so that deleting it does not drop
+// its routes from the proxy mid-request. Once it is deleted, its listeners are
+// reported as no longer accepting traffic and the proxy closes the connections
+// of the routes it serves as their responses complete, so that clients move
+// elsewhere. After the drain timeout, the infrastructure provisioned for the
+// Gateway is torn down and the finalizer removed, which lets the routes go.
+//
+// Each replica serves traffic from its own proxy, and the leader cannot see
+// the requests in flight on the others, so the drain lasts for a fixed time.
+
+// gatewayFinalizer holds a deleted Gateway until it is drained.
+const gatewayFinalizer = "gari.gke-labs.dev/drain"
+
+// defaultDrainTimeout is how long a deleted Gateway is drained by default.
+const defaultDrainTimeout = 30 * time.Second
+
+// gatewayReasonDraining is the reason of the Events recorded while a Gateway
+// is drained.
+const gatewayReasonDraining = "Draining"
+
+// drainTimeout returns the DrainTimeout of r, with the default filled in.
+func (r *GatewayReconciler) drainTimeout() time.Duration {
+	if r.DrainTimeout <= 0 {
+		return defaultDrainTimeout
+	}
+	return r.DrainTimeout
+}
+
+// addGatewayFinalizer adds gatewayFinalizer to a managed Gateway.
+func (r *GatewayReconciler) addGatewayFinalizer(ctx context.Context, gw *gatewayv1.Gateway) error {
+	if controllerutil.ContainsFinalizer(gw, gatewayFinalizer) {
+		return nil
+	}
+	original := gw.DeepCopy()
+	controllerutil.AddFinalizer(gw, gatewayFinalizer)
+	return r.Patch(ctx, gw, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
+
+// removeGatewayFinalizer removes gatewayFinalizer from a Gateway, once it is
+// drained or no longer managed by us.
+func (r *GatewayReconciler) removeGatewayFinalizer(ctx context.Context, gw *gatewayv1.Gateway) error {
+	if !controllerutil.ContainsFinalizer(gw, gatewayFinalizer) {
+		return nil
+	}
+	original := gw.DeepCopy()
+	controllerutil.RemoveFinalizer(gw, gatewayFinalizer)
+	return client.IgnoreNotFound(r.Patch(ctx, gw, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})))
+}
+
+// drainGateway drains a deleted Gateway, then tears down its infrastructure
+// and removes its finalizer.
+func (r *GatewayReconciler) drainGateway(ctx context.Context, gw *gatewayv1.Gateway) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+	r.addressRetries.reset(client.ObjectKeyFromObject(gw))
+	if !controllerutil.ContainsFinalizer(gw, gatewayFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if markDraining(gw) {
+		if err := applyStatus(ctx, r.Client, &r.statusWrites, gw, &gw.Status); err != nil {
+			return statusWriteFailed(l, err, "unable to update Gateway status")
+		}
+		eventf(r.Recorder, gw, corev1.EventTypeNormal, gatewayReasonDraining, "Draining connections for %s before deleting the Gateway", r.drainTimeout())
+	}
+	if remaining := time.Until(gw.DeletionTimestamp.Add(r.drainTimeout())); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	if err := r.teardownInfrastructure(ctx, gw); err != nil {
+		l.Error(err, "unable to tear down Gateway infrastructure")
+		return ctrl.Result{}, err
+	}
+	if err := r.removeGatewayFinalizer(ctx, gw); err != nil {
+		l.Error(err, "unable to remove Gateway finalizer")
+		return ctrl.Result{}, err
+	}
+	l.Info("Drained Gateway")
+	return ctrl.Result{}, nil
+}
+
+// markDraining reports the Gateway and its listeners as no longer programmed
+// or accepting traffic, returning whether any condition changed.
+func markDraining(gw *gatewayv1.Gateway) bool {
+	message := "Gateway is being deleted, draining connections"
+	changed := conditions.Set(&gw.Status.Conditions, gw.Generation, metav1.Condition{
+		Type:    string(gatewayv1.GatewayConditionProgrammed),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(gatewayv1.GatewayReasonPending),
+		Message: message,
+	})
+	for i := range gw.Status.Listeners {
+		ls := &gw.Status.Listeners[i]
+		for _, conditionType := range []gatewayv1.ListenerConditionType{gatewayv1.ListenerConditionAccepted, gatewayv1.ListenerConditionProgrammed} {
+			if conditions.Set(&ls.Conditions, gw.Generation, metav1.Condition{
+				Type:    string(conditionType),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(gatewayv1.ListenerReasonPending),
+				Message: message,
+			}) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// teardownInfrastructure deletes the resources provisioned for a Gateway,
+// rather than leaving them to the garbage collector once the Gateway is gone,
+// so that traffic stops reaching the proxy before the routes are dropped.
+func (r *GatewayReconciler) teardownInfrastructure(ctx context.Context, gw *gatewayv1.Gateway) error {
+	if !r.ProvisionServices {
+		return nil
+	}
+	key := types.NamespacedName{Namespace: gw.Namespace, Name: provisionedName(gw)}
+	for _, obj := range []client.Object{&corev1.Service{}, &discoveryv1.EndpointSlice{}} {
+		if err := r.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !metav1.IsControlledBy(obj, gw) {
+			continue
+		}
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("deleting %s: %w", key, err)
+		}
+	}
+	return nil
+}
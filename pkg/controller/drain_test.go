@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestDrainGateway(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gw := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw", UID: "gw-uid"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "ours",
+			Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(gw).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		gw,
+	).Build()
+	r := &GatewayReconciler{
+		Client:            c,
+		Scheme:            scheme,
+		ProxyService:      types.NamespacedName{Namespace: "gari-system", Name: "proxy"},
+		ProvisionServices: true,
+		DrainTimeout:      time.Hour,
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gw)}
+	serviceKey := types.NamespacedName{Namespace: "default", Name: "gw-ours"}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actual gatewayv1.Gateway
+	if err := c.Get(ctx, req.NamespacedName, &actual); err != nil {
+		t.Fatalf("unable to get Gateway: %v", err)
+	}
+	if !controllerutil.ContainsFinalizer(&actual, gatewayFinalizer) {
+		t.Fatalf("expected the Gateway to hold the drain finalizer, got %v", actual.Finalizers)
+	}
+
+	// A deleted Gateway is held while it drains, with its listeners no
+	// longer accepting traffic.
+	if err := c.Delete(ctx, &actual); err != nil {
+		t.Fatalf("unable to delete Gateway: %v", err)
+	}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+		t.Errorf("expected a requeue at the end of the drain, got %v", result.RequeueAfter)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &actual); err != nil {
+		t.Fatalf("expected the Gateway to be kept while draining, got %v", err)
+	}
+	for _, ls := range actual.Status.Listeners {
+		if condition := meta.FindStatusCondition(ls.Conditions, string(gatewayv1.ListenerConditionAccepted)); condition == nil || condition.Status != metav1.ConditionFalse {
+			t.Errorf("expected listener %s to no longer be accepted, got %v", ls.Name, condition)
+		}
+	}
+	if err := c.Get(ctx, serviceKey, &corev1.Service{}); err != nil {
+		t.Errorf("expected the Service to be kept while draining, got %v", err)
+	}
+
+	// Once drained, the infrastructure is torn down and the Gateway let go.
+	r.DrainTimeout = time.Nanosecond
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(ctx, serviceKey, &corev1.Service{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the Service to be deleted, got %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &actual); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the Gateway to be deleted, got %v", err)
+	}
+}
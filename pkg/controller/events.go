@@ -0,0 +1,38 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventSource is the component name under which the controller records
+// Kubernetes Events.
+const EventSource = "gateway-api-reference-implementation"
+
+// eventReasonProgrammed is recorded when a route is programmed into the proxy;
+// it is not a Gateway API route condition reason.
+const eventReasonProgrammed = "Programmed"
+
+// eventf records an Event on obj if a recorder is configured. Events are only
+// recorded when an object's status changes, so that `kubectl describe` shows
+// each transition once.
+func eventf(recorder record.EventRecorder, obj runtime.Object, eventType, reason, messageFmt string, args ...any) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,279 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/conditions"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayxv1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+)
+
+var (
+	tcpRouteGVK    = gatewayv1alpha2.SchemeGroupVersion.WithKind("TCPRoute")
+	tlsRouteGVK    = gatewayv1alpha2.SchemeGroupVersion.WithKind("TLSRoute")
+	udpRouteGVK    = gatewayv1alpha2.SchemeGroupVersion.WithKind("UDPRoute")
+	listenerSetGVK = gatewayxv1alpha1.SchemeGroupVersion.WithKind("XListenerSet")
+)
+
+// AddExperimentalToScheme registers the experimental-channel API types.
+func AddExperimentalToScheme(scheme *runtime.Scheme) error {
+	if err := gatewayv1alpha2.Install(scheme); err != nil {
+		return err
+	}
+	return gatewayxv1alpha1.Install(scheme)
+}
+
+// SetupExperimentalWithManager sets up the reconcilers of the
+// experimental-channel APIs whose CRDs are installed. The HTTPRouteReconciler
+// applies BackendTLSPolicies itself when its ExperimentalAPIs field is set.
+// The reconcilers serve the GatewayClasses of controllerName, and retry failed
+// reconciles with backoff.
+func SetupExperimentalWithManager(mgr ctrl.Manager, controllerName gatewayv1.GatewayController, backoff Backoff) error {
+	l := mgr.GetLogger().WithName("experimental")
+	setups := []struct {
+		gvk   schema.GroupVersionKind
+		setup func() error
+	}{
+		{gvk: backendTLSPolicyGVK, setup: func() error {
+			return (&BackendTLSPolicyReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), ControllerName: controllerName, Backoff: backoff}).SetupWithManager(mgr)
+		}},
+		{gvk: tcpRouteGVK, setup: func() error {
+			return newUnsupportedRouteReconciler(mgr, controllerName, backoff, &gatewayv1alpha2.TCPRoute{}, func(obj client.Object) ([]gatewayv1.ParentReference, *gatewayv1.RouteStatus) {
+				route := obj.(*gatewayv1alpha2.TCPRoute)
+				return route.Spec.ParentRefs, &route.Status.RouteStatus
+			}).SetupWithManager(mgr)
+		}},
+		{gvk: tlsRouteGVK, setup: func() error {
+			return newUnsupportedRouteReconciler(mgr, controllerName, backoff, &gatewayv1alpha2.TLSRoute{}, func(obj client.Object) ([]gatewayv1.ParentReference, *gatewayv1.RouteStatus) {
+				route := obj.(*gatewayv1alpha2.TLSRoute)
+				return route.Spec.ParentRefs, &route.Status.RouteStatus
+			}).SetupWithManager(mgr)
+		}},
+		{gvk: udpRouteGVK, setup: func() error {
+			return newUnsupportedRouteReconciler(mgr, controllerName, backoff, &gatewayv1alpha2.UDPRoute{}, func(obj client.Object) ([]gatewayv1.ParentReference, *gatewayv1.RouteStatus) {
+				route := obj.(*gatewayv1alpha2.UDPRoute)
+				return route.Spec.ParentRefs, &route.Status.RouteStatus
+			}).SetupWithManager(mgr)
+		}},
+		{gvk: listenerSetGVK, setup: func() error {
+			return (&ListenerSetReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), ControllerName: controllerName, Backoff: backoff}).SetupWithManager(mgr)
+		}},
+	}
+	for _, s := range setups {
+		installed, err := apiInstalled(mgr.GetRESTMapper(), s.gvk)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			l.Info("Experimental API is not installed, skipping", "kind", s.gvk.Kind)
+			continue
+		}
+		if err := s.setup(); err != nil {
+			return fmt.Errorf("unable to set up %s: %w", s.gvk.Kind, err)
+		}
+	}
+	return nil
+}
+
+// unsupportedRouteReconciler rejects routes of a kind the proxy cannot serve,
+// such as TCPRoutes, on our Gateways, so that their users learn why no
+// traffic reaches them.
+type unsupportedRouteReconciler struct {
+	client.Client
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Backoff bounds the delays between retries of failed reconciles.
+	Backoff Backoff
+	// object is an empty route of the reconciled kind.
+	object client.Object
+	kind   string
+	// routeStatus returns the parentRefs and status of a route.
+	routeStatus func(client.Object) ([]gatewayv1.ParentReference, *gatewayv1.RouteStatus)
+	// statusWrites limits how often the status of each route is written.
+	statusWrites statusWriteLimiter
+}
+
+func newUnsupportedRouteReconciler(mgr ctrl.Manager, controllerName gatewayv1.GatewayController, backoff Backoff, object client.Object, routeStatus func(client.Object) ([]gatewayv1.ParentReference, *gatewayv1.RouteStatus)) *unsupportedRouteReconciler {
+	kind := fmt.Sprintf("%T", object)
+	if gvk, err := mgr.GetClient().GroupVersionKindFor(object); err == nil {
+		kind = gvk.Kind
+	}
+	return &unsupportedRouteReconciler{Client: mgr.GetClient(), ControllerName: controllerName, Backoff: backoff, object: object, kind: kind, routeStatus: routeStatus}
+}
+
+func (r *unsupportedRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+	controllerName := controllerNameOrDefault(r.ControllerName)
+
+	route := r.object.DeepCopyObject().(client.Object)
+	if err := r.Get(ctx, req.NamespacedName, route); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	original := route.DeepCopyObject().(client.Object)
+	parentRefs, status := r.routeStatus(route)
+	_, originalStatus := r.routeStatus(original)
+
+	var parentStatuses []gatewayv1.RouteParentStatus
+	for _, ps := range status.Parents {
+		if ps.ControllerName != controllerName {
+			parentStatuses = append(parentStatuses, ps)
+		}
+	}
+	for _, parentRef := range parentRefs {
+		managed, err := r.managedParent(ctx, route.GetNamespace(), parentRef)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !managed {
+			continue
+		}
+		var previous []metav1.Condition
+		for _, ps := range originalStatus.Parents {
+			if ps.ControllerName == controllerName && equality.Semantic.DeepEqual(ps.ParentRef, parentRef) {
+				previous = ps.Conditions
+			}
+		}
+		parentConditions, _ := conditions.Merge(previous, route.GetGeneration(), metav1.Condition{
+			Type:    string(gatewayv1.RouteConditionAccepted),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(gatewayv1.RouteReasonNotAllowedByListeners),
+			Message: fmt.Sprintf("%ss are not supported: the proxy only serves HTTPRoutes", r.kind),
+		})
+		parentStatuses = append(parentStatuses, gatewayv1.RouteParentStatus{
+			ParentRef:      parentRef,
+			ControllerName: controllerName,
+			Conditions:     parentConditions,
+		})
+	}
+	status.Parents = parentStatuses
+
+	if equality.Semantic.DeepEqual(originalStatus, status) {
+		return ctrl.Result{}, nil
+	}
+	if err := patchStatus(ctx, r.Client, &r.statusWrites, route, original); err != nil {
+		return statusWriteFailed(l, err, "unable to update route status", "kind", r.kind)
+	}
+	return ctrl.Result{}, nil
+}
+
+// managedParent reports whether parentRef, of a route in namespace, is a
+// Gateway of one of our GatewayClasses.
+func (r *unsupportedRouteReconciler) managedParent(ctx context.Context, namespace string, parentRef gatewayv1.ParentReference) (bool, error) {
+	if !ir.IsGatewayParent(parentRef) {
+		return false, nil
+	}
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	var gw gatewayv1.Gateway
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)}, &gw); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return managesGatewayClass(ctx, r.Client, controllerNameOrDefault(r.ControllerName), gw.Spec.GatewayClassName)
+}
+
+func (r *unsupportedRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(strings.ToLower(r.kind)).
+		For(r.object, builder.WithPredicates(specChanged)).
+		WithOptions(r.Backoff.options()).
+		Complete(r)
+}
+
+// ListenerSetReconciler rejects XListenerSets attached to our Gateways: the
+// proxy only serves the listeners of the Gateway itself.
+type ListenerSetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Backoff bounds the delays between retries of failed reconciles.
+	Backoff Backoff
+
+	// statusWrites limits how often the status of each XListenerSet is written.
+	statusWrites statusWriteLimiter
+}
+
+func (r *ListenerSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var ls gatewayxv1alpha1.XListenerSet
+	if err := r.Get(ctx, req.NamespacedName, &ls); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	parent := ls.Spec.ParentRef
+	if (parent.Group != nil && *parent.Group != gatewayv1.GroupName) || (parent.Kind != nil && *parent.Kind != kindGateway) {
+		return ctrl.Result{}, nil
+	}
+	namespace := ls.Namespace
+	if parent.Namespace != nil {
+		namespace = string(*parent.Namespace)
+	}
+	var gw gatewayv1.Gateway
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: string(parent.Name)}, &gw); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	managed, err := managesGatewayClass(ctx, r.Client, controllerNameOrDefault(r.ControllerName), gw.Spec.GatewayClassName)
+	if err != nil || !managed {
+		return ctrl.Result{}, err
+	}
+
+	original := ls.DeepCopy()
+	message := "ListenerSets are not supported: add the listeners to the Gateway instead"
+	accepted := conditions.Set(&ls.Status.Conditions, ls.Generation, metav1.Condition{
+		Type:    string(gatewayxv1alpha1.ListenerSetConditionAccepted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(gatewayxv1alpha1.ListenerSetReasonNotAllowed),
+		Message: message,
+	})
+	programmed := conditions.Set(&ls.Status.Conditions, ls.Generation, metav1.Condition{
+		Type:    string(gatewayxv1alpha1.ListenerSetConditionProgrammed),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(gatewayxv1alpha1.ListenerSetReasonNotAllowed),
+		Message: message,
+	})
+	if !accepted && !programmed {
+		return ctrl.Result{}, nil
+	}
+	if err := patchStatus(ctx, r.Client, &r.statusWrites, &ls, original); err != nil {
+		return statusWriteFailed(l, err, "unable to update XListenerSet status")
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *ListenerSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayxv1alpha1.XListenerSet{}, builder.WithPredicates(specChanged)).
+		WithOptions(r.Backoff.options()).
+		Complete(r)
+}
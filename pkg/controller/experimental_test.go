@@ -0,0 +1,97 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestUnsupportedRouteReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := AddExperimentalToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	otherStatus := gatewayv1.RouteParentStatus{
+		ParentRef:      gatewayv1.ParentReference{Name: "other-gw"},
+		ControllerName: "example.com/other",
+		Conditions:     []metav1.Condition{{Type: string(gatewayv1.RouteConditionAccepted), Status: metav1.ConditionTrue, Reason: "Accepted"}},
+	}
+	route := &gatewayv1alpha2.TCPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"},
+		Spec: gatewayv1alpha2.TCPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}, {Name: "other-gw"}}},
+		},
+		Status: gatewayv1alpha2.TCPRouteStatus{RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{otherStatus}}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(route).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "theirs"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.com/other"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "ours"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other-gw"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "theirs"},
+		},
+		route,
+	).Build()
+
+	r := &unsupportedRouteReconciler{
+		Client: c,
+		object: &gatewayv1alpha2.TCPRoute{},
+		kind:   "TCPRoute",
+		routeStatus: func(obj client.Object) ([]gatewayv1.ParentReference, *gatewayv1.RouteStatus) {
+			route := obj.(*gatewayv1alpha2.TCPRoute)
+			return route.Spec.ParentRefs, &route.Status.RouteStatus
+		},
+	}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual gatewayv1alpha2.TCPRoute
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(route), &actual); err != nil {
+		t.Fatalf("unable to get TCPRoute: %v", err)
+	}
+	if len(actual.Status.Parents) != 2 {
+		t.Fatalf("expected two parent statuses, got %v", actual.Status.Parents)
+	}
+	if actual.Status.Parents[0].ControllerName != otherStatus.ControllerName {
+		t.Errorf("expected the other controller's status to be preserved, got %v", actual.Status.Parents[0])
+	}
+	ours := actual.Status.Parents[1]
+	if ours.ParentRef.Name != "gw" || ours.Conditions[0].Reason != string(gatewayv1.RouteReasonNotAllowedByListeners) {
+		t.Errorf("expected gw to reject the route with NotAllowedByListeners, got %v", ours)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// WasmPluginConfigMapKey is the binaryData key holding the WebAssembly module
+// in a ConfigMap referenced by an ExtensionRef filter.
+const WasmPluginConfigMapKey = "plugin.wasm"
+
+// wasmPluginCache keeps compiled plugins across reconciles so that modules are
+// only recompiled when their ConfigMap changes.
+type wasmPluginCache struct {
+	mu      sync.Mutex
+	plugins map[types.NamespacedName]cachedWasmPlugin
+}
+
+type cachedWasmPlugin struct {
+	resourceVersion string
+	plugin          *proxy.WasmPlugin
+}
+
+// resolve loads the WebAssembly plugins referenced by ExtensionRef filters on
+// the given routes, reading their ConfigMaps from reader. Plugins that cannot
+// be loaded are replaced by a hook that fails requests, since the Gateway API
+// does not allow skipping an unresolved filter.
+func (c *wasmPluginCache) resolve(ctx context.Context, reader client.Reader, routes []gatewayv1.HTTPRoute) (map[types.NamespacedName]proxy.RequestHook, error) {
+	l := log.FromContext(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.plugins == nil {
+		c.plugins = map[types.NamespacedName]cachedWasmPlugin{}
+	}
+	hooks := map[types.NamespacedName]proxy.RequestHook{}
+
+	for _, route := range routes {
+		for _, rule := range route.Spec.Rules {
+			for _, filter := range rule.Filters {
+				key, ok := ir.ExtensionRefConfigMap(route.Namespace, filter)
+				if !ok {
+					continue
+				}
+				if _, done := hooks[key]; done {
+					continue
+				}
+
+				var cm corev1.ConfigMap
+				if err := reader.Get(ctx, key, &cm); err != nil {
+					if !apierrors.IsNotFound(err) {
+						return nil, err
+					}
+					hooks[key] = proxy.NewUnresolvedHook(fmt.Errorf("ConfigMap %s not found", key))
+					continue
+				}
+
+				if cached, ok := c.plugins[key]; ok && cached.resourceVersion == cm.ResourceVersion {
+					hooks[key] = cached.plugin
+					continue
+				}
+
+				wasm, ok := cm.BinaryData[WasmPluginConfigMapKey]
+				if !ok {
+					hooks[key] = proxy.NewUnresolvedHook(fmt.Errorf("ConfigMap %s has no %q binaryData key", key, WasmPluginConfigMapKey))
+					continue
+				}
+				plugin, err := proxy.NewWasmPlugin(ctx, key.String(), wasm)
+				if err != nil {
+					l.Error(err, "unable to load WebAssembly plugin", "configmap", key)
+					hooks[key] = proxy.NewUnresolvedHook(err)
+					continue
+				}
+				c.plugins[key] = cachedWasmPlugin{resourceVersion: cm.ResourceVersion, plugin: plugin}
+				hooks[key] = plugin
+			}
+		}
+	}
+
+	return hooks, nil
+}
+
+// prune drops the plugins that are not referenced by any route, given the
+// hooks resolved for every route. Plugins dropped from the cache are not
+// closed: requests that are still in flight against the previous route table
+// may be using them, and the interpreter's memory is reclaimed by the garbage
+// collector.
+func (c *wasmPluginCache) prune(referenced map[types.NamespacedName]proxy.RequestHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.plugins {
+		if _, ok := referenced[key]; !ok {
+			delete(c.plugins, key)
+		}
+	}
+}
+
+// mapConfigMapToRoutes enqueues the HTTPRoutes whose ExtensionRef filters
+// reference the changed ConfigMap, and the routes attached to Gateways whose
+// GatewayClass takes its parameters from it.
+func (r *HTTPRouteReconciler) mapConfigMapToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	var routes gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &routes, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list HTTPRoutes")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, route := range routes.Items {
+		if routeReferencesConfigMap(&route, obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&route)})
+		}
+	}
+	return append(requests, r.mapParametersToRoutes(ctx, obj)...)
+}
+
+func routeReferencesConfigMap(route *gatewayv1.HTTPRoute, name string) bool {
+	for _, rule := range route.Spec.Rules {
+		for _, filter := range rule.Filters {
+			if key, ok := ir.ExtensionRefConfigMap(route.Namespace, filter); ok && key.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
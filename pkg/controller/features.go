@@ -0,0 +1,49 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/gateway-api/pkg/features"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// supportedFeatures is the authoritative list of the Gateway API features
+// implemented by this controller. It is reported in the status of accepted
+// GatewayClasses so that conformance tooling can discover it; add to it
+// whenever a filter, route kind or listener capability is implemented.
+var supportedFeatures = []features.FeatureName{
+	features.SupportGateway,
+	features.SupportHTTPRoute,
+	features.SupportHTTPRouteBackendRequestHeaderModification,
+	features.SupportHTTPRouteParentRefPort,
+	features.SupportHTTPRouteRequestTimeout,
+}
+
+// supportedFeaturesStatus returns supportedFeatures in the form of the
+// GatewayClass status, sorted by name as the API requires.
+func supportedFeaturesStatus() []gatewayv1.SupportedFeature {
+	status := make([]gatewayv1.SupportedFeature, 0, len(supportedFeatures))
+	for _, name := range supportedFeatures {
+		status = append(status, gatewayv1.SupportedFeature{Name: gatewayv1.FeatureName(name)})
+	}
+	slices.SortFunc(status, func(a, b gatewayv1.SupportedFeature) int {
+		return strings.Compare(string(a.Name), string(b.Name))
+	})
+	return status
+}
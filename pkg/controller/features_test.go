@@ -0,0 +1,38 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestSupportedFeaturesStatus(t *testing.T) {
+	status := supportedFeaturesStatus()
+	if len(status) != len(supportedFeatures) {
+		t.Errorf("expected %d features, got %v", len(supportedFeatures), status)
+	}
+	if !slices.IsSortedFunc(status, func(a, b gatewayv1.SupportedFeature) int {
+		return strings.Compare(string(a.Name), string(b.Name))
+	}) {
+		t.Errorf("expected features sorted by name, got %v", status)
+	}
+	if len(status) > 64 {
+		t.Errorf("expected at most 64 features, got %d", len(status))
+	}
+}
@@ -16,13 +16,31 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/conditions"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
@@ -30,8 +48,30 @@ import (
 type GatewayClassReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Recorder, if set, records Kubernetes Events when the class is accepted.
+	Recorder record.EventRecorder
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Backoff bounds the delays between retries of failed reconciles.
+	Backoff Backoff
+	// DataPlane configures the proxies provisioned for the classes in
+	// DaemonSet mode; see provisionDataPlane.
+	DataPlane DataPlaneOptions
+
+	// resync delivers the GatewayClasses requeued by a Resyncer.
+	resync resyncChannel
+	// statusWrites limits how often the status of each GatewayClass is written.
+	statusWrites statusWriteLimiter
 }
 
+// Conditions reported on a GatewayClass whose deletion waits for the Gateways
+// that still use it.
+const (
+	gatewayClassConditionDeletionBlocked = "DeletionBlocked"
+	gatewayClassReasonGatewaysExist      = "GatewaysExist"
+)
+
 func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	l := log.FromContext(ctx)
 
@@ -40,115 +80,612 @@ func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if gc.Spec.ControllerName != ControllerName {
+	if gc.Spec.ControllerName != controllerNameOrDefault(r.ControllerName) {
 		return ctrl.Result{}, nil
 	}
 
-	// Update status to Accepted
-	gc.Status.Conditions = []metav1.Condition{
-		{
-			Type:               string(gatewayv1.GatewayClassConditionStatusAccepted),
-			Status:             metav1.ConditionTrue,
-			ObservedGeneration: gc.Generation,
-			LastTransitionTime: metav1.Now(),
-			Reason:             string(gatewayv1.GatewayClassReasonAccepted),
-			Message:            "GatewayClass accepted by reference implementation",
-		},
+	// The class keeps the gateways-exist finalizer while any Gateway uses it,
+	// so that it cannot be deleted from under them.
+	gateways, err := r.gatewaysForClass(ctx, gc.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	original := gc.DeepCopy()
+	if len(gateways) > 0 {
+		controllerutil.AddFinalizer(&gc, gatewayv1.GatewayClassFinalizerGatewaysExist)
+	} else {
+		controllerutil.RemoveFinalizer(&gc, gatewayv1.GatewayClassFinalizerGatewaysExist)
+	}
+	if len(gc.Finalizers) != len(original.Finalizers) {
+		if err := r.Patch(ctx, &gc, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+			l.Error(err, "unable to update GatewayClass finalizers")
+			return ctrl.Result{}, err
+		}
+	}
+	if !gc.DeletionTimestamp.IsZero() && len(gateways) == 0 {
+		return ctrl.Result{}, nil
 	}
 
-	if err := r.Status().Update(ctx, &gc); err != nil {
-		l.Error(err, "unable to update GatewayClass status")
+	acceptedCondition, params, err := classAcceptedCondition(ctx, r.Client, r.DataPlane, &gc)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	// The proxies of a class with invalid parameters are left as they are,
+	// so that a typo does not take its Gateways down.
+	if acceptedCondition.Status == metav1.ConditionTrue {
+		if err := r.provisionDataPlane(ctx, &gc, params); err != nil {
+			l.Error(err, "unable to provision data plane")
+			return ctrl.Result{}, err
+		}
+	}
+	accepted := conditions.Set(&gc.Status.Conditions, gc.Generation, acceptedCondition)
+	blocked := false
+	if !gc.DeletionTimestamp.IsZero() {
+		blocked = conditions.Set(&gc.Status.Conditions, gc.Generation, metav1.Condition{
+			Type:    gatewayClassConditionDeletionBlocked,
+			Status:  metav1.ConditionTrue,
+			Reason:  gatewayClassReasonGatewaysExist,
+			Message: fmt.Sprintf("Deletion is blocked until these Gateways are deleted: %s", strings.Join(gateways, ", ")),
+		})
+	}
+	var features []gatewayv1.SupportedFeature
+	if acceptedCondition.Status == metav1.ConditionTrue {
+		features = supportedFeaturesStatus()
+	}
+	featuresChanged := !equality.Semantic.DeepEqual(gc.Status.SupportedFeatures, features)
+	gc.Status.SupportedFeatures = features
+	if !accepted && !blocked && !featuresChanged {
+		return ctrl.Result{}, nil
+	}
+
+	if err := applyStatus(ctx, r.Client, &r.statusWrites, &gc, &gc.Status); err != nil {
+		return statusWriteFailed(l, err, "unable to update GatewayClass status")
+	}
+	if accepted && acceptedCondition.Status == metav1.ConditionTrue {
+		eventf(r.Recorder, &gc, corev1.EventTypeNormal, acceptedCondition.Reason, "GatewayClass accepted")
+	} else if accepted {
+		eventf(r.Recorder, &gc, corev1.EventTypeWarning, acceptedCondition.Reason, acceptedCondition.Message)
+	}
+	if blocked {
+		eventf(r.Recorder, &gc, corev1.EventTypeWarning, gatewayClassReasonGatewaysExist, "Deletion is blocked by %d Gateways", len(gateways))
+	}
 
 	return ctrl.Result{}, nil
 }
 
+// classAcceptedCondition returns the Accepted condition of a GatewayClass
+// served by us, along with its parameters if they can be used. The class is
+// accepted unless its parametersRef cannot be used.
+func classAcceptedCondition(ctx context.Context, c client.Reader, dataPlane DataPlaneOptions, gc *gatewayv1.GatewayClass) (metav1.Condition, *classParameters, error) {
+	acceptedCondition := metav1.Condition{
+		Type:    string(gatewayv1.GatewayClassConditionStatusAccepted),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(gatewayv1.GatewayClassReasonAccepted),
+		Message: "GatewayClass accepted by reference implementation",
+	}
+	params, err := resolveClassParameters(ctx, c, gc)
+	if err == nil {
+		if reason := dataPlane.dataPlaneUnavailable(params); reason != "" {
+			err = invalidParameters("%s", reason)
+		}
+	}
+	if err != nil {
+		var invalid *invalidParametersError
+		if !errors.As(err, &invalid) {
+			return metav1.Condition{}, nil, err
+		}
+		acceptedCondition.Status = metav1.ConditionFalse
+		acceptedCondition.Reason = string(gatewayv1.GatewayClassReasonInvalidParameters)
+		acceptedCondition.Message = fmt.Sprintf("Invalid parameters: %s", invalid.message)
+		return acceptedCondition, nil, nil
+	}
+	return acceptedCondition, params, nil
+}
+
+// gatewaysForClass returns the namespaced names of the Gateways that use the
+// GatewayClass, in sorted order.
+func (r *GatewayClassReconciler) gatewaysForClass(ctx context.Context, name string) ([]string, error) {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.MatchingFields{indexGatewayClassName: name}); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, gw := range gateways.Items {
+		names = append(names, client.ObjectKeyFromObject(&gw).String())
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// mapGatewayToClass enqueues the GatewayClass of a Gateway. Updates are mapped
+// for both the old and new object, so a Gateway moving to another class
+// releases the finalizer of the previous one.
+func (r *GatewayClassReconciler) mapGatewayToClass(_ context.Context, obj client.Object) []reconcile.Request {
+	gw, ok := obj.(*gatewayv1.Gateway)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: string(gw.Spec.GatewayClassName)}}}
+}
+
+// mapParametersToClasses enqueues the GatewayClasses whose parametersRef
+// refers to the changed ConfigMap or GatewayClassConfig.
+func (r *GatewayClassReconciler) mapParametersToClasses(ctx context.Context, obj client.Object) []reconcile.Request {
+	var classes gatewayv1.GatewayClassList
+	if err := r.List(ctx, &classes); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list GatewayClasses")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, gc := range classes.Items {
+		if referencesParameters(&gc, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gc)})
+		}
+	}
+	return requests
+}
+
 func (r *GatewayClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&gatewayv1.GatewayClass{}).
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.GatewayClass{}, builder.WithPredicates(specChanged)).
+		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.mapGatewayToClass), builder.WithPredicates(specChanged)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapParametersToClasses)).
+		Watches(&v1alpha1.GatewayClassConfig{}, handler.EnqueueRequestsFromMapFunc(r.mapParametersToClasses), builder.WithPredicates(specChanged)).
+		Owns(&appsv1.DaemonSet{})
+	if r.resync != nil {
+		b = b.WatchesRawSource(r.resync.source())
+	}
+	return b.WithOptions(r.Backoff.options()).Complete(r)
 }
 
 type GatewayReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Recorder, if set, records Kubernetes Events when the Gateway is
+	// programmed or waiting for an address.
+	Recorder record.EventRecorder
+	// ProxyService is the Service exposing the shared proxy. Its load
+	// balancer address is published on every Gateway that has no Service of
+	// its own; see proxyService.
+	ProxyService types.NamespacedName
+	// ProvisionServices, if set, creates a Service owned by each Gateway
+	// instead of publishing the address of the ProxyService; see
+	// provisionService.
+	ProvisionServices bool
+	// DNSEndpoints, if set, publishes the hostnames of the HTTPRoutes
+	// accepted by each Gateway in a DNSEndpoint for external-dns; see
+	// reconcileDNSEndpoint. It requires the DNSEndpoint CRD.
+	DNSEndpoints bool
+	// DataPlaneNamespace is the namespace of the proxies provisioned for the
+	// classes in DaemonSet mode, whose nodes' addresses are published on the
+	// Gateways of those classes; see DataPlaneOptions.
+	DataPlaneNamespace string
+	// Shard, if set, restricts the reconciler to the Gateways labeled with
+	// GatewayShardLabel set to it, and otherwise to the Gateways without the
+	// label.
+	Shard string
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Backoff bounds the delays between retries of failed reconciles and
+	// of checks of Gateways waiting for an address.
+	Backoff Backoff
+	// DrainTimeout is how long a deleted Gateway keeps serving the requests
+	// in flight before its infrastructure is torn down, or
+	// defaultDrainTimeout if zero; see drainGateway.
+	DrainTimeout time.Duration
+
+	// resync delivers the Gateways requeued by a Resyncer.
+	resync resyncChannel
+	// addressRetries delays the checks of Gateways whose Service has no
+	// address yet.
+	addressRetries retryBackoff
+	// statusWrites limits how often the status of each Gateway is written.
+	statusWrites statusWriteLimiter
 }
 
+// DefaultProxyServiceName is the name of the Service exposing the proxy in the
+// default install.
+const DefaultProxyServiceName = "gari-proxy"
+
+// gatewayNameLabel is set on the resources that belong to a single Gateway.
+const gatewayNameLabel = "gateway.networking.k8s.io/gateway-name"
+
 func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	l := log.FromContext(ctx)
+	defer r.recordManagedGateways(ctx)
 
 	var gw gatewayv1.Gateway
 	if err := r.Get(ctx, req.NamespacedName, &gw); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.addressRetries.reset(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	if !ir.InShard(&gw, r.Shard) {
+		r.addressRetries.reset(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
 
 	// Check if the GatewayClass is managed by us
 	var gc gatewayv1.GatewayClass
 	if err := r.Get(ctx, client.ObjectKey{Name: string(gw.Spec.GatewayClassName)}, &gc); err != nil {
 		l.Error(err, "unable to fetch GatewayClass", "gatewayclass", gw.Spec.GatewayClassName)
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.removeGatewayFinalizer(ctx, &gw)
+		}
+		return ctrl.Result{}, err
 	}
 
-	if gc.Spec.ControllerName != ControllerName {
-		return ctrl.Result{}, nil
+	if gc.Spec.ControllerName != controllerNameOrDefault(r.ControllerName) {
+		return ctrl.Result{}, r.removeGatewayFinalizer(ctx, &gw)
 	}
 
-	// Find the LoadBalancer IP of the gari-proxy service
-	var svc corev1.Service
-	if err := r.Get(ctx, client.ObjectKey{Name: "gari-proxy", Namespace: "default"}, &svc); err != nil {
-		l.Error(err, "unable to fetch gari-proxy service")
+	if !gw.DeletionTimestamp.IsZero() {
+		return r.drainGateway(ctx, &gw)
+	}
+	if err := r.addGatewayFinalizer(ctx, &gw); err != nil {
+		l.Error(err, "unable to add Gateway finalizer")
+		return ctrl.Result{}, err
+	}
+
+	params, err := resolveInfrastructureParameters(ctx, r.Client, &gw)
+	if err != nil {
+		var invalid *invalidParametersError
+		if !errors.As(err, &invalid) {
+			return ctrl.Result{}, err
+		}
+		return r.rejectInvalidParameters(ctx, &gw, invalid)
+	}
+
+	// The Gateways of a class in DaemonSet mode are exposed on the nodes
+	// running its proxies; the others, by a Service, as the class's
+	// serviceType selects. A class with invalid parameters is not accepted,
+	// so its mode does not matter.
+	classParams, err := resolveClassParameters(ctx, r.Client, &gc)
+	if invalid := (*invalidParametersError)(nil); err != nil && !errors.As(err, &invalid) {
 		return ctrl.Result{}, err
 	}
+	var svc *corev1.Service
+	var serviceType corev1.ServiceType
+	var addresses []string
+	var waiting string
+	if classParams != nil && classParams.DataPlaneMode == v1alpha1.DataPlaneModeDaemonSet {
+		addresses, err = r.dataPlaneAddresses(ctx, gc.Name)
+		if err != nil {
+			l.Error(err, "unable to list data plane Pods")
+			return ctrl.Result{}, err
+		}
+		waiting = fmt.Sprintf("Waiting for a proxy of GatewayClass %s to be ready on a node", gc.Name)
+	} else {
+		if r.ProvisionServices {
+			svc, err = r.provisionService(ctx, &gw, params, classParams)
+			if err != nil {
+				l.Error(err, "unable to provision Service")
+				return ctrl.Result{}, err
+			}
+		} else {
+			svc, err = r.proxyService(ctx, &gw)
+			if err != nil {
+				l.Error(err, "unable to fetch proxy Service")
+				return ctrl.Result{}, err
+			}
+		}
+		// A provisioned Service already is of the type the class selects,
+		// unless the Gateway selects its own.
+		serviceType = svc.Spec.Type
+		if !r.ProvisionServices && classParams != nil && classParams.ServiceType != "" {
+			serviceType = classParams.ServiceType
+		}
+		addresses, waiting, err = r.serviceAddresses(ctx, svc, serviceType)
+		if err != nil {
+			l.Error(err, "unable to list Nodes")
+			return ctrl.Result{}, err
+		}
+	}
 
-	var ip string
-	if len(svc.Status.LoadBalancer.Ingress) > 0 {
-		ip = svc.Status.LoadBalancer.Ingress[0].IP
-	}
-
-	if ip == "" {
-		l.Info("gari-proxy service has no LoadBalancer IP yet")
-		return ctrl.Result{Requeue: true}, nil
-	}
-
-	// Update status to Programmed and add address
-	gw.Status.Conditions = []metav1.Condition{
-		{
-			Type:               string(gatewayv1.GatewayConditionProgrammed),
-			Status:             metav1.ConditionTrue,
-			ObservedGeneration: gw.Generation,
-			LastTransitionTime: metav1.Now(),
-			Reason:             string(gatewayv1.GatewayReasonProgrammed),
-			Message:            "Gateway programmed by reference implementation",
-		},
-		{
-			Type:               string(gatewayv1.GatewayConditionAccepted),
-			Status:             metav1.ConditionTrue,
-			ObservedGeneration: gw.Generation,
-			LastTransitionTime: metav1.Now(),
-			Reason:             string(gatewayv1.GatewayReasonAccepted),
-			Message:            "Gateway accepted by reference implementation",
-		},
-	}
-	gw.Status.Addresses = []gatewayv1.GatewayStatusAddress{
-		{
-			Type:  ptr(gatewayv1.IPAddressType),
-			Value: ip,
-		},
-	}
-
-	if err := r.Status().Update(ctx, &gw); err != nil {
-		l.Error(err, "unable to update Gateway status")
+	refErrors, err := resolveCertificateRefs(ctx, r.Client, &gw)
+	if err != nil {
+		l.Error(err, "unable to resolve listener certificateRefs")
 		return ctrl.Result{}, err
 	}
 
-	l.Info("Updated Gateway status", "address", ip)
+	// Update status to Programmed and add address. Until the Service has an
+	// address, the Gateway is accepted but not programmed.
+	original := gw.Status.DeepCopy()
+	programmed := metav1.Condition{
+		Type:    string(gatewayv1.GatewayConditionProgrammed),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(gatewayv1.GatewayReasonProgrammed),
+		Message: "Gateway programmed by reference implementation",
+	}
+	gw.Status.Addresses = nil
+	for _, address := range addresses {
+		gw.Status.Addresses = append(gw.Status.Addresses, statusAddress(address))
+	}
+	if serviceType == corev1.ServiceTypeNodePort && len(addresses) > 0 {
+		programmed.Message += ", on node ports " + nodePorts(svc)
+	}
+	if len(addresses) == 0 {
+		programmed.Status = metav1.ConditionFalse
+		programmed.Reason = string(gatewayv1.GatewayReasonAddressNotAssigned)
+		programmed.Message = waiting
+	}
+	var debugDetails []string
+	if debugEnabled(&gw) {
+		debugDetails = gatewayDiagnostics(&gw, svc)
+		programmed.Message = debugMessage(programmed.Message, debugDetails)
+	}
+	setGatewayAccepted(&gw, programmed, refErrors)
+
+	if !equality.Semantic.DeepEqual(original, &gw.Status) {
+		if err := applyStatus(ctx, r.Client, &r.statusWrites, &gw, &gw.Status); err != nil {
+			return statusWriteFailed(l, err, "unable to update Gateway status")
+		}
+
+		l.Info("Updated Gateway status", "addresses", addresses)
+		if len(addresses) == 0 {
+			eventf(r.Recorder, &gw, corev1.EventTypeNormal, programmed.Reason, programmed.Message)
+		} else {
+			eventf(r.Recorder, &gw, corev1.EventTypeNormal, programmed.Reason, "Gateway programmed with address %s", strings.Join(addresses, ", "))
+		}
+		for _, ls := range gw.Status.Listeners {
+			for _, c := range ls.Conditions {
+				if c.Status != metav1.ConditionTrue {
+					eventf(r.Recorder, &gw, corev1.EventTypeWarning, c.Reason, "Listener %s: %s", ls.Name, c.Message)
+				}
+			}
+		}
+		for _, detail := range debugDetails {
+			eventf(r.Recorder, &gw, corev1.EventTypeNormal, eventReasonDebug, "%s", detail)
+		}
+	}
+
+	if r.DNSEndpoints {
+		if err := r.reconcileDNSEndpoint(ctx, &gw, addresses); err != nil {
+			l.Error(err, "unable to reconcile DNSEndpoint")
+			return ctrl.Result{}, err
+		}
+	}
 
+	// The Service and proxies are watched, so the retry only matters if
+	// their update is missed; back off to avoid polling them.
+	if len(addresses) == 0 {
+		return ctrl.Result{RequeueAfter: r.addressRetries.next(req.NamespacedName, r.Backoff)}, nil
+	}
+	r.addressRetries.reset(req.NamespacedName)
 	return ctrl.Result{}, nil
 }
 
+// proxyService returns the Service exposing a Gateway: the Service in the
+// Gateway's namespace labelled with its name if there is one, and the shared
+// ProxyService otherwise.
+func (r *GatewayReconciler) proxyService(ctx context.Context, gw *gatewayv1.Gateway) (*corev1.Service, error) {
+	var services corev1.ServiceList
+	if err := r.List(ctx, &services, client.InNamespace(gw.Namespace), client.MatchingLabels{gatewayNameLabel: gw.Name}); err != nil {
+		return nil, err
+	}
+	if len(services.Items) > 0 {
+		// Prefer the first by name, so that the choice is stable.
+		svc := slices.MinFunc(services.Items, func(a, b corev1.Service) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		return &svc, nil
+	}
+
+	var svc corev1.Service
+	if err := r.Get(ctx, r.ProxyService, &svc); err != nil {
+		return nil, err
+	}
+	return &svc, nil
+}
+
+// mapServiceToGateways enqueues the Gateway a Service is labelled with, or
+// every Gateway if the Service is the shared ProxyService.
+func (r *GatewayReconciler) mapServiceToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	if name, ok := obj.GetLabels()[gatewayNameLabel]; ok {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+	}
+	if client.ObjectKeyFromObject(obj) != r.ProxyService {
+		return nil
+	}
+	return r.allGateways(ctx)
+}
+
+// mapEndpointSliceToGateways enqueues every Gateway when the endpoints of the
+// ProxyService change, so that the provisioned EndpointSlices mirror them.
+func (r *GatewayReconciler) mapEndpointSliceToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != r.ProxyService.Namespace || obj.GetLabels()[discoveryv1.LabelServiceName] != r.ProxyService.Name {
+		return nil
+	}
+	return r.allGateways(ctx)
+}
+
+// allGateways enqueues every Gateway.
+func (r *GatewayReconciler) allGateways(ctx context.Context) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Gateways")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(gateways.Items))
+	for _, gw := range gateways.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
+	}
+	return requests
+}
+
+// setGatewayAccepted sets the conditions of an accepted Gateway, programmed as
+// given, and the statuses of its listeners.
+func setGatewayAccepted(gw *gatewayv1.Gateway, programmed metav1.Condition, refErrors map[gatewayv1.SectionName]*certificateRefError) {
+	conditions.Set(&gw.Status.Conditions, gw.Generation, programmed)
+	conditions.Set(&gw.Status.Conditions, gw.Generation, metav1.Condition{
+		Type:    string(gatewayv1.GatewayConditionAccepted),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(gatewayv1.GatewayReasonAccepted),
+		Message: "Gateway accepted by reference implementation",
+	})
+	gw.Status.Listeners = listenerStatuses(gw, refErrors)
+}
+
+// setGatewayInvalidParameters sets the conditions of a Gateway whose
+// infrastructure parameters cannot be used, neither accepted nor programmed,
+// and reports whether they changed.
+func setGatewayInvalidParameters(gw *gatewayv1.Gateway, invalid *invalidParametersError) bool {
+	message := fmt.Sprintf("Invalid infrastructure parameters: %s", invalid.message)
+	changed := conditions.Set(&gw.Status.Conditions, gw.Generation, metav1.Condition{
+		Type:    string(gatewayv1.GatewayConditionAccepted),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(gatewayv1.GatewayReasonInvalidParameters),
+		Message: message,
+	})
+	if conditions.Set(&gw.Status.Conditions, gw.Generation, metav1.Condition{
+		Type:    string(gatewayv1.GatewayConditionProgrammed),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(gatewayv1.GatewayReasonInvalid),
+		Message: message,
+	}) {
+		changed = true
+	}
+	return changed
+}
+
+// rejectInvalidParameters reports a Gateway whose infrastructure parameters
+// cannot be used as neither accepted nor programmed.
+func (r *GatewayReconciler) rejectInvalidParameters(ctx context.Context, gw *gatewayv1.Gateway, invalid *invalidParametersError) (ctrl.Result, error) {
+	if !setGatewayInvalidParameters(gw, invalid) {
+		return ctrl.Result{}, nil
+	}
+
+	if err := applyStatus(ctx, r.Client, &r.statusWrites, gw, &gw.Status); err != nil {
+		return statusWriteFailed(log.FromContext(ctx), err, "unable to update Gateway status")
+	}
+	eventf(r.Recorder, gw, corev1.EventTypeWarning, string(gatewayv1.GatewayReasonInvalidParameters), "Invalid infrastructure parameters: %s", invalid.message)
+	return ctrl.Result{}, nil
+}
+
+// mapParametersToGateways enqueues the Gateways whose infrastructure
+// parametersRef refers to the changed ConfigMap or GatewayConfig.
+func (r *GatewayReconciler) mapParametersToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Gateways")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, gw := range gateways.Items {
+		if referencesInfrastructureParameters(&gw, obj) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
+		}
+	}
+	return requests
+}
+
+// mapClassParametersToGateways enqueues the Gateways of the classes whose
+// parametersRef refers to the changed ConfigMap or GatewayClassConfig, since
+// their data plane mode decides how they are exposed.
+func (r *GatewayReconciler) mapClassParametersToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	var classes gatewayv1.GatewayClassList
+	if err := r.List(ctx, &classes); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list GatewayClasses")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, gc := range classes.Items {
+		if gc.Spec.ControllerName == controllerNameOrDefault(r.ControllerName) && referencesParameters(&gc, obj) {
+			requests = append(requests, r.gatewaysOfClass(ctx, gc.Name)...)
+		}
+	}
+	return requests
+}
+
+// mapClassToGateways enqueues the Gateways of the changed GatewayClass.
+func (r *GatewayReconciler) mapClassToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.gatewaysOfClass(ctx, obj.GetName())
+}
+
+// gatewaysOfClass enqueues the Gateways of a class.
+func (r *GatewayReconciler) gatewaysOfClass(ctx context.Context, className string) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.MatchingFields{indexGatewayClassName: className}); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Gateways")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(gateways.Items))
+	for _, gw := range gateways.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
+	}
+	return requests
+}
+
+// mapSecretToGateways enqueues the Gateways with a listener certificateRef to
+// the changed Secret.
+func (r *GatewayReconciler) mapSecretToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.MatchingFields{indexListenerSecrets: client.ObjectKeyFromObject(obj).String()}); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Gateways")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, gw := range gateways.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
+	}
+	return requests
+}
+
+// recordManagedGateways counts the Gateways of our shard whose GatewayClass is
+// managed by this controller. Gateways are counted again on every reconcile, which also
+// covers deletions and changes of class.
+func (r *GatewayReconciler) recordManagedGateways(ctx context.Context) {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Gateways")
+		return
+	}
+	var classes gatewayv1.GatewayClassList
+	if err := r.List(ctx, &classes); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list GatewayClasses")
+		return
+	}
+
+	ours := map[gatewayv1.ObjectName]bool{}
+	for _, gc := range classes.Items {
+		if gc.Spec.ControllerName == controllerNameOrDefault(r.ControllerName) {
+			ours[gatewayv1.ObjectName(gc.Name)] = true
+		}
+	}
+	managed := 0
+	for i := range gateways.Items {
+		if ours[gateways.Items[i].Spec.GatewayClassName] && ir.InShard(&gateways.Items[i], r.Shard) {
+			managed++
+		}
+	}
+	gatewaysManaged.Set(float64(managed))
+}
+
 func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&gatewayv1.Gateway{}).
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.Gateway{}, builder.WithPredicates(gatewayChanged)).
+		Watches(&corev1.Service{}, handler.EnqueueRequestsFromMapFunc(r.mapServiceToGateways)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapParametersToGateways)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.mapSecretToGateways), builder.OnlyMetadata).
+		Watches(&v1alpha1.GatewayConfig{}, handler.EnqueueRequestsFromMapFunc(r.mapParametersToGateways), builder.WithPredicates(specChanged)).
+		Watches(&gatewayv1.GatewayClass{}, handler.EnqueueRequestsFromMapFunc(r.mapClassToGateways), builder.WithPredicates(specChanged)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapClassParametersToGateways)).
+		Watches(&v1alpha1.GatewayClassConfig{}, handler.EnqueueRequestsFromMapFunc(r.mapClassParametersToGateways), builder.WithPredicates(specChanged)).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.mapDataPlanePodToGateways), builder.WithPredicates(dataPlanePodChanged)).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.mapNodeToGateways), builder.WithPredicates(nodeAddressChanged))
+	if r.ProvisionServices {
+		b = b.Owns(&corev1.Service{}).
+			Owns(&discoveryv1.EndpointSlice{}).
+			Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(r.mapEndpointSliceToGateways))
+	}
+	if r.DNSEndpoints {
+		b = b.Owns(newDNSEndpoint()).
+			Watches(&gatewayv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.mapRouteToGateways))
+	}
+	if r.resync != nil {
+		b = b.WatchesRawSource(r.resync.source())
+	}
+	return b.WithOptions(r.Backoff.options()).Complete(r)
 }
@@ -0,0 +1,214 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGatewayClassFinalizer(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gc := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+		Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+	}
+	gw := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "ours"},
+	}
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithStatusSubresource(gc).WithObjects(gc, gw).Build()
+	r := &GatewayClassReconciler{Client: c, Scheme: scheme}
+	reconcileClass := func() *gatewayv1.GatewayClass {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gc)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var actual gatewayv1.GatewayClass
+		if err := c.Get(ctx, client.ObjectKeyFromObject(gc), &actual); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			t.Fatalf("unable to get GatewayClass: %v", err)
+		}
+		return &actual
+	}
+
+	actual := reconcileClass()
+	if !controllerutil.ContainsFinalizer(actual, gatewayv1.GatewayClassFinalizerGatewaysExist) {
+		t.Errorf("expected finalizer while a Gateway uses the class, got %v", actual.Finalizers)
+	}
+
+	if err := c.Delete(ctx, actual); err != nil {
+		t.Fatalf("unable to delete GatewayClass: %v", err)
+	}
+	actual = reconcileClass()
+	if actual == nil {
+		t.Fatalf("expected GatewayClass to wait for its Gateways")
+	}
+	condition := meta.FindStatusCondition(actual.Status.Conditions, gatewayClassConditionDeletionBlocked)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != gatewayClassReasonGatewaysExist {
+		t.Errorf("expected DeletionBlocked=True, got %v", condition)
+	}
+
+	if err := c.Delete(ctx, gw); err != nil {
+		t.Fatalf("unable to delete Gateway: %v", err)
+	}
+	if actual := reconcileClass(); actual != nil {
+		t.Errorf("expected GatewayClass to be deleted, got finalizers %v", actual.Finalizers)
+	}
+}
+
+func TestGatewayProxyService(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	newLabelledService := func(namespace, name, gateway string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{gatewayNameLabel: gateway},
+		}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "gari-system", Name: "proxy"}},
+		newLabelledService("apps", "dedicated-b", "dedicated"),
+		newLabelledService("apps", "dedicated-a", "dedicated"),
+		newLabelledService("other", "shared", "shared"),
+	).Build()
+	r := &GatewayReconciler{Client: c, Scheme: scheme, ProxyService: types.NamespacedName{Namespace: "gari-system", Name: "proxy"}}
+
+	tests := []struct {
+		name     string
+		gateway  types.NamespacedName
+		expected types.NamespacedName
+	}{
+		{
+			name:     "labelled Service",
+			gateway:  types.NamespacedName{Namespace: "apps", Name: "dedicated"},
+			expected: types.NamespacedName{Namespace: "apps", Name: "dedicated-a"},
+		},
+		{
+			name:     "shared Service",
+			gateway:  types.NamespacedName{Namespace: "apps", Name: "shared"},
+			expected: types.NamespacedName{Namespace: "gari-system", Name: "proxy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := &gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: tt.gateway.Namespace, Name: tt.gateway.Name}}
+			svc, err := r.proxyService(context.Background(), gw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual := client.ObjectKeyFromObject(svc); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGatewayAddressNotAssigned(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gw := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "ours"},
+	}
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: DefaultProxyServiceName}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(gw, svc).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		gw,
+		svc,
+	).Build()
+	r := &GatewayReconciler{Client: c, Scheme: scheme, ProxyService: client.ObjectKeyFromObject(svc)}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gw)}
+
+	programmed := func() *metav1.Condition {
+		t.Helper()
+		var actual gatewayv1.Gateway
+		if err := c.Get(ctx, client.ObjectKeyFromObject(gw), &actual); err != nil {
+			t.Fatalf("unable to get Gateway: %v", err)
+		}
+		return meta.FindStatusCondition(actual.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+	}
+
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second} {
+		result, err := r.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.RequeueAfter != expected {
+			t.Errorf("expected requeue after %v, got %v", expected, result.RequeueAfter)
+		}
+	}
+	if condition := programmed(); condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != string(gatewayv1.GatewayReasonAddressNotAssigned) {
+		t.Errorf("expected Programmed=False with reason AddressNotAssigned, got %v", condition)
+	}
+
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.0.2.1"}}
+	if err := c.Status().Update(ctx, svc); err != nil {
+		t.Fatalf("unable to update Service: %v", err)
+	}
+	if requests := r.mapServiceToGateways(ctx, svc); len(requests) != 1 || requests[0] != req {
+		t.Errorf("expected Service to enqueue %v, got %v", req, requests)
+	}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("expected no requeue, got %v", result.RequeueAfter)
+	}
+	if condition := programmed(); condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Errorf("expected Programmed=True, got %v", condition)
+	}
+}
@@ -16,23 +16,92 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"regexp"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
 
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/audit"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/conditions"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
 	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 type HTTPRouteReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
-	Proxy  *proxy.Proxy
+	// Proxy receives the routes to serve: the proxy embedded in the
+	// controller, the server streaming them to standalone proxies, or both.
+	Proxy RouteSink
+	// Audit, if set, records the routes that are programmed, rejected or
+	// removed.
+	Audit *audit.Recorder
+	// Recorder, if set, records Kubernetes Events on routes when they are
+	// accepted, rejected or programmed.
+	Recorder record.EventRecorder
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Backoff bounds the delays between retries of failed reconciles.
+	Backoff Backoff
+
+	// Shard, if set, restricts the reconciler to the Gateways labeled with
+	// GatewayShardLabel set to it, and otherwise to the Gateways without the
+	// label.
+	Shard string
+
+	// ExperimentalAPIs enables the experimental-channel APIs that affect how
+	// HTTPRoutes are served, currently BackendTLSPolicy, if their CRDs are
+	// installed.
+	ExperimentalAPIs bool
+	// ProxyUpdateDelay is the window over which route changes are coalesced
+	// before they are applied to the proxy, so that a burst of changes swaps
+	// the proxy's route table once. Changes are applied right away if zero.
+	ProxyUpdateDelay time.Duration
+	// BuiltinRoutes are served along with the routes of HTTPRoutes, ahead of
+	// them; see ACMESolverRoute.
+	BuiltinRoutes []proxy.HTTPRoute
+	// Backends configures how the Services of backendRefs are reached.
+	Backends ir.BackendOptions
+
+	wasmPlugins wasmPluginCache
+	// routeTable holds the translation of each route served by the proxy.
+	routeTable routeTable
+	// backendTLSPolicies is set if BackendTLSPolicies are applied to
+	// backends.
+	backendTLSPolicies bool
+	// synced is set once the complete route table has been built at startup.
+	synced atomic.Bool
+	// resync delivers the routes requeued by a Resyncer.
+	resync resyncChannel
+	// elected is closed once this replica is the leader; see leading.
+	elected <-chan struct{}
+	// takeover delivers the routes requeued once this replica is elected.
+	takeover resyncChannel
+	// statusWrites limits how often the status of each route is written.
+	statusWrites statusWriteLimiter
 }
 
 func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -40,186 +109,625 @@ func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	var route gatewayv1.HTTPRoute
 	if err := r.Get(ctx, req.NamespacedName, &route); err != nil {
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.removeRoute(ctx, req.NamespacedName)
+		if r.leading() {
+			r.Audit.RecordRemoved(ctx, string(kindHTTPRoute), req.NamespacedName)
+		}
+		return ctrl.Result{}, nil
 	}
 
 	// Update status
-	// For each parentRef, we should add a ParentStatus
+	// For each parentRef managed by us, we add a ParentStatus. Our entries are
+	// rebuilt from the current parentRefs, so those of removed parentRefs are
+	// pruned; entries written by other controllers, or by the instances
+	// serving other shards, are preserved.
+	controllerName := controllerNameOrDefault(r.ControllerName)
+	originalRoute := route.DeepCopy()
+	original := &originalRoute.Status
 	var parentStatuses []gatewayv1.RouteParentStatus
+	for _, ps := range route.Status.Parents {
+		if ps.ControllerName != controllerName {
+			parentStatuses = append(parentStatuses, ps)
+			continue
+		}
+		owned, err := r.ownsParent(ctx, route.Namespace, ps.ParentRef)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !owned {
+			parentStatuses = append(parentStatuses, ps)
+		}
+	}
 
-	acceptedStatus := metav1.ConditionTrue
-	acceptedReason := gatewayv1.RouteReasonAccepted
-	acceptedMessage := "Route accepted by reference implementation"
+	in, err := r.inputReader().routeInput(ctx, &route)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	built := ir.Build(in)
+	resolvedRefs := resolvedRefsCondition(&route, in.Targets)
+
+	others, err := r.routesSharingHostname(ctx, &route)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	conflicted := conflictedCondition(routeConflicts(&route, others, controllerName))
 
-	if err := r.validateRoute(&route); err != nil {
-		acceptedStatus = metav1.ConditionFalse
-		acceptedReason = gatewayv1.RouteReasonUnsupportedValue
-		acceptedMessage = fmt.Sprintf("Invalid route: %v", err)
+	// Routes with the debug annotation get the details behind their
+	// conditions in the messages, and as Events.
+	debug := debugEnabled(&route)
+	var debugDetails, precedence []string
+	if debug {
+		backends := backendDiagnostics(&route, in.Targets)
+		resolvedRefs.Message = debugMessage(resolvedRefs.Message, backends)
+		precedence = precedenceDiagnostics(&route, others, controllerName)
+		debugDetails = append(backends, precedence...)
 	}
 
-	for _, parentRef := range route.Spec.ParentRefs {
-		// For simplicity, we assume all parents are Gateways and we accept them if they are in the same namespace
-		// or if we want to be more thorough, we should check the Gateway and its GatewayClass.
-		// But for now, let's just accept everything to get the test to pass.
+	// The route is programmed if at least one parent accepts it.
+	anyAccepted := false
+	rejectedMessage := "Route has no parentRefs managed by this controller"
+	for _, parent := range built.Parents[client.ObjectKeyFromObject(&route)] {
+		accepted := parentAcceptedCondition(parent)
+		if parent.Accepted {
+			anyAccepted = true
+		} else {
+			rejectedMessage = parent.Message
+		}
 
+		if debug {
+			listeners := listenerDiagnostics(parent.Listeners, route.Spec.Hostnames)
+			accepted.Message = debugMessage(accepted.Message, append(listeners, precedence...))
+			for _, detail := range listeners {
+				debugDetails = append(debugDetails, fmt.Sprintf("Gateway %s: %s", parent.ParentRef.Name, detail))
+			}
+		}
+		desired := []metav1.Condition{accepted, resolvedRefs}
+		if conflicted != nil && parent.Accepted {
+			desired = append(desired, *conflicted)
+		}
+		parentConditions, _ := conditions.Merge(existingParentConditions(original, parent.ParentRef, controllerName), route.Generation, desired...)
 		parentStatuses = append(parentStatuses, gatewayv1.RouteParentStatus{
-			ParentRef:      parentRef,
-			ControllerName: ControllerName,
-			Conditions: []metav1.Condition{
-				{
-					Type:               string(gatewayv1.RouteConditionAccepted),
-					Status:             acceptedStatus,
-					ObservedGeneration: route.Generation,
-					LastTransitionTime: metav1.Now(),
-					Reason:             string(acceptedReason),
-					Message:            acceptedMessage,
-				},
-				{
-					Type:               string(gatewayv1.RouteConditionResolvedRefs),
-					Status:             metav1.ConditionTrue,
-					ObservedGeneration: route.Generation,
-					LastTransitionTime: metav1.Now(),
-					Reason:             string(gatewayv1.RouteReasonResolvedRefs),
-					Message:            "All references resolved",
-				},
-			},
+			ParentRef:      parent.ParentRef,
+			ControllerName: controllerName,
+			Conditions:     parentConditions,
 		})
 	}
 	route.Status.Parents = parentStatuses
-	if err := r.Status().Update(ctx, &route); err != nil {
-		l.Error(err, "unable to update HTTPRoute status")
-		return ctrl.Result{}, err
+	statusChanged := !equality.Semantic.DeepEqual(original, &route.Status)
+	leading := r.leading()
+	if statusChanged && leading {
+		if err := patchStatus(ctx, r.Client, &r.statusWrites, &route, originalRoute); err != nil {
+			return statusWriteFailed(l, err, "unable to update HTTPRoute status")
+		}
+		r.recordStatusEvents(&route)
+		for _, detail := range debugDetails {
+			eventf(r.Recorder, &route, corev1.EventTypeNormal, eventReasonDebug, "%s", detail)
+		}
 	}
 
-	// If the route is not accepted, we should not update the proxy
-	if acceptedStatus == metav1.ConditionFalse {
+	// If the route is not accepted, it must not be served. It may have been
+	// programmed before its parentRefs changed, so drop it from the proxy.
+	if !anyAccepted {
+		if statusChanged {
+			r.updateRoute(ctx, &route, nil)
+		}
+		if leading {
+			r.Audit.Record(ctx, string(kindHTTPRoute), &route, audit.ActionRejected, rejectedMessage)
+		}
 		return ctrl.Result{}, nil
 	}
 
+	r.updateRoute(ctx, &route, &built.Routes[0])
+	if leading {
+		r.Audit.Record(ctx, string(kindHTTPRoute), &route, audit.ActionProgrammed, "")
+	}
+	if statusChanged {
+		eventf(r.Recorder, &route, corev1.EventTypeNormal, eventReasonProgrammed, "Route programmed into the proxy")
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// updateProxy recomputes the proxy configuration from all accepted routes. It
+// runs when the route table is built at startup and on periodic resyncs;
+// reconciles of a single route go through updateRoute.
+func (r *HTTPRouteReconciler) updateProxy(ctx context.Context) error {
+	r.routeTable.mu.Lock()
+	defer r.routeTable.mu.Unlock()
+
+	start := time.Now()
 	var routes gatewayv1.HTTPRouteList
 	if err := r.List(ctx, &routes); err != nil {
-		return ctrl.Result{}, err
+		return err
 	}
 
-	newRoutes := r.extractRoutes(ctx, &routes)
+	translationStart := time.Now()
+	in, err := r.inputReader().buildInput(ctx, routes.Items)
+	if err != nil {
+		return err
+	}
+	r.wasmPlugins.prune(in.Policies.Hooks)
 
-	r.Proxy.UpdateRoutes(newRoutes)
-	l.Info("Updated proxy routes", "count", len(newRoutes))
+	newRoutes := ir.Build(in).Routes
+	translationDuration.Observe(time.Since(translationStart).Seconds())
 
-	return ctrl.Result{}, nil
+	r.routeTable.reset(routes.Items, newRoutes, controllerNameOrDefault(r.ControllerName))
+	r.Proxy.UpdateRoutes(slices.Concat(newRoutes, r.routeTable.ingressRoutes(), r.BuiltinRoutes))
+	proxyUpdateDuration.Observe(time.Since(start).Seconds())
+	recordRouteMetrics(routes.Items, controllerNameOrDefault(r.ControllerName))
+	log.FromContext(ctx).Info("Updated proxy routes", "count", len(newRoutes))
+	return nil
 }
 
-func (r *HTTPRouteReconciler) validateRoute(route *gatewayv1.HTTPRoute) error {
-	for _, rule := range route.Spec.Rules {
-		for _, match := range rule.Matches {
-			for _, header := range match.Headers {
-				if header.Type != nil && *header.Type == gatewayv1.HeaderMatchRegularExpression {
-					if _, err := regexp.Compile(header.Value); err != nil {
-						return fmt.Errorf("invalid regular expression in header match: %w", err)
-					}
-				}
+// updateRoute applies the translation of the route being reconciled to the
+// proxy, leaving the other routes as they are; translated is nil if the route
+// is not served. The route is used as given rather than read from the cache,
+// which may not yet reflect the status just written. Status-only updates are
+// filtered out, so there is no later event that would catch up.
+func (r *HTTPRouteReconciler) updateRoute(ctx context.Context, route *gatewayv1.HTTPRoute, translated *proxy.HTTPRoute) {
+	r.routeTable.mu.Lock()
+	defer r.routeTable.mu.Unlock()
+
+	start := time.Now()
+	r.routeTable.update(route, translated, controllerNameOrDefault(r.ControllerName))
+	r.publishRoutes()
+	proxyUpdateDuration.Observe(time.Since(start).Seconds())
+	log.FromContext(ctx).Info("Updated proxy route", "programmed", translated != nil)
+}
+
+// removeRoute stops serving a deleted route.
+func (r *HTTPRouteReconciler) removeRoute(ctx context.Context, key types.NamespacedName) {
+	r.routeTable.mu.Lock()
+	defer r.routeTable.mu.Unlock()
+
+	r.routeTable.remove(key)
+	r.publishRoutes()
+	log.FromContext(ctx).Info("Removed proxy route")
+}
+
+// updateIngress serves the routes translated from the Ingress with the given
+// name in place of those translated before; see IngressReconciler.
+func (r *HTTPRouteReconciler) updateIngress(ctx context.Context, key types.NamespacedName, translated []proxy.HTTPRoute) {
+	r.routeTable.mu.Lock()
+	defer r.routeTable.mu.Unlock()
+
+	r.routeTable.updateIngress(key, translated)
+	r.publishRoutes()
+	log.FromContext(ctx).Info("Updated proxy Ingress routes", "count", len(translated))
+}
+
+// publishRoutes applies the pending changes of the route table to the proxy,
+// right away or at the end of the window started by the first of them. The
+// window is not extended by later changes, so that a steady stream of them
+// cannot hold back the proxy indefinitely. It must be called with
+// routeTable.mu held.
+func (r *HTTPRouteReconciler) publishRoutes() {
+	if r.ProxyUpdateDelay <= 0 {
+		r.routeTable.flush(r.Proxy)
+		return
+	}
+	if r.routeTable.flushScheduled {
+		return
+	}
+	r.routeTable.flushScheduled = true
+	time.AfterFunc(r.ProxyUpdateDelay, func() {
+		r.routeTable.mu.Lock()
+		defer r.routeTable.mu.Unlock()
+		r.routeTable.flushScheduled = false
+		r.routeTable.flush(r.Proxy)
+	})
+}
+
+// recordStatusEvents records an Event for the Accepted and ResolvedRefs
+// conditions reported for each of our parents.
+func (r *HTTPRouteReconciler) recordStatusEvents(route *gatewayv1.HTTPRoute) {
+	for _, ps := range route.Status.Parents {
+		if ps.ControllerName != controllerNameOrDefault(r.ControllerName) {
+			continue
+		}
+		for _, c := range ps.Conditions {
+			eventType := corev1.EventTypeNormal
+			switch {
+			case c.Type == routeConditionConflicted:
+				eventType = corev1.EventTypeWarning
+			case c.Status != metav1.ConditionTrue:
+				eventType = corev1.EventTypeWarning
+			case c.Type != string(gatewayv1.RouteConditionAccepted):
+				continue
 			}
+			eventf(r.Recorder, route, eventType, c.Reason, "Gateway %s: %s", ps.ParentRef.Name, c.Message)
+		}
+	}
+}
+
+// existingParentConditions returns the conditions we previously reported for
+// parentRef.
+func existingParentConditions(status *gatewayv1.HTTPRouteStatus, parentRef gatewayv1.ParentReference, controllerName gatewayv1.GatewayController) []metav1.Condition {
+	for _, ps := range status.Parents {
+		if ps.ControllerName == controllerName && equality.Semantic.DeepEqual(ps.ParentRef, parentRef) {
+			return ps.Conditions
 		}
 	}
 	return nil
 }
 
-func (r *HTTPRouteReconciler) extractRoutes(ctx context.Context, routes *gatewayv1.HTTPRouteList) []proxy.HTTPRoute {
-	l := log.FromContext(ctx)
-	var newRoutes []proxy.HTTPRoute
-	for _, route := range routes.Items {
-		// Only extract routes that are accepted
-		accepted := false
-		for _, ps := range route.Status.Parents {
-			if ps.ControllerName == ControllerName {
-				for _, c := range ps.Conditions {
-					if c.Type == string(gatewayv1.RouteConditionAccepted) && c.Status == metav1.ConditionTrue {
-						accepted = true
-						break
-					}
-				}
-			}
-			if accepted {
-				break
+// parentAcceptedCondition returns the Accepted condition reported for parent.
+func parentAcceptedCondition(parent ir.ParentAcceptance) metav1.Condition {
+	status := metav1.ConditionFalse
+	if parent.Accepted {
+		status = metav1.ConditionTrue
+	}
+	return metav1.Condition{
+		Type:    string(gatewayv1.RouteConditionAccepted),
+		Status:  status,
+		Reason:  string(parent.Reason),
+		Message: parent.Message,
+	}
+}
+
+func (r *HTTPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.Add(&initialRouteSync{r: r}); err != nil {
+		return err
+	}
+	r.elected = mgr.Elected()
+	r.takeover = make(resyncChannel)
+	if err := mgr.Add(&routeStatusTakeover{r: r}); err != nil {
+		return err
+	}
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.HTTPRoute{}, builder.WithPredicates(specChanged)).
+		// Status updates are not filtered here: a route only shadows others
+		// once it is accepted.
+		Watches(&gatewayv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.mapRouteToConflicts)).
+		Watches(&gatewayv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.mapRouteToGatewayPeers), builder.WithPredicates(specChanged)).
+		Watches(&v1alpha1.BasicAuthPolicy{}, handler.EnqueueRequestsFromMapFunc(r.mapBasicAuthPolicyToRoutes), builder.WithPredicates(specChanged)).
+		Watches(&v1alpha1.SecurityHeadersPolicy{}, handler.EnqueueRequestsFromMapFunc(r.mapSecurityHeadersPolicyToRoutes), builder.WithPredicates(specChanged)).
+		Watches(&v1alpha1.TransformPolicy{}, handler.EnqueueRequestsFromMapFunc(r.mapTransformPolicyToRoutes), builder.WithPredicates(specChanged)).
+		Watches(&v1alpha1.TelemetryPolicy{}, handler.EnqueueRequestsFromMapFunc(r.mapTelemetryPolicyToRoutes), builder.WithPredicates(specChanged)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.mapSecretToRoutes), builder.OnlyMetadata).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapConfigMapToRoutes)).
+		Watches(&corev1.Service{}, handler.EnqueueRequestsFromMapFunc(r.mapServiceToRoutes)).
+		Watches(&v1alpha1.Backend{}, handler.EnqueueRequestsFromMapFunc(r.mapBackendToRoutes), builder.WithPredicates(specChanged)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToRoutes), builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.mapGatewayToRoutes), builder.WithPredicates(gatewayChanged)).
+		Watches(&gatewayv1.GatewayClass{}, handler.EnqueueRequestsFromMapFunc(r.mapGatewayClassToRoutes), builder.WithPredicates(specChanged)).
+		Watches(&v1alpha1.GatewayClassConfig{}, handler.EnqueueRequestsFromMapFunc(r.mapParametersToRoutes), builder.WithPredicates(specChanged))
+	// ServiceImports, ReferenceGrants and InferencePools can only be watched
+	// if their APIs are installed.
+	installed, err := apiInstalled(mgr.GetRESTMapper(), ir.ServiceImportGVK)
+	if err != nil {
+		return err
+	}
+	if installed {
+		serviceImport := &unstructured.Unstructured{}
+		serviceImport.SetGroupVersionKind(ir.ServiceImportGVK)
+		b = b.Watches(serviceImport, handler.EnqueueRequestsFromMapFunc(r.mapServiceImportToRoutes))
+	}
+	installed, err = apiInstalled(mgr.GetRESTMapper(), referenceGrantGVK)
+	if err != nil {
+		return err
+	}
+	if installed {
+		b = b.Watches(&gatewayv1beta1.ReferenceGrant{}, handler.EnqueueRequestsFromMapFunc(r.mapReferenceGrantToRoutes), builder.WithPredicates(specChanged))
+	}
+	installed, err = apiInstalled(mgr.GetRESTMapper(), ir.InferencePoolGVK)
+	if err != nil {
+		return err
+	}
+	if installed {
+		pool := &unstructured.Unstructured{}
+		pool.SetGroupVersionKind(ir.InferencePoolGVK)
+		b = b.Watches(pool, handler.EnqueueRequestsFromMapFunc(r.mapInferencePoolToRoutes)).
+			Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.mapPodToRoutes), builder.WithPredicates(podEndpointChanged))
+	}
+	if r.ExperimentalAPIs {
+		installed, err = apiInstalled(mgr.GetRESTMapper(), backendTLSPolicyGVK)
+		if err != nil {
+			return err
+		}
+		if installed {
+			r.backendTLSPolicies = true
+			b = b.Watches(&gatewayv1.BackendTLSPolicy{}, handler.EnqueueRequestsFromMapFunc(r.mapBackendTLSPolicyToRoutes), builder.WithPredicates(specChanged)).
+				Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapCABundleToRoutes))
+		}
+	}
+	if r.Backends.Resolution == ir.BackendResolutionEndpointSlice {
+		b = b.Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(r.mapEndpointSliceToRoutes))
+	}
+	if r.resync != nil {
+		b = b.WatchesRawSource(r.resync.source())
+	}
+	// The controller runs on every replica to keep its route table in sync;
+	// see leading.
+	opts := r.Backoff.options()
+	needLeaderElection := false
+	opts.NeedLeaderElection = &needLeaderElection
+	return b.WatchesRawSource(r.takeover.source()).WithOptions(opts).Complete(r)
+}
+
+// ReadyCheck is a readiness check that passes once the proxy serves every
+// accepted route, so that rolling restarts do not drop traffic.
+func (r *HTTPRouteReconciler) ReadyCheck(_ *http.Request) error {
+	if !r.synced.Load() {
+		return errors.New("route table not built yet")
+	}
+	return nil
+}
+
+// initialRouteSync builds the complete route table once the caches have
+// synced, instead of waiting for each route to be reconciled. It runs on every
+// replica, since each one serves traffic.
+type initialRouteSync struct {
+	r *HTTPRouteReconciler
+}
+
+func (s *initialRouteSync) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("initial-route-sync")
+	for {
+		err := s.r.updateProxy(ctx)
+		if err == nil {
+			s.r.synced.Store(true)
+			return nil
+		}
+		l.Error(err, "unable to build the initial route table, retrying")
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (s *initialRouteSync) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *HTTPRouteReconciler) mapBasicAuthPolicyToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*v1alpha1.BasicAuthPolicy)
+	if !ok {
+		return nil
+	}
+	return r.mapTargetsToRoutes(ctx, policy.Namespace, policy.Spec.TargetRefs)
+}
+
+func (r *HTTPRouteReconciler) mapSecurityHeadersPolicyToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*v1alpha1.SecurityHeadersPolicy)
+	if !ok {
+		return nil
+	}
+	return r.mapTargetsToRoutes(ctx, policy.Namespace, policy.Spec.TargetRefs)
+}
+
+func (r *HTTPRouteReconciler) mapTransformPolicyToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*v1alpha1.TransformPolicy)
+	if !ok {
+		return nil
+	}
+	return r.mapTargetsToRoutes(ctx, policy.Namespace, policy.Spec.TargetRefs)
+}
+
+func (r *HTTPRouteReconciler) mapTelemetryPolicyToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*v1alpha1.TelemetryPolicy)
+	if !ok {
+		return nil
+	}
+	return r.mapTargetsToRoutes(ctx, policy.Namespace, policy.Spec.TargetRefs)
+}
+
+// mapTargetsToRoutes enqueues the HTTPRoutes affected by a policy: those it
+// targets directly and those attached to the Gateways it targets.
+func (r *HTTPRouteReconciler) mapTargetsToRoutes(ctx context.Context, namespace string, targetRefs []gatewayv1.LocalPolicyTargetReferenceWithSectionName) []reconcile.Request {
+	var requests []reconcile.Request
+	var gateways []types.NamespacedName
+	for _, targetRef := range targetRefs {
+		key := types.NamespacedName{Namespace: namespace, Name: string(targetRef.Name)}
+		switch {
+		case isPolicyTarget(targetRef, kindHTTPRoute):
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		case isPolicyTarget(targetRef, kindGateway):
+			gateways = append(gateways, key)
+		}
+	}
+	return append(requests, r.routesForGateways(ctx, gateways)...)
+}
+
+// routesForGateways enqueues the HTTPRoutes attached to any of the Gateways.
+func (r *HTTPRouteReconciler) routesForGateways(ctx context.Context, gateways []types.NamespacedName) []reconcile.Request {
+	if len(gateways) == 0 {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, gw := range gateways {
+		var routes gatewayv1.HTTPRouteList
+		if err := r.List(ctx, &routes, client.MatchingFields{indexRouteParentGateways: gw.String()}); err != nil {
+			log.FromContext(ctx).Error(err, "unable to list HTTPRoutes")
+			return nil
+		}
+		for i := range routes.Items {
+			request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&routes.Items[i])}
+			if !slices.Contains(requests, request) {
+				requests = append(requests, request)
 			}
 		}
-		if !accepted {
+	}
+	return requests
+}
+
+// mapGatewayToRoutes enqueues the HTTPRoutes attached to the changed Gateway,
+// whose listeners decide which routes may attach.
+func (r *HTTPRouteReconciler) mapGatewayToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.routesForGateways(ctx, []types.NamespacedName{client.ObjectKeyFromObject(obj)})
+}
+
+// mapGatewayClassToRoutes enqueues the HTTPRoutes attached to Gateways of the
+// changed GatewayClass, whose parameters may set defaults for them.
+func (r *HTTPRouteReconciler) mapGatewayClassToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways, client.MatchingFields{indexGatewayClassName: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Gateways")
+		return nil
+	}
+	var keys []types.NamespacedName
+	for _, gw := range gateways.Items {
+		keys = append(keys, client.ObjectKeyFromObject(&gw))
+	}
+	return r.routesForGateways(ctx, keys)
+}
+
+// mapParametersToRoutes enqueues the HTTPRoutes attached to Gateways whose
+// GatewayClass takes its parameters from the changed ConfigMap or
+// GatewayClassConfig.
+func (r *HTTPRouteReconciler) mapParametersToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	var classes gatewayv1.GatewayClassList
+	if err := r.List(ctx, &classes); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list GatewayClasses")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range classes.Items {
+		if referencesParameters(&classes.Items[i], obj) {
+			requests = append(requests, r.mapGatewayClassToRoutes(ctx, &classes.Items[i])...)
+		}
+	}
+	return requests
+}
+
+// mapNamespaceToRoutes enqueues the HTTPRoutes in a namespace whose labels
+// changed, since listeners may select the namespaces routes attach from.
+func (r *HTTPRouteReconciler) mapNamespaceToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	var routes gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &routes, client.InNamespace(obj.GetName())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list HTTPRoutes")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, route := range routes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&route)})
+	}
+	return requests
+}
+
+// mapSecretToRoutes enqueues the HTTPRoutes targeted by BasicAuthPolicies that
+// reference the changed Secret.
+func (r *HTTPRouteReconciler) mapSecretToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	var policies v1alpha1.BasicAuthPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list BasicAuthPolicies")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range policies.Items {
+		if string(policies.Items[i].Spec.SecretRef.Name) == obj.GetName() {
+			requests = append(requests, r.mapBasicAuthPolicyToRoutes(ctx, &policies.Items[i])...)
+		}
+	}
+	return requests
+}
+
+// mapServiceToRoutes enqueues the HTTPRoutes with a backendRef to the changed
+// Service, so that routes are reprogrammed when a Service appears, disappears
+// or changes its ports.
+func (r *HTTPRouteReconciler) mapServiceToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.routesReferencingBackend(ctx, "", "Service", client.ObjectKeyFromObject(obj))
+}
+
+// mapEndpointSliceToRoutes enqueues the HTTPRoutes with a backendRef to the
+// Service of the changed EndpointSlice, whose endpoints they are forwarded to.
+func (r *HTTPRouteReconciler) mapEndpointSliceToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	service, ok := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if !ok {
+		return nil
+	}
+	return r.routesReferencingBackend(ctx, "", "Service", types.NamespacedName{Namespace: obj.GetNamespace(), Name: service})
+}
+
+// routeReferencesService reports whether any rule of the route has a
+// backendRef to the Service.
+func routeReferencesService(route *gatewayv1.HTTPRoute, service types.NamespacedName) bool {
+	return routeReferencesBackend(route, "", "Service", service)
+}
+
+// mapBackendToRoutes enqueues the HTTPRoutes with a backendRef to the changed
+// Backend.
+func (r *HTTPRouteReconciler) mapBackendToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.routesReferencingBackend(ctx, gatewayv1.Group(v1alpha1.GroupVersion.Group), ir.KindBackend, client.ObjectKeyFromObject(obj))
+}
+
+// mapReferenceGrantToRoutes enqueues the HTTPRoutes in the namespaces the
+// changed ReferenceGrant permits references from, with a backendRef to its
+// namespace.
+func (r *HTTPRouteReconciler) mapReferenceGrantToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	grant, ok := obj.(*gatewayv1beta1.ReferenceGrant)
+	if !ok {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, from := range grant.Spec.From {
+		if from.Group != gatewayv1.GroupName || from.Kind != "HTTPRoute" {
 			continue
 		}
+		var routes gatewayv1.HTTPRouteList
+		if err := r.List(ctx, &routes, client.InNamespace(string(from.Namespace))); err != nil {
+			log.FromContext(ctx).Error(err, "unable to list HTTPRoutes")
+			return nil
+		}
+		for i := range routes.Items {
+			if routeReferencesNamespace(&routes.Items[i], grant.Namespace) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&routes.Items[i])})
+			}
+		}
+	}
+	return requests
+}
 
-		pr := proxy.HTTPRoute{}
-		for _, hostname := range route.Spec.Hostnames {
-			pr.Hostnames = append(pr.Hostnames, string(hostname))
-		}
-
-		for _, rule := range route.Spec.Rules {
-			for _, backendRef := range rule.BackendRefs {
-				if backendRef.Kind != nil && *backendRef.Kind != "Service" {
-					continue
-				}
-
-				if backendRef.Port == nil {
-					continue
-				}
-
-				backend := proxy.Backend{
-					Host: fmt.Sprintf("%s.%s.svc.cluster.local", backendRef.Name, route.Namespace),
-					Port: int32(*backendRef.Port),
-				}
-
-				pRule := proxy.RouteRule{
-					Backend: backend,
-				}
-
-				for _, match := range rule.Matches {
-					pMatch := proxy.RouteMatch{}
-					if match.Path != nil {
-						pathType := gatewayv1.PathMatchPathPrefix
-						if match.Path.Type != nil {
-							pathType = *match.Path.Type
-						}
-						pMatch.Path = &proxy.PathMatch{
-							Type:  proxy.PathMatchType(pathType),
-							Value: *match.Path.Value,
-						}
-					}
-					for _, header := range match.Headers {
-						headerType := gatewayv1.HeaderMatchExact
-						if header.Type != nil {
-							headerType = *header.Type
-						}
-						hm := proxy.HeaderMatch{
-							Type:            string(headerType),
-							Name:            string(header.Name),
-							MatchExactValue: header.Value,
-						}
-						if headerType == gatewayv1.HeaderMatchRegularExpression {
-							re, err := regexp.Compile(header.Value)
-							if err != nil {
-								// In a real controller we would set a condition on the route
-								l.Error(err, "invalid regular expression in header match", "value", header.Value)
-								continue
-							}
-							hm.MatchRegularExpressionValue = re
-						}
-						pMatch.Headers = append(pMatch.Headers, hm)
-					}
-					pRule.Matches = append(pRule.Matches, pMatch)
-				}
-
-				pr.Rules = append(pr.Rules, pRule)
-
-				// For minimal implementation, we just take the first Service backendRef for each rule
-				break
+// routeReferencesNamespace reports whether any rule of the route has a
+// backendRef to an object in the given namespace other than its own.
+func routeReferencesNamespace(route *gatewayv1.HTTPRoute, namespace string) bool {
+	for _, rule := range route.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			if backendRef.Namespace != nil && string(*backendRef.Namespace) == namespace && namespace != route.Namespace {
+				return true
 			}
 		}
-		newRoutes = append(newRoutes, pr)
 	}
-	return newRoutes
+	return false
 }
 
-func (r *HTTPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&gatewayv1.HTTPRoute{}).
-		Complete(r)
+// routesReferencingBackend returns requests for the HTTPRoutes with a
+// backendRef to the object of the given group and kind.
+func (r *HTTPRouteReconciler) routesReferencingBackend(ctx context.Context, group gatewayv1.Group, kind gatewayv1.Kind, key types.NamespacedName) []reconcile.Request {
+	var routes gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &routes, client.MatchingFields{indexRouteBackendRefs: backendRefIndexKey(group, kind, key)}); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list HTTPRoutes")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range routes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&routes.Items[i])})
+	}
+	return requests
+}
+
+// routeReferencesBackend reports whether any rule of the route has a
+// backendRef to the object of the given group and kind.
+func routeReferencesBackend(route *gatewayv1.HTTPRoute, group gatewayv1.Group, kind gatewayv1.Kind, key types.NamespacedName) bool {
+	for _, rule := range route.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			if !ir.IsBackendRef(backendRef.BackendObjectReference, group, kind) {
+				continue
+			}
+			namespace := route.Namespace
+			if backendRef.Namespace != nil {
+				namespace = string(*backendRef.Namespace)
+			}
+			if namespace == key.Namespace && string(backendRef.Name) == key.Name {
+				return true
+			}
+		}
+	}
+	return false
 }
@@ -17,14 +17,28 @@ package controller
 import (
 	"context"
 	"reflect"
+	"slices"
 	"testing"
 
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
 	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
-func TestExtractRoutes(t *testing.T) {
+func TestBuildRoutes(t *testing.T) {
+	listeners := []proxy.RouteListener{{GatewayNamespace: "default", Gateway: "gw", Listener: "http"}}
 	tests := []struct {
 		name     string
 		routes   *gatewayv1.HTTPRouteList
@@ -39,7 +53,8 @@ func TestExtractRoutes(t *testing.T) {
 							Namespace: "default",
 						},
 						Spec: gatewayv1.HTTPRouteSpec{
-							Hostnames: []gatewayv1.Hostname{"example.com"},
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Namespace: ptr(gatewayv1.Namespace("default")), Name: "gw"}}},
+							Hostnames:       []gatewayv1.Hostname{"example.com"},
 							Rules: []gatewayv1.HTTPRouteRule{
 								{
 									BackendRefs: []gatewayv1.HTTPBackendRef{
@@ -56,30 +71,18 @@ func TestExtractRoutes(t *testing.T) {
 								},
 							},
 						},
-						Status: gatewayv1.HTTPRouteStatus{
-							RouteStatus: gatewayv1.RouteStatus{
-								Parents: []gatewayv1.RouteParentStatus{
-									{
-										ControllerName: ControllerName,
-										Conditions: []metav1.Condition{
-											{
-												Type:   string(gatewayv1.RouteConditionAccepted),
-												Status: metav1.ConditionTrue,
-											},
-										},
-									},
-								},
-							},
-						},
 					},
 				},
 			},
 			expected: []proxy.HTTPRoute{
 				{
+					Namespace: "default",
+					Listeners: listeners,
 					Hostnames: []string{"example.com"},
 					Rules: []proxy.RouteRule{
 						{
-							Backend: proxy.Backend{Host: "backend-svc.default.svc.cluster.local", Port: 80},
+							Name:     "0",
+							Backends: []proxy.WeightedBackend{{Backend: proxy.Backend{Host: "backend-svc.default.svc.cluster.local", Port: 80}, Weight: 1}},
 						},
 					},
 				},
@@ -94,7 +97,8 @@ func TestExtractRoutes(t *testing.T) {
 							Namespace: "test-ns",
 						},
 						Spec: gatewayv1.HTTPRouteSpec{
-							Hostnames: []gatewayv1.Hostname{"example.com", "foo.bar"},
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Namespace: ptr(gatewayv1.Namespace("default")), Name: "gw"}}},
+							Hostnames:       []gatewayv1.Hostname{"example.com", "foo.bar"},
 							Rules: []gatewayv1.HTTPRouteRule{
 								{
 									BackendRefs: []gatewayv1.HTTPBackendRef{
@@ -110,30 +114,18 @@ func TestExtractRoutes(t *testing.T) {
 								},
 							},
 						},
-						Status: gatewayv1.HTTPRouteStatus{
-							RouteStatus: gatewayv1.RouteStatus{
-								Parents: []gatewayv1.RouteParentStatus{
-									{
-										ControllerName: ControllerName,
-										Conditions: []metav1.Condition{
-											{
-												Type:   string(gatewayv1.RouteConditionAccepted),
-												Status: metav1.ConditionTrue,
-											},
-										},
-									},
-								},
-							},
-						},
 					},
 				},
 			},
 			expected: []proxy.HTTPRoute{
 				{
+					Namespace: "test-ns",
+					Listeners: listeners,
 					Hostnames: []string{"example.com", "foo.bar"},
 					Rules: []proxy.RouteRule{
 						{
-							Backend: proxy.Backend{Host: "backend-svc.test-ns.svc.cluster.local", Port: 8080},
+							Name:     "0",
+							Backends: []proxy.WeightedBackend{{Backend: proxy.Backend{Host: "backend-svc.test-ns.svc.cluster.local", Port: 8080}, Weight: 1}},
 						},
 					},
 				},
@@ -148,8 +140,10 @@ func TestExtractRoutes(t *testing.T) {
 							Namespace: "default",
 						},
 						Spec: gatewayv1.HTTPRouteSpec{
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Namespace: ptr(gatewayv1.Namespace("default")), Name: "gw"}}},
 							Rules: []gatewayv1.HTTPRouteRule{
 								{
+									Name: ptr(gatewayv1.SectionName("exact")),
 									Matches: []gatewayv1.HTTPRouteMatch{
 										{
 											Path: &gatewayv1.HTTPPathMatch{
@@ -171,28 +165,16 @@ func TestExtractRoutes(t *testing.T) {
 								},
 							},
 						},
-						Status: gatewayv1.HTTPRouteStatus{
-							RouteStatus: gatewayv1.RouteStatus{
-								Parents: []gatewayv1.RouteParentStatus{
-									{
-										ControllerName: ControllerName,
-										Conditions: []metav1.Condition{
-											{
-												Type:   string(gatewayv1.RouteConditionAccepted),
-												Status: metav1.ConditionTrue,
-											},
-										},
-									},
-								},
-							},
-						},
 					},
 				},
 			},
 			expected: []proxy.HTTPRoute{
 				{
+					Namespace: "default",
+					Listeners: listeners,
 					Rules: []proxy.RouteRule{
 						{
+							Name: "exact",
 							Matches: []proxy.RouteMatch{
 								{
 									Path: &proxy.PathMatch{
@@ -201,7 +183,59 @@ func TestExtractRoutes(t *testing.T) {
 									},
 								},
 							},
-							Backend: proxy.Backend{Host: "backend-svc.default.svc.cluster.local", Port: 80},
+							Backends: []proxy.WeightedBackend{{Backend: proxy.Backend{Host: "backend-svc.default.svc.cluster.local", Port: 80}, Weight: 1}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unresolved backend keeps its weight",
+			routes: &gatewayv1.HTTPRouteList{
+				Items: []gatewayv1.HTTPRoute{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "default",
+						},
+						Spec: gatewayv1.HTTPRouteSpec{
+							CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Namespace: ptr(gatewayv1.Namespace("default")), Name: "gw"}}},
+							Rules: []gatewayv1.HTTPRouteRule{
+								{
+									BackendRefs: []gatewayv1.HTTPBackendRef{
+										{
+											BackendRef: gatewayv1.BackendRef{
+												BackendObjectReference: gatewayv1.BackendObjectReference{
+													Name: "missing-svc",
+													Port: ptr(gatewayv1.PortNumber(80)),
+												},
+											},
+										},
+										{
+											BackendRef: gatewayv1.BackendRef{
+												BackendObjectReference: gatewayv1.BackendObjectReference{
+													Name: "backend-svc",
+													Port: ptr(gatewayv1.PortNumber(80)),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []proxy.HTTPRoute{
+				{
+					Namespace: "default",
+					Listeners: listeners,
+					Rules: []proxy.RouteRule{
+						{
+							Name: "0",
+							Backends: []proxy.WeightedBackend{
+								{Weight: 1, Invalid: true},
+								{Backend: proxy.Backend{Host: "backend-svc.default.svc.cluster.local", Port: 80}, Weight: 1},
+							},
 						},
 					},
 				},
@@ -209,13 +243,551 @@ func TestExtractRoutes(t *testing.T) {
 		},
 	}
 
-	reconciler := &HTTPRouteReconciler{}
+	in := ir.Input{
+		ControllerName: DefaultControllerName,
+		GatewayClasses: []gatewayv1.GatewayClass{{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		}},
+		Gateways: []gatewayv1.Gateway{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners: []gatewayv1.Listener{{
+					Name:          "http",
+					Port:          80,
+					Protocol:      gatewayv1.HTTPProtocolType,
+					AllowedRoutes: &gatewayv1.AllowedRoutes{Namespaces: &gatewayv1.RouteNamespaces{From: ptr(gatewayv1.NamespacesFromAll)}},
+				}},
+			},
+		}},
+		Targets: ir.Targets{Services: map[types.NamespacedName]*corev1.Service{
+			{Namespace: "default", Name: "backend-svc"}: newService("default", "backend-svc", 80),
+			{Namespace: "test-ns", Name: "backend-svc"}: newService("test-ns", "backend-svc", 8080),
+		}},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := reconciler.extractRoutes(context.Background(), tt.routes)
+			in.HTTPRoutes = tt.routes.Items
+			actual := ir.Build(in).Routes
 			if !reflect.DeepEqual(actual, tt.expected) {
 				t.Errorf("expected %v, got %v", tt.expected, actual)
 			}
 		})
 	}
 }
+
+func TestRouteReferencesService(t *testing.T) {
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "local"}}},
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "remote", Namespace: ptr(gatewayv1.Namespace("other"))}}},
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "bucket", Group: ptr(gatewayv1.Group("example.com")), Kind: ptr(gatewayv1.Kind("Bucket"))}}},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		service  types.NamespacedName
+		expected bool
+	}{
+		{service: types.NamespacedName{Namespace: "default", Name: "local"}, expected: true},
+		{service: types.NamespacedName{Namespace: "other", Name: "remote"}, expected: true},
+		{service: types.NamespacedName{Namespace: "default", Name: "remote"}, expected: false},
+		{service: types.NamespacedName{Namespace: "default", Name: "bucket"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service.String(), func(t *testing.T) {
+			if actual := routeReferencesService(route, tt.service); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMapReferenceGrantToRoutes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	route := func(namespace, name string, backendNamespace *gatewayv1.Namespace) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{
+				{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "web", Namespace: backendNamespace}}},
+			}}}},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		route("apps", "remote", ptr(gatewayv1.Namespace("backends"))),
+		route("apps", "local", nil),
+		route("apps", "elsewhere", ptr(gatewayv1.Namespace("other"))),
+		route("other", "remote", ptr(gatewayv1.Namespace("backends"))),
+	).Build()
+	r := &HTTPRouteReconciler{Client: c}
+
+	grant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "backends", Name: "apps"},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{
+				{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "apps"},
+				{Group: gatewayv1.GroupName, Kind: "GRPCRoute", Namespace: "other"},
+			},
+			To: []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service"}},
+		},
+	}
+	expected := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "apps", Name: "remote"}}}
+	if actual := r.mapReferenceGrantToRoutes(context.Background(), grant); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func newService(namespace, name string, ports ...int32) *corev1.Service {
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	for _, port := range ports {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{Port: port})
+	}
+	return service
+}
+
+func TestRouteBackendTargets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, gatewayv1.Install, gatewayv1beta1.AddToScheme, v1alpha1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("unable to build scheme: %v", err)
+		}
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+		Spec: gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{
+			{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "web"}}},
+			{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "api", Namespace: ptr(gatewayv1.Namespace("backends"))}}},
+			{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: "vm", Group: ptr(gatewayv1.Group(v1alpha1.GroupVersion.Group)), Kind: ptr(gatewayv1.Kind(ir.KindBackend)),
+			}}},
+		}}}},
+	}
+	slice := func(namespace, service string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Namespace: namespace, Name: service + "-1", Labels: map[string]string{discoveryv1.LabelServiceName: service}},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		}
+	}
+	grant := func(namespace string) *gatewayv1beta1.ReferenceGrant {
+		return &gatewayv1beta1.ReferenceGrant{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "apps"}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newService("apps", "web", 80),
+		newService("apps", "unrelated", 80),
+		newService("backends", "api", 80),
+		&v1alpha1.Backend{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "vm"}},
+		&v1alpha1.Backend{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "other-vm"}},
+		slice("apps", "web"),
+		slice("apps", "unrelated"),
+		grant("backends"),
+		grant("elsewhere"),
+	).Build()
+
+	targets, err := routeBackendTargets(context.Background(), c, route, false, ir.BackendOptions{Resolution: ir.BackendResolutionEndpointSlice})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keys := func(m map[types.NamespacedName]bool) []string {
+		var keys []string
+		for key := range m {
+			keys = append(keys, key.String())
+		}
+		slices.Sort(keys)
+		return keys
+	}
+	services, backends, endpointSlices := map[types.NamespacedName]bool{}, map[types.NamespacedName]bool{}, map[types.NamespacedName]bool{}
+	for key := range targets.Services {
+		services[key] = true
+	}
+	for key := range targets.Backends {
+		backends[key] = true
+	}
+	for key := range targets.EndpointSlices {
+		endpointSlices[key] = true
+	}
+	if actual, expected := keys(services), []string{"apps/web", "backends/api"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected Services %v, got %v", expected, actual)
+	}
+	if actual, expected := keys(backends), []string{"apps/vm"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected Backends %v, got %v", expected, actual)
+	}
+	if actual, expected := keys(endpointSlices), []string{"apps/web"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the EndpointSlices of %v, got %v", expected, actual)
+	}
+	if len(targets.ReferenceGrants) != 1 || targets.ReferenceGrants[0].Namespace != "backends" {
+		t.Errorf("expected the ReferenceGrants of namespace backends only, got %+v", targets.ReferenceGrants)
+	}
+}
+
+func TestRouteInput(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, gatewayv1.Install, v1alpha1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("unable to build scheme: %v", err)
+		}
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+		Spec: gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{
+			{Name: "gw", Namespace: ptr(gatewayv1.Namespace("infra"))},
+		}}},
+	}
+	policy := func(namespace, name string, kind gatewayv1.Kind, target string) *v1alpha1.SecurityHeadersPolicy {
+		return &v1alpha1.SecurityHeadersPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: v1alpha1.SecurityHeadersPolicySpec{TargetRefs: []gatewayv1.LocalPolicyTargetReferenceWithSectionName{{
+				LocalPolicyTargetReference: gatewayv1.LocalPolicyTargetReference{Group: gatewayv1.GroupName, Kind: kind, Name: gatewayv1.ObjectName(target)},
+			}}},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		&gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "gw"}},
+		&gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "gw"}},
+		policy("apps", "route", "HTTPRoute", "web"),
+		policy("infra", "gateway", "Gateway", "gw"),
+		policy("other", "route", "HTTPRoute", "web"),
+		policy("other", "gateway", "Gateway", "gw"),
+	).Build()
+	r := &HTTPRouteReconciler{Client: c}
+
+	in, err := r.inputReader().routeInput(context.Background(), route)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	apps, infra := types.NamespacedName{Namespace: "apps", Name: "web"}, types.NamespacedName{Namespace: "infra", Name: "gw"}
+	if len(in.Policies.SecurityHeaders) != 1 || in.Policies.SecurityHeaders[apps] == nil {
+		t.Errorf("expected the policy of the route only, got %v", in.Policies.SecurityHeaders)
+	}
+	if len(in.Policies.GatewaySecurityHeaders) != 1 || in.Policies.GatewaySecurityHeaders[infra] == nil {
+		t.Errorf("expected the policy of the route's Gateway only, got %v", in.Policies.GatewaySecurityHeaders)
+	}
+	if len(in.Gateways) != 1 || client.ObjectKeyFromObject(&in.Gateways[0]) != infra {
+		t.Errorf("expected the route's Gateway only, got %v", in.Gateways)
+	}
+	if len(in.Namespaces) != 1 || in.Namespaces[0].Name != "apps" {
+		t.Errorf("expected the route's Namespace only, got %v", in.Namespaces)
+	}
+}
+
+func TestResolvedRefsCondition(t *testing.T) {
+	services := map[types.NamespacedName]*corev1.Service{
+		{Namespace: "default", Name: "web"}: newService("default", "web", 80),
+	}
+	backends := map[types.NamespacedName]*v1alpha1.Backend{
+		{Namespace: "default", Name: "vm"}: {Spec: v1alpha1.BackendSpec{Host: "10.0.0.1", Port: 8080}},
+	}
+	backendRef := func(ref gatewayv1.BackendObjectReference) gatewayv1.HTTPBackendRef {
+		return gatewayv1.HTTPBackendRef{BackendRef: gatewayv1.BackendRef{BackendObjectReference: ref}}
+	}
+
+	tests := []struct {
+		name           string
+		backendRefs    []gatewayv1.HTTPBackendRef
+		expectedStatus metav1.ConditionStatus
+		expectedReason gatewayv1.RouteConditionReason
+	}{
+		{
+			name:           "resolved",
+			backendRefs:    []gatewayv1.HTTPBackendRef{backendRef(gatewayv1.BackendObjectReference{Name: "web", Port: ptr(gatewayv1.PortNumber(80))})},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: gatewayv1.RouteReasonResolvedRefs,
+		},
+		{
+			name:           "missing service",
+			backendRefs:    []gatewayv1.HTTPBackendRef{backendRef(gatewayv1.BackendObjectReference{Name: "missing", Port: ptr(gatewayv1.PortNumber(80))})},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: gatewayv1.RouteReasonBackendNotFound,
+		},
+		{
+			name:           "missing port",
+			backendRefs:    []gatewayv1.HTTPBackendRef{backendRef(gatewayv1.BackendObjectReference{Name: "web", Port: ptr(gatewayv1.PortNumber(8080))})},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: gatewayv1.RouteReasonBackendNotFound,
+		},
+		{
+			name:           "backend",
+			backendRefs:    []gatewayv1.HTTPBackendRef{backendRef(gatewayv1.BackendObjectReference{Name: "vm", Group: ptr(gatewayv1.Group("gari.gke-labs.dev")), Kind: ptr(gatewayv1.Kind("Backend"))})},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: gatewayv1.RouteReasonResolvedRefs,
+		},
+		{
+			name:           "missing backend",
+			backendRefs:    []gatewayv1.HTTPBackendRef{backendRef(gatewayv1.BackendObjectReference{Name: "web", Group: ptr(gatewayv1.Group("gari.gke-labs.dev")), Kind: ptr(gatewayv1.Kind("Backend"))})},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: gatewayv1.RouteReasonBackendNotFound,
+		},
+		{
+			name:           "backend port mismatch",
+			backendRefs:    []gatewayv1.HTTPBackendRef{backendRef(gatewayv1.BackendObjectReference{Name: "vm", Group: ptr(gatewayv1.Group("gari.gke-labs.dev")), Kind: ptr(gatewayv1.Kind("Backend")), Port: ptr(gatewayv1.PortNumber(80))})},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: gatewayv1.RouteReasonBackendNotFound,
+		},
+		{
+			name: "unsupported kind",
+			backendRefs: []gatewayv1.HTTPBackendRef{
+				backendRef(gatewayv1.BackendObjectReference{Name: "web", Port: ptr(gatewayv1.PortNumber(80))}),
+				backendRef(gatewayv1.BackendObjectReference{Name: "bucket", Group: ptr(gatewayv1.Group("example.com")), Kind: ptr(gatewayv1.Kind("Bucket"))}),
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: gatewayv1.RouteReasonInvalidKind,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+				Spec:       gatewayv1.HTTPRouteSpec{Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: tt.backendRefs}}},
+			}
+			actual := resolvedRefsCondition(route, ir.Targets{Services: services, Backends: backends})
+			if actual.Status != tt.expectedStatus || actual.Reason != string(tt.expectedReason) {
+				t.Errorf("expected %v/%v, got %v/%v", tt.expectedStatus, tt.expectedReason, actual.Status, actual.Reason)
+			}
+		})
+	}
+}
+
+func TestReconcilePreservesOtherParentStatuses(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+
+	otherStatus := gatewayv1.RouteParentStatus{
+		ParentRef:      gatewayv1.ParentReference{Name: "other-gw"},
+		ControllerName: "example.com/other",
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(gatewayv1.RouteConditionAccepted),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(gatewayv1.RouteReasonAccepted),
+				LastTransitionTime: metav1.Now(),
+			},
+		},
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}, {Name: "other-gw"}},
+			},
+		},
+		Status: gatewayv1.HTTPRouteStatus{
+			RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{otherStatus}},
+		},
+	}
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithStatusSubresource(route).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "theirs"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.com/other"},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other-gw"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "theirs"},
+		},
+		route,
+	).Build()
+
+	recorder := record.NewFakeRecorder(10)
+	r := &HTTPRouteReconciler{Client: c, Scheme: scheme, Proxy: proxy.NewProxy(proxy.Options{}), Recorder: recorder}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var events []string
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	expectedEvents := []string{
+		"Normal Accepted Gateway gw: Route accepted by reference implementation",
+		"Normal Programmed Route programmed into the proxy",
+	}
+	if !reflect.DeepEqual(events, expectedEvents) {
+		t.Errorf("expected events %v, got %v", expectedEvents, events)
+	}
+
+	var actual gatewayv1.HTTPRoute
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(route), &actual); err != nil {
+		t.Fatalf("unable to get route: %v", err)
+	}
+	var controllers []gatewayv1.GatewayController
+	for _, ps := range actual.Status.Parents {
+		controllers = append(controllers, ps.ControllerName)
+	}
+	expected := []gatewayv1.GatewayController{"example.com/other", DefaultControllerName}
+	if !reflect.DeepEqual(controllers, expected) {
+		t.Errorf("expected %v, got %v", expected, controllers)
+	}
+}
+
+func TestReconcilePrunesStaleParentStatuses(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+
+	parentStatus := func(name string) gatewayv1.RouteParentStatus {
+		return gatewayv1.RouteParentStatus{
+			ParentRef:      gatewayv1.ParentReference{Name: gatewayv1.ObjectName(name)},
+			ControllerName: DefaultControllerName,
+			Conditions: []metav1.Condition{
+				{
+					Type:               string(gatewayv1.RouteConditionAccepted),
+					Status:             metav1.ConditionTrue,
+					Reason:             string(gatewayv1.RouteReasonAccepted),
+					LastTransitionTime: metav1.Now(),
+				},
+			},
+		}
+	}
+	newRoute := func(name string, parentRefs ...gatewayv1.ParentReference) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+				Rules: []gatewayv1.HTTPRouteRule{{
+					BackendRefs: []gatewayv1.HTTPBackendRef{{
+						BackendRef: gatewayv1.BackendRef{
+							BackendObjectReference: gatewayv1.BackendObjectReference{Name: "web", Port: ptr(gatewayv1.PortNumber(80))},
+						},
+					}},
+				}},
+			},
+			Status: gatewayv1.HTTPRouteStatus{
+				RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{parentStatus("gw"), parentStatus("old-gw")}},
+			},
+		}
+	}
+	moved := newRoute("moved", gatewayv1.ParentReference{Name: "gw"})
+	detached := newRoute("detached")
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithStatusSubresource(moved, detached).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		},
+		newService("default", "web", 80),
+		moved,
+		detached,
+	).Build()
+
+	p := proxy.NewProxy(proxy.Options{})
+	r := &HTTPRouteReconciler{Client: c, Scheme: scheme, Proxy: p}
+	for _, route := range []*gatewayv1.HTTPRoute{moved, detached} {
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expectedParents := map[string][]gatewayv1.ObjectName{"moved": {"gw"}, "detached": nil}
+	for name, expected := range expectedParents {
+		var actual gatewayv1.HTTPRoute
+		if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, &actual); err != nil {
+			t.Fatalf("unable to get route: %v", err)
+		}
+		var parents []gatewayv1.ObjectName
+		for _, ps := range actual.Status.Parents {
+			parents = append(parents, ps.ParentRef.Name)
+		}
+		if !reflect.DeepEqual(parents, expected) {
+			t.Errorf("route %s: expected parents %v, got %v", name, expected, parents)
+		}
+	}
+
+	var served []string
+	for _, route := range p.Routes() {
+		served = append(served, route.Name)
+	}
+	if !reflect.DeepEqual(served, []string{"moved"}) {
+		t.Errorf("expected only the attached route to be served, got %v", served)
+	}
+}
+
+func TestInitialRouteSync(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}}}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		},
+		route,
+	).Build()
+	p := proxy.NewProxy(proxy.Options{})
+	r := &HTTPRouteReconciler{Client: c, Scheme: scheme, Proxy: p}
+
+	if err := r.ReadyCheck(nil); err == nil {
+		t.Errorf("expected the ready check to fail before the initial sync")
+	}
+	if err := (&initialRouteSync{r: r}).Start(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.ReadyCheck(nil); err != nil {
+		t.Errorf("expected the ready check to pass, got %v", err)
+	}
+	if routes := p.Routes(); len(routes) != 1 || routes[0].Name != "web" {
+		t.Errorf("expected route default/web to be served, got %v", routes)
+	}
+}
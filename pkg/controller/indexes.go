@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Field indexes registered on the manager's cache, so that the mapping
+// functions can look up the objects referencing a changed object instead of
+// listing them all.
+const (
+	// indexRouteParentGateways indexes HTTPRoutes by the namespaced names of
+	// the Gateways in their parentRefs.
+	indexRouteParentGateways = "httproute.parentRefs.gateway"
+	// indexRouteBackendRefs indexes HTTPRoutes by the group, kind and
+	// namespaced name of their backendRefs, as built by backendRefIndexKey.
+	indexRouteBackendRefs = "httproute.backendRefs"
+	// indexRouteHostnames indexes HTTPRoutes by their hostnames, or by
+	// noHostnames if they list none.
+	indexRouteHostnames = "httproute.hostnames"
+	// indexGatewayClassName indexes Gateways by their GatewayClass.
+	indexGatewayClassName = "gateway.gatewayClassName"
+	// indexListenerSecrets indexes Gateways by the namespaced names of the
+	// Secrets referenced by the certificateRefs of their listeners.
+	indexListenerSecrets = "gateway.listeners.certificateRefs"
+)
+
+// fieldIndex describes an index on a field of an object.
+type fieldIndex struct {
+	object  client.Object
+	field   string
+	extract client.IndexerFunc
+}
+
+// fieldIndexes lists the indexes registered by SetupIndexes.
+var fieldIndexes = []fieldIndex{
+	{object: &gatewayv1.HTTPRoute{}, field: indexRouteParentGateways, extract: routeParentGatewaysIndex},
+	{object: &gatewayv1.HTTPRoute{}, field: indexRouteBackendRefs, extract: routeBackendRefsIndex},
+	{object: &gatewayv1.HTTPRoute{}, field: indexRouteHostnames, extract: routeHostnamesIndex},
+	{object: &gatewayv1.Gateway{}, field: indexGatewayClassName, extract: gatewayClassNameIndex},
+	{object: &gatewayv1.Gateway{}, field: indexListenerSecrets, extract: listenerSecretsIndex},
+}
+
+// SetupIndexes registers the field indexes used by the reconcilers. It must
+// be called before the manager is started.
+func SetupIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	for _, index := range fieldIndexes {
+		if err := indexer.IndexField(ctx, index.object, index.field, index.extract); err != nil {
+			return fmt.Errorf("unable to index %s: %w", index.field, err)
+		}
+	}
+	return nil
+}
+
+// backendRefIndexKey returns the value indexRouteBackendRefs records for a
+// backendRef to the object of the given group and kind.
+func backendRefIndexKey(group gatewayv1.Group, kind gatewayv1.Kind, key types.NamespacedName) string {
+	return fmt.Sprintf("%s/%s/%s", group, kind, key)
+}
+
+// routeParentGatewaysIndex extracts the values of indexRouteParentGateways.
+func routeParentGatewaysIndex(obj client.Object) []string {
+	route, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, gw := range ir.RouteGateways(route) {
+		values = append(values, gw.String())
+	}
+	return values
+}
+
+// routeBackendRefsIndex extracts the values of indexRouteBackendRefs.
+func routeBackendRefsIndex(obj client.Object) []string {
+	route, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, rule := range route.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			group, kind := gatewayv1.Group(""), gatewayv1.Kind("Service")
+			if backendRef.Group != nil {
+				group = *backendRef.Group
+			}
+			if backendRef.Kind != nil {
+				kind = *backendRef.Kind
+			}
+			namespace := route.Namespace
+			if backendRef.Namespace != nil {
+				namespace = string(*backendRef.Namespace)
+			}
+			values = append(values, backendRefIndexKey(group, kind, types.NamespacedName{Namespace: namespace, Name: string(backendRef.Name)}))
+		}
+	}
+	return values
+}
+
+// noHostnames is the value indexRouteHostnames records for routes without
+// hostnames. It is not a valid hostname, so no route lists it.
+const noHostnames = "*"
+
+// routeHostnamesIndex extracts the values of indexRouteHostnames.
+func routeHostnamesIndex(obj client.Object) []string {
+	route, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil
+	}
+	if len(route.Spec.Hostnames) == 0 {
+		return []string{noHostnames}
+	}
+	values := make([]string, 0, len(route.Spec.Hostnames))
+	for _, hostname := range route.Spec.Hostnames {
+		values = append(values, string(hostname))
+	}
+	return values
+}
+
+// gatewayClassNameIndex extracts the value of indexGatewayClassName.
+func gatewayClassNameIndex(obj client.Object) []string {
+	gw, ok := obj.(*gatewayv1.Gateway)
+	if !ok {
+		return nil
+	}
+	return []string{string(gw.Spec.GatewayClassName)}
+}
+
+// listenerSecretsIndex extracts the values of indexListenerSecrets.
+func listenerSecretsIndex(obj client.Object) []string {
+	gw, ok := obj.(*gatewayv1.Gateway)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, listener := range gw.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			if !isSecretRef(ref) {
+				continue
+			}
+			namespace := gw.Namespace
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+			values = append(values, types.NamespacedName{Namespace: namespace, Name: string(ref.Name)}.String())
+		}
+	}
+	return values
+}
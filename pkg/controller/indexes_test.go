@@ -0,0 +1,179 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// withIndexes registers the field indexes of SetupIndexes on a fake client
+// builder, whose scheme must already be set.
+func withIndexes(b *fake.ClientBuilder) *fake.ClientBuilder {
+	for _, index := range fieldIndexes {
+		b = b.WithIndex(index.object, index.field, index.extract)
+	}
+	return b
+}
+
+func TestRouteIndexes(t *testing.T) {
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{
+				{Name: "gw"},
+				{Name: "shared", Namespace: ptr(gatewayv1.Namespace("infra"))},
+			}},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "svc"}}},
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{
+						Group:     ptr(gatewayv1.Group("multicluster.x-k8s.io")),
+						Kind:      ptr(gatewayv1.Kind("ServiceImport")),
+						Namespace: ptr(gatewayv1.Namespace("other")),
+						Name:      "imported",
+					}}},
+				},
+			}},
+		},
+	}
+
+	expectedParents := []string{"apps/gw", "infra/shared"}
+	if actual := routeParentGatewaysIndex(route); !reflect.DeepEqual(actual, expectedParents) {
+		t.Errorf("expected parent index %v, got %v", expectedParents, actual)
+	}
+	expectedBackends := []string{"/Service/apps/svc", "multicluster.x-k8s.io/ServiceImport/other/imported"}
+	if actual := routeBackendRefsIndex(route); !reflect.DeepEqual(actual, expectedBackends) {
+		t.Errorf("expected backend index %v, got %v", expectedBackends, actual)
+	}
+	expectedHostnames := []string{noHostnames}
+	if actual := routeHostnamesIndex(route); !reflect.DeepEqual(actual, expectedHostnames) {
+		t.Errorf("expected hostname index %v, got %v", expectedHostnames, actual)
+	}
+	route.Spec.Hostnames = []gatewayv1.Hostname{"web.example.com", "*.example.com"}
+	expectedHostnames = []string{"web.example.com", "*.example.com"}
+	if actual := routeHostnamesIndex(route); !reflect.DeepEqual(actual, expectedHostnames) {
+		t.Errorf("expected hostname index %v, got %v", expectedHostnames, actual)
+	}
+}
+
+func TestListenerSecretsIndex(t *testing.T) {
+	gw := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "gw"},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{
+				{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+				{Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType, TLS: &gatewayv1.ListenerTLSConfig{
+					CertificateRefs: []gatewayv1.SecretObjectReference{
+						{Name: "cert"},
+						{Name: "other", Kind: ptr(gatewayv1.Kind("ConfigMap"))},
+					},
+				}},
+			},
+		},
+	}
+
+	expected := []string{"infra/cert"}
+	if actual := listenerSecretsIndex(gw); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestIndexedMapFuncs(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	route := func(name, gateway, service string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway)}}},
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{
+					{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(service)}}},
+				}}},
+			},
+		}
+	}
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithObjects(
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners: []gatewayv1.Listener{{Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType, TLS: &gatewayv1.ListenerTLSConfig{
+					CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "cert"}},
+				}}},
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other-gw"},
+			Spec:       gatewayv1.GatewaySpec{GatewayClassName: "theirs"},
+		},
+		route("a", "gw", "svc-a"),
+		route("b", "gw", "svc-b"),
+		route("c", "other-gw", "svc-a"),
+	).Build()
+	routes := &HTTPRouteReconciler{Client: c}
+	gateways := &GatewayReconciler{Client: c}
+
+	requests := func(names ...string) []reconcile.Request {
+		var requests []reconcile.Request
+		for _, name := range names {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}})
+		}
+		return requests
+	}
+
+	if actual, expected := routes.mapServiceToRoutes(ctx, newService("default", "svc-a")), requests("a", "c"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("mapServiceToRoutes: expected %v, got %v", expected, actual)
+	}
+	if actual, expected := routes.mapGatewayClassToRoutes(ctx, &gatewayv1.GatewayClass{ObjectMeta: metav1.ObjectMeta{Name: "ours"}}), requests("a", "b"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("mapGatewayClassToRoutes: expected %v, got %v", expected, actual)
+	}
+	sharing, err := routes.routesSharingHostname(ctx, route("d", "gw", "svc-d"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, r := range sharing {
+		names = append(names, r.Name)
+	}
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("routesSharingHostname: expected %v, got %v", expected, names)
+	}
+	named := route("d", "gw", "svc-d")
+	named.Spec.Hostnames = []gatewayv1.Hostname{"d.example.com"}
+	if sharing, err := routes.routesSharingHostname(ctx, named); err != nil || len(sharing) != 0 {
+		t.Errorf("routesSharingHostname: expected no routes, got %v, %v", sharing, err)
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cert"}}
+	if actual, expected := gateways.mapSecretToGateways(ctx, secret), requests("gw"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("mapSecretToGateways: expected %v, got %v", expected, actual)
+	}
+}
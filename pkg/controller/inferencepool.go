@@ -0,0 +1,156 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// listInferencePools returns all InferencePools, keyed by name, along with
+// the endpoints of the Pods they select. It returns no InferencePools if the
+// Inference Extension API is not installed in the cluster.
+func listInferencePools(ctx context.Context, c client.Reader) (map[types.NamespacedName]*ir.InferencePool, error) {
+	var list unstructured.UnstructuredList
+	list.SetGroupVersionKind(ir.InferencePoolGVK.GroupVersion().WithKind(ir.InferencePoolGVK.Kind + "List"))
+	if err := c.List(ctx, &list); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	pools := make(map[types.NamespacedName]*ir.InferencePool, len(list.Items))
+	for i := range list.Items {
+		pool := ir.ParseInferencePool(&list.Items[i])
+		if err := inferencePoolEndpoints(ctx, c, list.Items[i].GetNamespace(), pool); err != nil {
+			return nil, err
+		}
+		pools[client.ObjectKeyFromObject(&list.Items[i])] = pool
+	}
+	return pools, nil
+}
+
+// getInferencePool returns the InferencePool with the given key, along with
+// the endpoints of the Pods it selects, or nil if it does not exist or the
+// Inference Extension API is not installed in the cluster.
+func getInferencePool(ctx context.Context, c client.Reader, key types.NamespacedName) (*ir.InferencePool, error) {
+	var u unstructured.Unstructured
+	u.SetGroupVersionKind(ir.InferencePoolGVK)
+	if err := c.Get(ctx, key, &u); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	pool := ir.ParseInferencePool(&u)
+	if err := inferencePoolEndpoints(ctx, c, key.Namespace, pool); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// inferencePoolEndpoints sets the endpoints of pool, in namespace, to those
+// of the ready Pods it selects on each of its target ports.
+func inferencePoolEndpoints(ctx context.Context, c client.Reader, namespace string, pool *ir.InferencePool) error {
+	if len(pool.Selector) == 0 {
+		return nil
+	}
+	var pods corev1.PodList
+	if err := c.List(ctx, &pods, client.InNamespace(namespace), client.MatchingLabels(pool.Selector)); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		if !podReady(&pods.Items[i]) {
+			continue
+		}
+		for _, port := range pool.TargetPorts {
+			pool.Endpoints = append(pool.Endpoints, net.JoinHostPort(pods.Items[i].Status.PodIP, strconv.Itoa(int(port))))
+		}
+	}
+	return nil
+}
+
+// podReady reports whether a Pod has an IP and is ready to serve requests.
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// mapInferencePoolToRoutes enqueues the HTTPRoutes with a backendRef to the
+// changed InferencePool.
+func (r *HTTPRouteReconciler) mapInferencePoolToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.routesReferencingBackend(ctx, gatewayv1.Group(ir.InferencePoolGVK.Group), gatewayv1.Kind(ir.InferencePoolGVK.Kind), client.ObjectKeyFromObject(obj))
+}
+
+// mapPodToRoutes enqueues the HTTPRoutes with a backendRef to an
+// InferencePool selecting the changed Pod, whose endpoints the proxy falls
+// back to when the endpoint picker fails open.
+func (r *HTTPRouteReconciler) mapPodToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list unstructured.UnstructuredList
+	list.SetGroupVersionKind(ir.InferencePoolGVK.GroupVersion().WithKind(ir.InferencePoolGVK.Kind + "List"))
+	if err := r.List(ctx, &list, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list InferencePools")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range list.Items {
+		pool := ir.ParseInferencePool(&list.Items[i])
+		if len(pool.Selector) > 0 && labels.SelectorFromSet(pool.Selector).Matches(labels.Set(obj.GetLabels())) {
+			requests = append(requests, r.mapInferencePoolToRoutes(ctx, &list.Items[i])...)
+		}
+	}
+	return requests
+}
+
+// podEndpointChanged filters Pod updates to those that change whether or
+// where the Pod serves an InferencePool.
+var podEndpointChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, ok := e.ObjectOld.(*corev1.Pod)
+		if !ok {
+			return true
+		}
+		newPod, ok := e.ObjectNew.(*corev1.Pod)
+		if !ok {
+			return true
+		}
+		return podReady(oldPod) != podReady(newPod) ||
+			oldPod.Status.PodIP != newPod.Status.PodIP ||
+			!labels.Equals(oldPod.Labels, newPod.Labels)
+	},
+}
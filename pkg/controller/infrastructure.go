@@ -0,0 +1,184 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// kindGatewayConfig is the kind of the typed infrastructure parameters of a
+// Gateway.
+const kindGatewayConfig gatewayv1.Kind = "GatewayConfig"
+
+// Keys of the ConfigMap referenced by the infrastructure parametersRef of a
+// Gateway. nodeSelector and resources hold YAML documents.
+const (
+	infrastructureReplicas     = "replicas"
+	infrastructureResources    = "resources"
+	infrastructureNodeSelector = "nodeSelector"
+	infrastructureServiceType  = "serviceType"
+)
+
+var serviceTypes = []corev1.ServiceType{corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer}
+
+// infrastructureParameters is the per-Gateway infrastructure configuration.
+// Only ServiceType is applied, to the Service provisioned for the Gateway.
+// The proxies serving a Gateway are shared with the other Gateways, so
+// Replicas, Resources and NodeSelector cannot be applied to them: they are
+// parsed so that malformed values are reported as such, and Gateways that set
+// them are rejected rather than accepted without them.
+type infrastructureParameters struct {
+	Replicas     *int32
+	Resources    *corev1.ResourceRequirements
+	NodeSelector map[string]string
+	ServiceType  corev1.ServiceType
+}
+
+// resolveInfrastructureParameters returns the infrastructure parameters
+// referenced by a Gateway, or nil if it has none. The parameters are read
+// from a ConfigMap or a GatewayConfig in the Gateway's namespace; a missing
+// or malformed object is reported as an *invalidParametersError.
+func resolveInfrastructureParameters(ctx context.Context, c client.Reader, gw *gatewayv1.Gateway) (*infrastructureParameters, error) {
+	if gw.Spec.Infrastructure == nil || gw.Spec.Infrastructure.ParametersRef == nil {
+		return nil, nil
+	}
+	ref := gw.Spec.Infrastructure.ParametersRef
+	key := types.NamespacedName{Namespace: gw.Namespace, Name: ref.Name}
+
+	switch {
+	case string(ref.Group) == v1alpha1.GroupVersion.Group && ref.Kind == kindGatewayConfig:
+		var config v1alpha1.GatewayConfig
+		if err := c.Get(ctx, key, &config); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, invalidParameters("GatewayConfig %s not found", key)
+			}
+			return nil, err
+		}
+		params := &infrastructureParameters{
+			Replicas:     config.Spec.Replicas,
+			Resources:    config.Spec.Resources,
+			NodeSelector: config.Spec.NodeSelector,
+		}
+		if config.Spec.ServiceType != nil {
+			params.ServiceType = *config.Spec.ServiceType
+		}
+		if unsupported := params.unsupported(); len(unsupported) > 0 {
+			return nil, invalidParameters("GatewayConfig %s: %s not supported, since the proxies are shared between Gateways", key, strings.Join(unsupported, ", "))
+		}
+		return params, nil
+
+	case ref.Group == "" && ref.Kind == "ConfigMap":
+		var cm corev1.ConfigMap
+		if err := c.Get(ctx, key, &cm); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, invalidParameters("ConfigMap %s not found", key)
+			}
+			return nil, err
+		}
+		params, err := parseInfrastructureParameters(cm.Data)
+		if err != nil {
+			return nil, invalidParameters("ConfigMap %s: %v", key, err)
+		}
+		if unsupported := params.unsupported(); len(unsupported) > 0 {
+			return nil, invalidParameters("ConfigMap %s: %s not supported, since the proxies are shared between Gateways", key, strings.Join(unsupported, ", "))
+		}
+		return params, nil
+	}
+	return nil, invalidParameters("infrastructure parametersRef must refer to a GatewayConfig or a ConfigMap, got %s %s", ref.Kind, ref.Name)
+}
+
+// parseInfrastructureParameters validates the data of an infrastructure
+// parameters ConfigMap. Unknown keys are rejected so that typos do not go
+// unnoticed.
+func parseInfrastructureParameters(data map[string]string) (*infrastructureParameters, error) {
+	params := &infrastructureParameters{}
+	for key, value := range data {
+		switch key {
+		case infrastructureReplicas:
+			replicas, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || replicas < 1 {
+				return nil, fmt.Errorf("%s must be a positive integer, got %q", key, value)
+			}
+			params.Replicas = ptr(int32(replicas))
+		case infrastructureResources:
+			var resources corev1.ResourceRequirements
+			if err := yaml.UnmarshalStrict([]byte(value), &resources); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			params.Resources = &resources
+		case infrastructureNodeSelector:
+			if err := yaml.UnmarshalStrict([]byte(value), &params.NodeSelector); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		case infrastructureServiceType:
+			if !slices.Contains(serviceTypes, corev1.ServiceType(value)) {
+				return nil, fmt.Errorf("%s must be one of ClusterIP, NodePort, LoadBalancer, got %q", key, value)
+			}
+			params.ServiceType = corev1.ServiceType(value)
+		default:
+			return nil, fmt.Errorf("unknown parameter %q", key)
+		}
+	}
+	return params, nil
+}
+
+// unsupported returns the names of the parameters that are set but cannot be
+// applied to the proxies serving the Gateway.
+func (p *infrastructureParameters) unsupported() []string {
+	var names []string
+	if p.Replicas != nil {
+		names = append(names, infrastructureReplicas)
+	}
+	if p.Resources != nil {
+		names = append(names, infrastructureResources)
+	}
+	if p.NodeSelector != nil {
+		names = append(names, infrastructureNodeSelector)
+	}
+	return names
+}
+
+// referencesInfrastructureParameters reports whether the infrastructure
+// parametersRef of a Gateway refers to obj, which is a ConfigMap or a
+// GatewayConfig.
+func referencesInfrastructureParameters(gw *gatewayv1.Gateway, obj client.Object) bool {
+	if gw.Spec.Infrastructure == nil || gw.Spec.Infrastructure.ParametersRef == nil || gw.Namespace != obj.GetNamespace() {
+		return false
+	}
+	ref := gw.Spec.Infrastructure.ParametersRef
+	if ref.Name != obj.GetName() {
+		return false
+	}
+	switch obj.(type) {
+	case *corev1.ConfigMap:
+		return ref.Group == "" && ref.Kind == "ConfigMap"
+	case *v1alpha1.GatewayConfig:
+		return string(ref.Group) == v1alpha1.GroupVersion.Group && ref.Kind == kindGatewayConfig
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestParseInfrastructureParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]string
+		expected *infrastructureParameters
+	}{
+		{
+			name: "all parameters",
+			data: map[string]string{
+				"replicas":     "3",
+				"resources":    "requests:\n  cpu: 100m\n",
+				"nodeSelector": "pool: edge\n",
+				"serviceType":  "NodePort",
+			},
+			expected: &infrastructureParameters{
+				Replicas: ptr(int32(3)),
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				},
+				NodeSelector: map[string]string{"pool": "edge"},
+				ServiceType:  corev1.ServiceTypeNodePort,
+			},
+		},
+		{
+			name: "zero replicas",
+			data: map[string]string{"replicas": "0"},
+		},
+		{
+			name: "malformed resources",
+			data: map[string]string{"resources": "requests:\n  memory: lots\n"},
+		},
+		{
+			name: "unknown resources field",
+			data: map[string]string{"resources": "request:\n  cpu: 100m\n"},
+		},
+		{
+			name: "unknown service type",
+			data: map[string]string{"serviceType": "ExternalName"},
+		},
+		{
+			name: "unknown key",
+			data: map[string]string{"replica": "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseInfrastructureParameters(tt.data)
+			if tt.expected == nil {
+				if err == nil {
+					t.Errorf("expected error, got %v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolveInfrastructureParameters(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "service-type"},
+			Data:       map[string]string{"serviceType": "NodePort"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "replicas"},
+			Data:       map[string]string{"replicas": "3", "serviceType": "NodePort"},
+		},
+		&v1alpha1.GatewayConfig{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "node-selector"},
+			Spec:       v1alpha1.GatewayConfigSpec{NodeSelector: map[string]string{"pool": "edge"}},
+		},
+	).Build()
+
+	tests := []struct {
+		name     string
+		ref      gatewayv1.LocalParametersReference
+		expected *infrastructureParameters
+	}{
+		{
+			name:     "service type",
+			ref:      gatewayv1.LocalParametersReference{Kind: "ConfigMap", Name: "service-type"},
+			expected: &infrastructureParameters{ServiceType: corev1.ServiceTypeNodePort},
+		},
+		{
+			name: "replicas",
+			ref:  gatewayv1.LocalParametersReference{Kind: "ConfigMap", Name: "replicas"},
+		},
+		{
+			name: "node selector",
+			ref:  gatewayv1.LocalParametersReference{Group: "gari.gke-labs.dev", Kind: "GatewayConfig", Name: "node-selector"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+				Spec:       gatewayv1.GatewaySpec{Infrastructure: &gatewayv1.GatewayInfrastructure{ParametersRef: &tt.ref}},
+			}
+			actual, err := resolveInfrastructureParameters(context.Background(), c, gw)
+			if tt.expected == nil {
+				var invalid *invalidParametersError
+				if !errors.As(err, &invalid) {
+					t.Errorf("expected invalid parameters, got %v, %v", actual, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGatewayInvalidInfrastructureParameters(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gw := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw", Generation: 2},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "ours",
+			Infrastructure: &gatewayv1.GatewayInfrastructure{
+				ParametersRef: &gatewayv1.LocalParametersReference{Group: "gari.gke-labs.dev", Kind: "GatewayConfig", Name: "missing"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(gw).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		gw,
+	).Build()
+
+	r := &GatewayReconciler{Client: c, Scheme: scheme}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gw)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual gatewayv1.Gateway
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(gw), &actual); err != nil {
+		t.Fatalf("unable to get Gateway: %v", err)
+	}
+	accepted := meta.FindStatusCondition(actual.Status.Conditions, string(gatewayv1.GatewayConditionAccepted))
+	if accepted == nil || accepted.Status != metav1.ConditionFalse || accepted.Reason != string(gatewayv1.GatewayReasonInvalidParameters) || accepted.ObservedGeneration != 2 {
+		t.Errorf("expected Accepted=False with reason InvalidParameters, got %v", accepted)
+	}
+	if !referencesInfrastructureParameters(&actual, &v1alpha1.GatewayConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "missing"}}) {
+		t.Errorf("expected Gateway to reference GatewayConfig missing")
+	}
+}
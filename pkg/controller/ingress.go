@@ -0,0 +1,262 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ingressClassAnnotation is the annotation that selected the class of an
+// Ingress before spec.ingressClassName, still set by older manifests.
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+// ingressRoutePrefix prefixes the names of the proxy routes translated from
+// Ingresses, which cannot otherwise collide with those of HTTPRoutes since
+// object names cannot contain a slash.
+const ingressRoutePrefix = "ingress/"
+
+// IngressReconciler serves the Ingresses of an IngressClass from the route
+// table of the HTTPRoute reconciler, so that users can migrate from Ingress to
+// Gateway API on the same data plane. Each rule of an Ingress becomes a route
+// for its host, and the default backend a route for every host.
+//
+// Like the HTTPRoute controller, it runs on every replica, and only the
+// leader publishes the address of the ProxyService on the Ingresses.
+type IngressReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// IngressClass is the name of the IngressClass whose Ingresses are
+	// served.
+	IngressClass string
+	// Routes is the reconciler whose route table and proxy serve the
+	// Ingresses.
+	Routes *HTTPRouteReconciler
+	// ProxyService is the Service exposing the proxy, whose load balancer
+	// address is published on the Ingresses served.
+	ProxyService types.NamespacedName
+	// Backoff bounds the delays between retries of failed reconciles.
+	Backoff Backoff
+
+	// statusWrites limits how often the status of each Ingress is written.
+	statusWrites statusWriteLimiter
+}
+
+func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var ing networkingv1.Ingress
+	if err := r.Get(ctx, req.NamespacedName, &ing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.Routes.updateIngress(ctx, req.NamespacedName, nil)
+		return ctrl.Result{}, nil
+	}
+	if !r.servesIngress(&ing) || !ing.DeletionTimestamp.IsZero() {
+		r.Routes.updateIngress(ctx, req.NamespacedName, nil)
+		return ctrl.Result{}, nil
+	}
+
+	var services corev1.ServiceList
+	if err := r.List(ctx, &services, client.InNamespace(ing.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Routes.updateIngress(ctx, req.NamespacedName, translateIngress(&ing, services.Items, r.Routes.Backends.ClusterDomain))
+
+	if !r.Routes.leading() {
+		return ctrl.Result{}, nil
+	}
+	var svc corev1.Service
+	if err := r.Get(ctx, r.ProxyService, &svc); err != nil {
+		l.Error(err, "unable to fetch proxy Service")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	var addresses []networkingv1.IngressLoadBalancerIngress
+	for _, lb := range svc.Status.LoadBalancer.Ingress {
+		addresses = append(addresses, networkingv1.IngressLoadBalancerIngress{IP: lb.IP, Hostname: lb.Hostname})
+	}
+	if equality.Semantic.DeepEqual(ing.Status.LoadBalancer.Ingress, addresses) {
+		return ctrl.Result{}, nil
+	}
+	original := ing.DeepCopy()
+	ing.Status.LoadBalancer.Ingress = addresses
+	if err := patchStatus(ctx, r.Client, &r.statusWrites, &ing, original); err != nil {
+		return statusWriteFailed(l, err, "unable to update Ingress status")
+	}
+	return ctrl.Result{}, nil
+}
+
+// servesIngress reports whether ing belongs to the IngressClass served.
+func (r *IngressReconciler) servesIngress(ing *networkingv1.Ingress) bool {
+	if ing.Spec.IngressClassName != nil {
+		return *ing.Spec.IngressClassName == r.IngressClass
+	}
+	return ing.Annotations[ingressClassAnnotation] == r.IngressClass
+}
+
+// translateIngress translates an Ingress into proxy routes, resolving its
+// backends against the Services of its namespace, which are reached by their
+// name in clusterDomain. Paths whose backend cannot be resolved are skipped,
+// as are resource backends.
+func translateIngress(ing *networkingv1.Ingress, services []corev1.Service, clusterDomain string) []proxy.HTTPRoute {
+	newRoute := func(name string, hostnames []string) proxy.HTTPRoute {
+		return proxy.HTTPRoute{
+			Namespace:         ing.Namespace,
+			Name:              ingressRoutePrefix + ing.Name + "/" + name,
+			CreationTimestamp: ing.CreationTimestamp.Time,
+			Hostnames:         hostnames,
+		}
+	}
+
+	var routes []proxy.HTTPRoute
+	for i, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		var hostnames []string
+		if rule.Host != "" {
+			hostnames = []string{rule.Host}
+		}
+		route := newRoute(fmt.Sprintf("rule-%d", i), hostnames)
+		for j, path := range rule.HTTP.Paths {
+			backend, ok := ingressBackend(ing.Namespace, path.Backend, services, clusterDomain)
+			if !ok {
+				continue
+			}
+			match := proxy.PathMatch{Type: proxy.PathMatchTypePathPrefix, Value: path.Path}
+			if path.PathType != nil && *path.PathType == networkingv1.PathTypeExact {
+				match.Type = proxy.PathMatchTypeExact
+			}
+			if match.Value == "" {
+				match.Value = "/"
+			}
+			route.Rules = append(route.Rules, proxy.RouteRule{
+				Name:     fmt.Sprintf("path-%d", j),
+				Matches:  []proxy.RouteMatch{{Path: &match}},
+				Backends: []proxy.WeightedBackend{{Backend: backend, Weight: 1}},
+			})
+		}
+		if len(route.Rules) > 0 {
+			routes = append(routes, route)
+		}
+	}
+	if ing.Spec.DefaultBackend != nil {
+		if backend, ok := ingressBackend(ing.Namespace, *ing.Spec.DefaultBackend, services, clusterDomain); ok {
+			route := newRoute("default", nil)
+			route.Rules = []proxy.RouteRule{{
+				Name:     "default",
+				Matches:  []proxy.RouteMatch{{Path: &proxy.PathMatch{Type: proxy.PathMatchTypePathPrefix, Value: "/"}}},
+				Backends: []proxy.WeightedBackend{{Backend: backend, Weight: 1}},
+			}}
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// ingressBackend resolves the Service backend of an Ingress in namespace. The
+// port may be given by number or by name.
+func ingressBackend(namespace string, backend networkingv1.IngressBackend, services []corev1.Service, clusterDomain string) (proxy.Backend, bool) {
+	if backend.Service == nil {
+		return proxy.Backend{}, false
+	}
+	for _, svc := range services {
+		if svc.Name != backend.Service.Name {
+			continue
+		}
+		for _, port := range svc.Spec.Ports {
+			if (backend.Service.Port.Number != 0 && port.Port == backend.Service.Port.Number) ||
+				(backend.Service.Port.Name != "" && port.Name == backend.Service.Port.Name) {
+				return proxy.Backend{Host: ir.ServiceHost(namespace, svc.Name, clusterDomain), Port: port.Port}, true
+			}
+		}
+	}
+	return proxy.Backend{}, false
+}
+
+// ingressReferencesService reports whether a backend of ing is the Service
+// with the given name.
+func ingressReferencesService(ing *networkingv1.Ingress, name string) bool {
+	references := func(backend *networkingv1.IngressBackend) bool {
+		return backend != nil && backend.Service != nil && backend.Service.Name == name
+	}
+	if references(ing.Spec.DefaultBackend) {
+		return true
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if references(&path.Backend) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mapServiceToIngresses enqueues the Ingresses served with a backend to the
+// changed Service, or every Ingress served if it is the ProxyService, whose
+// address they publish.
+func (r *IngressReconciler) mapServiceToIngresses(ctx context.Context, obj client.Object) []reconcile.Request {
+	isProxyService := client.ObjectKeyFromObject(obj) == r.ProxyService
+	var opts []client.ListOption
+	if !isProxyService {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+	var ingresses networkingv1.IngressList
+	if err := r.List(ctx, &ingresses, opts...); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list Ingresses")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range ingresses.Items {
+		ing := &ingresses.Items[i]
+		if r.servesIngress(ing) && (isProxyService || ingressReferencesService(ing, obj.GetName())) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(ing)})
+		}
+	}
+	return requests
+}
+
+func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The controller runs on every replica to keep its route table in sync,
+	// like the HTTPRoute controller.
+	opts := r.Backoff.options()
+	needLeaderElection := false
+	opts.NeedLeaderElection = &needLeaderElection
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1.Ingress{}, builder.WithPredicates(specChanged)).
+		Watches(&corev1.Service{}, handler.EnqueueRequestsFromMapFunc(r.mapServiceToIngresses)).
+		WithOptions(opts).
+		Complete(r)
+}
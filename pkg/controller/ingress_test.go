@@ -0,0 +1,162 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestTranslateIngress(t *testing.T) {
+	services := []corev1.Service{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8080}}},
+	}}
+	web := func(port networkingv1.ServiceBackendPort) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web", Port: port}}
+	}
+	ing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "site"},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: ptr(web(networkingv1.ServiceBackendPort{Name: "http"})),
+			Rules: []networkingv1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+					{Path: "/api", PathType: ptr(networkingv1.PathTypePrefix), Backend: web(networkingv1.ServiceBackendPort{Number: 8080})},
+					{Path: "/health", PathType: ptr(networkingv1.PathTypeExact), Backend: web(networkingv1.ServiceBackendPort{Name: "http"})},
+					{Path: "/missing", Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "missing", Port: networkingv1.ServiceBackendPort{Number: 80}}}},
+				}}},
+			}},
+		},
+	}
+
+	backend := proxy.Backend{Host: "web.apps.svc.cluster.local", Port: 8080}
+	expected := []proxy.HTTPRoute{
+		{
+			Namespace: "apps",
+			Name:      "ingress/site/rule-0",
+			Hostnames: []string{"example.com"},
+			Rules: []proxy.RouteRule{
+				{
+					Name:     "path-0",
+					Matches:  []proxy.RouteMatch{{Path: &proxy.PathMatch{Type: proxy.PathMatchTypePathPrefix, Value: "/api"}}},
+					Backends: []proxy.WeightedBackend{{Backend: backend, Weight: 1}},
+				},
+				{
+					Name:     "path-1",
+					Matches:  []proxy.RouteMatch{{Path: &proxy.PathMatch{Type: proxy.PathMatchTypeExact, Value: "/health"}}},
+					Backends: []proxy.WeightedBackend{{Backend: backend, Weight: 1}},
+				},
+			},
+		},
+		{
+			Namespace: "apps",
+			Name:      "ingress/site/default",
+			Rules: []proxy.RouteRule{{
+				Name:     "default",
+				Matches:  []proxy.RouteMatch{{Path: &proxy.PathMatch{Type: proxy.PathMatchTypePathPrefix, Value: "/"}}},
+				Backends: []proxy.WeightedBackend{{Backend: backend, Weight: 1}},
+			}},
+		},
+	}
+	if actual := translateIngress(ing, services, ""); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestReconcileIngress(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	ing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "site", Annotations: map[string]string{ingressClassAnnotation: "gari"}},
+		Spec: networkingv1.IngressSpec{DefaultBackend: &networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 80}},
+		}},
+	}
+	proxyService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "gari-system", Name: "proxy"},
+		Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "192.0.2.1"}}}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(ing).WithObjects(
+		ing,
+		proxyService,
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+		},
+	).Build()
+	p := proxy.NewProxy(proxy.Options{})
+	r := &IngressReconciler{
+		Client:       c,
+		Scheme:       scheme,
+		IngressClass: "gari",
+		Routes:       &HTTPRouteReconciler{Proxy: p},
+		ProxyService: client.ObjectKeyFromObject(proxyService),
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(ing)}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if routes := p.Routes(); len(routes) != 1 || routes[0].Name != "ingress/site/default" {
+		t.Errorf("expected the Ingress to be served, got %v", routes)
+	}
+	var actual networkingv1.Ingress
+	if err := c.Get(ctx, req.NamespacedName, &actual); err != nil {
+		t.Fatalf("unable to get Ingress: %v", err)
+	}
+	if lb := actual.Status.LoadBalancer.Ingress; len(lb) != 1 || lb[0].IP != "192.0.2.1" {
+		t.Errorf("expected the proxy address to be published, got %v", lb)
+	}
+	if requests := r.mapServiceToIngresses(ctx, proxyService); len(requests) != 1 || requests[0] != req {
+		t.Errorf("expected the proxy Service to enqueue %v, got %v", req, requests)
+	}
+
+	// Ingresses are kept when the HTTPRoutes are rebuilt.
+	r.Routes.routeTable.reset(nil, nil, DefaultControllerName)
+	if routes := r.Routes.routeTable.ingressRoutes(); len(routes) != 1 {
+		t.Errorf("expected the Ingress routes to be kept, got %v", routes)
+	}
+
+	// An Ingress moved to another class is no longer served.
+	actual.Annotations[ingressClassAnnotation] = "other"
+	if err := c.Update(ctx, &actual); err != nil {
+		t.Fatalf("unable to update Ingress: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if routes := p.Routes(); len(routes) != 0 {
+		t.Errorf("expected no routes, got %v", routes)
+	}
+	if _, ok := r.Routes.routeTable.ingresses[types.NamespacedName{Namespace: "apps", Name: "site"}]; ok {
+		t.Errorf("expected the Ingress to be dropped from the route table")
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// inputReader reads the objects routes are translated against into an
+// ir.Input: from the manager's cache for the HTTPRouteReconciler, and from the
+// objects of manifests for Translate.
+type inputReader struct {
+	client.Reader
+	// controllerName is the controllerName of the GatewayClasses served.
+	controllerName gatewayv1.GatewayController
+	// shard is the shard of the Gateways served; see GatewayShardLabel.
+	shard string
+	// backendTLSPolicies is set if BackendTLSPolicies are applied to
+	// backends.
+	backendTLSPolicies bool
+	// backends configures how the Services of backendRefs are reached.
+	backends ir.BackendOptions
+	// wasmPlugins keeps the plugins of ExtensionRef filters loaded.
+	wasmPlugins *wasmPluginCache
+}
+
+// inputReader returns the inputReader of the reconciler.
+func (r *HTTPRouteReconciler) inputReader() inputReader {
+	return inputReader{
+		Reader:             r.Client,
+		controllerName:     controllerNameOrDefault(r.ControllerName),
+		shard:              r.Shard,
+		backendTLSPolicies: r.backendTLSPolicies,
+		backends:           r.Backends,
+		wasmPlugins:        &r.wasmPlugins,
+	}
+}
+
+// newInput returns an Input with no objects read yet.
+func (r inputReader) newInput() ir.Input {
+	return ir.Input{
+		ControllerName: r.controllerName,
+		Shard:          r.shard,
+		Limits:         map[string]ir.Limits{},
+		Policies:       ir.NewPolicies(),
+	}
+}
+
+// buildInput reads every object routes are translated against. It runs when
+// the whole route table is rebuilt; reconciles of a single route go through
+// routeInput.
+func (r inputReader) buildInput(ctx context.Context, routes []gatewayv1.HTTPRoute) (ir.Input, error) {
+	in := r.newInput()
+	in.HTTPRoutes = routes
+	var classes gatewayv1.GatewayClassList
+	if err := r.List(ctx, &classes); err != nil {
+		return ir.Input{}, err
+	}
+	for i := range classes.Items {
+		if err := r.addGatewayClass(ctx, &in, &classes.Items[i]); err != nil {
+			return ir.Input{}, err
+		}
+	}
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		return ir.Input{}, err
+	}
+	in.Gateways = gateways.Items
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces); err != nil {
+		return ir.Input{}, err
+	}
+	in.Namespaces = namespaces.Items
+	if err := r.resolvePolicies(ctx, &in.Policies); err != nil {
+		return ir.Input{}, err
+	}
+	var err error
+	if in.Policies.Hooks, err = r.wasmPlugins.resolve(ctx, r.Reader, routes); err != nil {
+		return ir.Input{}, err
+	}
+	if in.Policies.GatewayRequestTimeouts, err = requestTimeoutsForGateways(ctx, r.Reader, in.ControllerName, in.Gateways); err != nil {
+		return ir.Input{}, err
+	}
+	if in.Targets, err = listBackendTargets(ctx, r.Reader, r.backendTLSPolicies, r.backends); err != nil {
+		return ir.Input{}, err
+	}
+	return in, nil
+}
+
+// routeInput reads the objects route is translated against, and only those:
+// its Gateways and their classes, its Namespace, the policies in its namespace
+// and in those of its Gateways, the objects its backendRefs refer to, and the
+// other routes of those of its Gateways whose class limits their routes.
+func (r inputReader) routeInput(ctx context.Context, route *gatewayv1.HTTPRoute) (ir.Input, error) {
+	in := r.newInput()
+	in.HTTPRoutes = []gatewayv1.HTTPRoute{*route}
+	namespaces := []string{route.Namespace}
+	seen := map[types.NamespacedName]bool{}
+	for _, key := range ir.RouteGateways(route) {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		var gw gatewayv1.Gateway
+		if err := r.Get(ctx, key, &gw); err != nil {
+			if err := client.IgnoreNotFound(err); err != nil {
+				return ir.Input{}, err
+			}
+			continue
+		}
+		in.Gateways = append(in.Gateways, gw)
+		if !slices.Contains(namespaces, key.Namespace) {
+			namespaces = append(namespaces, key.Namespace)
+		}
+
+		className := string(gw.Spec.GatewayClassName)
+		if !slices.ContainsFunc(in.GatewayClasses, func(gc gatewayv1.GatewayClass) bool { return gc.Name == className }) {
+			var gc gatewayv1.GatewayClass
+			if err := r.Get(ctx, client.ObjectKey{Name: className}, &gc); err != nil {
+				if err := client.IgnoreNotFound(err); err != nil {
+					return ir.Input{}, err
+				}
+				continue
+			}
+			if err := r.addGatewayClass(ctx, &in, &gc); err != nil {
+				return ir.Input{}, err
+			}
+		}
+		if in.Limits[className].MaxRoutesPerGateway > 0 {
+			var peers gatewayv1.HTTPRouteList
+			if err := r.List(ctx, &peers, client.MatchingFields{indexRouteParentGateways: key.String()}); err != nil {
+				return ir.Input{}, err
+			}
+			in.PeerRoutes = append(in.PeerRoutes, peers.Items...)
+		}
+	}
+	var namespace corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: route.Namespace}, &namespace); err != nil {
+		if err := client.IgnoreNotFound(err); err != nil {
+			return ir.Input{}, err
+		}
+	} else {
+		in.Namespaces = append(in.Namespaces, namespace)
+	}
+
+	// Policies live in the namespace of the route or Gateway they target.
+	for _, namespace := range namespaces {
+		if err := r.resolvePolicies(ctx, &in.Policies, client.InNamespace(namespace)); err != nil {
+			return ir.Input{}, err
+		}
+	}
+	var err error
+	if in.Policies.Hooks, err = r.wasmPlugins.resolve(ctx, r.Reader, in.HTTPRoutes); err != nil {
+		return ir.Input{}, err
+	}
+	if in.Policies.GatewayRequestTimeouts, err = requestTimeoutsForGateways(ctx, r.Reader, in.ControllerName, in.Gateways); err != nil {
+		return ir.Input{}, err
+	}
+	if in.Targets, err = routeBackendTargets(ctx, r.Reader, route, r.backendTLSPolicies, r.backends); err != nil {
+		return ir.Input{}, err
+	}
+	return in, nil
+}
+
+// addGatewayClass adds gc to in, along with the limits set by its parameters
+// if it is served.
+func (r inputReader) addGatewayClass(ctx context.Context, in *ir.Input, gc *gatewayv1.GatewayClass) error {
+	in.GatewayClasses = append(in.GatewayClasses, *gc)
+	if gc.Spec.ControllerName != in.ControllerName {
+		return nil
+	}
+	limits, err := classLimits(ctx, r.Reader, gc)
+	if err != nil {
+		return err
+	}
+	in.Limits[gc.Name] = limits
+	return nil
+}
+
+// resolvePolicies adds the state resolved from the policies matching opts to
+// policies.
+func (r inputReader) resolvePolicies(ctx context.Context, policies *ir.Policies, opts ...client.ListOption) error {
+	basicAuth, err := basicAuthForRoutes(ctx, r.Reader, opts...)
+	if err != nil {
+		return err
+	}
+	securityHeaders, gatewaySecurityHeaders, err := securityHeadersForTargets(ctx, r.Reader, opts...)
+	if err != nil {
+		return err
+	}
+	transforms, gatewayTransforms, err := transformsForTargets(ctx, r.Reader, opts...)
+	if err != nil {
+		return err
+	}
+	telemetry, gatewayTelemetry, err := telemetryForTargets(ctx, r.Reader, opts...)
+	if err != nil {
+		return err
+	}
+	maps.Copy(policies.BasicAuth, basicAuth)
+	maps.Copy(policies.SecurityHeaders, securityHeaders)
+	maps.Copy(policies.GatewaySecurityHeaders, gatewaySecurityHeaders)
+	maps.Copy(policies.Transforms, transforms)
+	maps.Copy(policies.GatewayTransforms, gatewayTransforms)
+	maps.Copy(policies.Telemetry, telemetry)
+	maps.Copy(policies.GatewayTelemetry, gatewayTelemetry)
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The HTTPRoute controller runs on every replica, whether or not it is the
+// leader, since each replica serves traffic from its own route table. Only the
+// leader writes route statuses and records Events and audit events about them;
+// the other replicas compute the statuses they would write and program their
+// proxy from them. The other controllers only write statuses, so they only run
+// on the leader.
+
+// leading reports whether this replica writes route statuses: once it is
+// elected leader, or always if the reconciler was not set up with a manager.
+func (r *HTTPRouteReconciler) leading() bool {
+	if r.elected == nil {
+		return true
+	}
+	select {
+	case <-r.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// routeStatusTakeover requeues every HTTPRoute once this replica is elected
+// leader, so that the statuses computed while it was not are written. It only
+// runs on the leader.
+type routeStatusTakeover struct {
+	r *HTTPRouteReconciler
+}
+
+func (t *routeStatusTakeover) Start(ctx context.Context) error {
+	var routes gatewayv1.HTTPRouteList
+	if err := t.r.List(ctx, &routes); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list HTTPRoutes to write their status")
+		return nil
+	}
+	for i := range routes.Items {
+		if !requeue(ctx, t.r.takeover, &routes.Items[i]) {
+			return nil
+		}
+	}
+	return nil
+}
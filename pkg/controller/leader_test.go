@@ -0,0 +1,92 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcileRouteWhileNotLeading(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route", UID: "route"},
+		Spec: gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{
+			ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}},
+		}},
+	}
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithStatusSubresource(route).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		},
+		route,
+	).Build()
+
+	p := proxy.NewProxy(proxy.Options{})
+	elected := make(chan struct{})
+	r := &HTTPRouteReconciler{Client: c, Scheme: scheme, Proxy: p, elected: elected}
+	reconcileRoute := func() *gatewayv1.HTTPRoute {
+		t.Helper()
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var actual gatewayv1.HTTPRoute
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(route), &actual); err != nil {
+			t.Fatalf("unable to get route: %v", err)
+		}
+		return &actual
+	}
+
+	// A replica that is not the leader serves the route without reporting it.
+	if actual := reconcileRoute(); len(actual.Status.Parents) != 0 {
+		t.Errorf("expected no status to be written before being elected, got %v", actual.Status.Parents)
+	}
+	if routes := p.Routes(); len(routes) != 1 {
+		t.Errorf("expected the route to be served before being elected, got %v", routes)
+	}
+
+	close(elected)
+	if actual := reconcileRoute(); !isRouteAccepted(actual, DefaultControllerName) {
+		t.Errorf("expected the route to be reported accepted once elected, got %v", actual.Status.Parents)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// classLimits returns the limits set by the parameters of gc. Invalid
+// parameters set none: the class is not accepted, which is reported on it.
+func classLimits(ctx context.Context, c client.Reader, gc *gatewayv1.GatewayClass) (ir.Limits, error) {
+	params, err := resolveClassParameters(ctx, c, gc)
+	if err != nil {
+		if invalid := (*invalidParametersError)(nil); errors.As(err, &invalid) {
+			return ir.Limits{}, nil
+		}
+		return ir.Limits{}, err
+	}
+	if params == nil {
+		return ir.Limits{}, nil
+	}
+	return params.Limits, nil
+}
+
+// mapRouteToGatewayPeers enqueues the other routes of the Gateways of the
+// changed route whose class limits their number of routes, since a route
+// created or deleted may push another over the limit or make room for it.
+func (r *HTTPRouteReconciler) mapRouteToGatewayPeers(ctx context.Context, obj client.Object) []reconcile.Request {
+	route, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil
+	}
+	var limited []types.NamespacedName
+	for _, key := range ir.RouteGateways(route) {
+		var gw gatewayv1.Gateway
+		if err := r.Get(ctx, key, &gw); err != nil {
+			continue
+		}
+		var gc gatewayv1.GatewayClass
+		if err := r.Get(ctx, client.ObjectKey{Name: string(gw.Spec.GatewayClassName)}, &gc); err != nil || gc.Spec.ControllerName != controllerNameOrDefault(r.ControllerName) {
+			continue
+		}
+		limits, err := classLimits(ctx, r.Client, &gc)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "unable to resolve GatewayClass parameters", "gatewayclass", gc.Name)
+			continue
+		}
+		if limits.MaxRoutesPerGateway > 0 {
+			limited = append(limited, key)
+		}
+	}
+	requests := r.routesForGateways(ctx, limited)
+	return slices.DeleteFunc(requests, func(request reconcile.Request) bool {
+		return request.NamespacedName == client.ObjectKeyFromObject(route)
+	})
+}
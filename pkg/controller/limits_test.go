@@ -0,0 +1,130 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestRouteLimits(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newRoute := func(name string, age time.Duration, rules ...gatewayv1.HTTPRouteRule) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: metav1.NewTime(created.Add(-age))},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}}},
+				Rules:           rules,
+			},
+		}
+	}
+	regexRule := gatewayv1.HTTPRouteRule{Matches: []gatewayv1.HTTPRouteMatch{{
+		Headers: []gatewayv1.HTTPHeaderMatch{{Type: ptr(gatewayv1.HeaderMatchRegularExpression), Name: "x-user", Value: "a.*"}},
+	}}}
+	oldest := newRoute("oldest", 3*time.Hour, gatewayv1.HTTPRouteRule{})
+	older := newRoute("older", 2*time.Hour, gatewayv1.HTTPRouteRule{})
+	newest := newRoute("newest", time.Hour, gatewayv1.HTTPRouteRule{})
+	rules := newRoute("rules", 4*time.Hour, gatewayv1.HTTPRouteRule{}, gatewayv1.HTTPRouteRule{}, gatewayv1.HTTPRouteRule{})
+	regex := newRoute("regex", 5*time.Hour, regexRule, regexRule)
+
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithObjects(
+		&v1alpha1.GatewayClassConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "limits"},
+			Spec: v1alpha1.GatewayClassConfigSpec{Limits: &v1alpha1.ResourceLimits{
+				MaxRoutesPerGateway: ptr(int32(4)),
+				MaxRulesPerRoute:    ptr(int32(2)),
+				MaxRegexMatchers:    ptr(int32(1)),
+			}},
+		},
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec: gatewayv1.GatewayClassSpec{
+				ControllerName: DefaultControllerName,
+				ParametersRef:  &gatewayv1.ParametersReference{Group: "gari.gke-labs.dev", Kind: "GatewayClassConfig", Name: "limits"},
+			},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		},
+		oldest, older, newest, rules, regex,
+	).Build()
+	r := &HTTPRouteReconciler{Client: c, Scheme: scheme}
+
+	tests := []struct {
+		route    *gatewayv1.HTTPRoute
+		expected gatewayv1.RouteConditionReason
+	}{
+		// Routes over a limit of their own still count towards the
+		// routes of the Gateway, so that fixing them does not displace others.
+		{route: regex, expected: ir.RouteReasonLimitExceeded},
+		{route: rules, expected: ir.RouteReasonLimitExceeded},
+		{route: oldest, expected: gatewayv1.RouteReasonAccepted},
+		{route: older, expected: gatewayv1.RouteReasonAccepted},
+		{route: newest, expected: ir.RouteReasonLimitExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route.Name, func(t *testing.T) {
+			in, err := r.inputReader().routeInput(context.Background(), tt.route)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			parents := ir.Build(in).Parents[client.ObjectKeyFromObject(tt.route)]
+			if len(parents) != 1 || parents[0].Reason != tt.expected {
+				t.Errorf("expected reason %s, got %+v", tt.expected, parents)
+			}
+		})
+	}
+
+	// Deleting a route makes room for the routes of the same Gateway.
+	requests := r.mapRouteToGatewayPeers(context.Background(), oldest)
+	if !containsRequest(requests, newest) || containsRequest(requests, oldest) {
+		t.Errorf("expected the other routes of the Gateway to be enqueued, got %v", requests)
+	}
+}
+
+func containsRequest(requests []reconcile.Request, obj client.Object) bool {
+	for _, request := range requests {
+		if request.NamespacedName == client.ObjectKeyFromObject(obj) {
+			return true
+		}
+	}
+	return false
+}
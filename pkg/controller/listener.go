@@ -0,0 +1,167 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/conditions"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// certificateRefError describes why the certificateRefs of a listener cannot
+// be resolved.
+type certificateRefError struct {
+	reason  gatewayv1.ListenerConditionReason
+	message string
+}
+
+// isSecretRef reports whether ref refers to a core Secret, which is the
+// default when no group or kind is given.
+func isSecretRef(ref gatewayv1.SecretObjectReference) bool {
+	return (ref.Group == nil || *ref.Group == "") && (ref.Kind == nil || *ref.Kind == "Secret")
+}
+
+// resolveCertificateRefs checks that the certificateRefs of each listener
+// refer to existing TLS Secrets in the Gateway's namespace, and returns the
+// error of each listener whose refs cannot be resolved.
+func resolveCertificateRefs(ctx context.Context, c client.Reader, gw *gatewayv1.Gateway) (map[gatewayv1.SectionName]*certificateRefError, error) {
+	refErrors := map[gatewayv1.SectionName]*certificateRefError{}
+	for _, listener := range gw.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			refErr, err := resolveCertificateRef(ctx, c, gw.Namespace, ref)
+			if err != nil {
+				return nil, err
+			}
+			if refErr != nil {
+				refErrors[listener.Name] = refErr
+				break
+			}
+		}
+	}
+	return refErrors, nil
+}
+
+// resolveCertificateRef checks a single certificateRef of a listener of a
+// Gateway in namespace.
+func resolveCertificateRef(ctx context.Context, c client.Reader, namespace string, ref gatewayv1.SecretObjectReference) (*certificateRefError, error) {
+	if !isSecretRef(ref) {
+		return &certificateRefError{
+			reason:  gatewayv1.ListenerReasonInvalidCertificateRef,
+			message: fmt.Sprintf("certificateRef %s: unsupported kind", ref.Name),
+		}, nil
+	}
+	if ref.Namespace != nil && string(*ref.Namespace) != namespace {
+		return &certificateRefError{
+			reason:  gatewayv1.ListenerReasonRefNotPermitted,
+			message: fmt.Sprintf("certificateRef %s: references to other namespaces are not supported", ref.Name),
+		}, nil
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: string(ref.Name)}, &secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		return &certificateRefError{
+			reason:  gatewayv1.ListenerReasonInvalidCertificateRef,
+			message: fmt.Sprintf("certificateRef %s: Secret not found", ref.Name),
+		}, nil
+	}
+	if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return &certificateRefError{
+			reason:  gatewayv1.ListenerReasonInvalidCertificateRef,
+			message: fmt.Sprintf("certificateRef %s: Secret has no %s and %s", ref.Name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey),
+		}, nil
+	}
+	return nil, nil
+}
+
+// listenerStatuses computes the status of each listener of a Gateway, keeping
+// the transition times of unchanged conditions from the current status.
+// Listeners with unresolved certificateRefs are reported as not programmed.
+func listenerStatuses(gw *gatewayv1.Gateway, refErrors map[gatewayv1.SectionName]*certificateRefError) []gatewayv1.ListenerStatus {
+	existing := map[gatewayv1.SectionName][]metav1.Condition{}
+	for _, ls := range gw.Status.Listeners {
+		existing[ls.Name] = ls.Conditions
+	}
+
+	var statuses []gatewayv1.ListenerStatus
+	for i := range gw.Spec.Listeners {
+		listener := &gw.Spec.Listeners[i]
+		supported, invalid := ir.ListenerSupportedKinds(listener)
+		if supported == nil {
+			supported = []gatewayv1.RouteGroupKind{}
+		}
+
+		resolvedRefs := metav1.Condition{
+			Type:    string(gatewayv1.ListenerConditionResolvedRefs),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(gatewayv1.ListenerReasonResolvedRefs),
+			Message: "All references resolved",
+		}
+		if len(invalid) > 0 {
+			var names []string
+			for _, rgk := range invalid {
+				names = append(names, string(rgk.Kind))
+			}
+			resolvedRefs.Status = metav1.ConditionFalse
+			resolvedRefs.Reason = string(gatewayv1.ListenerReasonInvalidRouteKinds)
+			resolvedRefs.Message = fmt.Sprintf("Unsupported route kinds: %s", strings.Join(names, ", "))
+		}
+		programmed := metav1.Condition{
+			Type:    string(gatewayv1.ListenerConditionProgrammed),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(gatewayv1.ListenerReasonProgrammed),
+			Message: "Listener programmed by reference implementation",
+		}
+		if refErr := refErrors[listener.Name]; refErr != nil {
+			resolvedRefs.Status = metav1.ConditionFalse
+			resolvedRefs.Reason = string(refErr.reason)
+			resolvedRefs.Message = refErr.message
+			programmed.Status = metav1.ConditionFalse
+			programmed.Reason = string(gatewayv1.ListenerReasonInvalid)
+			programmed.Message = refErr.message
+		}
+
+		listenerConditions, _ := conditions.Merge(existing[listener.Name], gw.Generation,
+			metav1.Condition{
+				Type:    string(gatewayv1.ListenerConditionAccepted),
+				Status:  metav1.ConditionTrue,
+				Reason:  string(gatewayv1.ListenerReasonAccepted),
+				Message: "Listener accepted by reference implementation",
+			},
+			programmed,
+			resolvedRefs,
+		)
+		statuses = append(statuses, gatewayv1.ListenerStatus{
+			Name:           listener.Name,
+			SupportedKinds: supported,
+			Conditions:     listenerConditions,
+		})
+	}
+	return statuses
+}
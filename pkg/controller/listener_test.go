@@ -0,0 +1,115 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestListenerStatusesInvalidRouteKinds(t *testing.T) {
+	gw := &gatewayv1.Gateway{
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{
+				{
+					Name:          "tcp",
+					Protocol:      gatewayv1.HTTPProtocolType,
+					AllowedRoutes: &gatewayv1.AllowedRoutes{Kinds: []gatewayv1.RouteGroupKind{{Kind: "TCPRoute"}}},
+				},
+			},
+		},
+	}
+
+	statuses := listenerStatuses(gw, nil)
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 listener status, got %v", len(statuses))
+	}
+	condition := statuses[0].Conditions[2]
+	if condition.Status != metav1.ConditionFalse || condition.Reason != string(gatewayv1.ListenerReasonInvalidRouteKinds) {
+		t.Errorf("expected ResolvedRefs False/InvalidRouteKinds, got %v/%v", condition.Status, condition.Reason)
+	}
+	if len(statuses[0].SupportedKinds) != 0 {
+		t.Errorf("expected no supported kinds, got %v", statuses[0].SupportedKinds)
+	}
+}
+
+func TestResolveCertificateRefs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cert"},
+			Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "empty"}},
+	).Build()
+	listener := func(name string, ref gatewayv1.SecretObjectReference) gatewayv1.Listener {
+		return gatewayv1.Listener{
+			Name: gatewayv1.SectionName(name), Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+			TLS: &gatewayv1.ListenerTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{ref}},
+		}
+	}
+	gw := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+		Spec: gatewayv1.GatewaySpec{
+			Listeners: []gatewayv1.Listener{
+				listener("valid", gatewayv1.SecretObjectReference{Name: "cert"}),
+				listener("missing", gatewayv1.SecretObjectReference{Name: "missing"}),
+				listener("empty", gatewayv1.SecretObjectReference{Name: "empty"}),
+				listener("kind", gatewayv1.SecretObjectReference{Name: "cert", Kind: ptr(gatewayv1.Kind("ConfigMap"))}),
+				listener("cross", gatewayv1.SecretObjectReference{Name: "cert", Namespace: ptr(gatewayv1.Namespace("other"))}),
+			},
+		},
+	}
+
+	refErrors, err := resolveCertificateRefs(context.Background(), c, gw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[gatewayv1.SectionName]gatewayv1.ListenerConditionReason{
+		"missing": gatewayv1.ListenerReasonInvalidCertificateRef,
+		"empty":   gatewayv1.ListenerReasonInvalidCertificateRef,
+		"kind":    gatewayv1.ListenerReasonInvalidCertificateRef,
+		"cross":   gatewayv1.ListenerReasonRefNotPermitted,
+	}
+	actual := map[gatewayv1.SectionName]gatewayv1.ListenerConditionReason{}
+	for name, refErr := range refErrors {
+		actual[name] = refErr.reason
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	statuses := listenerStatuses(gw, refErrors)
+	for _, status := range statuses {
+		expectedStatus := metav1.ConditionFalse
+		if status.Name == "valid" {
+			expectedStatus = metav1.ConditionTrue
+		}
+		if programmed := status.Conditions[1]; programmed.Status != expectedStatus {
+			t.Errorf("listener %s: expected Programmed %v, got %v", status.Name, expectedStatus, programmed.Status)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var (
+	routesProgrammed = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gari_controller_routes_programmed",
+		Help: "Number of HTTPRoutes programmed into the proxy.",
+	})
+
+	routesRejected = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gari_controller_routes_rejected",
+		Help: "Number of HTTPRoutes not accepted by any of their parents, by reason.",
+	}, []string{"reason"})
+
+	gatewaysManaged = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gari_controller_gateways_managed",
+		Help: "Number of Gateways whose GatewayClass is managed by this controller.",
+	})
+
+	statusUpdateFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gari_controller_status_update_failures_total",
+		Help: "Number of failed status writes, by kind of object.",
+	}, []string{"kind"})
+
+	statusWritesRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gari_controller_status_writes_rate_limited_total",
+		Help: "Number of status writes deferred because the object's status was written too often, by kind of object.",
+	}, []string{"kind"})
+
+	translationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gari_controller_translation_duration_seconds",
+		Help:    "Time taken to translate HTTPRoutes and policies into the proxy configuration.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	proxyUpdateDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gari_controller_proxy_update_duration_seconds",
+		Help:    "Time taken to recompute and install the proxy configuration, from listing routes until the proxy serves it.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(routesProgrammed, routesRejected, gatewaysManaged, statusUpdateFailures, statusWritesRateLimited, translationDuration, proxyUpdateDuration)
+}
+
+// recordRouteMetrics sets the route gauges from the status of every route.
+// Routes without a status from controllerName are not counted.
+func recordRouteMetrics(routes []gatewayv1.HTTPRoute, controllerName gatewayv1.GatewayController) {
+	programmed := 0
+	rejected := map[string]int{}
+	for i := range routes {
+		ours, accepted, reason := routeAcceptanceState(&routes[i], controllerName)
+		switch {
+		case accepted:
+			programmed++
+		case ours:
+			rejected[reason]++
+		}
+	}
+	setRouteGauges(programmed, rejected)
+}
+
+// routeAcceptanceState reports whether the route has a status from
+// controllerName, whether any of our parents accepted it, and otherwise the reason
+// the first of them rejected it.
+func routeAcceptanceState(route *gatewayv1.HTTPRoute, controllerName gatewayv1.GatewayController) (ours, accepted bool, reason string) {
+	for _, ps := range route.Status.Parents {
+		if ps.ControllerName != controllerName {
+			continue
+		}
+		ours = true
+		for _, c := range ps.Conditions {
+			if c.Type != string(gatewayv1.RouteConditionAccepted) {
+				continue
+			}
+			if c.Status == metav1.ConditionTrue {
+				accepted = true
+			} else if reason == "" {
+				reason = c.Reason
+			}
+		}
+	}
+	return ours, accepted, reason
+}
+
+// setRouteGauges sets the number of programmed routes and of rejected routes
+// by reason.
+func setRouteGauges(programmed int, rejected map[string]int) {
+	routesProgrammed.Set(float64(programmed))
+	routesRejected.Reset()
+	for reason, count := range rejected {
+		routesRejected.WithLabelValues(reason).Set(float64(count))
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestRecordRouteMetrics(t *testing.T) {
+	newRoute := func(controller gatewayv1.GatewayController, statuses ...metav1.ConditionStatus) gatewayv1.HTTPRoute {
+		var route gatewayv1.HTTPRoute
+		for _, status := range statuses {
+			reason := string(gatewayv1.RouteReasonAccepted)
+			if status != metav1.ConditionTrue {
+				reason = string(gatewayv1.RouteReasonNotAllowedByListeners)
+			}
+			route.Status.Parents = append(route.Status.Parents, gatewayv1.RouteParentStatus{
+				ControllerName: controller,
+				Conditions: []metav1.Condition{
+					{Type: string(gatewayv1.RouteConditionAccepted), Status: status, Reason: reason},
+				},
+			})
+		}
+		return route
+	}
+
+	recordRouteMetrics([]gatewayv1.HTTPRoute{
+		newRoute(DefaultControllerName, metav1.ConditionTrue),
+		newRoute(DefaultControllerName, metav1.ConditionFalse, metav1.ConditionTrue),
+		newRoute(DefaultControllerName, metav1.ConditionFalse),
+		newRoute("example.com/other", metav1.ConditionFalse),
+		newRoute(DefaultControllerName),
+	}, DefaultControllerName)
+
+	if actual := testutil.ToFloat64(routesProgrammed); actual != 2 {
+		t.Errorf("expected 2 programmed routes, got %v", actual)
+	}
+	if actual := testutil.ToFloat64(routesRejected.WithLabelValues(string(gatewayv1.RouteReasonNotAllowedByListeners))); actual != 1 {
+		t.Errorf("expected 1 rejected route, got %v", actual)
+	}
+	if actual := testutil.CollectAndCount(routesRejected); actual != 1 {
+		t.Errorf("expected 1 rejection reason, got %v", actual)
+	}
+}
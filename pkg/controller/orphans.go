@@ -0,0 +1,128 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var orphansFound = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gari_controller_orphaned_resources_found_total",
+	Help: "Number of provisioned resources found without their Gateway by the orphan sweep, by kind of resource.",
+}, []string{"kind"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(orphansFound)
+}
+
+// OrphanSweeper periodically deletes the resources provisioned for Gateways
+// that no longer exist. They are owned by their Gateway, so the garbage
+// collector deletes them with it, but not when they were provisioned before
+// the owner references were set, or when their owner reference was removed
+// or is to a Gateway since deleted and created again under the same name.
+//
+// The resources provisioned are the Services and EndpointSlices exposing the
+// shared proxy; see provisionService. There are no per-Gateway Deployments to
+// sweep.
+type OrphanSweeper struct {
+	client.Client
+	Period time.Duration
+}
+
+// Start runs the sweep loop until ctx is cancelled.
+func (s *OrphanSweeper) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("orphan-sweep")
+	ticker := time.NewTicker(s.Period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := s.sweep(ctx); err != nil {
+			l.Error(err, "orphan sweep failed")
+		}
+	}
+}
+
+func (s *OrphanSweeper) sweep(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("orphan-sweep")
+
+	var gateways gatewayv1.GatewayList
+	if err := s.List(ctx, &gateways); err != nil {
+		return err
+	}
+	uids := make(map[types.NamespacedName]types.UID, len(gateways.Items))
+	for _, gw := range gateways.Items {
+		uids[client.ObjectKeyFromObject(&gw)] = gw.UID
+	}
+
+	var services corev1.ServiceList
+	if err := s.List(ctx, &services, client.MatchingLabels{managedByLabel: FieldManager}, client.HasLabels{gatewayNameLabel}); err != nil {
+		return err
+	}
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := s.List(ctx, &endpointSlices, client.MatchingLabels{discoveryv1.LabelManagedBy: FieldManager}, client.HasLabels{gatewayNameLabel}); err != nil {
+		return err
+	}
+	candidates := map[string][]client.Object{}
+	for i := range services.Items {
+		candidates["Service"] = append(candidates["Service"], &services.Items[i])
+	}
+	for i := range endpointSlices.Items {
+		candidates["EndpointSlice"] = append(candidates["EndpointSlice"], &endpointSlices.Items[i])
+	}
+
+	for kind, objs := range candidates {
+		for _, obj := range objs {
+			if !orphaned(obj, uids) {
+				continue
+			}
+			orphansFound.WithLabelValues(kind).Inc()
+			// The precondition keeps a resource created again in the
+			// meantime from being deleted in place of the orphan.
+			if err := s.Delete(ctx, obj, client.Preconditions{UID: ptr(obj.GetUID())}); client.IgnoreNotFound(err) != nil && !apierrors.IsConflict(err) {
+				return err
+			}
+			l.Info("Deleted orphaned resource", "kind", kind, "resource", client.ObjectKeyFromObject(obj))
+		}
+	}
+	return nil
+}
+
+// orphaned reports whether obj, labelled with the name of the Gateway it was
+// provisioned for, has lost its Gateway. uids holds the UID of every Gateway.
+func orphaned(obj client.Object, uids map[types.NamespacedName]types.UID) bool {
+	uid, ok := uids[types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetLabels()[gatewayNameLabel]}]
+	if !ok {
+		return true
+	}
+	owner := metav1.GetControllerOf(obj)
+	return owner != nil && owner.Kind == string(kindGateway) && owner.UID != uid
+}
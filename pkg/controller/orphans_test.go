@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestOrphanSweep(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	meta := func(name, gateway string, owner types.UID, labels map[string]string) metav1.ObjectMeta {
+		m := metav1.ObjectMeta{Namespace: "apps", Name: name, UID: types.UID(name), Labels: map[string]string{gatewayNameLabel: gateway}}
+		for k, v := range labels {
+			m.Labels[k] = v
+		}
+		if owner != "" {
+			m.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: gatewayv1.GroupVersion.String(),
+				Kind:       string(kindGateway),
+				Name:       gateway,
+				UID:        owner,
+				Controller: ptr(true),
+			}}
+		}
+		return m
+	}
+	service := map[string]string{managedByLabel: FieldManager}
+	slice := map[string]string{discoveryv1.LabelManagedBy: FieldManager}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "live", UID: "live-uid"}},
+		&corev1.Service{ObjectMeta: meta("live-ours", "live", "live-uid", service)},
+		&discoveryv1.EndpointSlice{ObjectMeta: meta("live-ours", "live", "live-uid", slice), AddressType: discoveryv1.AddressTypeIPv4},
+		// Provisioned for a Gateway since deleted and created again.
+		&discoveryv1.EndpointSlice{ObjectMeta: meta("live-previous", "live", "previous-uid", slice), AddressType: discoveryv1.AddressTypeIPv4},
+		// Provisioned before owner references were set.
+		&corev1.Service{ObjectMeta: meta("gone-ours", "gone", "", service)},
+		// Labelled by a user to expose the Gateway.
+		&corev1.Service{ObjectMeta: meta("gone-user", "gone", "", nil)},
+	).Build()
+
+	s := &OrphanSweeper{Client: c}
+	if err := s.sweep(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		obj     client.Object
+		deleted bool
+	}{
+		{obj: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "live-ours"}}},
+		{obj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "live-ours"}}},
+		{obj: &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "live-previous"}}, deleted: true},
+		{obj: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "gone-ours"}}, deleted: true},
+		{obj: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "gone-user"}}},
+	}
+	for _, tt := range tests {
+		err := c.Get(ctx, client.ObjectKeyFromObject(tt.obj), tt.obj)
+		if deleted := apierrors.IsNotFound(err); deleted != tt.deleted {
+			t.Errorf("%T %s: expected deleted=%v, got %v", tt.obj, tt.obj.GetName(), tt.deleted, err)
+		}
+	}
+	for kind, expected := range map[string]float64{"Service": 1, "EndpointSlice": 1} {
+		if actual := testutil.ToFloat64(orphansFound.WithLabelValues(kind)); actual != expected {
+			t.Errorf("expected %v orphaned %s found, got %v", expected, kind, actual)
+		}
+	}
+}
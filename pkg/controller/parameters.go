@@ -0,0 +1,330 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// kindGatewayClassConfig is the kind of the typed parameters of a
+// GatewayClass.
+const kindGatewayClassConfig gatewayv1.Kind = "GatewayClassConfig"
+
+// Keys of the ConfigMap referenced by the parametersRef of a GatewayClass.
+const (
+	parameterLogLevel          = "logLevel"
+	parameterRequestTimeout    = "requestTimeout"
+	parameterLoadBalancing     = "loadBalancing"
+	parameterDataPlaneImage    = "dataPlaneImage"
+	parameterDataPlaneMode     = "dataPlaneMode"
+	parameterDataPlaneExposure = "dataPlaneExposure"
+	parameterServiceType       = "serviceType"
+
+	parameterMaxRoutesPerGateway = "maxRoutesPerGateway"
+	parameterMaxRulesPerRoute    = "maxRulesPerRoute"
+	parameterMaxRegexMatchers    = "maxRegexMatchers"
+)
+
+var (
+	logLevels             = []string{"error", "info", "debug"}
+	loadBalancingPolicies = []string{"RoundRobin", "LeastRequest", "Random"}
+	dataPlaneModes        = []string{string(v1alpha1.DataPlaneModeShared), string(v1alpha1.DataPlaneModeDaemonSet)}
+	dataPlaneExposures    = []string{string(v1alpha1.DataPlaneExposureHostPort), string(v1alpha1.DataPlaneExposureHostNetwork)}
+)
+
+// classParameters is the implementation configuration of a GatewayClass.
+// RequestTimeout applies to every route attached to a Gateway of the class
+// that does not set its own timeout, and Limits to every route attached to
+// them. DataPlaneMode, DataPlaneImage and DataPlaneExposure select the
+// proxies provisioned for the class; see provisionDataPlane. ServiceType
+// selects how its Gateways are exposed; see serviceAddresses. LogLevel and
+// LoadBalancing are validated but not yet used by the proxies.
+type classParameters struct {
+	LogLevel          string
+	RequestTimeout    time.Duration
+	LoadBalancing     string
+	DataPlaneImage    string
+	DataPlaneMode     v1alpha1.DataPlaneMode
+	DataPlaneExposure v1alpha1.DataPlaneExposure
+	ServiceType       corev1.ServiceType
+	Limits            ir.Limits
+}
+
+// invalidParametersError describes why the parametersRef of a GatewayClass
+// cannot be used. It is reported in the class's Accepted condition.
+type invalidParametersError struct {
+	message string
+}
+
+func (e *invalidParametersError) Error() string {
+	return e.message
+}
+
+func invalidParameters(format string, args ...any) *invalidParametersError {
+	return &invalidParametersError{message: fmt.Sprintf(format, args...)}
+}
+
+// resolveClassParameters returns the parameters referenced by a GatewayClass,
+// or nil if it has no parametersRef. The parameters are read from a ConfigMap
+// or a GatewayClassConfig; a missing or malformed object is reported as an
+// *invalidParametersError.
+func resolveClassParameters(ctx context.Context, c client.Reader, gc *gatewayv1.GatewayClass) (*classParameters, error) {
+	ref := gc.Spec.ParametersRef
+	if ref == nil {
+		return nil, nil
+	}
+
+	if isGatewayClassConfigRef(ref) {
+		if ref.Namespace != nil {
+			return nil, invalidParameters("parametersRef to a GatewayClassConfig must not set a namespace")
+		}
+		var config v1alpha1.GatewayClassConfig
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, &config); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, invalidParameters("GatewayClassConfig %s not found", ref.Name)
+			}
+			return nil, err
+		}
+		params, err := configParameters(&config.Spec)
+		if err != nil {
+			return nil, invalidParameters("GatewayClassConfig %s: %v", ref.Name, err)
+		}
+		return params, nil
+	}
+
+	key, ok := parametersConfigMap(ref)
+	if !ok {
+		return nil, invalidParameters("parametersRef must refer to a GatewayClassConfig or to a ConfigMap with a namespace, got %s %s", ref.Kind, ref.Name)
+	}
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, key, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, invalidParameters("ConfigMap %s not found", key)
+		}
+		return nil, err
+	}
+	params, err := parseClassParameters(cm.Data)
+	if err != nil {
+		return nil, invalidParameters("ConfigMap %s: %v", key, err)
+	}
+	return params, nil
+}
+
+// isGatewayClassConfigRef reports whether a parametersRef refers to a
+// GatewayClassConfig.
+func isGatewayClassConfigRef(ref *gatewayv1.ParametersReference) bool {
+	return string(ref.Group) == v1alpha1.GroupVersion.Group && ref.Kind == kindGatewayClassConfig
+}
+
+// parametersConfigMap returns the ConfigMap referenced by a parametersRef.
+func parametersConfigMap(ref *gatewayv1.ParametersReference) (types.NamespacedName, bool) {
+	if ref.Group != "" || ref.Kind != "ConfigMap" || ref.Namespace == nil {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: string(*ref.Namespace), Name: ref.Name}, true
+}
+
+// referencesParameters reports whether the parametersRef of a GatewayClass
+// refers to obj, which is a ConfigMap or a GatewayClassConfig.
+func referencesParameters(gc *gatewayv1.GatewayClass, obj client.Object) bool {
+	ref := gc.Spec.ParametersRef
+	if ref == nil {
+		return false
+	}
+	switch obj.(type) {
+	case *corev1.ConfigMap:
+		key, ok := parametersConfigMap(ref)
+		return ok && key == client.ObjectKeyFromObject(obj)
+	case *v1alpha1.GatewayClassConfig:
+		return isGatewayClassConfigRef(ref) && ref.Name == obj.GetName()
+	}
+	return false
+}
+
+// configParameters converts the spec of a GatewayClassConfig. The API server
+// has already validated it, except for the range of the timeout.
+func configParameters(spec *v1alpha1.GatewayClassConfigSpec) (*classParameters, error) {
+	params := &classParameters{}
+	if spec.LogLevel != nil {
+		params.LogLevel = string(*spec.LogLevel)
+	}
+	if spec.RequestTimeout != nil {
+		timeout, err := time.ParseDuration(string(*spec.RequestTimeout))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("requestTimeout must be a positive duration, got %q", *spec.RequestTimeout)
+		}
+		params.RequestTimeout = timeout
+	}
+	if spec.LoadBalancing != nil {
+		params.LoadBalancing = string(*spec.LoadBalancing)
+	}
+	if spec.DataPlaneImage != nil {
+		params.DataPlaneImage = *spec.DataPlaneImage
+	}
+	if spec.DataPlaneMode != nil {
+		params.DataPlaneMode = *spec.DataPlaneMode
+	}
+	if spec.DataPlaneExposure != nil {
+		params.DataPlaneExposure = *spec.DataPlaneExposure
+	}
+	if spec.ServiceType != nil {
+		params.ServiceType = *spec.ServiceType
+	}
+	if limits := spec.Limits; limits != nil {
+		if limits.MaxRoutesPerGateway != nil {
+			params.Limits.MaxRoutesPerGateway = int(*limits.MaxRoutesPerGateway)
+		}
+		if limits.MaxRulesPerRoute != nil {
+			params.Limits.MaxRulesPerRoute = int(*limits.MaxRulesPerRoute)
+		}
+		if limits.MaxRegexMatchers != nil {
+			params.Limits.MaxRegexMatchers = ptr(int(*limits.MaxRegexMatchers))
+		}
+	}
+	return params, nil
+}
+
+// parseClassParameters validates the data of a parameters ConfigMap. Unknown
+// keys are rejected so that typos do not go unnoticed.
+func parseClassParameters(data map[string]string) (*classParameters, error) {
+	params := &classParameters{}
+	for key, value := range data {
+		switch key {
+		case parameterLogLevel:
+			if !slices.Contains(logLevels, value) {
+				return nil, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(logLevels, ", "), value)
+			}
+			params.LogLevel = value
+		case parameterRequestTimeout:
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("%s must be a positive duration, got %q", key, value)
+			}
+			params.RequestTimeout = timeout
+		case parameterLoadBalancing:
+			if !slices.Contains(loadBalancingPolicies, value) {
+				return nil, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(loadBalancingPolicies, ", "), value)
+			}
+			params.LoadBalancing = value
+		case parameterDataPlaneImage:
+			if value == "" || strings.ContainsAny(value, " \t\n") {
+				return nil, fmt.Errorf("%s must be an image reference, got %q", key, value)
+			}
+			params.DataPlaneImage = value
+		case parameterDataPlaneMode:
+			if !slices.Contains(dataPlaneModes, value) {
+				return nil, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(dataPlaneModes, ", "), value)
+			}
+			params.DataPlaneMode = v1alpha1.DataPlaneMode(value)
+		case parameterDataPlaneExposure:
+			if !slices.Contains(dataPlaneExposures, value) {
+				return nil, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(dataPlaneExposures, ", "), value)
+			}
+			params.DataPlaneExposure = v1alpha1.DataPlaneExposure(value)
+		case parameterServiceType:
+			if !slices.Contains(serviceTypes, corev1.ServiceType(value)) {
+				return nil, fmt.Errorf("%s must be one of ClusterIP, NodePort, LoadBalancer, got %q", key, value)
+			}
+			params.ServiceType = corev1.ServiceType(value)
+		case parameterMaxRoutesPerGateway, parameterMaxRulesPerRoute:
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit <= 0 {
+				return nil, fmt.Errorf("%s must be a positive integer, got %q", key, value)
+			}
+			if key == parameterMaxRoutesPerGateway {
+				params.Limits.MaxRoutesPerGateway = limit
+			} else {
+				params.Limits.MaxRulesPerRoute = limit
+			}
+		case parameterMaxRegexMatchers:
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				return nil, fmt.Errorf("%s must be a non-negative integer, got %q", key, value)
+			}
+			params.Limits.MaxRegexMatchers = &limit
+		default:
+			return nil, fmt.Errorf("unknown parameter %q", key)
+		}
+	}
+	return params, nil
+}
+
+// requestTimeoutsForGateways returns the default request timeout of each of
+// gateways whose class is managed by this controller and sets one. Classes
+// with invalid parameters are skipped.
+func requestTimeoutsForGateways(ctx context.Context, c client.Reader, controllerName gatewayv1.GatewayController, gateways []gatewayv1.Gateway) (map[types.NamespacedName]time.Duration, error) {
+	byClass := map[gatewayv1.ObjectName]time.Duration{}
+	resolved := map[gatewayv1.ObjectName]bool{}
+	timeouts := map[types.NamespacedName]time.Duration{}
+	for i := range gateways {
+		key := client.ObjectKeyFromObject(&gateways[i])
+		className := gateways[i].Spec.GatewayClassName
+		if !resolved[className] {
+			resolved[className] = true
+			timeout, err := classRequestTimeout(ctx, c, controllerName, className)
+			if err != nil {
+				return nil, err
+			}
+			if timeout > 0 {
+				byClass[className] = timeout
+			}
+		}
+		if timeout, ok := byClass[className]; ok {
+			timeouts[key] = timeout
+		}
+	}
+	return timeouts, nil
+}
+
+// classRequestTimeout returns the default request timeout set by the
+// parameters of the GatewayClass with the given name, or zero if it sets
+// none, is not managed by this controller, or has invalid parameters.
+func classRequestTimeout(ctx context.Context, c client.Reader, controllerName gatewayv1.GatewayController, name gatewayv1.ObjectName) (time.Duration, error) {
+	var gc gatewayv1.GatewayClass
+	if err := c.Get(ctx, client.ObjectKey{Name: string(name)}, &gc); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	if gc.Spec.ControllerName != controllerName {
+		return 0, nil
+	}
+	params, err := resolveClassParameters(ctx, c, &gc)
+	if err != nil {
+		if invalid := (*invalidParametersError)(nil); !errors.As(err, &invalid) {
+			return 0, err
+		}
+		log.FromContext(ctx).Info("skipping invalid GatewayClass parameters", "gatewayclass", gc.Name, "error", err.Error())
+		return 0, nil
+	}
+	if params == nil {
+		return 0, nil
+	}
+	return params.RequestTimeout, nil
+}
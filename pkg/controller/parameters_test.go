@@ -0,0 +1,279 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestParseClassParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]string
+		expected *classParameters
+	}{
+		{
+			name:     "empty",
+			data:     nil,
+			expected: &classParameters{},
+		},
+		{
+			name: "all parameters",
+			data: map[string]string{
+				"logLevel":          "debug",
+				"requestTimeout":    "30s",
+				"loadBalancing":     "LeastRequest",
+				"dataPlaneImage":    "example.com/proxy:v1",
+				"dataPlaneMode":     "DaemonSet",
+				"dataPlaneExposure": "HostNetwork",
+				"serviceType":       "NodePort",
+			},
+			expected: &classParameters{
+				LogLevel:          "debug",
+				RequestTimeout:    30 * time.Second,
+				LoadBalancing:     "LeastRequest",
+				DataPlaneImage:    "example.com/proxy:v1",
+				DataPlaneMode:     v1alpha1.DataPlaneModeDaemonSet,
+				DataPlaneExposure: v1alpha1.DataPlaneExposureHostNetwork,
+				ServiceType:       corev1.ServiceTypeNodePort,
+			},
+		},
+		{
+			name: "limits",
+			data: map[string]string{
+				"maxRoutesPerGateway": "100",
+				"maxRulesPerRoute":    "10",
+				"maxRegexMatchers":    "0",
+			},
+			expected: &classParameters{
+				Limits: ir.Limits{MaxRoutesPerGateway: 100, MaxRulesPerRoute: 10, MaxRegexMatchers: ptr(0)},
+			},
+		},
+		{
+			name: "zero route limit",
+			data: map[string]string{"maxRoutesPerGateway": "0"},
+		},
+		{
+			name: "malformed regex limit",
+			data: map[string]string{"maxRegexMatchers": "many"},
+		},
+		{
+			name: "unknown log level",
+			data: map[string]string{"logLevel": "verbose"},
+		},
+		{
+			name: "negative timeout",
+			data: map[string]string{"requestTimeout": "-1s"},
+		},
+		{
+			name: "malformed timeout",
+			data: map[string]string{"requestTimeout": "thirty"},
+		},
+		{
+			name: "unknown load balancing",
+			data: map[string]string{"loadBalancing": "Sticky"},
+		},
+		{
+			name: "malformed image",
+			data: map[string]string{"dataPlaneImage": "example.com/proxy v1"},
+		},
+		{
+			name: "unknown data plane mode",
+			data: map[string]string{"dataPlaneMode": "Deployment"},
+		},
+		{
+			name: "unknown service type",
+			data: map[string]string{"serviceType": "ExternalName"},
+		},
+		{
+			name: "unknown data plane exposure",
+			data: map[string]string{"dataPlaneExposure": "NodePort"},
+		},
+		{
+			name: "unknown key",
+			data: map[string]string{"requestTimout": "30s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseClassParameters(tt.data)
+			if tt.expected == nil {
+				if err == nil {
+					t.Errorf("expected error, got %v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolveClassParameters(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "valid"},
+			Data:       map[string]string{"requestTimeout": "5s"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "invalid"},
+			Data:       map[string]string{"requestTimeout": "soon"},
+		},
+	).Build()
+
+	configMapRef := func(name string) *gatewayv1.ParametersReference {
+		return &gatewayv1.ParametersReference{Kind: "ConfigMap", Name: name, Namespace: ptr(gatewayv1.Namespace("infra"))}
+	}
+	tests := []struct {
+		name            string
+		ref             *gatewayv1.ParametersReference
+		expected        *classParameters
+		expectedInvalid bool
+	}{
+		{
+			name: "no parametersRef",
+		},
+		{
+			name:     "valid ConfigMap",
+			ref:      configMapRef("valid"),
+			expected: &classParameters{RequestTimeout: 5 * time.Second},
+		},
+		{
+			name:            "invalid ConfigMap",
+			ref:             configMapRef("invalid"),
+			expectedInvalid: true,
+		},
+		{
+			name:            "missing ConfigMap",
+			ref:             configMapRef("missing"),
+			expectedInvalid: true,
+		},
+		{
+			name:            "missing namespace",
+			ref:             &gatewayv1.ParametersReference{Kind: "ConfigMap", Name: "valid"},
+			expectedInvalid: true,
+		},
+		{
+			name:            "unsupported kind",
+			ref:             &gatewayv1.ParametersReference{Group: "example.com", Kind: "Config", Name: "valid"},
+			expectedInvalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc := &gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+				Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName, ParametersRef: tt.ref},
+			}
+			actual, err := resolveClassParameters(context.Background(), c, gc)
+			var invalid *invalidParametersError
+			if isInvalid := errors.As(err, &invalid); isInvalid != tt.expectedInvalid {
+				t.Fatalf("expected invalid parameters %v, got error %v", tt.expectedInvalid, err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolveGatewayClassConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.GatewayClassConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "config"},
+			Spec: v1alpha1.GatewayClassConfigSpec{
+				LogLevel:       ptr(v1alpha1.LogLevelDebug),
+				RequestTimeout: ptr(gatewayv1.Duration("2m")),
+				LoadBalancing:  ptr(v1alpha1.LoadBalancingRandom),
+			},
+		},
+	).Build()
+
+	configRef := func(name string, namespace *gatewayv1.Namespace) *gatewayv1.ParametersReference {
+		return &gatewayv1.ParametersReference{Group: "gari.gke-labs.dev", Kind: "GatewayClassConfig", Name: name, Namespace: namespace}
+	}
+	tests := []struct {
+		name            string
+		ref             *gatewayv1.ParametersReference
+		expected        *classParameters
+		expectedInvalid bool
+	}{
+		{
+			name:     "existing config",
+			ref:      configRef("config", nil),
+			expected: &classParameters{LogLevel: "debug", RequestTimeout: 2 * time.Minute, LoadBalancing: "Random"},
+		},
+		{
+			name:            "missing config",
+			ref:             configRef("missing", nil),
+			expectedInvalid: true,
+		},
+		{
+			name:            "namespaced reference",
+			ref:             configRef("config", ptr(gatewayv1.Namespace("infra"))),
+			expectedInvalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc := &gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+				Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName, ParametersRef: tt.ref},
+			}
+			actual, err := resolveClassParameters(context.Background(), c, gc)
+			var invalid *invalidParametersError
+			if isInvalid := errors.As(err, &invalid); isInvalid != tt.expectedInvalid {
+				t.Fatalf("expected invalid parameters %v, got error %v", tt.expectedInvalid, err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+			if tt.ref != nil && !tt.expectedInvalid && !referencesParameters(gc, &v1alpha1.GatewayClassConfig{ObjectMeta: metav1.ObjectMeta{Name: tt.ref.Name}}) {
+				t.Errorf("expected GatewayClass to reference GatewayClassConfig %s", tt.ref.Name)
+			}
+		})
+	}
+}
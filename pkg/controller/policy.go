@@ -0,0 +1,177 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/conditions"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	kindGateway   gatewayv1.Kind = "Gateway"
+	kindHTTPRoute gatewayv1.Kind = "HTTPRoute"
+)
+
+// policyAcceptance is the outcome of validating a policy, independent of its
+// targets.
+type policyAcceptance struct {
+	status  metav1.ConditionStatus
+	reason  gatewayv1.PolicyConditionReason
+	message string
+}
+
+var policyAccepted = policyAcceptance{
+	status:  metav1.ConditionTrue,
+	reason:  gatewayv1.PolicyReasonAccepted,
+	message: "Policy accepted by reference implementation",
+}
+
+// policyAncestorStatuses computes the ancestor statuses for a policy. Entries
+// written by other controllers are preserved. For each target, the policy is
+// reported against the target's ancestors: a Gateway is its own ancestor, and
+// an HTTPRoute reports against each of its parent Gateways.
+func policyAncestorStatuses(ctx context.Context, c client.Client, controllerName gatewayv1.GatewayController, policy client.Object, targetRefs []gatewayv1.LocalPolicyTargetReferenceWithSectionName, supportedKinds []gatewayv1.Kind, existing []gatewayv1.PolicyAncestorStatus, accepted policyAcceptance) ([]gatewayv1.PolicyAncestorStatus, error) {
+	var ancestors []gatewayv1.PolicyAncestorStatus
+	for _, ancestor := range existing {
+		if ancestor.ControllerName != controllerName {
+			ancestors = append(ancestors, ancestor)
+		}
+	}
+
+	for _, targetRef := range targetRefs {
+		result := accepted
+		var ancestorRefs []gatewayv1.ParentReference
+
+		key := types.NamespacedName{Namespace: policy.GetNamespace(), Name: string(targetRef.Name)}
+		switch {
+		case targetRef.Group != gatewayv1.GroupName || !slices.Contains(supportedKinds, targetRef.Kind):
+			result = policyAcceptance{
+				status:  metav1.ConditionFalse,
+				reason:  gatewayv1.PolicyReasonInvalid,
+				message: fmt.Sprintf("Unsupported target kind %s/%s", targetRef.Group, targetRef.Kind),
+			}
+		case targetRef.SectionName != nil:
+			result = policyAcceptance{
+				status:  metav1.ConditionFalse,
+				reason:  gatewayv1.PolicyReasonInvalid,
+				message: "sectionName is not supported in policy targets",
+			}
+		case targetRef.Kind == kindGateway:
+			var gw gatewayv1.Gateway
+			if err := c.Get(ctx, key, &gw); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, err
+				}
+				result = targetNotFound(targetRef)
+				break
+			}
+			ancestorRefs = append(ancestorRefs, gatewayv1.ParentReference{
+				Group:     ptr(gatewayv1.Group(gatewayv1.GroupName)),
+				Kind:      ptr(kindGateway),
+				Namespace: ptr(gatewayv1.Namespace(gw.Namespace)),
+				Name:      gatewayv1.ObjectName(gw.Name),
+			})
+		case targetRef.Kind == kindHTTPRoute:
+			var route gatewayv1.HTTPRoute
+			if err := c.Get(ctx, key, &route); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, err
+				}
+				result = targetNotFound(targetRef)
+				break
+			}
+			for _, parentRef := range route.Spec.ParentRefs {
+				if parentRef.Namespace == nil {
+					parentRef.Namespace = ptr(gatewayv1.Namespace(route.Namespace))
+				}
+				ancestorRefs = append(ancestorRefs, parentRef)
+			}
+		}
+
+		// Without a resolvable ancestor, report against the target itself so
+		// that the condition is still visible to users.
+		if len(ancestorRefs) == 0 {
+			ancestorRefs = append(ancestorRefs, gatewayv1.ParentReference{
+				Group:     ptr(targetRef.Group),
+				Kind:      ptr(targetRef.Kind),
+				Namespace: ptr(gatewayv1.Namespace(policy.GetNamespace())),
+				Name:      targetRef.Name,
+			})
+		}
+
+		for _, ancestorRef := range ancestorRefs {
+			var previous []metav1.Condition
+			for _, ancestor := range existing {
+				if ancestor.ControllerName == controllerName && equality.Semantic.DeepEqual(ancestor.AncestorRef, ancestorRef) {
+					previous = ancestor.Conditions
+				}
+			}
+			ancestorConditions, _ := conditions.Merge(previous, policy.GetGeneration(), metav1.Condition{
+				Type:    string(gatewayv1.PolicyConditionAccepted),
+				Status:  result.status,
+				Reason:  string(result.reason),
+				Message: result.message,
+			})
+			ancestors = append(ancestors, gatewayv1.PolicyAncestorStatus{
+				AncestorRef:    ancestorRef,
+				ControllerName: controllerName,
+				Conditions:     ancestorConditions,
+			})
+		}
+	}
+	return ancestors, nil
+}
+
+func targetNotFound(targetRef gatewayv1.LocalPolicyTargetReferenceWithSectionName) policyAcceptance {
+	return policyAcceptance{
+		status:  metav1.ConditionFalse,
+		reason:  gatewayv1.PolicyReasonTargetNotFound,
+		message: fmt.Sprintf("%s %s not found", targetRef.Kind, targetRef.Name),
+	}
+}
+
+// isPolicyTarget reports whether targetRef refers to a whole object of the
+// given Gateway API kind.
+func isPolicyTarget(targetRef gatewayv1.LocalPolicyTargetReferenceWithSectionName, kind gatewayv1.Kind) bool {
+	return targetRef.Group == gatewayv1.GroupName && targetRef.Kind == kind && targetRef.SectionName == nil
+}
+
+// sortPoliciesByAge orders policies oldest first, breaking ties by namespaced
+// name, which is the precedence used when several policies target the same
+// object.
+func sortPoliciesByAge[T any, PT interface {
+	*T
+	client.Object
+}](policies []T) {
+	sort.Slice(policies, func(i, j int) bool {
+		a, b := PT(&policies[i]), PT(&policies[j])
+		ta, tb := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+		if !ta.Equal(&tb) {
+			return ta.Before(&tb)
+		}
+		return client.ObjectKeyFromObject(a).String() < client.ObjectKeyFromObject(b).String()
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestSpecChanged(t *testing.T) {
+	old := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "web", Generation: 1}}
+
+	statusOnly := old.DeepCopy()
+	statusOnly.Status.Parents = []gatewayv1.RouteParentStatus{{ControllerName: DefaultControllerName}}
+
+	specChange := old.DeepCopy()
+	specChange.Generation = 2
+
+	annotationChange := old.DeepCopy()
+	annotationChange.Annotations = map[string]string{"example.com/note": "changed"}
+
+	tests := []struct {
+		name     string
+		new      *gatewayv1.HTTPRoute
+		expected bool
+	}{
+		{name: "status only", new: statusOnly, expected: false},
+		{name: "spec change", new: specChange, expected: true},
+		{name: "annotation change", new: annotationChange, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := specChanged.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: tt.new}); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
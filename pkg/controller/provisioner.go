@@ -0,0 +1,201 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// The proxy pods are shared by every Gateway and run in the namespace of the
+// ProxyService, where a Service in the Gateway's namespace cannot select
+// them. Each provisioned Service therefore has no selector and is backed by
+// an EndpointSlice mirroring the endpoints of the ProxyService. Both are
+// owned by the Gateway, so they are garbage collected with it.
+
+// managedByLabel is set on the Services provisioned for Gateways, as
+// discoveryv1.LabelManagedBy is on their EndpointSlices, so that orphans can
+// be told from the Services labelled with a Gateway's name by users.
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// provisionedName returns the name of the resources provisioned for a
+// Gateway: "<gateway>-<gatewayclass>", shortened with a hash to fit in a DNS
+// label if necessary.
+func provisionedName(gw *gatewayv1.Gateway) string {
+	return dnsLabel(fmt.Sprintf("%s-%s", gw.Name, gw.Spec.GatewayClassName))
+}
+
+// dnsLabel returns name, shortened with a hash if it does not fit in a DNS
+// label.
+func dnsLabel(name string) string {
+	if len(name) <= 63 {
+		return name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("%s-%08x", name[:54], h.Sum32())
+}
+
+// provisionService creates or updates the Service and EndpointSlice exposing
+// a Gateway, reverting any drift in the fields we set. The Service is of the
+// type set by the Gateway's parameters, or else by its class's, or else a
+// LoadBalancer.
+func (r *GatewayReconciler) provisionService(ctx context.Context, gw *gatewayv1.Gateway, params *infrastructureParameters, classParams *classParameters) (*corev1.Service, error) {
+	name := provisionedName(gw)
+	labels := map[string]string{}
+	annotations := map[string]string{}
+	if infra := gw.Spec.Infrastructure; infra != nil {
+		for k, v := range infra.Labels {
+			labels[string(k)] = string(v)
+		}
+		for k, v := range infra.Annotations {
+			annotations[string(k)] = string(v)
+		}
+	}
+	labels[gatewayNameLabel] = gw.Name
+	labels[managedByLabel] = FieldManager
+
+	serviceType := corev1.ServiceTypeLoadBalancer
+	if params != nil && params.ServiceType != "" {
+		serviceType = params.ServiceType
+	} else if classParams != nil && classParams.ServiceType != "" {
+		serviceType = classParams.ServiceType
+	}
+
+	var servicePorts []corev1.ServicePort
+	for _, port := range listenerPorts(gw) {
+		servicePorts = append(servicePorts, corev1.ServicePort{
+			Name:     fmt.Sprintf("port-%d", port),
+			Protocol: corev1.ProtocolTCP,
+			Port:     port,
+		})
+	}
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: gw.Namespace, Name: name}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		svc.Labels = mergeStrings(svc.Labels, labels)
+		svc.Annotations = mergeStrings(svc.Annotations, annotations)
+		if svc.Spec.Type != serviceType {
+			// Node ports are allocated again by the API server when needed.
+			svc.Spec.Ports = nil
+		}
+		svc.Spec.Type = serviceType
+		svc.Spec.Selector = nil
+		svc.Spec.Ports = mergeServicePorts(svc.Spec.Ports, servicePorts)
+		return controllerutil.SetControllerReference(gw, svc, r.Scheme)
+	}); err != nil {
+		return nil, err
+	}
+
+	endpoints, targetPort, err := r.proxyEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var endpointPorts []discoveryv1.EndpointPort
+	for _, port := range servicePorts {
+		endpointPorts = append(endpointPorts, discoveryv1.EndpointPort{
+			Name:     ptr(port.Name),
+			Protocol: ptr(corev1.ProtocolTCP),
+			Port:     targetPort,
+		})
+	}
+	slice := &discoveryv1.EndpointSlice{ObjectMeta: metav1.ObjectMeta{Namespace: gw.Namespace, Name: name}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, slice, func() error {
+		slice.Labels = mergeStrings(slice.Labels, map[string]string{
+			discoveryv1.LabelServiceName: name,
+			discoveryv1.LabelManagedBy:   FieldManager,
+			gatewayNameLabel:             gw.Name,
+		})
+		slice.AddressType = discoveryv1.AddressTypeIPv4
+		slice.Endpoints = endpoints
+		slice.Ports = endpointPorts
+		return controllerutil.SetControllerReference(gw, slice, r.Scheme)
+	}); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// proxyEndpoints returns the IPv4 endpoints of the ProxyService and the port
+// the proxy listens on, which is nil while no endpoint is known.
+func (r *GatewayReconciler) proxyEndpoints(ctx context.Context) ([]discoveryv1.Endpoint, *int32, error) {
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := r.List(ctx, &endpointSlices, client.InNamespace(r.ProxyService.Namespace), client.MatchingLabels{discoveryv1.LabelServiceName: r.ProxyService.Name}); err != nil {
+		return nil, nil, err
+	}
+	var endpoints []discoveryv1.Endpoint
+	var port *int32
+	for _, slice := range endpointSlices.Items {
+		if slice.AddressType != discoveryv1.AddressTypeIPv4 {
+			continue
+		}
+		if port == nil && len(slice.Ports) > 0 && slice.Ports[0].Port != nil {
+			port = ptr(*slice.Ports[0].Port)
+		}
+		for _, endpoint := range slice.Endpoints {
+			endpoints = append(endpoints, discoveryv1.Endpoint{
+				Addresses:  endpoint.Addresses,
+				Conditions: endpoint.Conditions,
+				NodeName:   endpoint.NodeName,
+				Zone:       endpoint.Zone,
+			})
+		}
+	}
+	return endpoints, port, nil
+}
+
+// listenerPorts returns the distinct ports of a Gateway's listeners, in order.
+func listenerPorts(gw *gatewayv1.Gateway) []int32 {
+	var ports []int32
+	for _, listener := range gw.Spec.Listeners {
+		ports = append(ports, int32(listener.Port))
+	}
+	slices.Sort(ports)
+	return slices.Compact(ports)
+}
+
+// mergeServicePorts returns desired, keeping the node ports already allocated
+// to existing ports so that updates do not reallocate them.
+func mergeServicePorts(existing, desired []corev1.ServicePort) []corev1.ServicePort {
+	for i := range desired {
+		for _, port := range existing {
+			if port.Name == desired[i].Name && port.Port == desired[i].Port {
+				desired[i].NodePort = port.NodePort
+			}
+		}
+	}
+	return desired
+}
+
+// mergeStrings returns existing with the entries of desired set, keeping
+// entries added by others.
+func mergeStrings(existing, desired map[string]string) map[string]string {
+	if existing == nil {
+		existing = map[string]string{}
+	}
+	maps.Copy(existing, desired)
+	return existing
+}
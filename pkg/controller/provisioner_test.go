@@ -0,0 +1,153 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestProvisionService(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gw := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "gw", UID: "gw-uid"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "ours",
+			Listeners: []gatewayv1.Listener{
+				{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+				{Name: "other", Port: 8080, Protocol: gatewayv1.HTTPProtocolType},
+				{Name: "http-2", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			},
+			Infrastructure: &gatewayv1.GatewayInfrastructure{
+				Labels: map[gatewayv1.LabelKey]gatewayv1.LabelValue{"team": "edge"},
+			},
+		},
+	}
+	proxySlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "gari-system",
+			Name:      "proxy-abcde",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "proxy"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.7"}}},
+		Ports:       []discoveryv1.EndpointPort{{Port: ptr(int32(8000))}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw, proxySlice).Build()
+	r := &GatewayReconciler{
+		Client:            c,
+		Scheme:            scheme,
+		ProxyService:      types.NamespacedName{Namespace: "gari-system", Name: "proxy"},
+		ProvisionServices: true,
+	}
+
+	params := &infrastructureParameters{ServiceType: corev1.ServiceTypeNodePort}
+	if _, err := r.provisionService(ctx, gw, params, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	key := types.NamespacedName{Namespace: "apps", Name: "gw-ours"}
+	var svc corev1.Service
+	if err := c.Get(ctx, key, &svc); err != nil {
+		t.Fatalf("unable to get Service: %v", err)
+	}
+	if svc.Spec.Type != corev1.ServiceTypeNodePort {
+		t.Errorf("expected Service type NodePort, got %v", svc.Spec.Type)
+	}
+	var ports []int32
+	for _, port := range svc.Spec.Ports {
+		ports = append(ports, port.Port)
+	}
+	if !reflect.DeepEqual(ports, []int32{80, 8080}) {
+		t.Errorf("expected ports [80 8080], got %v", ports)
+	}
+	if svc.Labels["team"] != "edge" || svc.Labels[gatewayNameLabel] != "gw" || svc.Labels[managedByLabel] != FieldManager {
+		t.Errorf("expected infrastructure, Gateway and managed-by labels, got %v", svc.Labels)
+	}
+	if !isOwnedBy(&svc, gw) {
+		t.Errorf("expected Service to be owned by the Gateway, got %v", svc.OwnerReferences)
+	}
+
+	var slice discoveryv1.EndpointSlice
+	if err := c.Get(ctx, key, &slice); err != nil {
+		t.Fatalf("unable to get EndpointSlice: %v", err)
+	}
+	if len(slice.Endpoints) != 1 || slice.Endpoints[0].Addresses[0] != "10.0.0.7" {
+		t.Errorf("expected the proxy endpoints, got %v", slice.Endpoints)
+	}
+	if len(slice.Ports) != 2 || *slice.Ports[0].Port != 8000 || *slice.Ports[0].Name != svc.Spec.Ports[0].Name {
+		t.Errorf("expected ports targeting the proxy port 8000, got %v", slice.Ports)
+	}
+	if !isOwnedBy(&slice, gw) {
+		t.Errorf("expected EndpointSlice to be owned by the Gateway, got %v", slice.OwnerReferences)
+	}
+
+	// Drift in the provisioned Service is reverted.
+	svc.Spec.Type = corev1.ServiceTypeClusterIP
+	svc.Spec.Ports = svc.Spec.Ports[:1]
+	if err := c.Update(ctx, &svc); err != nil {
+		t.Fatalf("unable to update Service: %v", err)
+	}
+	if _, err := r.provisionService(ctx, gw, params, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(ctx, key, &svc); err != nil {
+		t.Fatalf("unable to get Service: %v", err)
+	}
+	if svc.Spec.Type != corev1.ServiceTypeNodePort || len(svc.Spec.Ports) != 2 {
+		t.Errorf("expected drift to be reverted, got type %v with %d ports", svc.Spec.Type, len(svc.Spec.Ports))
+	}
+}
+
+func isOwnedBy(obj, owner client.Object) bool {
+	ref := metav1.GetControllerOf(obj)
+	return ref != nil && ref.UID == owner.GetUID() && ref.Kind == "Gateway"
+}
+
+func TestProvisionedName(t *testing.T) {
+	long := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("g", 60)},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "ours"},
+	}
+	name := provisionedName(long)
+	if len(name) > 63 {
+		t.Errorf("expected at most 63 characters, got %d", len(name))
+	}
+	if name == provisionedName(&gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("g", 60)},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "theirs"},
+	}) {
+		t.Errorf("expected shortened names to stay distinct, got %s for both", name)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var lastResync = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "gari_controller_last_resync_timestamp_seconds",
+	Help: "Time of the last successful periodic resync, in seconds since the epoch.",
+})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(lastResync)
+}
+
+// resyncChannel carries the objects requeued by a Resyncer to a controller.
+type resyncChannel chan event.GenericEvent
+
+// source returns the controller source for the channel.
+func (c resyncChannel) source() source.Source {
+	return source.Channel(c, &handler.EnqueueRequestForObject{})
+}
+
+// Resyncer periodically recomputes the proxy route table from the cluster and
+// requeues every HTTPRoute, Gateway and GatewayClass so that their status is
+// recomputed. This corrects drift caused by missed events or bugs. It runs on
+// every replica, since each one serves traffic, but only the leader requeues
+// Gateways and GatewayClasses, whose controllers run on the leader only.
+type Resyncer struct {
+	client.Client
+	Period time.Duration
+	Routes *HTTPRouteReconciler
+
+	routes         resyncChannel
+	gateways       resyncChannel
+	gatewayClasses resyncChannel
+}
+
+// NewResyncer returns a Resyncer for the given reconcilers, which must be set
+// up with the manager after this call.
+func NewResyncer(c client.Client, period time.Duration, routes *HTTPRouteReconciler, gateways *GatewayReconciler, gatewayClasses *GatewayClassReconciler) *Resyncer {
+	r := &Resyncer{
+		Client:         c,
+		Period:         period,
+		Routes:         routes,
+		routes:         make(resyncChannel),
+		gateways:       make(resyncChannel),
+		gatewayClasses: make(resyncChannel),
+	}
+	routes.resync = r.routes
+	gateways.resync = r.gateways
+	gatewayClasses.resync = r.gatewayClasses
+	return r
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (r *Resyncer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the resync loop until ctx is cancelled.
+func (r *Resyncer) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("resync")
+	ticker := time.NewTicker(r.Period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := r.resync(ctx); err != nil {
+			l.Error(err, "periodic resync failed")
+			continue
+		}
+		lastResync.SetToCurrentTime()
+	}
+}
+
+func (r *Resyncer) resync(ctx context.Context) error {
+	if err := r.Routes.updateProxy(ctx); err != nil {
+		return err
+	}
+
+	var routes gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &routes); err != nil {
+		return err
+	}
+	for i := range routes.Items {
+		if !requeue(ctx, r.routes, &routes.Items[i]) {
+			return ctx.Err()
+		}
+	}
+	if !r.Routes.leading() {
+		return nil
+	}
+
+	var gateways gatewayv1.GatewayList
+	if err := r.List(ctx, &gateways); err != nil {
+		return err
+	}
+	var gatewayClasses gatewayv1.GatewayClassList
+	if err := r.List(ctx, &gatewayClasses); err != nil {
+		return err
+	}
+	for i := range gateways.Items {
+		if !requeue(ctx, r.gateways, &gateways.Items[i]) {
+			return ctx.Err()
+		}
+	}
+	for i := range gatewayClasses.Items {
+		if !requeue(ctx, r.gatewayClasses, &gatewayClasses.Items[i]) {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// requeue sends obj to the controller behind ch, reporting false if ctx was
+// cancelled first.
+func requeue(ctx context.Context, ch resyncChannel, obj client.Object) bool {
+	select {
+	case ch <- event.GenericEvent{Object: obj}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
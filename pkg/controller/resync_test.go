@@ -0,0 +1,94 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestResync(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, gatewayv1.Install, v1alpha1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("unable to build scheme: %v", err)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		},
+		&gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+			Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}}}},
+		},
+	).Build()
+
+	p := proxy.NewProxy(proxy.Options{})
+	routes := &HTTPRouteReconciler{Client: c, Scheme: scheme, Proxy: p}
+	r := NewResyncer(c, 0, routes, &GatewayReconciler{}, &GatewayClassReconciler{})
+
+	var mu sync.Mutex
+	var requeued []string
+	var wg sync.WaitGroup
+	for _, ch := range []resyncChannel{r.routes, r.gateways, r.gatewayClasses} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range ch {
+				mu.Lock()
+				requeued = append(requeued, client.ObjectKeyFromObject(e.Object).String())
+				mu.Unlock()
+			}
+		}()
+	}
+
+	if err := r.resync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(r.routes)
+	close(r.gateways)
+	close(r.gatewayClasses)
+	wg.Wait()
+
+	sort.Strings(requeued)
+	expected := []string{"/ours", "default/gw", "default/web"}
+	if !reflect.DeepEqual(requeued, expected) {
+		t.Errorf("expected %v, got %v", expected, requeued)
+	}
+	if served := p.Routes(); len(served) != 1 {
+		t.Errorf("expected 1 route to be served, got %v", served)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sync"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// RouteSink receives the routes computed by the HTTPRouteReconciler, as a
+// Proxy does.
+type RouteSink interface {
+	// UpdateRoutes replaces every route.
+	UpdateRoutes(routes []proxy.HTTPRoute)
+	// ApplyRouteChanges replaces the changed routes, and removes those whose
+	// change is nil. The changes must not be modified.
+	ApplyRouteChanges(changes map[proxy.RouteKey]*proxy.HTTPRoute)
+}
+
+// RouteSinks passes the routes to each of its sinks.
+type RouteSinks []RouteSink
+
+func (s RouteSinks) UpdateRoutes(routes []proxy.HTTPRoute) {
+	for _, sink := range s {
+		sink.UpdateRoutes(routes)
+	}
+}
+
+func (s RouteSinks) ApplyRouteChanges(changes map[proxy.RouteKey]*proxy.HTTPRoute) {
+	for _, sink := range s {
+		sink.ApplyRouteChanges(changes)
+	}
+}
+
+// routeTable caches the proxy configuration translated from each route, so
+// that a reconcile only translates the route that changed and applies the
+// difference to the proxy instead of translating every route again. Changes
+// are held as pending until they are flushed, so that a burst of them can be
+// applied to the proxy as one batch.
+type routeTable struct {
+	// mu is held for the whole of an update, from translation until the
+	// change is recorded, and while pending changes are flushed, so that the
+	// table and the proxy stay in step.
+	mu sync.Mutex
+	// programmed holds the routes served by the proxy, keyed by route UID.
+	programmed map[types.UID]proxy.HTTPRoute
+	// rejected holds the reason each route rejected by all of our parents
+	// was rejected for, keyed by route UID.
+	rejected map[types.UID]string
+	// uids holds the UID of each route in the table, since deleted routes
+	// are only known by name.
+	uids map[types.NamespacedName]types.UID
+	// ingresses holds the routes translated from each Ingress served in
+	// compatibility mode, keyed by Ingress. They are kept on reset, which
+	// only covers HTTPRoutes.
+	ingresses map[types.NamespacedName][]proxy.HTTPRoute
+	// pending holds the changes not yet applied to the proxy, with nil for
+	// the routes to stop serving.
+	pending map[proxy.RouteKey]*proxy.HTTPRoute
+	// flushScheduled is set while a flush of the pending changes is
+	// scheduled.
+	flushScheduled bool
+}
+
+// reset replaces the content of the table with the translation of every
+// route. translated holds the routes served by the proxy, which replace them
+// all at once, so pending changes are dropped. Routes are counted as rejected
+// from their status from controllerName.
+func (t *routeTable) reset(routes []gatewayv1.HTTPRoute, translated []proxy.HTTPRoute, controllerName gatewayv1.GatewayController) {
+	t.pending = map[proxy.RouteKey]*proxy.HTTPRoute{}
+	t.programmed = make(map[types.UID]proxy.HTTPRoute, len(translated))
+	t.rejected = map[types.UID]string{}
+	t.uids = make(map[types.NamespacedName]types.UID, len(routes))
+	for i := range routes {
+		if ours, accepted, reason := routeAcceptanceState(&routes[i], controllerName); ours && !accepted {
+			t.rejected[routes[i].UID] = reason
+		}
+		t.uids[client.ObjectKeyFromObject(&routes[i])] = routes[i].UID
+	}
+	for _, route := range translated {
+		t.programmed[t.uids[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}]] = route
+	}
+}
+
+// update records the translation of route, or its absence if the route is
+// not served, as a pending change.
+func (t *routeTable) update(route *gatewayv1.HTTPRoute, translated *proxy.HTTPRoute, controllerName gatewayv1.GatewayController) {
+	if t.uids == nil {
+		t.reset(nil, nil, controllerName)
+	}
+	key := client.ObjectKeyFromObject(route)
+	if uid, ok := t.uids[key]; ok && uid != route.UID {
+		// The route was deleted and created again under the same name.
+		delete(t.programmed, uid)
+		delete(t.rejected, uid)
+	}
+	delete(t.rejected, route.UID)
+	delete(t.uids, key)
+
+	if translated != nil {
+		t.programmed[route.UID] = *translated
+		t.uids[key] = route.UID
+	} else {
+		delete(t.programmed, route.UID)
+		if ours, _, reason := routeAcceptanceState(route, controllerName); ours {
+			t.rejected[route.UID] = reason
+			t.uids[key] = route.UID
+		}
+	}
+	t.pending[proxy.RouteKey{Namespace: route.Namespace, Name: route.Name}] = translated
+	t.recordMetrics()
+}
+
+// remove drops the deleted route with the given name from the table, as a
+// pending change.
+func (t *routeTable) remove(key types.NamespacedName) {
+	if t.uids == nil {
+		t.reset(nil, nil, "")
+	}
+	if uid, ok := t.uids[key]; ok {
+		delete(t.programmed, uid)
+		delete(t.rejected, uid)
+		delete(t.uids, key)
+	}
+	t.pending[proxy.RouteKey{Namespace: key.Namespace, Name: key.Name}] = nil
+	t.recordMetrics()
+}
+
+// updateIngress records the routes translated from the Ingress with the given
+// name, replacing those translated before, as pending changes. An Ingress that
+// is not served has no routes.
+func (t *routeTable) updateIngress(key types.NamespacedName, translated []proxy.HTTPRoute) {
+	if t.uids == nil {
+		t.reset(nil, nil, "")
+	}
+	if t.ingresses == nil {
+		t.ingresses = map[types.NamespacedName][]proxy.HTTPRoute{}
+	}
+	for _, route := range t.ingresses[key] {
+		t.pending[proxy.RouteKey{Namespace: route.Namespace, Name: route.Name}] = nil
+	}
+	for i := range translated {
+		t.pending[proxy.RouteKey{Namespace: translated[i].Namespace, Name: translated[i].Name}] = &translated[i]
+	}
+	if len(translated) == 0 {
+		delete(t.ingresses, key)
+	} else {
+		t.ingresses[key] = translated
+	}
+}
+
+// ingressRoutes returns the routes translated from every Ingress.
+func (t *routeTable) ingressRoutes() []proxy.HTTPRoute {
+	var routes []proxy.HTTPRoute
+	for _, translated := range t.ingresses {
+		routes = append(routes, translated...)
+	}
+	return routes
+}
+
+// flush applies the pending changes to sink as one batch.
+func (t *routeTable) flush(sink RouteSink) {
+	if len(t.pending) == 0 {
+		return
+	}
+	sink.ApplyRouteChanges(t.pending)
+	t.pending = map[proxy.RouteKey]*proxy.HTTPRoute{}
+}
+
+// recordMetrics sets the route gauges from the content of the table.
+func (t *routeTable) recordMetrics() {
+	rejected := map[string]int{}
+	for _, reason := range t.rejected {
+		rejected[reason]++
+	}
+	setRouteGauges(len(t.programmed), rejected)
+}
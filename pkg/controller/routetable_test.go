@@ -0,0 +1,125 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestRouteTable(t *testing.T) {
+	newRoute := func(name string, uid types.UID, accepted metav1.ConditionStatus) *gatewayv1.HTTPRoute {
+		reason := string(gatewayv1.RouteReasonAccepted)
+		if accepted != metav1.ConditionTrue {
+			reason = string(gatewayv1.RouteReasonNotAllowedByListeners)
+		}
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: uid},
+			Status: gatewayv1.HTTPRouteStatus{RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{{
+				ControllerName: DefaultControllerName,
+				Conditions:     []metav1.Condition{{Type: string(gatewayv1.RouteConditionAccepted), Status: accepted, Reason: reason}},
+			}}}},
+		}
+	}
+	translate := func(route *gatewayv1.HTTPRoute) *proxy.HTTPRoute {
+		return &proxy.HTTPRoute{Namespace: route.Namespace, Name: route.Name, Hostnames: []string{string(route.UID)}}
+	}
+	served := func(p *proxy.Proxy) []string {
+		var served []string
+		for _, route := range p.Routes() {
+			served = append(served, route.Name+"="+route.Hostnames[0])
+		}
+		return served
+	}
+
+	p := proxy.NewProxy(proxy.Options{})
+	var table routeTable
+	a, b := newRoute("a", "uid-a", metav1.ConditionTrue), newRoute("b", "uid-b", metav1.ConditionTrue)
+	table.update(a, translate(a), DefaultControllerName)
+	table.update(b, translate(b), DefaultControllerName)
+	if actual := served(p); len(actual) != 0 {
+		t.Errorf("expected no routes before the changes are flushed, got %v", actual)
+	}
+	table.flush(p)
+	if expected, actual := []string{"a=uid-a", "b=uid-b"}, served(p); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	// A route rejected by its parents is no longer served, and counted as
+	// rejected.
+	rejected := newRoute("b", "uid-b", metav1.ConditionFalse)
+	table.update(rejected, nil, DefaultControllerName)
+	table.flush(p)
+	if expected, actual := []string{"a=uid-a"}, served(p); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if actual := testutil.ToFloat64(routesRejected.WithLabelValues(string(gatewayv1.RouteReasonNotAllowedByListeners))); actual != 1 {
+		t.Errorf("expected 1 rejected route, got %v", actual)
+	}
+
+	// A route created again under the same name replaces the deleted one.
+	recreated := newRoute("a", "uid-a2", metav1.ConditionTrue)
+	table.update(recreated, translate(recreated), DefaultControllerName)
+	table.flush(p)
+	if expected, actual := []string{"a=uid-a2"}, served(p); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if len(table.programmed) != 1 {
+		t.Errorf("expected the previous translation to be dropped, got %v", table.programmed)
+	}
+
+	table.remove(types.NamespacedName{Namespace: "default", Name: "a"})
+	table.remove(types.NamespacedName{Namespace: "default", Name: "b"})
+	table.flush(p)
+	if actual := served(p); len(actual) != 0 {
+		t.Errorf("expected no routes, got %v", actual)
+	}
+	if len(table.programmed) != 0 || len(table.rejected) != 0 || len(table.uids) != 0 {
+		t.Errorf("expected an empty table, got %+v", &table)
+	}
+	if actual := testutil.ToFloat64(routesProgrammed); actual != 0 {
+		t.Errorf("expected 0 programmed routes, got %v", actual)
+	}
+}
+
+func TestPublishRoutesCoalescesChanges(t *testing.T) {
+	p := proxy.NewProxy(proxy.Options{})
+	r := &HTTPRouteReconciler{Proxy: p, ProxyUpdateDelay: 50 * time.Millisecond}
+	for _, name := range []string{"a", "b"} {
+		route := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)}}
+		r.routeTable.mu.Lock()
+		r.routeTable.update(route, &proxy.HTTPRoute{Namespace: route.Namespace, Name: route.Name}, DefaultControllerName)
+		r.publishRoutes()
+		r.routeTable.mu.Unlock()
+	}
+	if routes := p.Routes(); len(routes) != 0 {
+		t.Errorf("expected no routes within the window, got %v", routes)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(p.Routes()) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected both routes to be applied after the window, got %v", p.Routes())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// SecurityHeadersPolicyReconciler reports the status of SecurityHeadersPolicies.
+// The policies themselves are applied to the proxy by the HTTPRouteReconciler.
+type SecurityHeadersPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Backoff bounds the delays between retries of failed reconciles.
+	Backoff Backoff
+
+	// statusWrites limits how often the status of each policy is written.
+	statusWrites statusWriteLimiter
+}
+
+func (r *SecurityHeadersPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var policy v1alpha1.SecurityHeadersPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	ancestors, err := policyAncestorStatuses(ctx, r.Client, controllerNameOrDefault(r.ControllerName), &policy, policy.Spec.TargetRefs, []gatewayv1.Kind{kindGateway, kindHTTPRoute}, policy.Status.Ancestors, policyAccepted)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := policy.DeepCopy()
+	policy.Status.Ancestors = ancestors
+	if err := patchStatus(ctx, r.Client, &r.statusWrites, &policy, original); err != nil {
+		return statusWriteFailed(l, err, "unable to update SecurityHeadersPolicy status")
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *SecurityHeadersPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.SecurityHeadersPolicy{}, builder.WithPredicates(specChanged)).
+		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.mapToPolicies), builder.WithPredicates(specChanged)).
+		Watches(&gatewayv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.mapToPolicies), builder.WithPredicates(specChanged)).
+		WithOptions(r.Backoff.options()).
+		Complete(r)
+}
+
+// mapToPolicies enqueues every SecurityHeadersPolicy in the namespace of the
+// changed object, since any of them may target it.
+func (r *SecurityHeadersPolicyReconciler) mapToPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
+	var policies v1alpha1.SecurityHeadersPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list SecurityHeadersPolicies")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+	}
+	return requests
+}
+
+// securityHeaders returns the response headers configured by the policy,
+// applying defaults for unset fields and omitting headers set to "".
+func securityHeaders(spec *v1alpha1.SecurityHeadersPolicySpec) map[string]string {
+	headers := map[string]string{}
+	set := func(name string, value *string, defaultValue string) {
+		v := defaultValue
+		if value != nil {
+			v = *value
+		}
+		if v != "" {
+			headers[name] = v
+		}
+	}
+	set("Strict-Transport-Security", spec.StrictTransportSecurity, v1alpha1.DefaultStrictTransportSecurity)
+	set("X-Content-Type-Options", spec.ContentTypeOptions, v1alpha1.DefaultContentTypeOptions)
+	set("X-Frame-Options", spec.FrameOptions, v1alpha1.DefaultFrameOptions)
+	set("Referrer-Policy", spec.ReferrerPolicy, v1alpha1.DefaultReferrerPolicy)
+	return headers
+}
+
+// securityHeadersForTargets computes the security headers configured for each
+// HTTPRoute and Gateway targeted by a policy matching opts. When several
+// policies target the same object, the oldest one wins.
+func securityHeadersForTargets(ctx context.Context, c client.Reader, opts ...client.ListOption) (routes, gateways map[types.NamespacedName]map[string]string, err error) {
+	var policies v1alpha1.SecurityHeadersPolicyList
+	if err := c.List(ctx, &policies, opts...); err != nil {
+		return nil, nil, err
+	}
+	sortPoliciesByAge(policies.Items)
+
+	routes = map[types.NamespacedName]map[string]string{}
+	gateways = map[types.NamespacedName]map[string]string{}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		headers := securityHeaders(&policy.Spec)
+		for _, targetRef := range policy.Spec.TargetRefs {
+			key := types.NamespacedName{Namespace: policy.Namespace, Name: string(targetRef.Name)}
+			var targets map[types.NamespacedName]map[string]string
+			switch {
+			case isPolicyTarget(targetRef, kindHTTPRoute):
+				targets = routes
+			case isPolicyTarget(targetRef, kindGateway):
+				targets = gateways
+			default:
+				continue
+			}
+			if _, exists := targets[key]; !exists {
+				targets[key] = headers
+			}
+		}
+	}
+	return routes, gateways, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// listServiceImports returns all ServiceImports, keyed by name. It returns no
+// ServiceImports if the MCS API is not installed in the cluster.
+func listServiceImports(ctx context.Context, c client.Reader) (map[types.NamespacedName]*ir.ServiceImport, error) {
+	var list unstructured.UnstructuredList
+	list.SetGroupVersionKind(ir.ServiceImportGVK.GroupVersion().WithKind(ir.ServiceImportGVK.Kind + "List"))
+	if err := c.List(ctx, &list); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	imports := make(map[types.NamespacedName]*ir.ServiceImport, len(list.Items))
+	for i := range list.Items {
+		imports[client.ObjectKeyFromObject(&list.Items[i])] = ir.ParseServiceImport(&list.Items[i])
+	}
+	return imports, nil
+}
+
+// getServiceImport returns the ServiceImport with the given key, or nil if it
+// does not exist or the MCS API is not installed in the cluster.
+func getServiceImport(ctx context.Context, c client.Reader, key types.NamespacedName) (*ir.ServiceImport, error) {
+	var u unstructured.Unstructured
+	u.SetGroupVersionKind(ir.ServiceImportGVK)
+	if err := c.Get(ctx, key, &u); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ir.ParseServiceImport(&u), nil
+}
+
+// mapServiceImportToRoutes enqueues the HTTPRoutes with a backendRef to the
+// changed ServiceImport.
+func (r *HTTPRouteReconciler) mapServiceImportToRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.routesReferencingBackend(ctx, gatewayv1.Group(ir.ServiceImportGVK.Group), gatewayv1.Kind(ir.ServiceImportGVK.Kind), client.ObjectKeyFromObject(obj))
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GatewayShardLabel assigns a Gateway to the controller instance serving the
+// shard of the same name. Gateways without the label belong to the instances
+// that serve no particular shard.
+//
+// Sharding splits the Gateways of a GatewayClass between instances that share
+// its controllerName. Each instance programs its proxy and writes the status of
+// its own Gateways only, and writes the entries of the route statuses that
+// are about them, preserving those written by the other instances.
+const GatewayShardLabel = ir.ShardLabel
+
+// gatewayChanged passes the changes of a Gateway that may change what it
+// serves or the instance serving it.
+var gatewayChanged = predicate.Or(specChanged, predicate.LabelChangedPredicate{})
+
+// ownsParent reports whether parentRef, of a route in namespace, belongs to
+// the shard of r. Parents other than Gateways are never ours; Gateways that do
+// not exist have no shard label, so they belong to the instances serving no
+// particular shard, which report them as missing.
+func (r *HTTPRouteReconciler) ownsParent(ctx context.Context, namespace string, parentRef gatewayv1.ParentReference) (bool, error) {
+	if !ir.IsGatewayParent(parentRef) {
+		return false, nil
+	}
+	var gw gatewayv1.Gateway
+	if err := r.Get(ctx, ir.ParentGateway(namespace, parentRef), &gw); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.Shard == "", nil
+		}
+		return false, err
+	}
+	return ir.InShard(&gw, r.Shard), nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcileRouteOfShard(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+
+	gateway := func(name, shard string) *gatewayv1.Gateway {
+		return &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{GatewayShardLabel: shard}},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		}
+	}
+	acceptedBy := func(name string) gatewayv1.RouteParentStatus {
+		return gatewayv1.RouteParentStatus{
+			ParentRef:      gatewayv1.ParentReference{Name: gatewayv1.ObjectName(name)},
+			ControllerName: DefaultControllerName,
+			Conditions: []metav1.Condition{{
+				Type:               string(gatewayv1.RouteConditionAccepted),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(gatewayv1.RouteReasonAccepted),
+				LastTransitionTime: metav1.Now(),
+			}},
+		}
+	}
+	newRoute := func(name string, gateways ...string) *gatewayv1.HTTPRoute {
+		route := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)}}
+		for _, gw := range gateways {
+			route.Spec.ParentRefs = append(route.Spec.ParentRefs, gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gw)})
+		}
+		// The instance serving shard b has already reported its Gateway.
+		route.Status.Parents = []gatewayv1.RouteParentStatus{acceptedBy("b-gw")}
+		return route
+	}
+	shared := newRoute("shared", "a-gw", "b-gw")
+	other := newRoute("other", "b-gw")
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithStatusSubresource(shared, other).WithObjects(
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		gateway("a-gw", "a"),
+		gateway("b-gw", "b"),
+		shared,
+		other,
+	).Build()
+
+	p := proxy.NewProxy(proxy.Options{})
+	r := &HTTPRouteReconciler{Client: c, Scheme: scheme, Proxy: p, Shard: "a"}
+	for _, route := range []*gatewayv1.HTTPRoute{shared, other} {
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expectedParents := map[string][]gatewayv1.ObjectName{"shared": {"b-gw", "a-gw"}, "other": {"b-gw"}}
+	for name, expected := range expectedParents {
+		var actual gatewayv1.HTTPRoute
+		if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, &actual); err != nil {
+			t.Fatalf("unable to get route: %v", err)
+		}
+		var parents []gatewayv1.ObjectName
+		for _, ps := range actual.Status.Parents {
+			parents = append(parents, ps.ParentRef.Name)
+		}
+		if !reflect.DeepEqual(parents, expected) {
+			t.Errorf("route %s: expected parents %v, got %v", name, expected, parents)
+		}
+	}
+
+	var served []string
+	for _, route := range p.Routes() {
+		served = append(served, route.Name)
+	}
+	if !reflect.DeepEqual(served, []string{"shared"}) {
+		t.Errorf("expected only the route attached to shard a to be served, got %v", served)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// FieldManager identifies the controller in the managedFields of the objects
+// it writes.
+const FieldManager = "gateway-api-reference-implementation"
+
+// Status writes of each object are limited to statusWriteBurst in a row, then
+// to one per statusWriteInterval, so that an object whose status flaps does
+// not flood the API server.
+const (
+	statusWriteBurst    = 5
+	statusWriteInterval = time.Second
+)
+
+// statusWriteLimiter limits the status writes of a reconciler with a token
+// bucket per object. The zero value is ready to use.
+type statusWriteLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	// lastSweep is when buckets that have refilled were last dropped, so
+	// that the objects that have since been deleted are forgotten.
+	lastSweep time.Time
+}
+
+// reserve takes a token for a status write of the object identified by key,
+// returning zero if the write may proceed and otherwise how long to wait for
+// the next token.
+func (l *statusWriteLimiter) reserve(key string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limiters == nil {
+		l.limiters = map[string]*rate.Limiter{}
+	}
+	if now.Sub(l.lastSweep) > statusWriteBurst*statusWriteInterval {
+		for k, limiter := range l.limiters {
+			if limiter.TokensAt(now) >= statusWriteBurst {
+				delete(l.limiters, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(statusWriteInterval), statusWriteBurst)
+		l.limiters[key] = limiter
+	}
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// statusRateLimitedError reports a status write deferred by a
+// statusWriteLimiter.
+type statusRateLimitedError struct {
+	retryAfter time.Duration
+}
+
+func (e *statusRateLimitedError) Error() string {
+	return fmt.Sprintf("status writes rate limited, retry after %v", e.retryAfter)
+}
+
+// limitStatusWrite takes a token from limiter for a status write of obj of
+// the given kind.
+func limitStatusWrite(limiter *statusWriteLimiter, kind string, obj client.Object) error {
+	delay := limiter.reserve(kind+"/"+client.ObjectKeyFromObject(obj).String(), time.Now())
+	if delay == 0 {
+		return nil
+	}
+	statusWritesRateLimited.WithLabelValues(kind).Inc()
+	return &statusRateLimitedError{retryAfter: delay}
+}
+
+// statusWriteFailed returns the result of a reconcile whose status write
+// failed with err. A write deferred by the rate limiter is retried once it is
+// allowed, without being reported as an error.
+func statusWriteFailed(l logr.Logger, err error, msg string, keysAndValues ...any) (ctrl.Result, error) {
+	var limited *statusRateLimitedError
+	if errors.As(err, &limited) {
+		l.V(1).Info("Deferring status update", "retryAfter", limited.retryAfter)
+		return ctrl.Result{RequeueAfter: limited.retryAfter}, nil
+	}
+	l.Error(err, msg, keysAndValues...)
+	return ctrl.Result{}, err
+}
+
+// applyStatus writes the status of an object owned solely by this controller
+// with server-side apply, so that concurrent writers to other fields do not
+// cause resourceVersion conflicts. Only the status is applied. Callers skip
+// the write if the status has not changed.
+func applyStatus(ctx context.Context, c client.Client, limiter *statusWriteLimiter, obj client.Object, status any) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	if err := limitStatusWrite(limiter, gvk.Kind, obj); err != nil {
+		return err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(status)
+	if err != nil {
+		return err
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace(obj.GetNamespace())
+	u.SetName(obj.GetName())
+	u.Object["status"] = content
+	if err := c.Status().Patch(ctx, u, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		statusUpdateFailures.WithLabelValues(gvk.Kind).Inc()
+		return err
+	}
+	return nil
+}
+
+// patchStatus writes the status of an object whose status lists are shared
+// with other controllers. Gateway API declares these lists atomic, so
+// server-side apply would take over the whole list; instead the status is
+// merge patched, guarded by the resourceVersion of original so that entries
+// written concurrently by other controllers are never lost. Nothing is
+// written if the status of obj is semantically equal to that of original.
+func patchStatus(ctx context.Context, c client.Client, limiter *statusWriteLimiter, obj, original client.Object) error {
+	changed, err := statusChanged(obj, original)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	kind := "Unknown"
+	if gvk, gvkErr := apiutil.GVKForObject(obj, c.Scheme()); gvkErr == nil {
+		kind = gvk.Kind
+	}
+	if err := limitStatusWrite(limiter, kind, obj); err != nil {
+		return err
+	}
+	if err := c.Status().Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}), client.FieldOwner(FieldManager)); err != nil {
+		statusUpdateFailures.WithLabelValues(kind).Inc()
+		return err
+	}
+	return nil
+}
+
+// statusChanged reports whether the status of obj differs from that of
+// original.
+func statusChanged(obj, original client.Object) (bool, error) {
+	current, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return false, err
+	}
+	previous, err := runtime.DefaultUnstructuredConverter.ToUnstructured(original)
+	if err != nil {
+		return false, err
+	}
+	return !equality.Semantic.DeepEqual(current["status"], previous["status"]), nil
+}
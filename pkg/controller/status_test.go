@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGatewayClassStatusIsApplied(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gc := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "ours", Generation: 3},
+		Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+	}
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithStatusSubresource(gc).WithObjects(gc).Build()
+
+	r := &GatewayClassReconciler{Client: c, Scheme: scheme}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gc)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual gatewayv1.GatewayClass
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(gc), &actual); err != nil {
+		t.Fatalf("unable to get GatewayClass: %v", err)
+	}
+	condition := meta.FindStatusCondition(actual.Status.Conditions, string(gatewayv1.GatewayClassConditionStatusAccepted))
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.ObservedGeneration != 3 {
+		t.Errorf("expected Accepted=True for generation 3, got %v", condition)
+	}
+	if !reflect.DeepEqual(actual.Status.SupportedFeatures, supportedFeaturesStatus()) {
+		t.Errorf("expected supported features %v, got %v", supportedFeaturesStatus(), actual.Status.SupportedFeatures)
+	}
+	if actual.Spec.ControllerName != DefaultControllerName {
+		t.Errorf("expected spec to be unchanged, got %v", actual.Spec.ControllerName)
+	}
+}
+
+func TestGatewayClassOfOtherControllerName(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	gc := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+		Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+	}
+	c := withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithStatusSubresource(gc).WithObjects(gc).Build()
+
+	r := &GatewayClassReconciler{Client: c, Scheme: scheme, ControllerName: "example.com/other"}
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(gc)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual gatewayv1.GatewayClass
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(gc), &actual); err != nil {
+		t.Fatalf("unable to get GatewayClass: %v", err)
+	}
+	if len(actual.Status.Conditions) != 0 {
+		t.Errorf("expected the GatewayClass of another controller name to be left alone, got %v", actual.Status.Conditions)
+	}
+}
+
+func TestStatusWriteLimiter(t *testing.T) {
+	var limiter statusWriteLimiter
+	now := time.Now()
+	for i := 0; i < statusWriteBurst; i++ {
+		if delay := limiter.reserve("Gateway/default/gw", now); delay != 0 {
+			t.Fatalf("expected write %d of the burst to proceed, got a delay of %v", i, delay)
+		}
+	}
+	if delay := limiter.reserve("Gateway/default/gw", now); delay <= 0 || delay > statusWriteInterval {
+		t.Errorf("expected the write after the burst to be delayed by at most %v, got %v", statusWriteInterval, delay)
+	}
+	if delay := limiter.reserve("Gateway/default/other", now); delay != 0 {
+		t.Errorf("expected writes of other objects to proceed, got a delay of %v", delay)
+	}
+	if delay := limiter.reserve("Gateway/default/gw", now.Add(statusWriteInterval)); delay != 0 {
+		t.Errorf("expected a write after the interval to proceed, got a delay of %v", delay)
+	}
+}
+
+func TestPatchStatusSkipsUnchangedStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Status: gatewayv1.HTTPRouteStatus{RouteStatus: gatewayv1.RouteStatus{Parents: []gatewayv1.RouteParentStatus{{
+			ParentRef:      gatewayv1.ParentReference{Name: "gw"},
+			ControllerName: DefaultControllerName,
+			Conditions: []metav1.Condition{{
+				Type:               string(gatewayv1.RouteConditionAccepted),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(gatewayv1.RouteReasonAccepted),
+				LastTransitionTime: metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)),
+			}},
+		}}}},
+	}
+	patches := 0
+	c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(route).WithObjects(route).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				patches++
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+
+	var limiter statusWriteLimiter
+	original := route.DeepCopy()
+	if err := patchStatus(context.Background(), c, &limiter, route.DeepCopy(), original); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patches != 0 {
+		t.Errorf("expected an unchanged status not to be written, got %d patches", patches)
+	}
+
+	changed := route.DeepCopy()
+	changed.Status.Parents[0].Conditions[0].Status = metav1.ConditionFalse
+	if err := patchStatus(context.Background(), c, &limiter, changed, original); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patches != 1 {
+		t.Errorf("expected a changed status to be written once, got %d patches", patches)
+	}
+}
+
+func TestStatusWriteFailed(t *testing.T) {
+	result, err := statusWriteFailed(logr.Discard(), &statusRateLimitedError{retryAfter: time.Second}, "Failed to update status")
+	if err != nil || result.RequeueAfter != time.Second {
+		t.Errorf("expected a rate-limited write to be retried after 1s without an error, got %+v, %v", result, err)
+	}
+
+	failure := errors.New("conflict")
+	if _, err := statusWriteFailed(logr.Discard(), failure, "Failed to update status"); !errors.Is(err, failure) {
+		t.Errorf("expected other failures to be returned, got %v", err)
+	}
+}
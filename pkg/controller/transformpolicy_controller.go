@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// TransformPolicyReconciler compiles the CEL expressions of TransformPolicies
+// and reports the result in their status. The policies themselves are applied
+// to the proxy by the HTTPRouteReconciler.
+type TransformPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+	// Backoff bounds the delays between retries of failed reconciles.
+	Backoff Backoff
+
+	// statusWrites limits how often the status of each policy is written.
+	statusWrites statusWriteLimiter
+}
+
+func (r *TransformPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx)
+
+	var policy v1alpha1.TransformPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	accepted := policyAccepted
+	if _, err := compileTransform(&policy.Spec); err != nil {
+		accepted = policyAcceptance{
+			status:  metav1.ConditionFalse,
+			reason:  gatewayv1.PolicyReasonInvalid,
+			message: fmt.Sprintf("Invalid CEL expression: %v", err),
+		}
+	}
+
+	ancestors, err := policyAncestorStatuses(ctx, r.Client, controllerNameOrDefault(r.ControllerName), &policy, policy.Spec.TargetRefs, []gatewayv1.Kind{kindGateway, kindHTTPRoute}, policy.Status.Ancestors, accepted)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := policy.DeepCopy()
+	policy.Status.Ancestors = ancestors
+	if err := patchStatus(ctx, r.Client, &r.statusWrites, &policy, original); err != nil {
+		return statusWriteFailed(l, err, "unable to update TransformPolicy status")
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *TransformPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.TransformPolicy{}, builder.WithPredicates(specChanged)).
+		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.mapToPolicies), builder.WithPredicates(specChanged)).
+		Watches(&gatewayv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.mapToPolicies), builder.WithPredicates(specChanged)).
+		WithOptions(r.Backoff.options()).
+		Complete(r)
+}
+
+// mapToPolicies enqueues every TransformPolicy in the namespace of the changed
+// object, since any of them may target it.
+func (r *TransformPolicyReconciler) mapToPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
+	var policies v1alpha1.TransformPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "unable to list TransformPolicies")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)})
+	}
+	return requests
+}
+
+// compileTransform compiles every expression in the policy.
+func compileTransform(spec *v1alpha1.TransformPolicySpec) (*proxy.Transform, error) {
+	compile := func(transforms []v1alpha1.HeaderTransform) ([]proxy.HeaderTransform, error) {
+		var compiled []proxy.HeaderTransform
+		for _, t := range transforms {
+			when := ""
+			if t.When != nil {
+				when = *t.When
+			}
+			ht, err := proxy.NewHeaderTransform(string(t.Name), t.Value, when)
+			if err != nil {
+				return nil, err
+			}
+			compiled = append(compiled, ht)
+		}
+		return compiled, nil
+	}
+
+	requestHeaders, err := compile(spec.RequestHeaders)
+	if err != nil {
+		return nil, err
+	}
+	responseHeaders, err := compile(spec.ResponseHeaders)
+	if err != nil {
+		return nil, err
+	}
+	return &proxy.Transform{RequestHeaders: requestHeaders, ResponseHeaders: responseHeaders}, nil
+}
+
+// transformsForTargets compiles the transforms configured for each HTTPRoute
+// and Gateway targeted by a policy matching opts. Policies that fail to
+// compile are skipped. When several policies target the same object, the
+// oldest one wins.
+func transformsForTargets(ctx context.Context, c client.Reader, opts ...client.ListOption) (routes, gateways map[types.NamespacedName]*proxy.Transform, err error) {
+	l := log.FromContext(ctx)
+
+	var policies v1alpha1.TransformPolicyList
+	if err := c.List(ctx, &policies, opts...); err != nil {
+		return nil, nil, err
+	}
+	sortPoliciesByAge(policies.Items)
+
+	routes = map[types.NamespacedName]*proxy.Transform{}
+	gateways = map[types.NamespacedName]*proxy.Transform{}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		transform, err := compileTransform(&policy.Spec)
+		if err != nil {
+			l.Error(err, "skipping invalid TransformPolicy", "policy", client.ObjectKeyFromObject(policy))
+			continue
+		}
+		for _, targetRef := range policy.Spec.TargetRefs {
+			key := types.NamespacedName{Namespace: policy.Namespace, Name: string(targetRef.Name)}
+			var targets map[types.NamespacedName]*proxy.Transform
+			switch {
+			case isPolicyTarget(targetRef, kindHTTPRoute):
+				targets = routes
+			case isPolicyTarget(targetRef, kindGateway):
+				targets = gateways
+			default:
+				continue
+			}
+			if _, exists := targets[key]; !exists {
+				targets[key] = transform
+			}
+		}
+	}
+	return routes, gateways, nil
+}
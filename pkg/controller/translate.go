@@ -0,0 +1,148 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/conditions"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Manifests can be translated without a cluster, for instance to validate
+// route changes in CI: the routes are built from the objects of the manifests
+// with ir.Build, as the HTTPRouteReconciler builds them from the cluster, and
+// the status the reconcilers would set is computed along with them, without
+// provisioning anything or writing any object.
+
+// Translation is the outcome of translating manifests without a cluster.
+type Translation struct {
+	// Routes is the route table the proxy would serve.
+	Routes []proxy.HTTPRoute
+	// GatewayClasses, Gateways and HTTPRoutes hold the objects translated,
+	// with the status the controller would set.
+	GatewayClasses []gatewayv1.GatewayClass
+	Gateways       []gatewayv1.Gateway
+	HTTPRoutes     []gatewayv1.HTTPRoute
+}
+
+// Translate translates the objects held by c, read from manifests, for the
+// controller named controllerName, or DefaultControllerName if empty. c must
+// serve the field indexes registered by SetupIndexes. Gateways are accepted
+// but not programmed, since they have no address without a cluster.
+func Translate(ctx context.Context, c client.Reader, controllerName gatewayv1.GatewayController) (*Translation, error) {
+	controllerName = controllerNameOrDefault(controllerName)
+	reader := inputReader{Reader: c, controllerName: controllerName, wasmPlugins: &wasmPluginCache{}}
+	var routes gatewayv1.HTTPRouteList
+	if err := c.List(ctx, &routes); err != nil {
+		return nil, err
+	}
+	in, err := reader.buildInput(ctx, routes.Items)
+	if err != nil {
+		return nil, err
+	}
+	built := ir.Build(in)
+
+	t := &Translation{Routes: built.Routes, GatewayClasses: in.GatewayClasses, Gateways: in.Gateways, HTTPRoutes: in.HTTPRoutes}
+	served := map[gatewayv1.ObjectName]bool{}
+	for i := range t.GatewayClasses {
+		gc := &t.GatewayClasses[i]
+		if gc.Spec.ControllerName != controllerName {
+			continue
+		}
+		served[gatewayv1.ObjectName(gc.Name)] = true
+		accepted, _, err := classAcceptedCondition(ctx, c, DataPlaneOptions{}, gc)
+		if err != nil {
+			return nil, err
+		}
+		conditions.Set(&gc.Status.Conditions, gc.Generation, accepted)
+		gc.Status.SupportedFeatures = nil
+		if accepted.Status == metav1.ConditionTrue {
+			gc.Status.SupportedFeatures = supportedFeaturesStatus()
+		}
+	}
+	for i := range t.Gateways {
+		gw := &t.Gateways[i]
+		if !served[gw.Spec.GatewayClassName] || !ir.InShard(gw, "") {
+			continue
+		}
+		if err := translateGatewayStatus(ctx, c, gw); err != nil {
+			return nil, err
+		}
+	}
+	for i := range t.HTTPRoutes {
+		route := &t.HTTPRoutes[i]
+		resolvedRefs := resolvedRefsCondition(route, in.Targets)
+		route.Status.Parents = slices.DeleteFunc(route.Status.Parents, func(ps gatewayv1.RouteParentStatus) bool {
+			return ps.ControllerName == controllerName
+		})
+		for _, parent := range built.Parents[client.ObjectKeyFromObject(route)] {
+			parentConditions, _ := conditions.Merge(nil, route.Generation, parentAcceptedCondition(parent), resolvedRefs)
+			route.Status.Parents = append(route.Status.Parents, gatewayv1.RouteParentStatus{
+				ParentRef:      parent.ParentRef,
+				ControllerName: controllerName,
+				Conditions:     parentConditions,
+			})
+		}
+	}
+	// Conflicts are reported against the routes accepted above.
+	for i := range t.HTTPRoutes {
+		route := &t.HTTPRoutes[i]
+		conflicted := conflictedCondition(routeConflicts(route, t.HTTPRoutes, controllerName))
+		if conflicted == nil {
+			continue
+		}
+		for j := range route.Status.Parents {
+			ps := &route.Status.Parents[j]
+			if ps.ControllerName == controllerName && meta.IsStatusConditionTrue(ps.Conditions, string(gatewayv1.RouteConditionAccepted)) {
+				conditions.Set(&ps.Conditions, route.Generation, *conflicted)
+			}
+		}
+	}
+	return t, nil
+}
+
+// translateGatewayStatus sets the status the GatewayReconciler would set on
+// gw, a Gateway of a class served by us, but for its address.
+func translateGatewayStatus(ctx context.Context, c client.Reader, gw *gatewayv1.Gateway) error {
+	if _, err := resolveInfrastructureParameters(ctx, c, gw); err != nil {
+		var invalid *invalidParametersError
+		if !errors.As(err, &invalid) {
+			return err
+		}
+		setGatewayInvalidParameters(gw, invalid)
+		return nil
+	}
+	refErrors, err := resolveCertificateRefs(ctx, c, gw)
+	if err != nil {
+		return err
+	}
+	gw.Status.Addresses = nil
+	setGatewayAccepted(gw, metav1.Condition{
+		Type:    string(gatewayv1.GatewayConditionProgrammed),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(gatewayv1.GatewayReasonAddressNotAssigned),
+		Message: "Gateway translated without a cluster has no address",
+	}, refErrors)
+	return nil
+}
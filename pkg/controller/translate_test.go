@@ -0,0 +1,110 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// offlineClient returns an in-memory client holding objects, standing in for
+// the reader the translate and validate subcommands build.
+func offlineClient(scheme *runtime.Scheme, objects []client.Object) client.Client {
+	return withIndexes(fake.NewClientBuilder().WithScheme(scheme)).WithObjects(objects...).Build()
+}
+
+func TestTranslate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	newRoute := func(name, backend string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}}},
+				Hostnames:       []gatewayv1.Hostname{gatewayv1.Hostname(name + ".example.com")},
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(backend), Port: ptr(gatewayv1.PortNumber(80))},
+				}}}}},
+			},
+		}
+	}
+	objects := []client.Object{
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+		},
+		newRoute("web", "web"),
+		newRoute("broken", "missing"),
+	}
+
+	translation, err := Translate(context.Background(), offlineClient(scheme, objects), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(translation.Routes) != 2 {
+		t.Fatalf("expected both accepted routes to be served, got %+v", translation.Routes)
+	}
+
+	if len(translation.GatewayClasses) != 1 ||
+		!meta.IsStatusConditionTrue(translation.GatewayClasses[0].Status.Conditions, string(gatewayv1.GatewayClassConditionStatusAccepted)) {
+		t.Errorf("expected the GatewayClass to be accepted, got %+v", translation.GatewayClasses)
+	}
+	if len(translation.Gateways) != 1 {
+		t.Fatalf("expected one Gateway, got %+v", translation.Gateways)
+	}
+	gw := translation.Gateways[0]
+	if !meta.IsStatusConditionTrue(gw.Status.Conditions, string(gatewayv1.GatewayConditionAccepted)) ||
+		meta.IsStatusConditionTrue(gw.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed)) {
+		t.Errorf("expected the Gateway to be accepted and waiting for an address, got %+v", gw.Status.Conditions)
+	}
+	if len(gw.Status.Listeners) != 1 || !meta.IsStatusConditionTrue(gw.Status.Listeners[0].Conditions, string(gatewayv1.ListenerConditionAccepted)) {
+		t.Errorf("expected the listener to be accepted, got %+v", gw.Status.Listeners)
+	}
+	for _, route := range translation.HTTPRoutes {
+		resolved := meta.IsStatusConditionTrue(route.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+		if resolved != (route.Name == "web") {
+			t.Errorf("expected only the route to an existing Service to resolve its refs, got %s: %+v", route.Name, route.Status.Parents)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Finding is a problem found by Validate in an object of the manifests.
+type Finding struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s %s: %s", f.Kind, types.NamespacedName{Namespace: f.Namespace, Name: f.Name}, f.Message)
+}
+
+// Validate applies the checks of the admission webhooks to the objects held by
+// c, read from manifests as for Translate, along with the resolution of their
+// references among them: the Gateways in the parentRefs of HTTPRoutes, their
+// backendRefs, and the certificateRefs of listeners. Only the Gateways of the
+// GatewayClasses of controllerName, or DefaultControllerName if empty, and the
+// routes attached to them are checked, as the webhooks do, except for
+// parentRefs to Gateways missing from the manifests.
+func Validate(ctx context.Context, c client.Reader, controllerName gatewayv1.GatewayController) ([]Finding, error) {
+	controllerName = controllerNameOrDefault(controllerName)
+	var findings []Finding
+
+	var gateways gatewayv1.GatewayList
+	if err := c.List(ctx, &gateways); err != nil {
+		return nil, err
+	}
+	for i := range gateways.Items {
+		gw := &gateways.Items[i]
+		finding := func(format string, args ...any) {
+			findings = append(findings, Finding{Kind: string(kindGateway), Namespace: gw.Namespace, Name: gw.Name, Message: fmt.Sprintf(format, args...)})
+		}
+		managed, err := managesGatewayClass(ctx, c, controllerName, gw.Spec.GatewayClassName)
+		if err != nil {
+			return nil, err
+		}
+		if !managed {
+			continue
+		}
+		if err := validateGateway(ctx, c, gw); err != nil {
+			finding("%v", err)
+		}
+		refErrors, err := resolveCertificateRefs(ctx, c, gw)
+		if err != nil {
+			return nil, err
+		}
+		for _, listener := range gw.Spec.Listeners {
+			if refErr := refErrors[listener.Name]; refErr != nil {
+				finding("listener %s: %s", listener.Name, refErr.message)
+			}
+		}
+	}
+
+	targets, err := listBackendTargets(ctx, c, false, ir.BackendOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var routes gatewayv1.HTTPRouteList
+	if err := c.List(ctx, &routes); err != nil {
+		return nil, err
+	}
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		finding := func(format string, args ...any) {
+			findings = append(findings, Finding{Kind: string(kindHTTPRoute), Namespace: route.Namespace, Name: route.Name, Message: fmt.Sprintf(format, args...)})
+		}
+		for _, parentRef := range route.Spec.ParentRefs {
+			if (parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName) || (parentRef.Kind != nil && *parentRef.Kind != kindGateway) {
+				continue
+			}
+			key := ir.ParentGateway(route.Namespace, parentRef)
+			if err := c.Get(ctx, key, &gatewayv1.Gateway{}); err != nil {
+				if err := client.IgnoreNotFound(err); err != nil {
+					return nil, err
+				}
+				finding("parentRef %s: Gateway %s not found", parentRef.Name, key)
+			}
+		}
+		managed, err := routeHasManagedParent(ctx, c, controllerName, route)
+		if err != nil {
+			return nil, err
+		}
+		if !managed {
+			continue
+		}
+		if err := ir.ValidateRoute(route); err != nil {
+			finding("%v", err)
+		}
+		if resolved := resolvedRefsCondition(route, targets); resolved.Status != metav1.ConditionTrue {
+			finding("%s", resolved.Message)
+		}
+	}
+	return findings, nil
+}
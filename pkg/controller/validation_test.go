@@ -0,0 +1,120 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestValidateControllerName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: DefaultControllerName, valid: true},
+		{name: "example.com/gateway-controller", valid: true},
+		{name: "example.com/team-a/gateway", valid: true},
+		{name: "example.com", valid: false},
+		{name: "/gateway-controller", valid: false},
+		{name: "Example.com/gateway-controller", valid: false},
+		{name: "example.com/" + strings.Repeat("a", 250), valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateControllerName(tt.name)
+			if (err == nil) != tt.valid {
+				t.Errorf("expected valid=%v, got error %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := gatewayv1.Install(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	newRoute := func(name, gateway, backend string, hostname gatewayv1.Hostname) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway)}}},
+				Hostnames:       []gatewayv1.Hostname{hostname},
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(backend), Port: ptr(gatewayv1.PortNumber(80))},
+				}}}}},
+			},
+		}
+	}
+	objects := []client.Object{
+		&gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "ours"},
+			Spec:       gatewayv1.GatewayClassSpec{ControllerName: DefaultControllerName},
+		},
+		&gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners: []gatewayv1.Listener{{
+					Name: "https", Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+					TLS: &gatewayv1.ListenerTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "missing-cert"}}},
+				}},
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+		},
+		newRoute("web", "gw", "web", "web.example.com"),
+		newRoute("invalid", "gw", "missing", "192.0.2.1"),
+		newRoute("orphan", "other", "web", "web.example.com"),
+	}
+
+	findings, err := Validate(context.Background(), offlineClient(scheme, objects), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var actual []string
+	for _, finding := range findings {
+		actual = append(actual, finding.String())
+	}
+	expected := []string{
+		"Gateway apps/gw: listener https: certificateRef missing-cert: Secret not found",
+		`HTTPRoute apps/invalid: hostname "192.0.2.1" must not be an IP address`,
+		"HTTPRoute apps/invalid: rule 0: backendRef missing: Service not found",
+		"HTTPRoute apps/orphan: parentRef other: Gateway apps/other not found",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected findings %q, got %q", expected, actual)
+	}
+}
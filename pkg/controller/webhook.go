@@ -0,0 +1,181 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/ir"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// HTTPRouteValidator rejects, at admission time, HTTPRoutes attached to our
+// Gateways whose configuration the proxy cannot serve. Routes that are only
+// attached to Gateways of other controllers are always admitted.
+type HTTPRouteValidator struct {
+	client.Client
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+}
+
+func (v *HTTPRouteValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&gatewayv1.HTTPRoute{}).
+		WithValidator(v).
+		Complete()
+}
+
+func (v *HTTPRouteValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(ctx, obj)
+}
+
+func (v *HTTPRouteValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(ctx, newObj)
+}
+
+func (v *HTTPRouteValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *HTTPRouteValidator) validate(ctx context.Context, obj runtime.Object) error {
+	route, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return fmt.Errorf("expected an HTTPRoute, got %T", obj)
+	}
+	managed, err := routeHasManagedParent(ctx, v.Client, controllerNameOrDefault(v.ControllerName), route)
+	if err != nil || !managed {
+		return err
+	}
+	if err := ir.ValidateRoute(route); err != nil {
+		return fmt.Errorf("HTTPRoute cannot be served by %s: %w", controllerNameOrDefault(v.ControllerName), err)
+	}
+	return nil
+}
+
+// routeHasManagedParent reports whether any parentRef of the route is a
+// Gateway of a GatewayClass of controllerName. Gateways that do not exist yet
+// are ignored.
+func routeHasManagedParent(ctx context.Context, c client.Reader, controllerName gatewayv1.GatewayController, route *gatewayv1.HTTPRoute) (bool, error) {
+	for _, parentRef := range route.Spec.ParentRefs {
+		if (parentRef.Group != nil && *parentRef.Group != gatewayv1.GroupName) || (parentRef.Kind != nil && *parentRef.Kind != kindGateway) {
+			continue
+		}
+		namespace := route.Namespace
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+		var gw gatewayv1.Gateway
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)}, &gw); err != nil {
+			if err := client.IgnoreNotFound(err); err != nil {
+				return false, err
+			}
+			continue
+		}
+		managed, err := managesGatewayClass(ctx, c, controllerName, gw.Spec.GatewayClassName)
+		if err != nil || managed {
+			return managed, err
+		}
+	}
+	return false, nil
+}
+
+// managesGatewayClass reports whether the named GatewayClass is served by
+// controllerName.
+func managesGatewayClass(ctx context.Context, c client.Reader, controllerName gatewayv1.GatewayController, name gatewayv1.ObjectName) (bool, error) {
+	var gc gatewayv1.GatewayClass
+	if err := c.Get(ctx, client.ObjectKey{Name: string(name)}, &gc); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return gc.Spec.ControllerName == controllerName, nil
+}
+
+// GatewayValidator rejects, at admission time, Gateways of our GatewayClasses
+// whose listeners or infrastructure parameters the controller cannot serve.
+type GatewayValidator struct {
+	client.Client
+	// ControllerName is the controllerName of the GatewayClasses served, or
+	// DefaultControllerName if empty.
+	ControllerName gatewayv1.GatewayController
+}
+
+func (v *GatewayValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&gatewayv1.Gateway{}).
+		WithValidator(v).
+		Complete()
+}
+
+func (v *GatewayValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(ctx, obj)
+}
+
+func (v *GatewayValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(ctx, newObj)
+}
+
+func (v *GatewayValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *GatewayValidator) validate(ctx context.Context, obj runtime.Object) error {
+	gw, ok := obj.(*gatewayv1.Gateway)
+	if !ok {
+		return fmt.Errorf("expected a Gateway, got %T", obj)
+	}
+	managed, err := managesGatewayClass(ctx, v.Client, controllerNameOrDefault(v.ControllerName), gw.Spec.GatewayClassName)
+	if err != nil || !managed {
+		return err
+	}
+	if err := validateGateway(ctx, v.Client, gw); err != nil {
+		return fmt.Errorf("Gateway cannot be served by %s: %w", controllerNameOrDefault(v.ControllerName), err)
+	}
+	return nil
+}
+
+// validateGateway reports the first listener of the Gateway that the proxy
+// cannot serve, or invalid infrastructure parameters.
+func validateGateway(ctx context.Context, c client.Reader, gw *gatewayv1.Gateway) error {
+	for i := range gw.Spec.Listeners {
+		listener := &gw.Spec.Listeners[i]
+		if _, ok := ir.ProtocolRouteKinds[listener.Protocol]; !ok {
+			return fmt.Errorf("listener %s: protocol %s is not supported", listener.Name, listener.Protocol)
+		}
+		if _, invalid := ir.ListenerSupportedKinds(listener); len(invalid) > 0 {
+			var names []string
+			for _, rgk := range invalid {
+				names = append(names, string(rgk.Kind))
+			}
+			return fmt.Errorf("listener %s: route kinds %s are not supported on protocol %s", listener.Name, strings.Join(names, ", "), listener.Protocol)
+		}
+	}
+	if _, err := resolveInfrastructureParameters(ctx, c, gw); err != nil {
+		var invalid *invalidParametersError
+		if !errors.As(err, &invalid) {
+			return err
+		}
+		return fmt.Errorf("invalid infrastructure parameters: %s", invalid.message)
+	}
+	return nil
+}
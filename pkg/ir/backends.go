@@ -0,0 +1,497 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// KindBackend is the kind of the static destinations backendRefs can refer to
+// in addition to Services.
+const KindBackend gatewayv1.Kind = "Backend"
+
+// ServiceImportGVK is the Multi-Cluster Services API ServiceImport. Its types
+// are not vendored, so ServiceImports are read as unstructured objects.
+var ServiceImportGVK = schema.GroupVersionKind{Group: "multicluster.x-k8s.io", Version: "v1alpha1", Kind: "ServiceImport"}
+
+// InferencePoolGVK is the Gateway API Inference Extension InferencePool. Its
+// types are not vendored, so InferencePools are read as unstructured objects.
+var InferencePoolGVK = schema.GroupVersionKind{Group: "inference.networking.k8s.io", Version: "v1", Kind: "InferencePool"}
+
+// DefaultClusterDomain is the DNS domain of the cluster, under which Services
+// are named, unless BackendOptions sets another.
+const DefaultClusterDomain = "cluster.local"
+
+// BackendResolution selects the address the proxy forwards the requests for
+// a Service to.
+type BackendResolution string
+
+const (
+	// BackendResolutionServiceDNS forwards to the Service's DNS name,
+	// leaving the choice of an endpoint to the cluster's DNS and kube-proxy.
+	BackendResolutionServiceDNS BackendResolution = "ServiceDNS"
+	// BackendResolutionClusterIP forwards to the Service's cluster IP,
+	// sparing the proxy a DNS lookup. Headless and ExternalName Services are
+	// still reached by name.
+	BackendResolutionClusterIP BackendResolution = "ClusterIP"
+	// BackendResolutionEndpointSlice forwards to the ready endpoints of the
+	// Service, from its EndpointSlices, which the proxy balances requests
+	// across. A Service without ready endpoints is reached by name.
+	BackendResolutionEndpointSlice BackendResolution = "EndpointSlice"
+)
+
+// BackendResolutions are the supported values of BackendResolution.
+var BackendResolutions = []BackendResolution{BackendResolutionServiceDNS, BackendResolutionClusterIP, BackendResolutionEndpointSlice}
+
+// BackendOptions configures how the Services of backendRefs are reached.
+type BackendOptions struct {
+	// ClusterDomain is the DNS domain of the cluster, or DefaultClusterDomain
+	// if empty.
+	ClusterDomain string
+	// Resolution selects the address of a Service, or
+	// BackendResolutionServiceDNS if empty.
+	Resolution BackendResolution
+}
+
+// ServiceHost returns the DNS name of the Service with the given name in
+// clusterDomain, or in DefaultClusterDomain if empty.
+func ServiceHost(namespace, name, clusterDomain string) string {
+	if clusterDomain == "" {
+		clusterDomain = DefaultClusterDomain
+	}
+	return fmt.Sprintf("%s.%s.svc.%s", name, namespace, clusterDomain)
+}
+
+// serviceBackend returns the destination of a port of a Service, given the
+// EndpointSlices of the Service.
+func (o BackendOptions) serviceBackend(service *corev1.Service, port corev1.ServicePort, endpointSlices []*discoveryv1.EndpointSlice) proxy.Backend {
+	backend := proxy.Backend{Host: ServiceHost(service.Namespace, service.Name, o.ClusterDomain), Port: port.Port}
+	switch o.Resolution {
+	case BackendResolutionClusterIP:
+		if ip := service.Spec.ClusterIP; ip != "" && ip != corev1.ClusterIPNone {
+			backend.Host = ip
+		}
+	case BackendResolutionEndpointSlice:
+		backend.Endpoints = readyEndpoints(port, endpointSlices)
+	}
+	return backend
+}
+
+// readyEndpoints returns the host:port addresses of the ready endpoints of
+// a Service port, sorted.
+func readyEndpoints(port corev1.ServicePort, endpointSlices []*discoveryv1.EndpointSlice) []string {
+	var endpoints []string
+	for _, slice := range endpointSlices {
+		// The ports of EndpointSlices are named after those of their
+		// Service.
+		var targetPort *int32
+		for _, p := range slice.Ports {
+			if p.Port != nil && (p.Name == nil && port.Name == "" || p.Name != nil && *p.Name == port.Name) {
+				targetPort = p.Port
+			}
+		}
+		if targetPort == nil {
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready || len(endpoint.Addresses) == 0 {
+				continue
+			}
+			endpoints = append(endpoints, net.JoinHostPort(endpoint.Addresses[0], strconv.Itoa(int(*targetPort))))
+		}
+	}
+	slices.Sort(endpoints)
+	return slices.Compact(endpoints)
+}
+
+// ServiceImport holds the fields of a ServiceImport that backendRefs are
+// resolved against.
+type ServiceImport struct {
+	// IPs are the clusterset IPs allocated to the import. They are empty for
+	// headless imports and until the MCS implementation allocates one.
+	IPs      []string
+	Ports    []int32
+	Headless bool
+}
+
+// ParseServiceImport extracts the fields of a ServiceImport we resolve
+// backendRefs against.
+func ParseServiceImport(obj *unstructured.Unstructured) *ServiceImport {
+	si := &ServiceImport{}
+	si.IPs, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "ips")
+	importType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+	si.Headless = importType == "Headless"
+	ports, _, _ := unstructured.NestedSlice(obj.Object, "spec", "ports")
+	for _, p := range ports {
+		port, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		if number, ok, _ := unstructured.NestedInt64(port, "port"); ok {
+			si.Ports = append(si.Ports, int32(number))
+		}
+	}
+	return si
+}
+
+// InferencePool holds the fields of an InferencePool that backendRefs are
+// resolved against.
+type InferencePool struct {
+	Selector    map[string]string
+	TargetPorts []int32
+	// PickerService and PickerPort locate the endpoint picker Service in the
+	// pool's namespace.
+	PickerService string
+	PickerPort    int32
+	FailOpen      bool
+	// Endpoints are the host:port addresses of the ready Pods selected by the
+	// pool, on each of its target ports. They are not part of the
+	// InferencePool, and are filled in by the caller.
+	Endpoints []string
+}
+
+// ParseInferencePool extracts the fields of an InferencePool we resolve
+// backendRefs against.
+func ParseInferencePool(obj *unstructured.Unstructured) *InferencePool {
+	pool := &InferencePool{}
+	pool.Selector, _, _ = unstructured.NestedStringMap(obj.Object, "spec", "selector", "matchLabels")
+	targetPorts, _, _ := unstructured.NestedSlice(obj.Object, "spec", "targetPorts")
+	for _, p := range targetPorts {
+		port, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		if number, ok, _ := unstructured.NestedInt64(port, "number"); ok {
+			pool.TargetPorts = append(pool.TargetPorts, int32(number))
+		}
+	}
+	pool.PickerService, _, _ = unstructured.NestedString(obj.Object, "spec", "endpointPickerRef", "name")
+	if number, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "endpointPickerRef", "port", "number"); ok {
+		pool.PickerPort = int32(number)
+	}
+	failureMode, _, _ := unstructured.NestedString(obj.Object, "spec", "endpointPickerRef", "failureMode")
+	pool.FailOpen = failureMode == "FailOpen"
+	return pool
+}
+
+// BackendTLSKey identifies a Service, or one of its ports by name, targeted
+// by a BackendTLSPolicy.
+type BackendTLSKey struct {
+	Service types.NamespacedName
+	Port    string
+}
+
+// serviceBackendTLS returns the TLS settings for a port of a Service: those of
+// a policy targeting the port by name, or else the whole Service.
+func serviceBackendTLS(settings map[BackendTLSKey]*proxy.BackendTLS, service types.NamespacedName, port corev1.ServicePort) *proxy.BackendTLS {
+	if port.Name != "" {
+		if tls, ok := settings[BackendTLSKey{Service: service, Port: port.Name}]; ok {
+			return tls
+		}
+	}
+	return settings[BackendTLSKey{Service: service}]
+}
+
+// Targets holds the objects that backendRefs are resolved against, keyed by
+// name. Lookups of missing objects fail, so the maps need only hold those the
+// routes being translated refer to.
+type Targets struct {
+	// Options configures how Services are reached.
+	Options BackendOptions
+	// EndpointSlices holds the EndpointSlices of each Service. It is only
+	// used with BackendResolutionEndpointSlice.
+	EndpointSlices map[types.NamespacedName][]*discoveryv1.EndpointSlice
+
+	Services       map[types.NamespacedName]*corev1.Service
+	Backends       map[types.NamespacedName]*v1alpha1.Backend
+	ServiceImports map[types.NamespacedName]*ServiceImport
+	InferencePools map[types.NamespacedName]*InferencePool
+	// BackendTLS holds the BackendTLSPolicy settings of Services.
+	BackendTLS map[BackendTLSKey]*proxy.BackendTLS
+	// ReferenceGrants holds the ReferenceGrants of the namespaces referred
+	// to by backendRefs to other namespaces, which they must permit.
+	ReferenceGrants []gatewayv1beta1.ReferenceGrant
+}
+
+// RefError describes why a backendRef cannot be resolved.
+type RefError struct {
+	Reason  gatewayv1.RouteConditionReason
+	Message string
+}
+
+// IsBackendRef reports whether ref refers to an object of the given group and
+// kind, where an empty group and kind default to a core Service.
+func IsBackendRef(ref gatewayv1.BackendObjectReference, group gatewayv1.Group, kind gatewayv1.Kind) bool {
+	refGroup, refKind := gatewayv1.Group(""), gatewayv1.Kind("Service")
+	if ref.Group != nil {
+		refGroup = *ref.Group
+	}
+	if ref.Kind != nil {
+		refKind = *ref.Kind
+	}
+	return refGroup == group && refKind == kind
+}
+
+// referenceGranted reports whether a ReferenceGrant among grants permits the
+// HTTPRoutes in namespace to refer to the object of ref, in another
+// namespace.
+func referenceGranted(namespace string, ref gatewayv1.BackendObjectReference, grants []gatewayv1beta1.ReferenceGrant) bool {
+	group, kind := gatewayv1.Group(""), gatewayv1.Kind("Service")
+	if ref.Group != nil {
+		group = *ref.Group
+	}
+	if ref.Kind != nil {
+		kind = *ref.Kind
+	}
+	for _, grant := range grants {
+		if grant.Namespace != string(*ref.Namespace) {
+			continue
+		}
+		from := slices.ContainsFunc(grant.Spec.From, func(from gatewayv1beta1.ReferenceGrantFrom) bool {
+			return from.Group == gatewayv1.GroupName && from.Kind == kindHTTPRoute && string(from.Namespace) == namespace
+		})
+		to := slices.ContainsFunc(grant.Spec.To, func(to gatewayv1beta1.ReferenceGrantTo) bool {
+			return to.Group == group && to.Kind == kind && (to.Name == nil || *to.Name == ref.Name)
+		})
+		if from && to {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveBackendRef checks that a backendRef of a route in namespace refers to
+// an existing Service or ServiceImport port, Backend or InferencePool the
+// route may reference, and returns the destination requests are forwarded to.
+// References to other namespaces must be permitted by a ReferenceGrant.
+func ResolveBackendRef(namespace string, ref gatewayv1.BackendObjectReference, targets Targets) (proxy.Backend, *RefError) {
+	isService := IsBackendRef(ref, "", "Service")
+	isBackend := IsBackendRef(ref, gatewayv1.Group(v1alpha1.GroupVersion.Group), KindBackend)
+	isServiceImport := IsBackendRef(ref, gatewayv1.Group(ServiceImportGVK.Group), gatewayv1.Kind(ServiceImportGVK.Kind))
+	isInferencePool := IsBackendRef(ref, gatewayv1.Group(InferencePoolGVK.Group), gatewayv1.Kind(InferencePoolGVK.Kind))
+	if !isService && !isBackend && !isServiceImport && !isInferencePool {
+		return proxy.Backend{}, &RefError{
+			Reason:  gatewayv1.RouteReasonInvalidKind,
+			Message: fmt.Sprintf("backendRef %s: unsupported kind", ref.Name),
+		}
+	}
+	if ref.Namespace != nil && string(*ref.Namespace) != namespace {
+		if !referenceGranted(namespace, ref, targets.ReferenceGrants) {
+			return proxy.Backend{}, &RefError{
+				Reason:  gatewayv1.RouteReasonRefNotPermitted,
+				Message: fmt.Sprintf("backendRef %s: no ReferenceGrant in namespace %s permits the reference", ref.Name, *ref.Namespace),
+			}
+		}
+		namespace = string(*ref.Namespace)
+	}
+	key := types.NamespacedName{Namespace: namespace, Name: string(ref.Name)}
+
+	if isBackend {
+		backend, ok := targets.Backends[key]
+		if !ok {
+			return proxy.Backend{}, &RefError{
+				Reason:  gatewayv1.RouteReasonBackendNotFound,
+				Message: fmt.Sprintf("backendRef %s: Backend not found", ref.Name),
+			}
+		}
+		// The Backend carries its own port; a port on the backendRef must
+		// agree with it.
+		if ref.Port != nil && int32(*ref.Port) != backend.Spec.Port {
+			return proxy.Backend{}, &RefError{
+				Reason:  gatewayv1.RouteReasonBackendNotFound,
+				Message: fmt.Sprintf("backendRef %s: Backend has no port %d", ref.Name, *ref.Port),
+			}
+		}
+		resolved := proxy.Backend{Host: backend.Spec.Host, Port: backend.Spec.Port}
+		if backend.Spec.TLS != nil {
+			resolved.TLS = &proxy.BackendTLS{}
+			if backend.Spec.TLS.ServerName != nil {
+				resolved.TLS.ServerName = *backend.Spec.TLS.ServerName
+			}
+		}
+		return resolved, nil
+	}
+
+	if isServiceImport {
+		return resolveServiceImportRef(key, ref, targets.ServiceImports[key])
+	}
+	if isInferencePool {
+		return resolveInferencePoolRef(key, ref, targets.InferencePools[key], targets.Services, targets.Options.ClusterDomain)
+	}
+
+	service, ok := targets.Services[key]
+	if !ok {
+		return proxy.Backend{}, &RefError{
+			Reason:  gatewayv1.RouteReasonBackendNotFound,
+			Message: fmt.Sprintf("backendRef %s: Service not found", ref.Name),
+		}
+	}
+	if ref.Port == nil {
+		return proxy.Backend{}, &RefError{
+			Reason:  gatewayv1.RouteReasonUnsupportedValue,
+			Message: fmt.Sprintf("backendRef %s: port is required", ref.Name),
+		}
+	}
+	for _, port := range service.Spec.Ports {
+		if port.Port == int32(*ref.Port) {
+			backend := targets.Options.serviceBackend(service, port, targets.EndpointSlices[key])
+			backend.TLS = serviceBackendTLS(targets.BackendTLS, key, port)
+			return backend, nil
+		}
+	}
+	return proxy.Backend{}, &RefError{
+		Reason:  gatewayv1.RouteReasonBackendNotFound,
+		Message: fmt.Sprintf("backendRef %s: Service has no port %d", ref.Name, *ref.Port),
+	}
+}
+
+// resolveServiceImportRef returns the destination of a backendRef to a
+// ServiceImport: its clusterset IP, or its clusterset DNS name if the import
+// is headless.
+func resolveServiceImportRef(key types.NamespacedName, ref gatewayv1.BackendObjectReference, si *ServiceImport) (proxy.Backend, *RefError) {
+	if si == nil {
+		return proxy.Backend{}, &RefError{
+			Reason:  gatewayv1.RouteReasonBackendNotFound,
+			Message: fmt.Sprintf("backendRef %s: ServiceImport not found", ref.Name),
+		}
+	}
+	if ref.Port == nil {
+		return proxy.Backend{}, &RefError{
+			Reason:  gatewayv1.RouteReasonUnsupportedValue,
+			Message: fmt.Sprintf("backendRef %s: port is required", ref.Name),
+		}
+	}
+	if !slices.Contains(si.Ports, int32(*ref.Port)) {
+		return proxy.Backend{}, &RefError{
+			Reason:  gatewayv1.RouteReasonBackendNotFound,
+			Message: fmt.Sprintf("backendRef %s: ServiceImport has no port %d", ref.Name, *ref.Port),
+		}
+	}
+	switch {
+	case si.Headless:
+		return proxy.Backend{Host: fmt.Sprintf("%s.%s.svc.clusterset.local", key.Name, key.Namespace), Port: int32(*ref.Port)}, nil
+	case len(si.IPs) > 0:
+		return proxy.Backend{Host: si.IPs[0], Port: int32(*ref.Port)}, nil
+	}
+	return proxy.Backend{}, &RefError{
+		Reason:  gatewayv1.RouteReasonBackendNotFound,
+		Message: fmt.Sprintf("backendRef %s: ServiceImport has no clusterset IP yet", ref.Name),
+	}
+}
+
+// resolveInferencePoolRef returns the destination of a backendRef to an
+// InferencePool, whose endpoint picker selects the endpoint of each request.
+func resolveInferencePoolRef(key types.NamespacedName, ref gatewayv1.BackendObjectReference, pool *InferencePool, services map[types.NamespacedName]*corev1.Service, clusterDomain string) (proxy.Backend, *RefError) {
+	if pool == nil {
+		return proxy.Backend{}, &RefError{
+			Reason:  gatewayv1.RouteReasonBackendNotFound,
+			Message: fmt.Sprintf("backendRef %s: InferencePool not found", ref.Name),
+		}
+	}
+	if pool.PickerService == "" || pool.PickerPort == 0 || len(pool.TargetPorts) == 0 {
+		return proxy.Backend{}, &RefError{
+			Reason:  gatewayv1.RouteReasonUnsupportedValue,
+			Message: fmt.Sprintf("backendRef %s: InferencePool needs target ports and an endpoint picker Service and port", ref.Name),
+		}
+	}
+	if _, ok := services[types.NamespacedName{Namespace: key.Namespace, Name: pool.PickerService}]; !ok {
+		return proxy.Backend{}, &RefError{
+			Reason:  gatewayv1.RouteReasonBackendNotFound,
+			Message: fmt.Sprintf("backendRef %s: endpoint picker Service %s not found", ref.Name, pool.PickerService),
+		}
+	}
+	return proxy.Backend{
+		Port: pool.TargetPorts[0],
+		EndpointPicker: &proxy.EndpointPicker{
+			Address:   net.JoinHostPort(ServiceHost(key.Namespace, pool.PickerService, clusterDomain), strconv.Itoa(int(pool.PickerPort))),
+			FailOpen:  pool.FailOpen,
+			Endpoints: pool.Endpoints,
+		},
+	}, nil
+}
+
+// SupportedBackendFilters are the filter types that may be set on a
+// backendRef.
+var SupportedBackendFilters = []gatewayv1.HTTPRouteFilterType{
+	gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+	gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+	gatewayv1.HTTPRouteFilterRequestMirror,
+}
+
+// BackendFilters translates the filters of a backendRef of a route in
+// namespace. Mirrors to unresolved backends are skipped; they are reported in
+// the ResolvedRefs condition.
+func BackendFilters(namespace string, filters []gatewayv1.HTTPRouteFilter, targets Targets) proxy.Filters {
+	var translated proxy.Filters
+	for _, filter := range filters {
+		switch {
+		case filter.Type == gatewayv1.HTTPRouteFilterRequestHeaderModifier && filter.RequestHeaderModifier != nil:
+			translated.RequestHeaders = headerModifier(filter.RequestHeaderModifier)
+		case filter.Type == gatewayv1.HTTPRouteFilterResponseHeaderModifier && filter.ResponseHeaderModifier != nil:
+			translated.ResponseHeaders = headerModifier(filter.ResponseHeaderModifier)
+		case filter.Type == gatewayv1.HTTPRouteFilterRequestMirror && filter.RequestMirror != nil:
+			backend, err := ResolveBackendRef(namespace, filter.RequestMirror.BackendRef, targets)
+			if err != nil || backend.EndpointPicker != nil {
+				continue
+			}
+			translated.Mirrors = append(translated.Mirrors, proxy.Mirror{Backend: backend, Fraction: mirrorFraction(filter.RequestMirror)})
+		}
+	}
+	return translated
+}
+
+func headerModifier(filter *gatewayv1.HTTPHeaderFilter) *proxy.HeaderModifier {
+	modifier := &proxy.HeaderModifier{Remove: filter.Remove}
+	for _, h := range filter.Set {
+		modifier.Set = append(modifier.Set, proxy.Header{Name: string(h.Name), Value: h.Value})
+	}
+	for _, h := range filter.Add {
+		modifier.Add = append(modifier.Add, proxy.Header{Name: string(h.Name), Value: h.Value})
+	}
+	return modifier
+}
+
+// mirrorFraction returns the share of requests a mirror filter copies. All
+// requests are mirrored unless a percent or fraction is set.
+func mirrorFraction(filter *gatewayv1.HTTPRequestMirrorFilter) float64 {
+	switch {
+	case filter.Percent != nil:
+		return float64(*filter.Percent) / 100
+	case filter.Fraction != nil:
+		denominator := int32(100)
+		if filter.Fraction.Denominator != nil {
+			denominator = *filter.Fraction.Denominator
+		}
+		if denominator <= 0 {
+			return 0
+		}
+		return float64(filter.Fraction.Numerator) / float64(denominator)
+	}
+	return 1
+}
@@ -0,0 +1,409 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/apis/v1alpha1"
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestResolveBackendRefBackend(t *testing.T) {
+	targets := Targets{Backends: map[types.NamespacedName]*v1alpha1.Backend{
+		{Namespace: "default", Name: "external"}: {Spec: v1alpha1.BackendSpec{
+			Host: "api.example.com",
+			Port: 443,
+			TLS:  &v1alpha1.BackendTLS{ServerName: ptr("internal.example.com")},
+		}},
+	}}
+	ref := gatewayv1.BackendObjectReference{
+		Name:  "external",
+		Group: ptr(gatewayv1.Group("gari.gke-labs.dev")),
+		Kind:  ptr(gatewayv1.Kind("Backend")),
+	}
+
+	actual, err := ResolveBackendRef("default", ref, targets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err.Message)
+	}
+	expected := proxy.Backend{Host: "api.example.com", Port: 443, TLS: &proxy.BackendTLS{ServerName: "internal.example.com"}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestResolveBackendRefResolution(t *testing.T) {
+	service := newService("default", "web", 80)
+	service.Spec.Ports[0].Name = "http"
+	service.Spec.ClusterIP = "10.96.0.10"
+	key := types.NamespacedName{Namespace: "default", Name: "web"}
+	slice := &discoveryv1.EndpointSlice{
+		Ports: []discoveryv1.EndpointPort{{Name: ptr("http"), Port: ptr(int32(8080))}},
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.2"}},
+			{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr(true)}},
+			{Addresses: []string{"10.0.0.3"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr(false)}},
+		},
+	}
+	ref := gatewayv1.BackendObjectReference{Name: "web", Port: ptr(gatewayv1.PortNumber(80))}
+
+	tests := []struct {
+		name     string
+		options  BackendOptions
+		expected proxy.Backend
+	}{
+		{
+			name:     "Service DNS in the default domain",
+			expected: proxy.Backend{Host: "web.default.svc.cluster.local", Port: 80},
+		},
+		{
+			name:     "Service DNS in a custom domain",
+			options:  BackendOptions{ClusterDomain: "corp.internal"},
+			expected: proxy.Backend{Host: "web.default.svc.corp.internal", Port: 80},
+		},
+		{
+			name:     "cluster IP",
+			options:  BackendOptions{Resolution: BackendResolutionClusterIP},
+			expected: proxy.Backend{Host: "10.96.0.10", Port: 80},
+		},
+		{
+			name:     "ready endpoints",
+			options:  BackendOptions{Resolution: BackendResolutionEndpointSlice},
+			expected: proxy.Backend{Host: "web.default.svc.cluster.local", Port: 80, Endpoints: []string{"10.0.0.1:8080", "10.0.0.2:8080"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets := Targets{
+				Options:        tt.options,
+				Services:       map[types.NamespacedName]*corev1.Service{key: service},
+				EndpointSlices: map[types.NamespacedName][]*discoveryv1.EndpointSlice{key: {slice}},
+			}
+			actual, err := ResolveBackendRef("default", ref, targets)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err.Message)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBackendFilters(t *testing.T) {
+	targets := Targets{Services: map[types.NamespacedName]*corev1.Service{
+		{Namespace: "default", Name: "shadow"}: newService("default", "shadow", 80),
+	}}
+	mirror := func(name string, percent int32) gatewayv1.HTTPRouteFilter {
+		return gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterRequestMirror,
+			RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+				BackendRef: gatewayv1.BackendObjectReference{Name: gatewayv1.ObjectName(name), Port: ptr(gatewayv1.PortNumber(80))},
+				Percent:    &percent,
+			},
+		}
+	}
+	filters := []gatewayv1.HTTPRouteFilter{
+		{
+			Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+			RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+				Set:    []gatewayv1.HTTPHeader{{Name: "X-Canary", Value: "true"}},
+				Remove: []string{"X-Debug"},
+			},
+		},
+		{
+			Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{Add: []gatewayv1.HTTPHeader{{Name: "X-Served-By", Value: "canary"}}},
+		},
+		mirror("shadow", 25),
+		mirror("missing", 100),
+	}
+
+	expected := proxy.Filters{
+		RequestHeaders:  &proxy.HeaderModifier{Set: []proxy.Header{{Name: "X-Canary", Value: "true"}}, Remove: []string{"X-Debug"}},
+		ResponseHeaders: &proxy.HeaderModifier{Add: []proxy.Header{{Name: "X-Served-By", Value: "canary"}}},
+		Mirrors:         []proxy.Mirror{{Backend: proxy.Backend{Host: "shadow.default.svc.cluster.local", Port: 80}, Fraction: 0.25}},
+	}
+	if actual := BackendFilters("default", filters, targets); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestResolveServiceImportRef(t *testing.T) {
+	newImport := func(spec map[string]any) *ServiceImport {
+		return ParseServiceImport(&unstructured.Unstructured{Object: map[string]any{"spec": spec}})
+	}
+	ports := []any{map[string]any{"port": int64(80), "protocol": "TCP"}}
+	targets := Targets{ServiceImports: map[types.NamespacedName]*ServiceImport{
+		{Namespace: "default", Name: "clusterset"}: newImport(map[string]any{"type": "ClusterSetIP", "ips": []any{"10.10.0.1"}, "ports": ports}),
+		{Namespace: "default", Name: "headless"}:   newImport(map[string]any{"type": "Headless", "ports": ports}),
+		{Namespace: "default", Name: "pending"}:    newImport(map[string]any{"type": "ClusterSetIP", "ports": ports}),
+	}}
+
+	tests := []struct {
+		name           string
+		ref            string
+		port           gatewayv1.PortNumber
+		expected       proxy.Backend
+		expectedReason gatewayv1.RouteConditionReason
+	}{
+		{
+			name:     "clusterset IP",
+			ref:      "clusterset",
+			port:     80,
+			expected: proxy.Backend{Host: "10.10.0.1", Port: 80},
+		},
+		{
+			name:     "headless",
+			ref:      "headless",
+			port:     80,
+			expected: proxy.Backend{Host: "headless.default.svc.clusterset.local", Port: 80},
+		},
+		{
+			name:           "no clusterset IP yet",
+			ref:            "pending",
+			port:           80,
+			expectedReason: gatewayv1.RouteReasonBackendNotFound,
+		},
+		{
+			name:           "missing port",
+			ref:            "clusterset",
+			port:           8080,
+			expectedReason: gatewayv1.RouteReasonBackendNotFound,
+		},
+		{
+			name:           "missing import",
+			ref:            "missing",
+			port:           80,
+			expectedReason: gatewayv1.RouteReasonBackendNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := gatewayv1.BackendObjectReference{
+				Group: ptr(gatewayv1.Group("multicluster.x-k8s.io")),
+				Kind:  ptr(gatewayv1.Kind("ServiceImport")),
+				Name:  gatewayv1.ObjectName(tt.ref),
+				Port:  &tt.port,
+			}
+			actual, err := ResolveBackendRef("default", ref, targets)
+			if err != nil {
+				if err.Reason != tt.expectedReason {
+					t.Errorf("expected %v, got %v", tt.expectedReason, err.Reason)
+				}
+				return
+			}
+			if tt.expectedReason != "" {
+				t.Fatalf("expected %v, got %v", tt.expectedReason, actual)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResolveInferencePoolRef(t *testing.T) {
+	newPool := func(failureMode string) *InferencePool {
+		pool := ParseInferencePool(&unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{
+				"selector":    map[string]any{"matchLabels": map[string]any{"app": "vllm"}},
+				"targetPorts": []any{map[string]any{"number": int64(8000)}},
+				"endpointPickerRef": map[string]any{
+					"name":        "vllm-epp",
+					"port":        map[string]any{"number": int64(9002)},
+					"failureMode": failureMode,
+				},
+			},
+		}})
+		pool.Endpoints = []string{"10.0.0.5:8000"}
+		return pool
+	}
+	services := map[types.NamespacedName]*corev1.Service{
+		{Namespace: "default", Name: "vllm-epp"}: newService("default", "vllm-epp", 9002),
+	}
+
+	tests := []struct {
+		name           string
+		pool           *InferencePool
+		services       map[types.NamespacedName]*corev1.Service
+		expected       proxy.Backend
+		expectedReason gatewayv1.RouteConditionReason
+	}{
+		{
+			name:     "fail closed",
+			pool:     newPool("FailClose"),
+			services: services,
+			expected: proxy.Backend{Port: 8000, EndpointPicker: &proxy.EndpointPicker{
+				Address:   "vllm-epp.default.svc.cluster.local:9002",
+				Endpoints: []string{"10.0.0.5:8000"},
+			}},
+		},
+		{
+			name:     "fail open",
+			pool:     newPool("FailOpen"),
+			services: services,
+			expected: proxy.Backend{Port: 8000, EndpointPicker: &proxy.EndpointPicker{
+				Address:   "vllm-epp.default.svc.cluster.local:9002",
+				FailOpen:  true,
+				Endpoints: []string{"10.0.0.5:8000"},
+			}},
+		},
+		{
+			name:           "missing endpoint picker Service",
+			pool:           newPool("FailClose"),
+			expectedReason: gatewayv1.RouteReasonBackendNotFound,
+		},
+		{
+			name:           "missing pool",
+			services:       services,
+			expectedReason: gatewayv1.RouteReasonBackendNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets := Targets{Services: tt.services, InferencePools: map[types.NamespacedName]*InferencePool{}}
+			if tt.pool != nil {
+				targets.InferencePools[types.NamespacedName{Namespace: "default", Name: "vllm"}] = tt.pool
+			}
+			ref := gatewayv1.BackendObjectReference{
+				Group: ptr(gatewayv1.Group("inference.networking.k8s.io")),
+				Kind:  ptr(gatewayv1.Kind("InferencePool")),
+				Name:  "vllm",
+			}
+			actual, err := ResolveBackendRef("default", ref, targets)
+			if err != nil {
+				if err.Reason != tt.expectedReason {
+					t.Errorf("expected %v, got %v", tt.expectedReason, err.Reason)
+				}
+				return
+			}
+			if tt.expectedReason != "" {
+				t.Fatalf("expected %v, got %v", tt.expectedReason, actual)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func newService(namespace, name string, ports ...int32) *corev1.Service {
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	for _, port := range ports {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{Port: port})
+	}
+	return service
+}
+
+func TestResolveBackendRefReferenceGrant(t *testing.T) {
+	grant := func(namespace string, fromKind gatewayv1.Kind, fromNamespace string, to *gatewayv1.ObjectName) gatewayv1beta1.ReferenceGrant {
+		return gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "grant"},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: fromKind, Namespace: gatewayv1.Namespace(fromNamespace)}},
+				To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service", Name: to}},
+			},
+		}
+	}
+	ref := gatewayv1.BackendObjectReference{Name: "web", Namespace: ptr(gatewayv1.Namespace("backends")), Port: ptr(gatewayv1.PortNumber(80))}
+
+	tests := []struct {
+		name           string
+		grants         []gatewayv1beta1.ReferenceGrant
+		expectedReason gatewayv1.RouteConditionReason
+	}{
+		{
+			name:           "no grant",
+			expectedReason: gatewayv1.RouteReasonRefNotPermitted,
+		},
+		{
+			name:   "grant for every Service",
+			grants: []gatewayv1beta1.ReferenceGrant{grant("backends", "HTTPRoute", "default", nil)},
+		},
+		{
+			name:   "grant for the Service",
+			grants: []gatewayv1beta1.ReferenceGrant{grant("backends", "HTTPRoute", "default", ptr(gatewayv1.ObjectName("web")))},
+		},
+		{
+			name:           "grant for another Service",
+			grants:         []gatewayv1beta1.ReferenceGrant{grant("backends", "HTTPRoute", "default", ptr(gatewayv1.ObjectName("api")))},
+			expectedReason: gatewayv1.RouteReasonRefNotPermitted,
+		},
+		{
+			name:           "grant from another namespace",
+			grants:         []gatewayv1beta1.ReferenceGrant{grant("backends", "HTTPRoute", "other", nil)},
+			expectedReason: gatewayv1.RouteReasonRefNotPermitted,
+		},
+		{
+			name:           "grant for another kind of route",
+			grants:         []gatewayv1beta1.ReferenceGrant{grant("backends", "GRPCRoute", "default", nil)},
+			expectedReason: gatewayv1.RouteReasonRefNotPermitted,
+		},
+		{
+			name:           "grant in another namespace",
+			grants:         []gatewayv1beta1.ReferenceGrant{grant("default", "HTTPRoute", "default", nil)},
+			expectedReason: gatewayv1.RouteReasonRefNotPermitted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets := Targets{
+				Services:        map[types.NamespacedName]*corev1.Service{{Namespace: "backends", Name: "web"}: newService("backends", "web", 80)},
+				ReferenceGrants: tt.grants,
+			}
+			actual, err := ResolveBackendRef("default", ref, targets)
+			if err != nil {
+				if err.Reason != tt.expectedReason {
+					t.Errorf("expected %v, got %v: %s", tt.expectedReason, err.Reason, err.Message)
+				}
+				return
+			}
+			if tt.expectedReason != "" {
+				t.Fatalf("expected %v, got %v", tt.expectedReason, actual)
+			}
+			expected := proxy.Backend{Host: "web.backends.svc.cluster.local", Port: 80}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected %v, got %v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestServiceBackendTLS(t *testing.T) {
+	service := types.NamespacedName{Namespace: "default", Name: "api"}
+	settings := map[BackendTLSKey]*proxy.BackendTLS{
+		{Service: service}:               {ServerName: "api.example.com"},
+		{Service: service, Port: "grpc"}: {ServerName: "internal.example.com"},
+	}
+	if tls := serviceBackendTLS(settings, service, corev1.ServicePort{Name: "grpc"}); tls == nil || tls.ServerName != "internal.example.com" {
+		t.Errorf("expected the port's settings, got %v", tls)
+	}
+	if tls := serviceBackendTLS(settings, service, corev1.ServicePort{Name: "http"}); tls == nil || tls.ServerName != "api.example.com" {
+		t.Errorf("expected the Service's settings, got %v", tls)
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ir computes the intermediate representation of the configuration
+// of the data plane: what the controller serves for a set of Gateway API
+// objects, independent of the data plane serving it. The Go proxy serves its
+// routes as they are, and exporters such as the nginx one render them for
+// other data planes.
+//
+// The translation is made of pure functions of the objects involved: how
+// routes attach to listeners, how backendRefs resolve, and how a route
+// translates into the proxy configuration. Build puts them together. The
+// controller calls it on the objects it reads from the cluster, and the
+// translate command on objects read from files, so that both compute the same
+// configuration.
+package ir
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ShardLabel assigns a Gateway to the controller instances serving the shard
+// of the same name. Gateways without the label belong to the instances that
+// serve no particular shard.
+const ShardLabel = "gari.gke-labs.dev/shard"
+
+// InShard reports whether gw belongs to shard.
+func InShard(gw *gatewayv1.Gateway, shard string) bool {
+	return gw.Labels[ShardLabel] == shard
+}
+
+// Input holds the objects the configuration is computed from.
+type Input struct {
+	// ControllerName selects the GatewayClasses served.
+	ControllerName gatewayv1.GatewayController
+	// Shard selects the Gateways served; see InShard.
+	Shard          string
+	GatewayClasses []gatewayv1.GatewayClass
+	Gateways       []gatewayv1.Gateway
+	HTTPRoutes     []gatewayv1.HTTPRoute
+	// PeerRoutes holds HTTPRoutes that are not translated, but count
+	// towards the limit on the routes of the Gateways they refer to. They
+	// are set when HTTPRoutes does not hold every route of those Gateways.
+	PeerRoutes []gatewayv1.HTTPRoute
+	// Namespaces are matched against the namespace selectors of listeners.
+	// Routes in namespaces missing from them match no selector.
+	Namespaces []corev1.Namespace
+	// Limits holds the limits set by the parameters of the GatewayClasses
+	// served, keyed by name.
+	Limits   map[string]Limits
+	Policies Policies
+	// Targets holds the objects backendRefs are resolved against.
+	Targets Targets
+}
+
+// IR is the configuration computed from an Input.
+type IR struct {
+	// Routes holds the routes served, sorted by namespace and name, as the
+	// proxy takes them.
+	Routes []proxy.HTTPRoute
+	// Parents holds how each of the HTTPRoutes attached to its parents, in
+	// the order of its parentRefs. Parents that are not served, such as
+	// Gateways of other controllers or shards, are left out: they must not
+	// get a status from the controller.
+	Parents map[types.NamespacedName][]ParentAcceptance
+}
+
+// ParentAcceptance is the outcome of attaching a route to one of its parents.
+type ParentAcceptance struct {
+	ParentRef gatewayv1.ParentReference
+	Accepted  bool
+	Reason    gatewayv1.RouteConditionReason
+	Message   string
+	// Listeners holds the listeners of the Gateway that allow the route, if
+	// it got as far as matching their hostnames.
+	Listeners []*gatewayv1.Listener
+}
+
+// Build computes the configuration served for in. Routes attach to the
+// Gateways of the GatewayClasses served through the listeners that allow
+// them and share a hostname with them, within the limits of their class.
+// Invalid routes attach to none.
+func Build(in Input) *IR {
+	b := newBuilder(in)
+	ir := &IR{Parents: map[types.NamespacedName][]ParentAcceptance{}}
+	for i := range in.HTTPRoutes {
+		route := &in.HTTPRoutes[i]
+		parents := b.attach(route)
+		ir.Parents[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}] = parents
+
+		rc := in.Policies.forRoute(route)
+		rc.Targets = in.Targets
+		// The route drains once every Gateway accepting it is being
+		// deleted.
+		rc.Draining = true
+		for _, parent := range parents {
+			if !parent.Accepted {
+				continue
+			}
+			key := ParentGateway(route.Namespace, parent.ParentRef)
+			for _, listener := range parent.Listeners {
+				rc.Listeners = append(rc.Listeners, attachedListener{Gateway: key, Listener: listener})
+			}
+			if b.gateways[key].DeletionTimestamp.IsZero() {
+				rc.Draining = false
+			}
+		}
+		if len(rc.Listeners) == 0 {
+			continue
+		}
+		ir.Routes = append(ir.Routes, translateRoute(route, rc))
+	}
+	slices.SortFunc(ir.Routes, func(a, b proxy.HTTPRoute) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return ir
+}
+
+// builder indexes the objects of an Input.
+type builder struct {
+	in         Input
+	classes    map[string]*gatewayv1.GatewayClass
+	gateways   map[types.NamespacedName]*gatewayv1.Gateway
+	namespaces map[string]*corev1.Namespace
+	// gatewayRoutes holds the routes with a parentRef to each Gateway, once
+	// a Gateway with limited routes needs them.
+	gatewayRoutes map[types.NamespacedName][]*gatewayv1.HTTPRoute
+}
+
+func newBuilder(in Input) *builder {
+	b := &builder{
+		in:         in,
+		classes:    map[string]*gatewayv1.GatewayClass{},
+		gateways:   map[types.NamespacedName]*gatewayv1.Gateway{},
+		namespaces: map[string]*corev1.Namespace{},
+	}
+	for i := range in.GatewayClasses {
+		b.classes[in.GatewayClasses[i].Name] = &in.GatewayClasses[i]
+	}
+	for i := range in.Gateways {
+		b.gateways[types.NamespacedName{Namespace: in.Gateways[i].Namespace, Name: in.Gateways[i].Name}] = &in.Gateways[i]
+	}
+	for i := range in.Namespaces {
+		b.namespaces[in.Namespaces[i].Name] = &in.Namespaces[i]
+	}
+	return b
+}
+
+// attach attaches route to each of its parents that is served.
+func (b *builder) attach(route *gatewayv1.HTTPRoute) []ParentAcceptance {
+	invalid := ValidateRoute(route)
+	namespace, ok := b.namespaces[route.Namespace]
+	if !ok {
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: route.Namespace}}
+	}
+	var parents []ParentAcceptance
+	for _, parentRef := range route.Spec.ParentRefs {
+		parent, ok := b.parentAcceptance(route, namespace, parentRef)
+		if !ok {
+			continue
+		}
+		if invalid != nil {
+			parent = ParentAcceptance{
+				ParentRef: parentRef,
+				Reason:    gatewayv1.RouteReasonUnsupportedValue,
+				Message:   fmt.Sprintf("Invalid route: %v", invalid),
+			}
+		}
+		parents = append(parents, parent)
+	}
+	return parents
+}
+
+// parentAcceptance checks that parentRef refers to an existing Gateway with a
+// listener the route may attach to. It returns false for parents that are not
+// served.
+func (b *builder) parentAcceptance(route *gatewayv1.HTTPRoute, namespace *corev1.Namespace, parentRef gatewayv1.ParentReference) (ParentAcceptance, bool) {
+	if !IsGatewayParent(parentRef) {
+		return ParentAcceptance{}, false
+	}
+	rejected := func(reason gatewayv1.RouteConditionReason, format string, args ...any) (ParentAcceptance, bool) {
+		return ParentAcceptance{ParentRef: parentRef, Reason: reason, Message: fmt.Sprintf(format, args...)}, true
+	}
+	key := ParentGateway(route.Namespace, parentRef)
+	gw, ok := b.gateways[key]
+	if !ok {
+		// Gateways that do not exist have no shard label, so they are
+		// reported by the instances serving no particular shard.
+		if b.in.Shard != "" {
+			return ParentAcceptance{}, false
+		}
+		return rejected(gatewayv1.RouteReasonNoMatchingParent, "Gateway %s not found", key)
+	}
+	gc, ok := b.classes[string(gw.Spec.GatewayClassName)]
+	if !ok || gc.Spec.ControllerName != b.in.ControllerName || !InShard(gw, b.in.Shard) {
+		return ParentAcceptance{}, false
+	}
+
+	listeners := ParentListeners(gw, parentRef)
+	if len(listeners) == 0 {
+		return rejected(gatewayv1.RouteReasonNoMatchingParent, "No listener of the Gateway matches the parentRef's sectionName and port")
+	}
+	var allowed []*gatewayv1.Listener
+	kindAllowed := false
+	for _, listener := range listeners {
+		if !ListenerAllowsKind(listener, kindHTTPRoute) {
+			continue
+		}
+		kindAllowed = true
+		ok, err := ListenerAllowsNamespace(listener, gw.Namespace, namespace)
+		if err != nil {
+			return rejected(gatewayv1.RouteReasonNotAllowedByListeners, "Listener %s has an invalid namespace selector: %v", listener.Name, err)
+		}
+		if ok {
+			allowed = append(allowed, listener)
+		}
+	}
+	if len(allowed) == 0 {
+		if kindAllowed {
+			return rejected(gatewayv1.RouteReasonNotAllowedByListeners, "No listener of the Gateway allows HTTPRoutes from namespace %s", route.Namespace)
+		}
+		return rejected(gatewayv1.RouteReasonNotAllowedByListeners, "No listener of the Gateway allows HTTPRoutes")
+	}
+
+	parent := ParentAcceptance{ParentRef: parentRef, Listeners: allowed}
+	if _, ok := RouteHostnames(allowed, route.Spec.Hostnames); !ok {
+		parent.Reason = gatewayv1.RouteReasonNoMatchingListenerHostname
+		parent.Message = "No hostname of the route matches the hostname of a listener selected by the parentRef"
+		return parent, true
+	}
+	limits := b.in.Limits[gc.Name]
+	older := 0
+	if limits.MaxRoutesPerGateway > 0 {
+		older = b.olderRoutes(key, route)
+	}
+	if message := limits.exceeded(route, gc.Name, older); message != "" {
+		parent.Reason = RouteReasonLimitExceeded
+		parent.Message = message
+		return parent, true
+	}
+	parent.Accepted = true
+	parent.Reason = gatewayv1.RouteReasonAccepted
+	parent.Message = "Route accepted by reference implementation"
+	return parent, true
+}
+
+// olderRoutes counts the routes with a parentRef to the Gateway with the
+// given key that come before route in the order of compareRouteAge.
+func (b *builder) olderRoutes(key types.NamespacedName, route *gatewayv1.HTTPRoute) int {
+	if b.gatewayRoutes == nil {
+		b.gatewayRoutes = map[types.NamespacedName][]*gatewayv1.HTTPRoute{}
+		seen := map[types.NamespacedName]bool{}
+		for _, routes := range [][]gatewayv1.HTTPRoute{b.in.HTTPRoutes, b.in.PeerRoutes} {
+			for i := range routes {
+				r := &routes[i]
+				routeKey := types.NamespacedName{Namespace: r.Namespace, Name: r.Name}
+				if seen[routeKey] {
+					continue
+				}
+				seen[routeKey] = true
+				gateways := RouteGateways(r)
+				for j, gw := range gateways {
+					if !slices.Contains(gateways[:j], gw) {
+						b.gatewayRoutes[gw] = append(b.gatewayRoutes[gw], r)
+					}
+				}
+			}
+		}
+	}
+	older := 0
+	for _, r := range b.gatewayRoutes[key] {
+		if compareRouteAge(r, route) < 0 {
+			older++
+		}
+	}
+	return older
+}
@@ -0,0 +1,467 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const controllerName = "example.com/gateway-controller"
+
+func TestBuild(t *testing.T) {
+	port := gatewayv1.PortNumber(80)
+	newRoute := func(name, gateway string) gatewayv1.HTTPRoute {
+		return gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: gatewayv1.ObjectName(gateway)}}},
+				Hostnames:       []gatewayv1.Hostname{gatewayv1.Hostname(name + ".example.com")},
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{Name: "web", Port: &port},
+				}}}}},
+			},
+		}
+	}
+	newGateway := func(name, class string) gatewayv1.Gateway {
+		return gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: name},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: gatewayv1.ObjectName(class),
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		}
+	}
+	in := Input{
+		ControllerName: controllerName,
+		GatewayClasses: []gatewayv1.GatewayClass{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ours"}, Spec: gatewayv1.GatewayClassSpec{ControllerName: controllerName}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "theirs"}, Spec: gatewayv1.GatewayClassSpec{ControllerName: "example.com/other"}},
+		},
+		Gateways: []gatewayv1.Gateway{newGateway("gw", "ours"), newGateway("other", "theirs")},
+		HTTPRoutes: []gatewayv1.HTTPRoute{
+			newRoute("web", "gw"),
+			newRoute("api", "gw"),
+			newRoute("elsewhere", "other"),
+		},
+		Targets: Targets{Services: map[types.NamespacedName]*corev1.Service{
+			{Namespace: "apps", Name: "web"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+			},
+		}},
+	}
+
+	ir := Build(in)
+	for _, name := range []string{"web", "api"} {
+		parents := ir.Parents[types.NamespacedName{Namespace: "apps", Name: name}]
+		if len(parents) != 1 || !parents[0].Accepted || parents[0].ParentRef.Name != "gw" {
+			t.Errorf("expected %s to be accepted by gw, got %+v", name, parents)
+		}
+	}
+	if parents := ir.Parents[types.NamespacedName{Namespace: "apps", Name: "elsewhere"}]; len(parents) != 0 {
+		t.Errorf("expected no parent of another controller to be reported, got %+v", parents)
+	}
+	var names []string
+	for _, route := range ir.Routes {
+		names = append(names, route.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"api", "web"}) {
+		t.Errorf("expected the routes of our Gateway to be served in order, got %v", names)
+	}
+	if len(in.HTTPRoutes[0].Status.Parents) != 0 {
+		t.Errorf("expected the input to be left as it is, got %+v", in.HTTPRoutes[0].Status)
+	}
+
+	// The IR is consumed by the proxy as it is.
+	p := proxy.NewProxy(proxy.Options{})
+	p.UpdateRoutes(ir.Routes)
+	if len(p.Routes()) != 2 {
+		t.Errorf("expected the proxy to serve both routes, got %+v", p.Routes())
+	}
+}
+
+func TestBuildReferenceGrant(t *testing.T) {
+	port := gatewayv1.PortNumber(80)
+	namespace := gatewayv1.Namespace("backends")
+	in := Input{
+		ControllerName: controllerName,
+		GatewayClasses: []gatewayv1.GatewayClass{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ours"}, Spec: gatewayv1.GatewayClassSpec{ControllerName: controllerName}},
+		},
+		Gateways: []gatewayv1.Gateway{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		}},
+		HTTPRoutes: []gatewayv1.HTTPRoute{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "apps", Name: "web"},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{{Name: "gw"}}},
+				Rules: []gatewayv1.HTTPRouteRule{{BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: gatewayv1.BackendRef{
+					BackendObjectReference: gatewayv1.BackendObjectReference{Name: "web", Namespace: &namespace, Port: &port},
+				}}}}},
+			},
+		}},
+		Targets: Targets{Services: map[types.NamespacedName]*corev1.Service{
+			{Namespace: "backends", Name: "web"}: {
+				ObjectMeta: metav1.ObjectMeta{Namespace: "backends", Name: "web"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+			},
+		}},
+	}
+	grant := gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "backends", Name: "apps"},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "HTTPRoute", Namespace: "apps"}},
+			To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Service"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		grants   []gatewayv1beta1.ReferenceGrant
+		expected proxy.WeightedBackend
+	}{
+		{
+			name:     "no grant",
+			expected: proxy.WeightedBackend{Weight: 1, Invalid: true},
+		},
+		{
+			name:     "grant",
+			grants:   []gatewayv1beta1.ReferenceGrant{grant},
+			expected: proxy.WeightedBackend{Backend: proxy.Backend{Host: "web.backends.svc.cluster.local", Port: 80}, Weight: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in.Targets.ReferenceGrants = tt.grants
+			ir := Build(in)
+			if len(ir.Routes) != 1 || len(ir.Routes[0].Rules) != 1 {
+				t.Fatalf("expected the route to be served, got %+v", ir.Routes)
+			}
+			backends := ir.Routes[0].Rules[0].Backends
+			if len(backends) != 1 || !reflect.DeepEqual(backends[0], tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, backends)
+			}
+		})
+	}
+}
+
+func TestBuildDraining(t *testing.T) {
+	deleted := metav1.Now()
+	newGateway := func(name string, deletionTimestamp *metav1.Time) gatewayv1.Gateway {
+		return gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, DeletionTimestamp: deletionTimestamp},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners:        []gatewayv1.Listener{{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType}},
+			},
+		}
+	}
+	in := Input{
+		ControllerName: controllerName,
+		GatewayClasses: []gatewayv1.GatewayClass{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ours"}, Spec: gatewayv1.GatewayClassSpec{ControllerName: controllerName}},
+		},
+		Gateways: []gatewayv1.Gateway{newGateway("live", nil), newGateway("deleting", &deleted)},
+	}
+
+	tests := []struct {
+		name     string
+		parents  []string
+		expected bool
+	}{
+		{name: "live parent", parents: []string{"live"}, expected: false},
+		{name: "deleted parent", parents: []string{"deleting"}, expected: true},
+		{name: "deleted and live parents", parents: []string{"deleting", "live"}, expected: false},
+		{name: "deleted and missing parents", parents: []string{"deleting", "missing"}, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"}}
+			for _, parent := range tt.parents {
+				route.Spec.ParentRefs = append(route.Spec.ParentRefs, gatewayv1.ParentReference{Name: gatewayv1.ObjectName(parent)})
+			}
+			in.HTTPRoutes = []gatewayv1.HTTPRoute{route}
+			ir := Build(in)
+			if len(ir.Routes) != 1 {
+				t.Fatalf("expected the route to be served, got %+v", ir.Routes)
+			}
+			if ir.Routes[0].Draining != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, ir.Routes[0].Draining)
+			}
+		})
+	}
+}
+
+func TestBuildParents(t *testing.T) {
+	in := Input{
+		ControllerName: controllerName,
+		GatewayClasses: []gatewayv1.GatewayClass{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ours"}, Spec: gatewayv1.GatewayClassSpec{ControllerName: controllerName}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "theirs"}, Spec: gatewayv1.GatewayClassSpec{ControllerName: "example.com/other"}},
+		},
+		Gateways: []gatewayv1.Gateway{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "ours",
+					Listeners: []gatewayv1.Listener{
+						{Name: "http", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+						{Name: "tcp", Port: 9000, Protocol: gatewayv1.TCPProtocolType},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other-gw"},
+				Spec:       gatewayv1.GatewaySpec{GatewayClassName: "theirs"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		parentRef gatewayv1.ParentReference
+		expected  *gatewayv1.RouteConditionReason
+	}{
+		{
+			name:      "accepted",
+			parentRef: gatewayv1.ParentReference{Name: "gw"},
+			expected:  ptr(gatewayv1.RouteReasonAccepted),
+		},
+		{
+			name:      "matching section",
+			parentRef: gatewayv1.ParentReference{Name: "gw", SectionName: ptr(gatewayv1.SectionName("http"))},
+			expected:  ptr(gatewayv1.RouteReasonAccepted),
+		},
+		{
+			name:      "missing gateway",
+			parentRef: gatewayv1.ParentReference{Name: "missing"},
+			expected:  ptr(gatewayv1.RouteReasonNoMatchingParent),
+		},
+		{
+			name:      "missing section",
+			parentRef: gatewayv1.ParentReference{Name: "gw", SectionName: ptr(gatewayv1.SectionName("https"))},
+			expected:  ptr(gatewayv1.RouteReasonNoMatchingParent),
+		},
+		{
+			name:      "port mismatch",
+			parentRef: gatewayv1.ParentReference{Name: "gw", Port: ptr(gatewayv1.PortNumber(8080))},
+			expected:  ptr(gatewayv1.RouteReasonNoMatchingParent),
+		},
+		{
+			name:      "listener does not allow HTTPRoutes",
+			parentRef: gatewayv1.ParentReference{Name: "gw", SectionName: ptr(gatewayv1.SectionName("tcp"))},
+			expected:  ptr(gatewayv1.RouteReasonNotAllowedByListeners),
+		},
+		{
+			name:      "gateway of another controller",
+			parentRef: gatewayv1.ParentReference{Name: "other-gw"},
+		},
+		{
+			name:      "not a Gateway",
+			parentRef: gatewayv1.ParentReference{Kind: ptr(gatewayv1.Kind("Service")), Name: "gw"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in.HTTPRoutes = []gatewayv1.HTTPRoute{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+				Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{tt.parentRef}}},
+			}}
+			parents := Build(in).Parents[types.NamespacedName{Namespace: "default", Name: "web"}]
+			switch {
+			case tt.expected == nil && len(parents) != 0:
+				t.Errorf("expected no status, got %v", parents[0].Reason)
+			case tt.expected != nil && len(parents) != 1:
+				t.Errorf("expected %v, got %+v", *tt.expected, parents)
+			case tt.expected != nil && parents[0].Reason != *tt.expected:
+				t.Errorf("expected %v, got %v", *tt.expected, parents[0].Reason)
+			}
+		})
+	}
+}
+
+func TestBuildParentsCrossNamespace(t *testing.T) {
+	in := Input{
+		ControllerName: controllerName,
+		GatewayClasses: []gatewayv1.GatewayClass{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ours"}, Spec: gatewayv1.GatewayClassSpec{ControllerName: controllerName}},
+		},
+		Gateways: []gatewayv1.Gateway{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners: []gatewayv1.Listener{
+					{Name: "same", Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+					{
+						Name: "selected", Port: 8080, Protocol: gatewayv1.HTTPProtocolType,
+						AllowedRoutes: &gatewayv1.AllowedRoutes{Namespaces: &gatewayv1.RouteNamespaces{
+							From:     ptr(gatewayv1.NamespacesFromSelector),
+							Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"shared-gateway": "true"}},
+						}},
+					},
+					{
+						Name: "all", Port: 8081, Protocol: gatewayv1.HTTPProtocolType,
+						AllowedRoutes: &gatewayv1.AllowedRoutes{Namespaces: &gatewayv1.RouteNamespaces{
+							From: ptr(gatewayv1.NamespacesFromAll),
+						}},
+					},
+				},
+			},
+		}},
+		Namespaces: []corev1.Namespace{
+			{ObjectMeta: metav1.ObjectMeta{Name: "apps", Labels: map[string]string{"shared-gateway": "true"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		section   gatewayv1.SectionName
+		expected  gatewayv1.RouteConditionReason
+	}{
+		{
+			name:      "same namespace listener rejects other namespaces",
+			namespace: "apps",
+			section:   "same",
+			expected:  gatewayv1.RouteReasonNotAllowedByListeners,
+		},
+		{
+			name:      "selector matches namespace labels",
+			namespace: "apps",
+			section:   "selected",
+			expected:  gatewayv1.RouteReasonAccepted,
+		},
+		{
+			name:      "selector does not match namespace labels",
+			namespace: "other",
+			section:   "selected",
+			expected:  gatewayv1.RouteReasonNotAllowedByListeners,
+		},
+		{
+			name:      "all namespaces",
+			namespace: "other",
+			section:   "all",
+			expected:  gatewayv1.RouteReasonAccepted,
+		},
+		{
+			name:      "any listener",
+			namespace: "apps",
+			expected:  gatewayv1.RouteReasonAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parentRef := gatewayv1.ParentReference{Name: "gw", Namespace: ptr(gatewayv1.Namespace("infra"))}
+			if tt.section != "" {
+				parentRef.SectionName = &tt.section
+			}
+			in.HTTPRoutes = []gatewayv1.HTTPRoute{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tt.namespace, Name: "web"},
+				Spec:       gatewayv1.HTTPRouteSpec{CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{parentRef}}},
+			}}
+			parents := Build(in).Parents[types.NamespacedName{Namespace: tt.namespace, Name: "web"}]
+			if len(parents) != 1 || parents[0].Reason != tt.expected {
+				t.Errorf("expected %v, got %+v", tt.expected, parents)
+			}
+		})
+	}
+}
+
+func TestBuildHostnames(t *testing.T) {
+	in := Input{
+		ControllerName: controllerName,
+		GatewayClasses: []gatewayv1.GatewayClass{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ours"}, Spec: gatewayv1.GatewayClassSpec{ControllerName: controllerName}},
+		},
+		Gateways: []gatewayv1.Gateway{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gw"},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: "ours",
+				Listeners: []gatewayv1.Listener{
+					{Name: "foo", Port: 80, Protocol: gatewayv1.HTTPProtocolType, Hostname: ptr(gatewayv1.Hostname("foo.example.com"))},
+					{Name: "bar", Port: 8080, Protocol: gatewayv1.HTTPProtocolType, Hostname: ptr(gatewayv1.Hostname("bar.example.com"))},
+				},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		parentRef gatewayv1.ParentReference
+		expected  []string
+		listeners []string
+	}{
+		{
+			name:      "all listeners",
+			parentRef: gatewayv1.ParentReference{Name: "gw"},
+			expected:  []string{"foo.example.com", "bar.example.com"},
+			listeners: []string{"default/gw/foo", "default/gw/bar"},
+		},
+		{
+			name:      "sectionName",
+			parentRef: gatewayv1.ParentReference{Name: "gw", SectionName: ptr(gatewayv1.SectionName("bar"))},
+			expected:  []string{"bar.example.com"},
+			listeners: []string{"default/gw/bar"},
+		},
+		{
+			name:      "port",
+			parentRef: gatewayv1.ParentReference{Name: "gw", Port: ptr(gatewayv1.PortNumber(80))},
+			expected:  []string{"foo.example.com"},
+			listeners: []string{"default/gw/foo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in.HTTPRoutes = []gatewayv1.HTTPRoute{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: []gatewayv1.ParentReference{tt.parentRef}},
+					Hostnames:       []gatewayv1.Hostname{"*.example.com"},
+				},
+			}}
+			ir := Build(in)
+			if len(ir.Routes) != 1 {
+				t.Fatalf("expected the route to be served, got %+v", ir.Routes)
+			}
+			if !reflect.DeepEqual(ir.Routes[0].Hostnames, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ir.Routes[0].Hostnames)
+			}
+			var listeners []string
+			for _, l := range ir.Routes[0].Listeners {
+				listeners = append(listeners, l.GatewayNamespace+"/"+l.Gateway+"/"+l.Listener)
+			}
+			if !reflect.DeepEqual(listeners, tt.listeners) {
+				t.Errorf("expected listeners %v, got %v", tt.listeners, listeners)
+			}
+		})
+	}
+}
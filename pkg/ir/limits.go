@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"cmp"
+	"fmt"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// RouteReasonLimitExceeded is the reason a route is not accepted by a Gateway
+// whose GatewayClass sets a limit the route exceeds.
+const RouteReasonLimitExceeded gatewayv1.RouteConditionReason = "LimitExceeded"
+
+// Limits bounds the routes accepted by the Gateways of a class, so that a
+// single tenant cannot degrade the shared proxy. Zero fields and a nil
+// MaxRegexMatchers set no limit; a MaxRegexMatchers of zero forbids regular
+// expressions.
+type Limits struct {
+	MaxRoutesPerGateway int
+	MaxRulesPerRoute    int
+	MaxRegexMatchers    *int
+}
+
+// exceeded returns why route exceeds the limits of the GatewayClass named
+// className, on a Gateway that accepts older routes before it, or "" if it is
+// within them.
+func (l Limits) exceeded(route *gatewayv1.HTTPRoute, className string, older int) string {
+	if l.MaxRulesPerRoute > 0 && len(route.Spec.Rules) > l.MaxRulesPerRoute {
+		return fmt.Sprintf("Route has %d rules, more than the maximum of %d set by GatewayClass %s", len(route.Spec.Rules), l.MaxRulesPerRoute, className)
+	}
+	if l.MaxRegexMatchers != nil {
+		if n := regexMatchers(route); n > *l.MaxRegexMatchers {
+			return fmt.Sprintf("Route has %d regular expression matches, more than the maximum of %d set by GatewayClass %s", n, *l.MaxRegexMatchers, className)
+		}
+	}
+	if l.MaxRoutesPerGateway > 0 && older >= l.MaxRoutesPerGateway {
+		return fmt.Sprintf("Gateway already has the maximum of %d routes set by GatewayClass %s", l.MaxRoutesPerGateway, className)
+	}
+	return ""
+}
+
+// regexMatchers counts the regular expression matches of route.
+func regexMatchers(route *gatewayv1.HTTPRoute) int {
+	n := 0
+	for _, rule := range route.Spec.Rules {
+		for _, match := range rule.Matches {
+			if match.Path != nil && match.Path.Type != nil && *match.Path.Type == gatewayv1.PathMatchRegularExpression {
+				n++
+			}
+			for _, header := range match.Headers {
+				if header.Type != nil && *header.Type == gatewayv1.HeaderMatchRegularExpression {
+					n++
+				}
+			}
+			for _, param := range match.QueryParams {
+				if param.Type != nil && *param.Type == gatewayv1.QueryParamMatchRegularExpression {
+					n++
+				}
+			}
+		}
+	}
+	return n
+}
+
+// compareRouteAge orders routes oldest first, then by namespace and name, the
+// order in which the routes of a Gateway count towards its limit, so that
+// existing routes are not displaced by new ones.
+func compareRouteAge(a, b *gatewayv1.HTTPRoute) int {
+	if c := a.CreationTimestamp.Compare(b.CreationTimestamp.Time); c != 0 {
+		return c
+	}
+	return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+}
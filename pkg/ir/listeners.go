@@ -0,0 +1,226 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ProtocolRouteKinds lists the route kinds the proxy can serve on each
+// listener protocol.
+var ProtocolRouteKinds = map[gatewayv1.ProtocolType][]gatewayv1.Kind{
+	gatewayv1.HTTPProtocolType:  {kindHTTPRoute},
+	gatewayv1.HTTPSProtocolType: {kindHTTPRoute},
+}
+
+// ListenerSupportedKinds returns the route kinds that may attach to a
+// listener: those requested in allowedRoutes.kinds that the listener's
+// protocol supports, or every supported kind when none are requested. The
+// requested kinds that are not supported are returned separately.
+func ListenerSupportedKinds(listener *gatewayv1.Listener) (supported, invalid []gatewayv1.RouteGroupKind) {
+	protocolKinds := ProtocolRouteKinds[listener.Protocol]
+
+	if listener.AllowedRoutes == nil || len(listener.AllowedRoutes.Kinds) == 0 {
+		for _, kind := range protocolKinds {
+			supported = append(supported, gatewayv1.RouteGroupKind{Group: ptr(gatewayv1.Group(gatewayv1.GroupName)), Kind: kind})
+		}
+		return supported, nil
+	}
+
+	for _, rgk := range listener.AllowedRoutes.Kinds {
+		group := gatewayv1.GroupName
+		if rgk.Group != nil {
+			group = string(*rgk.Group)
+		}
+		if group == gatewayv1.GroupName && slices.Contains(protocolKinds, rgk.Kind) {
+			supported = append(supported, gatewayv1.RouteGroupKind{Group: ptr(gatewayv1.Group(group)), Kind: rgk.Kind})
+		} else {
+			invalid = append(invalid, rgk)
+		}
+	}
+	return supported, invalid
+}
+
+// ListenerAllowsKind reports whether routes of the given kind may attach to
+// the listener.
+func ListenerAllowsKind(listener *gatewayv1.Listener, kind gatewayv1.Kind) bool {
+	supported, _ := ListenerSupportedKinds(listener)
+	return slices.ContainsFunc(supported, func(rgk gatewayv1.RouteGroupKind) bool {
+		return rgk.Kind == kind
+	})
+}
+
+// ListenerAllowsNamespace reports whether routes in routeNamespace may attach
+// to a listener of a Gateway in gatewayNamespace, according to the listener's
+// allowedRoutes.namespaces. Only routes in the Gateway's namespace are allowed
+// by default.
+func ListenerAllowsNamespace(listener *gatewayv1.Listener, gatewayNamespace string, routeNamespace *corev1.Namespace) (bool, error) {
+	from := gatewayv1.NamespacesFromSame
+	var selector *metav1.LabelSelector
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil {
+		if listener.AllowedRoutes.Namespaces.From != nil {
+			from = *listener.AllowedRoutes.Namespaces.From
+		}
+		selector = listener.AllowedRoutes.Namespaces.Selector
+	}
+
+	switch from {
+	case gatewayv1.NamespacesFromAll:
+		return true, nil
+	case gatewayv1.NamespacesFromSelector:
+		if selector == nil {
+			return false, nil
+		}
+		s, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return false, err
+		}
+		return s.Matches(labels.Set(routeNamespace.Labels)), nil
+	case gatewayv1.NamespacesFromSame:
+		return routeNamespace.Name == gatewayNamespace, nil
+	}
+	return false, nil
+}
+
+// ListenersSelectNamespaces reports whether any of the listeners selects the
+// namespaces routes may attach from by label.
+func ListenersSelectNamespaces(listeners []*gatewayv1.Listener) bool {
+	for _, listener := range listeners {
+		if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil &&
+			listener.AllowedRoutes.Namespaces.From != nil && *listener.AllowedRoutes.Namespaces.From == gatewayv1.NamespacesFromSelector {
+			return true
+		}
+	}
+	return false
+}
+
+// ListenerHostnames returns the hostnames a route is served for on a listener:
+// the route's hostnames that match the listener's hostname, or the listener's
+// hostname if the route has none. A nil slice means any hostname; ok is false
+// if none of the route's hostnames match.
+func ListenerHostnames(listener *gatewayv1.Listener, RouteHostnames []gatewayv1.Hostname) (hostnames []string, ok bool) {
+	if listener.Hostname == nil || *listener.Hostname == "" {
+		for _, hostname := range RouteHostnames {
+			hostnames = append(hostnames, string(hostname))
+		}
+		return hostnames, true
+	}
+	if len(RouteHostnames) == 0 {
+		return []string{string(*listener.Hostname)}, true
+	}
+	for _, hostname := range RouteHostnames {
+		if match, ok := IntersectHostnames(string(*listener.Hostname), string(hostname)); ok {
+			hostnames = append(hostnames, match)
+		}
+	}
+	return hostnames, len(hostnames) > 0
+}
+
+// RouteHostnames returns the union of the hostnames a route is served for on
+// each of the listeners. A nil slice means any hostname; ok is false if the
+// route's hostnames match none of the listeners.
+func RouteHostnames(listeners []*gatewayv1.Listener, hostnames []gatewayv1.Hostname) ([]string, bool) {
+	var served []string
+	matched := false
+	for _, listener := range listeners {
+		listenerServed, ok := ListenerHostnames(listener, hostnames)
+		if !ok {
+			continue
+		}
+		if listenerServed == nil {
+			return nil, true
+		}
+		matched = true
+		for _, hostname := range listenerServed {
+			if !slices.Contains(served, hostname) {
+				served = append(served, hostname)
+			}
+		}
+	}
+	return served, matched
+}
+
+// IntersectHostnames returns the more specific of two hostnames if one matches
+// the other. A leading "*." matches one or more DNS labels.
+func IntersectHostnames(a, b string) (string, bool) {
+	switch {
+	case a == b:
+		return a, true
+	case wildcardMatches(a, b):
+		return b, true
+	case wildcardMatches(b, a):
+		return a, true
+	}
+	return "", false
+}
+
+func wildcardMatches(wildcard, hostname string) bool {
+	suffix, ok := strings.CutPrefix(wildcard, "*")
+	return ok && len(hostname) > len(suffix) && strings.HasSuffix(hostname, suffix)
+}
+
+// ParentListeners returns the listeners of a Gateway selected by a parentRef's
+// sectionName and port.
+func ParentListeners(gw *gatewayv1.Gateway, parentRef gatewayv1.ParentReference) []*gatewayv1.Listener {
+	var listeners []*gatewayv1.Listener
+	for i := range gw.Spec.Listeners {
+		listener := &gw.Spec.Listeners[i]
+		if parentRef.SectionName != nil && *parentRef.SectionName != listener.Name {
+			continue
+		}
+		if parentRef.Port != nil && *parentRef.Port != listener.Port {
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// IsGatewayParent reports whether parentRef refers to a Gateway.
+func IsGatewayParent(parentRef gatewayv1.ParentReference) bool {
+	return (parentRef.Group == nil || *parentRef.Group == gatewayv1.GroupName) && (parentRef.Kind == nil || *parentRef.Kind == kindGateway)
+}
+
+// ParentGateway returns the key of the Gateway referenced by parentRef, of a
+// route in namespace.
+func ParentGateway(namespace string, parentRef gatewayv1.ParentReference) types.NamespacedName {
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)}
+}
+
+// RouteGateways returns the Gateways referenced by an HTTPRoute's parentRefs.
+func RouteGateways(route *gatewayv1.HTTPRoute) []types.NamespacedName {
+	var gateways []types.NamespacedName
+	for _, parentRef := range route.Spec.ParentRefs {
+		if IsGatewayParent(parentRef) {
+			gateways = append(gateways, ParentGateway(route.Namespace, parentRef))
+		}
+	}
+	return gateways
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestListenerSupportedKinds(t *testing.T) {
+	httpRouteKind := gatewayv1.RouteGroupKind{Group: ptr(gatewayv1.Group(gatewayv1.GroupName)), Kind: "HTTPRoute"}
+	tcpRouteKind := gatewayv1.RouteGroupKind{Kind: "TCPRoute"}
+
+	tests := []struct {
+		name              string
+		listener          gatewayv1.Listener
+		expectedSupported []gatewayv1.RouteGroupKind
+		expectedInvalid   []gatewayv1.RouteGroupKind
+	}{
+		{
+			name:              "HTTP listener defaults to HTTPRoute",
+			listener:          gatewayv1.Listener{Protocol: gatewayv1.HTTPProtocolType},
+			expectedSupported: []gatewayv1.RouteGroupKind{httpRouteKind},
+		},
+		{
+			name:     "TCP listener supports nothing",
+			listener: gatewayv1.Listener{Protocol: gatewayv1.TCPProtocolType},
+		},
+		{
+			name: "unsupported kind requested",
+			listener: gatewayv1.Listener{
+				Protocol: gatewayv1.HTTPProtocolType,
+				AllowedRoutes: &gatewayv1.AllowedRoutes{
+					Kinds: []gatewayv1.RouteGroupKind{{Kind: "HTTPRoute"}, tcpRouteKind},
+				},
+			},
+			expectedSupported: []gatewayv1.RouteGroupKind{httpRouteKind},
+			expectedInvalid:   []gatewayv1.RouteGroupKind{tcpRouteKind},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supported, invalid := ListenerSupportedKinds(&tt.listener)
+			if !reflect.DeepEqual(supported, tt.expectedSupported) {
+				t.Errorf("expected supported %v, got %v", tt.expectedSupported, supported)
+			}
+			if !reflect.DeepEqual(invalid, tt.expectedInvalid) {
+				t.Errorf("expected invalid %v, got %v", tt.expectedInvalid, invalid)
+			}
+		})
+	}
+}
+
+func TestListenerAllowsNamespace(t *testing.T) {
+	withNamespaces := func(namespaces *gatewayv1.RouteNamespaces) gatewayv1.Listener {
+		return gatewayv1.Listener{AllowedRoutes: &gatewayv1.AllowedRoutes{Namespaces: namespaces}}
+	}
+	selected := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps", Labels: map[string]string{"team": "web"}}}
+	unselected := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	gatewayNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "infra"}}
+
+	tests := []struct {
+		name      string
+		listener  gatewayv1.Listener
+		namespace *corev1.Namespace
+		expected  bool
+		expectErr bool
+	}{
+		{
+			name:      "same namespace by default",
+			listener:  gatewayv1.Listener{},
+			namespace: gatewayNamespace,
+			expected:  true,
+		},
+		{
+			name:      "other namespace denied by default",
+			listener:  gatewayv1.Listener{},
+			namespace: selected,
+		},
+		{
+			name:      "all namespaces",
+			listener:  withNamespaces(&gatewayv1.RouteNamespaces{From: ptr(gatewayv1.NamespacesFromAll)}),
+			namespace: unselected,
+			expected:  true,
+		},
+		{
+			name: "selector matches",
+			listener: withNamespaces(&gatewayv1.RouteNamespaces{
+				From:     ptr(gatewayv1.NamespacesFromSelector),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			}),
+			namespace: selected,
+			expected:  true,
+		},
+		{
+			name: "selector does not match the Gateway's own namespace",
+			listener: withNamespaces(&gatewayv1.RouteNamespaces{
+				From:     ptr(gatewayv1.NamespacesFromSelector),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			}),
+			namespace: gatewayNamespace,
+		},
+		{
+			name: "invalid selector",
+			listener: withNamespaces(&gatewayv1.RouteNamespaces{
+				From: ptr(gatewayv1.NamespacesFromSelector),
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "team", Operator: "Unknown"},
+				}},
+			}),
+			namespace: selected,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ListenerAllowsNamespace(&tt.listener, "infra", tt.namespace)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRouteHostnames(t *testing.T) {
+	listener := func(hostname gatewayv1.Hostname) *gatewayv1.Listener {
+		if hostname == "" {
+			return &gatewayv1.Listener{}
+		}
+		return &gatewayv1.Listener{Hostname: &hostname}
+	}
+
+	tests := []struct {
+		name       string
+		listeners  []*gatewayv1.Listener
+		hostnames  []gatewayv1.Hostname
+		expected   []string
+		expectedOK bool
+	}{
+		{
+			name:       "any hostname",
+			listeners:  []*gatewayv1.Listener{listener("")},
+			expectedOK: true,
+		},
+		{
+			name:       "route hostnames on listener without hostname",
+			listeners:  []*gatewayv1.Listener{listener("")},
+			hostnames:  []gatewayv1.Hostname{"foo.example.com"},
+			expected:   []string{"foo.example.com"},
+			expectedOK: true,
+		},
+		{
+			name:       "listener hostname for route without hostnames",
+			listeners:  []*gatewayv1.Listener{listener("foo.example.com")},
+			expected:   []string{"foo.example.com"},
+			expectedOK: true,
+		},
+		{
+			name:       "route hostname narrows wildcard listener",
+			listeners:  []*gatewayv1.Listener{listener("*.example.com")},
+			hostnames:  []gatewayv1.Hostname{"foo.example.com", "example.com", "foo.example.org"},
+			expected:   []string{"foo.example.com"},
+			expectedOK: true,
+		},
+		{
+			name:       "listener hostname narrows wildcard route",
+			listeners:  []*gatewayv1.Listener{listener("foo.example.com")},
+			hostnames:  []gatewayv1.Hostname{"*.example.com"},
+			expected:   []string{"foo.example.com"},
+			expectedOK: true,
+		},
+		{
+			name:       "union over listeners",
+			listeners:  []*gatewayv1.Listener{listener("foo.example.com"), listener("bar.example.com")},
+			hostnames:  []gatewayv1.Hostname{"*.example.com"},
+			expected:   []string{"foo.example.com", "bar.example.com"},
+			expectedOK: true,
+		},
+		{
+			name:      "no matching hostname",
+			listeners: []*gatewayv1.Listener{listener("foo.example.com")},
+			hostnames: []gatewayv1.Hostname{"bar.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := RouteHostnames(tt.listeners, tt.hostnames)
+			if ok != tt.expectedOK {
+				t.Errorf("expected ok %v, got %v", tt.expectedOK, ok)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
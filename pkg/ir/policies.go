@@ -0,0 +1,88 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"k8s.io/apimachinery/pkg/types"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Policies holds the state resolved from policies and extensions that applies
+// to HTTPRoutes.
+type Policies struct {
+	// BasicAuth, SecurityHeaders, Transforms and Telemetry are keyed by the
+	// targeted HTTPRoute. The Gateway-prefixed fields are keyed by Gateway
+	// and apply to every route attached to it that has no route-level
+	// policy.
+	BasicAuth              map[types.NamespacedName]*proxy.BasicAuth
+	SecurityHeaders        map[types.NamespacedName]map[string]string
+	GatewaySecurityHeaders map[types.NamespacedName]map[string]string
+	Transforms             map[types.NamespacedName]*proxy.Transform
+	GatewayTransforms      map[types.NamespacedName]*proxy.Transform
+	Telemetry              map[types.NamespacedName]*proxy.Telemetry
+	GatewayTelemetry       map[types.NamespacedName]*proxy.Telemetry
+	// GatewayRequestTimeouts is keyed by Gateway and holds the default
+	// request timeout set by the parameters of its GatewayClass.
+	GatewayRequestTimeouts map[types.NamespacedName]time.Duration
+	// Hooks is keyed by the ConfigMap referenced by ExtensionRef filters.
+	Hooks map[types.NamespacedName]proxy.RequestHook
+}
+
+// NewPolicies returns Policies with no state resolved yet.
+func NewPolicies() Policies {
+	return Policies{
+		BasicAuth:              map[types.NamespacedName]*proxy.BasicAuth{},
+		SecurityHeaders:        map[types.NamespacedName]map[string]string{},
+		GatewaySecurityHeaders: map[types.NamespacedName]map[string]string{},
+		Transforms:             map[types.NamespacedName]*proxy.Transform{},
+		GatewayTransforms:      map[types.NamespacedName]*proxy.Transform{},
+		Telemetry:              map[types.NamespacedName]*proxy.Telemetry{},
+		GatewayTelemetry:       map[types.NamespacedName]*proxy.Telemetry{},
+		GatewayRequestTimeouts: map[types.NamespacedName]time.Duration{},
+		Hooks:                  map[types.NamespacedName]proxy.RequestHook{},
+	}
+}
+
+// forRoute returns the context route is translated in, with the policies
+// that apply to it.
+func (p Policies) forRoute(route *gatewayv1.HTTPRoute) routeContext {
+	return routeContext{
+		BasicAuth:       p.BasicAuth[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}],
+		SecurityHeaders: policyForRoute(p.SecurityHeaders, p.GatewaySecurityHeaders, route),
+		Transform:       policyForRoute(p.Transforms, p.GatewayTransforms, route),
+		Telemetry:       policyForRoute(p.Telemetry, p.GatewayTelemetry, route),
+		RequestTimeout:  policyForRoute(nil, p.GatewayRequestTimeouts, route),
+		Hooks:           p.Hooks,
+	}
+}
+
+// policyForRoute returns the policy state for a route, preferring a policy on
+// the route itself over one on any of its parent Gateways.
+func policyForRoute[T any](routes, gateways map[types.NamespacedName]T, route *gatewayv1.HTTPRoute) T {
+	if v, ok := routes[types.NamespacedName{Namespace: route.Namespace, Name: route.Name}]; ok {
+		return v
+	}
+	for _, gw := range RouteGateways(route) {
+		if v, ok := gateways[gw]; ok {
+			return v
+		}
+	}
+	var zero T
+	return zero
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestPolicyForRoute(t *testing.T) {
+	routeHeaders := map[string]string{"X-Frame-Options": "SAMEORIGIN"}
+	gatewayHeaders := map[string]string{"X-Frame-Options": "DENY"}
+
+	policies := Policies{
+		SecurityHeaders: map[types.NamespacedName]map[string]string{
+			{Namespace: "default", Name: "protected"}: routeHeaders,
+		},
+		GatewaySecurityHeaders: map[types.NamespacedName]map[string]string{
+			{Namespace: "infra", Name: "gw"}: gatewayHeaders,
+		},
+	}
+
+	newRoute := func(name string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayv1.HTTPRouteSpec{
+				CommonRouteSpec: gatewayv1.CommonRouteSpec{
+					ParentRefs: []gatewayv1.ParentReference{
+						{Name: "gw", Namespace: ptr(gatewayv1.Namespace("infra"))},
+					},
+				},
+			},
+		}
+	}
+
+	if actual := policyForRoute(policies.SecurityHeaders, policies.GatewaySecurityHeaders, newRoute("protected")); !reflect.DeepEqual(actual, routeHeaders) {
+		t.Errorf("expected route-level headers %v, got %v", routeHeaders, actual)
+	}
+	if actual := policyForRoute(policies.SecurityHeaders, policies.GatewaySecurityHeaders, newRoute("other")); !reflect.DeepEqual(actual, gatewayHeaders) {
+		t.Errorf("expected gateway-level headers %v, got %v", gatewayHeaders, actual)
+	}
+}
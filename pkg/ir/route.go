@@ -0,0 +1,213 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+	"k8s.io/apimachinery/pkg/types"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// kindHTTPRoute is the kind of the routes translated.
+	kindHTTPRoute gatewayv1.Kind = "HTTPRoute"
+	// kindGateway is the kind of the parents routes attach to.
+	kindGateway gatewayv1.Kind = "Gateway"
+)
+
+// attachedListener is a listener a route is attached to.
+type attachedListener struct {
+	Gateway  types.NamespacedName
+	Listener *gatewayv1.Listener
+}
+
+// routeContext holds what a route is translated against, besides the route
+// itself: where it is attached, and the state resolved from the objects it
+// refers to.
+type routeContext struct {
+	// Listeners holds the listeners the route is attached to through its
+	// accepted parentRefs. The route is served for the hostnames they have
+	// in common with it.
+	Listeners []attachedListener
+	// Draining is set if every Gateway of the route is being deleted.
+	Draining bool
+
+	BasicAuth       *proxy.BasicAuth
+	SecurityHeaders map[string]string
+	Transform       *proxy.Transform
+	Telemetry       *proxy.Telemetry
+	// RequestTimeout is the request timeout of the rules that set none.
+	RequestTimeout time.Duration
+	// Hooks holds the hooks of ExtensionRef filters, keyed by the ConfigMap
+	// they refer to.
+	Hooks map[types.NamespacedName]proxy.RequestHook
+	// Targets holds the objects backendRefs are resolved against.
+	Targets Targets
+}
+
+// translateRoute translates an accepted route into the configuration the
+// proxy serves. Unresolved backendRefs keep their share of the requests,
+// which are answered with 500 as the spec requires; they are reported in the
+// ResolvedRefs condition. Header matches with invalid regular expressions,
+// which the controller rejects, are skipped.
+func translateRoute(route *gatewayv1.HTTPRoute, rc routeContext) proxy.HTTPRoute {
+	pr := proxy.HTTPRoute{
+		Namespace:         route.Namespace,
+		Name:              route.Name,
+		CreationTimestamp: route.CreationTimestamp.Time,
+		BasicAuth:         rc.BasicAuth,
+		SecurityHeaders:   rc.SecurityHeaders,
+		Transform:         rc.Transform,
+		Telemetry:         rc.Telemetry,
+		Hostnames:         servedHostnames(route, rc),
+		Draining:          rc.Draining,
+	}
+	for _, a := range rc.Listeners {
+		listener := proxy.RouteListener{
+			GatewayNamespace: a.Gateway.Namespace,
+			Gateway:          a.Gateway.Name,
+			Listener:         string(a.Listener.Name),
+		}
+		if a.Listener.Hostname != nil {
+			listener.Hostname = string(*a.Listener.Hostname)
+		}
+		pr.Listeners = append(pr.Listeners, listener)
+	}
+
+	for i, rule := range route.Spec.Rules {
+		pRule := proxy.RouteRule{
+			Name:    RuleName(rule, i),
+			Timeout: RuleTimeout(rule, rc.RequestTimeout),
+		}
+		for _, backendRef := range rule.BackendRefs {
+			weight := int32(1)
+			if backendRef.Weight != nil {
+				weight = *backendRef.Weight
+			}
+			// A rule none of whose backends resolve is kept, so that its
+			// requests are answered with 500 too.
+			backend, err := ResolveBackendRef(route.Namespace, backendRef.BackendObjectReference, rc.Targets)
+			if err != nil {
+				pRule.Backends = append(pRule.Backends, proxy.WeightedBackend{Weight: weight, Invalid: true})
+				continue
+			}
+			pRule.Backends = append(pRule.Backends, proxy.WeightedBackend{
+				Backend: backend,
+				Weight:  weight,
+				Filters: BackendFilters(route.Namespace, backendRef.Filters, rc.Targets),
+			})
+		}
+
+		for _, filter := range rule.Filters {
+			if key, ok := ExtensionRefConfigMap(route.Namespace, filter); ok {
+				pRule.Hooks = append(pRule.Hooks, rc.Hooks[key])
+			}
+		}
+
+		for _, match := range rule.Matches {
+			pRule.Matches = append(pRule.Matches, translateMatch(match))
+		}
+		pr.Rules = append(pr.Rules, pRule)
+	}
+	return pr
+}
+
+// translateMatch translates a match of a rule.
+func translateMatch(match gatewayv1.HTTPRouteMatch) proxy.RouteMatch {
+	pMatch := proxy.RouteMatch{}
+	if match.Path != nil {
+		pathType := gatewayv1.PathMatchPathPrefix
+		if match.Path.Type != nil {
+			pathType = *match.Path.Type
+		}
+		pMatch.Path = &proxy.PathMatch{
+			Type:  proxy.PathMatchType(pathType),
+			Value: *match.Path.Value,
+		}
+	}
+	for _, header := range match.Headers {
+		headerType := gatewayv1.HeaderMatchExact
+		if header.Type != nil {
+			headerType = *header.Type
+		}
+		hm := proxy.HeaderMatch{
+			Type:            string(headerType),
+			Name:            string(header.Name),
+			MatchExactValue: header.Value,
+		}
+		if headerType == gatewayv1.HeaderMatchRegularExpression {
+			re, err := regexp.Compile(header.Value)
+			if err != nil {
+				continue
+			}
+			hm.MatchRegularExpressionValue = re
+		}
+		pMatch.Headers = append(pMatch.Headers, hm)
+	}
+	return pMatch
+}
+
+// servedHostnames returns the hostnames the proxy serves a route for: the
+// route's hostnames narrowed to those of the listeners it is attached to. The
+// proxy serves every listener on one address, so a parentRef's port only
+// selects listeners.
+func servedHostnames(route *gatewayv1.HTTPRoute, rc routeContext) []string {
+	listeners := make([]*gatewayv1.Listener, 0, len(rc.Listeners))
+	for _, a := range rc.Listeners {
+		listeners = append(listeners, a.Listener)
+	}
+	hostnames, _ := RouteHostnames(listeners, route.Spec.Hostnames)
+	return hostnames
+}
+
+// RuleName returns the name of the i-th rule of a route, or its index if the
+// rule is unnamed.
+func RuleName(rule gatewayv1.HTTPRouteRule, i int) string {
+	if rule.Name != nil {
+		return string(*rule.Name)
+	}
+	return strconv.Itoa(i)
+}
+
+// RuleTimeout returns the request timeout of a rule, falling back to the
+// default of its Gateway. A zero timeout in the rule disables the default.
+func RuleTimeout(rule gatewayv1.HTTPRouteRule, gatewayDefault time.Duration) time.Duration {
+	if rule.Timeouts == nil || rule.Timeouts.Request == nil {
+		return gatewayDefault
+	}
+	timeout, err := time.ParseDuration(string(*rule.Timeouts.Request))
+	if err != nil {
+		return gatewayDefault
+	}
+	return timeout
+}
+
+// ExtensionRefConfigMap returns the ConfigMap referenced by an ExtensionRef
+// filter, if the filter is one.
+func ExtensionRefConfigMap(namespace string, filter gatewayv1.HTTPRouteFilter) (types.NamespacedName, bool) {
+	if filter.Type != gatewayv1.HTTPRouteFilterExtensionRef || filter.ExtensionRef == nil {
+		return types.NamespacedName{}, false
+	}
+	ref := filter.ExtensionRef
+	if ref.Group != "" || ref.Kind != "ConfigMap" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: namespace, Name: string(ref.Name)}, true
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"testing"
+	"time"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestRuleTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts *gatewayv1.HTTPRouteTimeouts
+		expected time.Duration
+	}{
+		{
+			name:     "gateway default",
+			expected: time.Minute,
+		},
+		{
+			name:     "rule timeout",
+			timeouts: &gatewayv1.HTTPRouteTimeouts{Request: ptr(gatewayv1.Duration("10s"))},
+			expected: 10 * time.Second,
+		},
+		{
+			name:     "disabled by rule",
+			timeouts: &gatewayv1.HTTPRouteTimeouts{Request: ptr(gatewayv1.Duration("0s"))},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := RuleTimeout(gatewayv1.HTTPRouteRule{Timeouts: tt.timeouts}, time.Minute)
+			if actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ValidateRoute reports the first configuration of the route that the proxy
+// cannot serve. Build does not accept such routes, and the admission webhook
+// rejects them.
+func ValidateRoute(route *gatewayv1.HTTPRoute) error {
+	for _, hostname := range route.Spec.Hostnames {
+		if err := validateHostname(hostname); err != nil {
+			return err
+		}
+	}
+	for i, rule := range route.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			for _, filter := range backendRef.Filters {
+				if !slices.Contains(SupportedBackendFilters, filter.Type) {
+					return fmt.Errorf("rule %s: filter %s is not supported on backendRefs", RuleName(rule, i), filter.Type)
+				}
+			}
+		}
+		for _, match := range rule.Matches {
+			if match.Path != nil {
+				if err := validatePathMatch(match.Path); err != nil {
+					return fmt.Errorf("rule %s: %w", RuleName(rule, i), err)
+				}
+			}
+			for _, header := range match.Headers {
+				if header.Type != nil && *header.Type == gatewayv1.HeaderMatchRegularExpression {
+					if _, err := regexp.Compile(header.Value); err != nil {
+						return fmt.Errorf("rule %s: invalid regular expression in header match: %w", RuleName(rule, i), err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateHostname checks that a route hostname is a DNS subdomain, optionally
+// prefixed with a single "*." wildcard label. IP addresses are not allowed.
+func validateHostname(hostname gatewayv1.Hostname) error {
+	h := string(hostname)
+	if net.ParseIP(h) != nil {
+		return fmt.Errorf("hostname %q must not be an IP address", h)
+	}
+	name := strings.TrimPrefix(h, "*.")
+	if strings.Contains(name, "*") {
+		return fmt.Errorf("hostname %q may only use a wildcard as its first label", h)
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("hostname %q is invalid: %s", h, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validatePathMatch checks that a path match uses a supported type and that
+// its value is an absolute, normalized path the proxy can match reliably.
+func validatePathMatch(match *gatewayv1.HTTPPathMatch) error {
+	pathType := gatewayv1.PathMatchPathPrefix
+	if match.Type != nil {
+		pathType = *match.Type
+	}
+	if pathType != gatewayv1.PathMatchExact && pathType != gatewayv1.PathMatchPathPrefix {
+		return fmt.Errorf("path match type %s is not supported", pathType)
+	}
+	if match.Value == nil {
+		return nil
+	}
+	path := *match.Value
+	switch {
+	case !strings.HasPrefix(path, "/"):
+		return fmt.Errorf("path %q must start with /", path)
+	case strings.Contains(path, "//"):
+		return fmt.Errorf("path %q must not contain //", path)
+	case strings.ContainsAny(path, "#?"):
+		return fmt.Errorf("path %q must not contain a query or fragment", path)
+	case strings.Contains(strings.ToLower(path), "%2f"):
+		return fmt.Errorf("path %q must not contain an encoded /", path)
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("path %q must not contain %s segments", path, segment)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ir
+
+import (
+	"testing"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestValidateHostname(t *testing.T) {
+	tests := []struct {
+		hostname gatewayv1.Hostname
+		valid    bool
+	}{
+		{hostname: "example.com", valid: true},
+		{hostname: "*.example.com", valid: true},
+		{hostname: "foo.*.example.com", valid: false},
+		{hostname: "*example.com", valid: false},
+		{hostname: "Example.com", valid: false},
+		{hostname: "-foo.example.com", valid: false},
+		{hostname: "10.0.0.1", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.hostname), func(t *testing.T) {
+			err := validateHostname(tt.hostname)
+			if (err == nil) != tt.valid {
+				t.Errorf("expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidatePathMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		match gatewayv1.HTTPPathMatch
+		valid bool
+	}{
+		{name: "prefix", match: gatewayv1.HTTPPathMatch{Value: ptr("/api/v1")}, valid: true},
+		{name: "root", match: gatewayv1.HTTPPathMatch{Type: ptr(gatewayv1.PathMatchExact), Value: ptr("/")}, valid: true},
+		{name: "relative", match: gatewayv1.HTTPPathMatch{Value: ptr("api")}, valid: false},
+		{name: "double slash", match: gatewayv1.HTTPPathMatch{Value: ptr("/api//v1")}, valid: false},
+		{name: "dot segment", match: gatewayv1.HTTPPathMatch{Value: ptr("/api/../admin")}, valid: false},
+		{name: "encoded slash", match: gatewayv1.HTTPPathMatch{Value: ptr("/api%2Fv1")}, valid: false},
+		{name: "fragment", match: gatewayv1.HTTPPathMatch{Value: ptr("/api#v1")}, valid: false},
+		{name: "regular expression", match: gatewayv1.HTTPPathMatch{Type: ptr(gatewayv1.PathMatchRegularExpression), Value: ptr("/api/.*")}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePathMatch(&tt.match)
+			if (err == nil) != tt.valid {
+				t.Errorf("expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
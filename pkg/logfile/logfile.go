@@ -0,0 +1,225 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logfile writes logs to a file that is rotated once it grows past a
+// size or gets older than an interval, for environments that collect logs by
+// tailing files rather than container output. Rotated files are kept next to
+// the file, optionally compressed, up to a number of backups.
+package logfile
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// backupTimeFormat is the format of the time a backup was rotated at, in its
+// name, which sorts backups from the oldest to the newest.
+const backupTimeFormat = "20060102T150405.000"
+
+// Options configures the rotation of a File.
+type Options struct {
+	// MaxSize is the size in bytes past which the file is rotated, or zero
+	// not to rotate it on size.
+	MaxSize int64
+	// RotationInterval is how long the file is written to before it is
+	// rotated, or zero not to rotate it on time.
+	RotationInterval time.Duration
+	// MaxBackups is how many rotated files are kept, or zero to keep them
+	// all.
+	MaxBackups int
+	// Compress gzips the rotated files.
+	Compress bool
+}
+
+// File is an io.Writer appending to a file and rotating it. Each Write goes
+// to a single file, so that lines written at once are never split across
+// files. It is safe for concurrent use.
+type File struct {
+	path string
+	opts Options
+	now  func() time.Time
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+	// cleaned is signalled when the backups have been compressed and pruned
+	// after a rotation.
+	cleaned chan struct{}
+}
+
+// Open opens the file at path for appending, creating it and its directory
+// if needed.
+func Open(path string, opts Options) (*File, error) {
+	f := &File{path: path, opts: opts, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file, f.size, f.opened = file, info.Size(), f.now()
+	return nil
+}
+
+// Write appends p to the file, rotating it first if p would take it past
+// MaxSize or RotationInterval has passed since it was opened.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.size > 0 && (f.opts.MaxSize > 0 && f.size+int64(len(p)) > f.opts.MaxSize ||
+		f.opts.RotationInterval > 0 && f.now().Sub(f.opened) >= f.opts.RotationInterval) {
+		if err := f.rotate(); err != nil {
+			return 0, fmt.Errorf("unable to rotate %s: %w", f.path, err)
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the file, after the backups of the last rotation have been
+// cleaned up.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cleaned != nil {
+		<-f.cleaned
+	}
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// rotate renames the file to a backup named after the time it is rotated at
+// and opens a new one. Backups are compressed and pruned in the background.
+func (f *File) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	ext := filepath.Ext(f.path)
+	backup := strings.TrimSuffix(f.path, ext) + "-" + f.now().UTC().Format(backupTimeFormat) + ext
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	previous := f.cleaned
+	cleaned := make(chan struct{})
+	f.cleaned = cleaned
+	go func() {
+		defer close(cleaned)
+		if previous != nil {
+			<-previous
+		}
+		if f.opts.Compress {
+			if err := compress(backup); err != nil {
+				log.Log.Error(err, "unable to compress rotated log file", "file", backup)
+			}
+		}
+		if err := f.prune(); err != nil {
+			log.Log.Error(err, "unable to remove old rotated log files", "file", f.path)
+		}
+	}()
+	return nil
+}
+
+// compress replaces the file at path with its gzipped copy.
+func compress(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+// prune removes the oldest backups past MaxBackups.
+func (f *File) prune() error {
+	backups, err := f.backups()
+	if err != nil || f.opts.MaxBackups <= 0 || len(backups) <= f.opts.MaxBackups {
+		return err
+	}
+	for _, backup := range backups[:len(backups)-f.opts.MaxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backups returns the backups of the file, from the oldest to the newest.
+func (f *File) backups() ([]string, error) {
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(f.path, ext) + "-"
+	matches, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return nil, err
+	}
+	var backups []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimSuffix(match, ".gz"), ext)
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(stamp, prefix)); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	slices.Sort(backups)
+	return backups, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logfile
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "access.log")
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := &File{path: path, opts: Options{MaxSize: 10, RotationInterval: time.Hour, MaxBackups: 2, Compress: true}, now: func() time.Time { return now }}
+	if err := f.open(); err != nil {
+		t.Fatalf("unable to open: %v", err)
+	}
+	write := func(line string) {
+		t.Helper()
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("unable to write: %v", err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	// The file is rotated when a write would take it past MaxSize, and when
+	// RotationInterval has passed.
+	write("first\n")
+	write("second\n")
+	write("third\n")
+	now = now.Add(time.Hour)
+	write("fourth\n")
+	if err := f.Close(); err != nil {
+		t.Fatalf("unable to close: %v", err)
+	}
+
+	if content, err := os.ReadFile(path); err != nil || string(content) != "fourth\n" {
+		t.Errorf("expected the file to hold the last line, got %q, %v", content, err)
+	}
+	backups, err := f.backups()
+	if err != nil {
+		t.Fatalf("unable to list backups: %v", err)
+	}
+	expected := []string{
+		filepath.Join(filepath.Dir(path), "access-20260101T000002.000.log.gz"),
+		filepath.Join(filepath.Dir(path), "access-20260101T010003.000.log.gz"),
+	}
+	if len(backups) != len(expected) || backups[0] != expected[0] || backups[1] != expected[1] {
+		t.Fatalf("expected the two newest backups %v, got %v", expected, backups)
+	}
+	if content := gunzip(t, backups[0]); content != "second\n" {
+		t.Errorf("expected the backup to hold the rotated line, got %q", content)
+	}
+}
+
+func gunzip(t *testing.T, path string) string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unable to open %s: %v", path, err)
+	}
+	defer func() { _ = file.Close() }()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("unable to read %s: %v", path, err)
+	}
+	content, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("unable to read %s: %v", path, err)
+	}
+	return string(content)
+}
@@ -0,0 +1,555 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nginx renders the routes computed by ir.Build, as the proxy serves
+// them, as an nginx.conf, to show how they map onto a well-known data plane
+// and to check our translation against it. nginx has no equivalent for some
+// of what routes do, such as CEL transforms and extension hooks; those are
+// left as comments.
+package nginx
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+)
+
+// Prefixes of the internal locations the rendered configuration routes
+// requests through. Requests are rewritten to the location of the rule that
+// serves them when a choice between rules depends on headers, which nginx
+// locations cannot match.
+const (
+	ruleLocationPrefix   = "/.gari/"
+	mirrorLocationPrefix = "/.gari-mirror/"
+)
+
+// htpasswdDir is where the rendered configuration expects the user files of
+// routes requiring basic auth, which are not exported.
+const htpasswdDir = "/etc/nginx/htpasswd/"
+
+// Render returns the nginx.conf serving routes on port 80. If redactor is not
+// nil, the values of the headers it redacts, in matches as in filters, are
+// hidden, so that the configuration can be dumped; it then no longer serves
+// them as the proxy does.
+func Render(routes []proxy.HTTPRoute, redactor *proxy.HeaderRedactor) string {
+	c := newConfig(routes)
+	c.redactor = redactor
+	w := &writer{}
+	w.line("# Generated by gateway-api-reference-implementation from %d routes.", len(routes))
+	w.line("# What nginx cannot express is left as comments.")
+	w.line("events {}")
+	w.line("")
+	w.open("http")
+	// The proxy forwards the Host header of the request as it is.
+	w.line("proxy_http_version 1.1;")
+	w.line("proxy_set_header Host $host;")
+	for _, r := range c.rules {
+		c.renderUpstream(w, r)
+	}
+	for _, hostname := range c.hostnames() {
+		w.line("")
+		c.renderServer(w, hostname)
+	}
+	w.close()
+	return w.String()
+}
+
+// rule is a rule of a route, with the name of its upstream and locations.
+type rule struct {
+	route *proxy.HTTPRoute
+	rule  *proxy.RouteRule
+	id    string
+}
+
+// entry is a match of a rule. Rules without matches have one that matches
+// every request.
+type entry struct {
+	rule  *rule
+	match proxy.RouteMatch
+}
+
+type config struct {
+	rules []*rule
+	// servers holds the entries of the routes for each of their hostnames;
+	// the entries of routes for any hostname are under "_", and are also
+	// served for every other hostname.
+	servers map[string][]entry
+	// redactor, if set, hides the values of sensitive headers.
+	redactor *proxy.HeaderRedactor
+}
+
+// headerValue returns the value of the named header to render.
+func (c *config) headerValue(name, value string) string {
+	if c.redactor == nil {
+		return value
+	}
+	return c.redactor.Value(name, value)
+}
+
+var invalidIDCharacters = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+func newConfig(routes []proxy.HTTPRoute) *config {
+	c := &config{servers: map[string][]entry{}}
+	ids := map[string]bool{}
+	for i := range routes {
+		route := &routes[i]
+		var entries []entry
+		for j := range route.Rules {
+			id := invalidIDCharacters.ReplaceAllString(fmt.Sprintf("%s_%s_%s", route.Namespace, route.Name, route.Rules[j].Name), "_")
+			for n := 2; ids[id]; n++ {
+				id = fmt.Sprintf("%s_%d", strings.TrimSuffix(id, fmt.Sprintf("_%d", n-1)), n)
+			}
+			ids[id] = true
+			r := &rule{route: route, rule: &route.Rules[j], id: id}
+			c.rules = append(c.rules, r)
+			if len(r.rule.Matches) == 0 {
+				entries = append(entries, entry{rule: r})
+			}
+			for _, match := range r.rule.Matches {
+				entries = append(entries, entry{rule: r, match: match})
+			}
+		}
+		hostnames := route.Hostnames
+		if len(hostnames) == 0 || slices.Contains(hostnames, "*") {
+			hostnames = []string{"_"}
+		}
+		for _, hostname := range hostnames {
+			c.servers[hostname] = append(c.servers[hostname], entries...)
+		}
+	}
+	return c
+}
+
+// hostnames returns the server names in order, the catch-all server last.
+func (c *config) hostnames() []string {
+	hostnames := slices.Sorted(maps.Keys(c.servers))
+	hostnames = slices.DeleteFunc(hostnames, func(h string) bool { return h == "_" })
+	return append(hostnames, "_")
+}
+
+func (c *config) renderUpstream(w *writer, r *rule) {
+	var servers []string
+	for _, backend := range r.rule.Backends {
+		if backend.Weight <= 0 || backend.Invalid {
+			continue
+		}
+		if picker := backend.EndpointPicker; picker != nil {
+			for _, endpoint := range picker.Endpoints {
+				servers = append(servers, fmt.Sprintf("server %s weight=%d;", endpoint, backend.Weight))
+			}
+			continue
+		}
+		servers = append(servers, fmt.Sprintf("server %s:%d weight=%d;", backend.Host, backend.Port, backend.Weight))
+	}
+	if len(servers) == 0 {
+		return
+	}
+	w.line("")
+	w.line("# Rule %s of route %s/%s.", r.rule.Name, r.route.Namespace, r.route.Name)
+	w.open("upstream gari_%s", r.id)
+	for _, server := range servers {
+		w.line("%s", server)
+	}
+	w.close()
+}
+
+func (c *config) renderServer(w *writer, hostname string) {
+	entries := c.servers[hostname]
+	if hostname != "_" {
+		entries = append(slices.Clone(entries), c.servers["_"]...)
+	}
+
+	w.open("server")
+	if hostname == "_" {
+		w.line("listen 80 default_server;")
+	} else {
+		w.line("listen 80;")
+	}
+	w.line("server_name %s;", hostname)
+
+	locations := locationsOf(entries)
+	if len(locations) == 0 {
+		w.line("")
+		w.open("location /")
+		w.line("return 404;")
+		w.close()
+	}
+	var dispatched []*rule
+	for _, loc := range locations {
+		w.line("")
+		dispatched = append(dispatched, c.renderLocation(w, loc, entries)...)
+	}
+	for _, r := range uniqueRules(dispatched) {
+		w.line("")
+		w.open("location %s%s/", ruleLocationPrefix, r.id)
+		w.line("internal;")
+		w.line("rewrite ^%s%s(/.*)$ $1 break;", ruleLocationPrefix, r.id)
+		c.renderRule(w, r)
+		w.close()
+	}
+	seen := map[*rule]bool{}
+	for _, e := range entries {
+		if seen[e.rule] {
+			continue
+		}
+		seen[e.rule] = true
+		renderMirrorLocations(w, e.rule)
+	}
+	w.close()
+}
+
+// location is an nginx location: an exact path, or a prefix ending in "/".
+type location struct {
+	exact bool
+	path  string
+}
+
+func (l location) String() string {
+	if l.exact {
+		return "= " + l.path
+	}
+	return l.path
+}
+
+// locationsOf returns the locations the paths of entries need, exact ones
+// first and then the longest prefixes first, as nginx prefers them. The
+// path prefixes of routes only match whole segments, so a prefix without a
+// trailing "/" needs both an exact and a prefix location.
+func locationsOf(entries []entry) []location {
+	set := map[location]bool{}
+	for _, e := range entries {
+		path := e.match.Path
+		switch {
+		case path == nil || path.Type == proxy.PathMatchTypeNone:
+			set[location{path: "/"}] = true
+		case path.Type == proxy.PathMatchTypeExact:
+			set[location{exact: true, path: path.Value}] = true
+		case strings.HasSuffix(path.Value, "/"):
+			set[location{path: path.Value}] = true
+		default:
+			set[location{exact: true, path: path.Value}] = true
+			set[location{path: path.Value + "/"}] = true
+		}
+	}
+	locations := slices.Collect(maps.Keys(set))
+	slices.SortFunc(locations, func(a, b location) int {
+		if a.exact != b.exact {
+			if a.exact {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(cmp.Compare(len(b.path), len(a.path)), cmp.Compare(a.path, b.path))
+	})
+	return locations
+}
+
+// renderLocation renders a location serving the entries matching its path,
+// in the order the proxy prefers them. It returns the rules it dispatches
+// requests to, whose internal locations must be rendered.
+func (c *config) renderLocation(w *writer, loc location, entries []entry) []*rule {
+	var candidates []entry
+	for _, e := range entries {
+		if matchesPath(e.match, loc) {
+			candidates = append(candidates, e)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b entry) int {
+		return comparePrecedence(a.match, b.match)
+	})
+
+	w.open("location %s", loc)
+	defer w.close()
+	if len(candidates[0].match.Headers) == 0 {
+		c.renderRule(w, candidates[0].rule)
+		return nil
+	}
+
+	var dispatched []*rule
+	for i, e := range candidates {
+		w.line("# Rule %s of route %s/%s.", e.rule.rule.Name, e.rule.route.Namespace, e.rule.route.Name)
+		dispatch := fmt.Sprintf("rewrite ^ %s%s$uri last;", ruleLocationPrefix, e.rule.id)
+		dispatched = append(dispatched, e.rule)
+		switch len(e.match.Headers) {
+		case 0:
+			// The proxy never gets past a rule without header matches.
+			w.line("%s", dispatch)
+			return dispatched
+		case 1:
+			w.open("if (%s)", c.headerCondition(e.match.Headers[0], false))
+			w.line("%s", dispatch)
+			w.close()
+		default:
+			// nginx conditions test a single variable, so the header
+			// matches are combined into one.
+			matched := fmt.Sprintf("$gari_match_%d", i)
+			w.line("set %s 1;", matched)
+			for _, header := range e.match.Headers {
+				w.open("if (%s)", c.headerCondition(header, true))
+				w.line("set %s 0;", matched)
+				w.close()
+			}
+			w.open("if (%s)", matched)
+			w.line("%s", dispatch)
+			w.close()
+		}
+	}
+	w.line("return 404;")
+	return dispatched
+}
+
+// matchesPath reports whether a match matches every request path of loc.
+func matchesPath(match proxy.RouteMatch, loc location) bool {
+	path := match.Path
+	switch {
+	case path == nil || path.Type == proxy.PathMatchTypeNone:
+		return true
+	case path.Type == proxy.PathMatchTypeExact:
+		return loc.exact && loc.path == path.Value
+	case path.Value == "/" || loc.path == path.Value:
+		return true
+	case strings.HasSuffix(path.Value, "/"):
+		return strings.HasPrefix(loc.path, path.Value)
+	default:
+		return strings.HasPrefix(loc.path, path.Value+"/")
+	}
+}
+
+// comparePrecedence orders matches as the proxy prefers them: exact paths,
+// then the longest paths, then the most header matches.
+func comparePrecedence(a, b proxy.RouteMatch) int {
+	pathType := func(m proxy.RouteMatch) (proxy.PathMatchType, int) {
+		if m.Path == nil {
+			return proxy.PathMatchTypeNone, 0
+		}
+		return m.Path.Type, len(m.Path.Value)
+	}
+	aType, aLen := pathType(a)
+	bType, bLen := pathType(b)
+	return cmp.Or(
+		cmp.Compare(bType.Weight(), aType.Weight()),
+		cmp.Compare(bLen, aLen),
+		cmp.Compare(len(b.Headers), len(a.Headers)),
+	)
+}
+
+// headerCondition returns the condition of an if directive testing a header
+// match, or its negation.
+func (c *config) headerCondition(header proxy.HeaderMatch, negate bool) string {
+	variable := "$http_" + strings.ReplaceAll(strings.ToLower(header.Name), "-", "_")
+	if header.Type == "RegularExpression" && header.MatchRegularExpressionValue != nil {
+		value := c.headerValue(header.Name, header.MatchRegularExpressionValue.String())
+		if negate {
+			return fmt.Sprintf("%s !~ %s", variable, quote(value))
+		}
+		return fmt.Sprintf("%s ~ %s", variable, quote(value))
+	}
+	value := c.headerValue(header.Name, header.MatchExactValue)
+	if negate {
+		return fmt.Sprintf("%s != %s", variable, quote(value))
+	}
+	return fmt.Sprintf("%s = %s", variable, quote(value))
+}
+
+// renderRule renders the directives serving the requests of a rule.
+func (c *config) renderRule(w *writer, r *rule) {
+	route := r.route
+	w.line("# Rule %s of route %s/%s.", r.rule.Name, route.Namespace, route.Name)
+	if route.Draining {
+		w.line("keepalive_timeout 0;")
+	}
+	if route.Telemetry != nil && !route.Telemetry.AccessLog {
+		w.line("access_log off;")
+	}
+	if route.BasicAuth != nil {
+		w.line("# The users of the route are not exported.")
+		w.line("auth_basic %s;", quote(route.BasicAuth.Realm))
+		w.line("auth_basic_user_file %s%s_%s;", htpasswdDir, route.Namespace, route.Name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(route.SecurityHeaders)) {
+		w.line("proxy_hide_header %s;", name)
+		w.line("add_header %s %s always;", name, quote(c.headerValue(name, route.SecurityHeaders[name])))
+	}
+	if route.Transform != nil {
+		w.line("# Not supported by nginx: CEL header transforms.")
+	}
+	if len(r.rule.Hooks) > 0 {
+		w.line("# Not supported by nginx: %d extension hooks.", len(r.rule.Hooks))
+	}
+
+	var backends []proxy.WeightedBackend
+	invalid := false
+	for _, backend := range r.rule.Backends {
+		switch {
+		case backend.Weight <= 0:
+		case backend.Invalid:
+			invalid = true
+		default:
+			backends = append(backends, backend)
+		}
+	}
+	if len(backends) == 0 {
+		w.line("return 500;")
+		return
+	}
+	if invalid {
+		w.line("# Not supported by nginx: answering the share of requests of invalid backends with 500; they go to the valid backends.")
+	}
+	if backends[0].EndpointPicker != nil {
+		w.line("# Not supported by nginx: endpoint pickers; requests are balanced across the endpoints.")
+	}
+	// nginx applies filters to a location rather than to each upstream
+	// server, so only those shared by every backend can be rendered.
+	filters := backends[0].Filters
+	for _, backend := range backends[1:] {
+		if !reflect.DeepEqual(backend.Filters, filters) {
+			w.line("# Not supported by nginx: filters that differ between backends.")
+			filters = proxy.Filters{}
+			break
+		}
+	}
+	c.renderFilters(w, r, filters)
+	if r.rule.Timeout > 0 {
+		w.line("proxy_connect_timeout %s;", duration(r.rule.Timeout))
+		w.line("proxy_read_timeout %s;", duration(r.rule.Timeout))
+	}
+	scheme := "http"
+	if tls := backends[0].TLS; tls != nil {
+		scheme = "https"
+		w.line("proxy_ssl_server_name on;")
+		if tls.ServerName != "" {
+			w.line("proxy_ssl_name %s;", tls.ServerName)
+		}
+		if tls.CACertificates != nil {
+			w.line("# The CA certificates trusted for the backend are not exported.")
+		}
+	}
+	w.line("proxy_pass %s://gari_%s;", scheme, r.id)
+}
+
+func (c *config) renderFilters(w *writer, r *rule, filters proxy.Filters) {
+	if m := filters.RequestHeaders; m != nil {
+		for _, h := range m.Set {
+			w.line("proxy_set_header %s %s;", h.Name, quote(c.headerValue(h.Name, h.Value)))
+		}
+		for _, h := range m.Add {
+			w.line("proxy_set_header %s %s;", h.Name, quote(fmt.Sprintf("$http_%s,%s", strings.ReplaceAll(strings.ToLower(h.Name), "-", "_"), c.headerValue(h.Name, h.Value))))
+		}
+		for _, name := range m.Remove {
+			w.line("proxy_set_header %s \"\";", name)
+		}
+	}
+	if m := filters.ResponseHeaders; m != nil {
+		for _, h := range m.Set {
+			w.line("proxy_hide_header %s;", h.Name)
+			w.line("add_header %s %s always;", h.Name, quote(c.headerValue(h.Name, h.Value)))
+		}
+		for _, h := range m.Add {
+			w.line("add_header %s %s always;", h.Name, quote(c.headerValue(h.Name, h.Value)))
+		}
+		for _, name := range m.Remove {
+			w.line("proxy_hide_header %s;", name)
+		}
+	}
+	for i, mirror := range filters.Mirrors {
+		if mirror.Fraction > 0 && mirror.Fraction < 1 {
+			w.line("# Not supported by nginx: mirroring a fraction of requests; all of them are mirrored.")
+		}
+		w.line("mirror %s%s/%d;", mirrorLocationPrefix, r.id, i)
+	}
+}
+
+// renderMirrorLocations renders the internal locations the requests of a rule
+// are mirrored through.
+func renderMirrorLocations(w *writer, r *rule) {
+	for _, backend := range r.rule.Backends {
+		if backend.Weight <= 0 || backend.Invalid {
+			continue
+		}
+		for i, mirror := range backend.Filters.Mirrors {
+			scheme := "http"
+			if mirror.Backend.TLS != nil {
+				scheme = "https"
+			}
+			w.line("")
+			w.open("location = %s%s/%d", mirrorLocationPrefix, r.id, i)
+			w.line("internal;")
+			w.line("proxy_pass %s://%s:%d$request_uri;", scheme, mirror.Backend.Host, mirror.Backend.Port)
+			w.close()
+		}
+		// Only the mirrors of the first backend are rendered; see
+		// renderRule.
+		return
+	}
+}
+
+func uniqueRules(rules []*rule) []*rule {
+	seen := map[*rule]bool{}
+	var unique []*rule
+	for _, r := range rules {
+		if !seen[r] {
+			seen[r] = true
+			unique = append(unique, r)
+		}
+	}
+	slices.SortFunc(unique, func(a, b *rule) int { return cmp.Compare(a.id, b.id) })
+	return unique
+}
+
+// duration formats d as an nginx time.
+func duration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+// quote returns s as a quoted nginx string.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// writer writes indented nginx directives.
+type writer struct {
+	strings.Builder
+	indent int
+}
+
+func (w *writer) line(format string, args ...any) {
+	line := fmt.Sprintf(format, args...)
+	if line != "" {
+		w.WriteString(strings.Repeat("    ", w.indent))
+		w.WriteString(line)
+	}
+	w.WriteString("\n")
+}
+
+// open starts a block.
+func (w *writer) open(format string, args ...any) {
+	w.line(format+" {", args...)
+	w.indent++
+}
+
+// close ends the current block.
+func (w *writer) close() {
+	w.indent--
+	w.line("}")
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginx
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/proxy"
+)
+
+func TestRender(t *testing.T) {
+	routes := []proxy.HTTPRoute{
+		{
+			Namespace: "default",
+			Name:      "store",
+			Hostnames: []string{"store.example.com"},
+			BasicAuth: &proxy.BasicAuth{Realm: "store"},
+			Rules: []proxy.RouteRule{
+				{
+					Name:    "0",
+					Matches: []proxy.RouteMatch{{Path: &proxy.PathMatch{Type: proxy.PathMatchTypePathPrefix, Value: "/cart"}}},
+					Backends: []proxy.WeightedBackend{
+						{Backend: proxy.Backend{Host: "cart-v1.default.svc", Port: 8080}, Weight: 90},
+						{Backend: proxy.Backend{Host: "cart-v2.default.svc", Port: 8080}, Weight: 10},
+					},
+					Timeout: 1500 * time.Millisecond,
+				},
+				{
+					Name: "1",
+					Matches: []proxy.RouteMatch{{
+						Path: &proxy.PathMatch{Type: proxy.PathMatchTypePathPrefix, Value: "/"},
+						Headers: []proxy.HeaderMatch{
+							{Type: "Exact", Name: "X-Canary", MatchExactValue: "true"},
+							{Type: "RegularExpression", Name: "User-Agent", MatchRegularExpressionValue: regexp.MustCompile(`Mobile`)},
+						},
+					}},
+					Backends: []proxy.WeightedBackend{{
+						Backend: proxy.Backend{Host: "canary.default.svc", Port: 443, TLS: &proxy.BackendTLS{ServerName: "canary.example.com"}},
+						Weight:  1,
+						Filters: proxy.Filters{
+							RequestHeaders: &proxy.HeaderModifier{Set: []proxy.Header{{Name: "X-Canary", Value: "yes"}}},
+							Mirrors:        []proxy.Mirror{{Backend: proxy.Backend{Host: "shadow.default.svc", Port: 80}, Fraction: 0.5}},
+						},
+					}},
+				},
+				{
+					Name:     "2",
+					Backends: []proxy.WeightedBackend{{Backend: proxy.Backend{Host: "store.default.svc", Port: 80}, Weight: 1}},
+				},
+			},
+		},
+		{
+			Namespace: "default",
+			Name:      "fallback",
+			Rules: []proxy.RouteRule{{
+				Name:     "0",
+				Matches:  []proxy.RouteMatch{{Path: &proxy.PathMatch{Type: proxy.PathMatchTypeExact, Value: "/healthz"}}},
+				Backends: []proxy.WeightedBackend{{Weight: 1, Invalid: true}},
+			}},
+		},
+	}
+
+	conf := Render(routes, nil)
+	for _, expected := range []string{
+		"upstream gari_default_store_0 {\n        server cart-v1.default.svc:8080 weight=90;\n        server cart-v2.default.svc:8080 weight=10;\n    }",
+		"server {\n        listen 80;\n        server_name store.example.com;",
+		"server {\n        listen 80 default_server;\n        server_name _;",
+		// A path prefix matches whole segments.
+		"location = /cart {",
+		"location /cart/ {",
+		"proxy_connect_timeout 1500ms;",
+		"auth_basic \"store\";\n",
+		"auth_basic_user_file /etc/nginx/htpasswd/default_store;",
+		// Header matches dispatch to the rule's internal location, falling
+		// back to the rule without them.
+		"set $gari_match_0 1;",
+		"if ($http_x_canary != \"true\") {",
+		"if ($http_user_agent !~ \"Mobile\") {",
+		"rewrite ^ /.gari/default_store_1$uri last;",
+		"rewrite ^ /.gari/default_store_2$uri last;",
+		"location /.gari/default_store_1/ {\n            internal;",
+		"proxy_set_header X-Canary \"yes\";",
+		"proxy_ssl_name canary.example.com;",
+		"proxy_pass https://gari_default_store_1;",
+		"# Not supported by nginx: mirroring a fraction of requests",
+		"location = /.gari-mirror/default_store_1/0 {",
+		"proxy_pass http://shadow.default.svc:80$request_uri;",
+		// Routes without hostnames are served for every hostname, and
+		// rules without valid backends fail.
+		"location = /healthz {\n            # Rule 0 of route default/fallback.\n            return 500;",
+	} {
+		if !strings.Contains(conf, expected) {
+			t.Errorf("expected the configuration to contain %q, got:\n%s", expected, conf)
+		}
+	}
+	if strings.Contains(conf, "upstream gari_default_fallback_0") {
+		t.Errorf("expected no upstream for a rule without valid backends, got:\n%s", conf)
+	}
+	if count := strings.Count(conf, "location = /healthz {"); count != 2 {
+		t.Errorf("expected the route without hostnames in both servers, got it %d times", count)
+	}
+	if strings.Count(conf, "{") != strings.Count(conf, "}") {
+		t.Errorf("expected balanced blocks, got:\n%s", conf)
+	}
+}
+
+func TestRenderWithoutRoutes(t *testing.T) {
+	conf := Render(nil, nil)
+	if !strings.Contains(conf, "server_name _;") || !strings.Contains(conf, "return 404;") {
+		t.Errorf("expected a default server answering 404, got:\n%s", conf)
+	}
+}
+
+func TestRenderRedacted(t *testing.T) {
+	routes := []proxy.HTTPRoute{{
+		Namespace: "default",
+		Name:      "api",
+		Rules: []proxy.RouteRule{{
+			Name: "0",
+			Matches: []proxy.RouteMatch{{Headers: []proxy.HeaderMatch{
+				{Type: "Exact", Name: "Authorization", MatchExactValue: "Bearer match-secret"},
+			}}},
+			Backends: []proxy.WeightedBackend{{
+				Backend: proxy.Backend{Host: "api.default.svc", Port: 80},
+				Weight:  1,
+				Filters: proxy.Filters{RequestHeaders: &proxy.HeaderModifier{
+					Set: []proxy.Header{{Name: "Authorization", Value: "Bearer set-secret"}, {Name: "X-Env", Value: "prod"}},
+				}},
+			}},
+		}},
+	}}
+
+	conf := Render(routes, proxy.NewHeaderRedactor(proxy.DefaultRedactedHeaders))
+	if strings.Contains(conf, "secret") {
+		t.Errorf("expected the values of redacted headers to be hidden, got:\n%s", conf)
+	}
+	if !strings.Contains(conf, `proxy_set_header X-Env "prod";`) {
+		t.Errorf("expected the values of other headers to be kept, got:\n%s", conf)
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otlp pushes the metrics of a Prometheus registry to an
+// OpenTelemetry collector over OTLP/HTTP, for environments that collect
+// metrics through OpenTelemetry pipelines rather than by scraping. Metrics
+// are encoded as JSON, which every OTLP/HTTP receiver accepts, with
+// cumulative temporality as Prometheus counts.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultInterval is how often metrics are pushed unless
+// MetricsExporterOptions sets another interval.
+const DefaultInterval = time.Minute
+
+// MetricsExporterOptions configures a MetricsExporter.
+type MetricsExporterOptions struct {
+	// Endpoint is the URL metrics are POSTed to, the collector's OTLP/HTTP
+	// metrics endpoint, such as http://otel-collector:4318/v1/metrics.
+	Endpoint string
+	// Headers are set on every request, to authenticate to the collector.
+	Headers map[string]string
+	// Interval is how often metrics are pushed, or DefaultInterval if zero.
+	Interval time.Duration
+	// Gatherer is the registry whose metrics are pushed.
+	Gatherer prometheus.Gatherer
+	// Resource holds the attributes of the resource the metrics describe,
+	// such as service.name.
+	Resource map[string]string
+}
+
+// MetricsExporter pushes metrics periodically, and once more when it stops.
+// It implements manager.Runnable and must be added to the manager, or
+// started, to push metrics.
+type MetricsExporter struct {
+	opts   MetricsExporterOptions
+	client *http.Client
+	start  time.Time
+}
+
+// NewMetricsExporter returns a MetricsExporter configured by opts.
+func NewMetricsExporter(opts MetricsExporterOptions) *MetricsExporter {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	return &MetricsExporter{
+		opts:   opts,
+		client: &http.Client{Timeout: 10 * time.Second},
+		start:  time.Now(),
+	}
+}
+
+// Start pushes metrics every interval until ctx is done, then pushes them
+// a last time.
+func (e *MetricsExporter) Start(ctx context.Context) error {
+	l := log.FromContext(ctx).WithName("otlp")
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.client.Timeout)
+			defer cancel()
+			if err := e.Export(flushCtx); err != nil {
+				l.Error(err, "unable to push metrics", "endpoint", e.opts.Endpoint)
+			}
+			return nil
+		case <-ticker.C:
+			if err := e.Export(ctx); err != nil {
+				l.Error(err, "unable to push metrics", "endpoint", e.opts.Endpoint)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, since every
+// replica pushes its own metrics.
+func (e *MetricsExporter) NeedLeaderElection() bool {
+	return false
+}
+
+// Export pushes the current value of every metric.
+func (e *MetricsExporter) Export(ctx context.Context) error {
+	families, err := e.opts.Gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return err
+	}
+	body, err := json.Marshal(e.request(families, time.Now()))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.opts.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, value := range e.opts.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("OTLP endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// The messages of an OTLP ExportMetricsServiceRequest, in the JSON encoding
+// of protobuf, where 64-bit integers are strings.
+type (
+	exportRequest struct {
+		ResourceMetrics []resourceMetrics `json:"resourceMetrics"`
+	}
+	resourceMetrics struct {
+		Resource     resource       `json:"resource"`
+		ScopeMetrics []scopeMetrics `json:"scopeMetrics"`
+	}
+	resource struct {
+		Attributes []keyValue `json:"attributes,omitempty"`
+	}
+	scopeMetrics struct {
+		Scope   scope    `json:"scope"`
+		Metrics []metric `json:"metrics"`
+	}
+	scope struct {
+		Name string `json:"name"`
+	}
+	metric struct {
+		Name      string     `json:"name"`
+		Help      string     `json:"description,omitempty"`
+		Gauge     *gauge     `json:"gauge,omitempty"`
+		Sum       *sum       `json:"sum,omitempty"`
+		Histogram *histogram `json:"histogram,omitempty"`
+		Summary   *summary   `json:"summary,omitempty"`
+	}
+	gauge struct {
+		DataPoints []numberDataPoint `json:"dataPoints"`
+	}
+	sum struct {
+		DataPoints             []numberDataPoint `json:"dataPoints"`
+		AggregationTemporality int               `json:"aggregationTemporality"`
+		IsMonotonic            bool              `json:"isMonotonic"`
+	}
+	histogram struct {
+		DataPoints             []histogramDataPoint `json:"dataPoints"`
+		AggregationTemporality int                  `json:"aggregationTemporality"`
+	}
+	summary struct {
+		DataPoints []summaryDataPoint `json:"dataPoints"`
+	}
+	numberDataPoint struct {
+		Attributes        []keyValue `json:"attributes,omitempty"`
+		StartTimeUnixNano string     `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string     `json:"timeUnixNano"`
+		AsDouble          float64    `json:"asDouble"`
+	}
+	histogramDataPoint struct {
+		Attributes        []keyValue `json:"attributes,omitempty"`
+		StartTimeUnixNano string     `json:"startTimeUnixNano"`
+		TimeUnixNano      string     `json:"timeUnixNano"`
+		Count             string     `json:"count"`
+		Sum               float64    `json:"sum"`
+		BucketCounts      []string   `json:"bucketCounts"`
+		ExplicitBounds    []float64  `json:"explicitBounds"`
+	}
+	summaryDataPoint struct {
+		Attributes        []keyValue      `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		Count             string          `json:"count"`
+		Sum               float64         `json:"sum"`
+		QuantileValues    []quantileValue `json:"quantileValues"`
+	}
+	quantileValue struct {
+		Quantile float64 `json:"quantile"`
+		Value    float64 `json:"value"`
+	}
+	keyValue struct {
+		Key   string   `json:"key"`
+		Value anyValue `json:"value"`
+	}
+	anyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+// aggregationTemporalityCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE.
+const aggregationTemporalityCumulative = 2
+
+// request returns the export request of the metric families at now.
+func (e *MetricsExporter) request(families []*dto.MetricFamily, now time.Time) exportRequest {
+	start, timestamp := unixNano(e.start), unixNano(now)
+	var metrics []metric
+	for _, family := range families {
+		m := metric{Name: family.GetName(), Help: family.GetHelp()}
+		for _, sample := range family.GetMetric() {
+			attributes := labels(sample.GetLabel())
+			// JSON has no NaN or infinities, which gauges may be set to.
+			if value := sample.GetGauge().GetValue() + sample.GetCounter().GetValue() + sample.GetUntyped().GetValue(); math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				if m.Sum == nil {
+					m.Sum = &sum{AggregationTemporality: aggregationTemporalityCumulative, IsMonotonic: true}
+				}
+				m.Sum.DataPoints = append(m.Sum.DataPoints, numberDataPoint{
+					Attributes: attributes, StartTimeUnixNano: start, TimeUnixNano: timestamp, AsDouble: sample.GetCounter().GetValue(),
+				})
+			case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+				if m.Gauge == nil {
+					m.Gauge = &gauge{}
+				}
+				value := sample.GetGauge().GetValue()
+				if family.GetType() == dto.MetricType_UNTYPED {
+					value = sample.GetUntyped().GetValue()
+				}
+				m.Gauge.DataPoints = append(m.Gauge.DataPoints, numberDataPoint{
+					Attributes: attributes, TimeUnixNano: timestamp, AsDouble: value,
+				})
+			case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+				if m.Histogram == nil {
+					m.Histogram = &histogram{AggregationTemporality: aggregationTemporalityCumulative}
+				}
+				m.Histogram.DataPoints = append(m.Histogram.DataPoints, histogramPoint(sample.GetHistogram(), attributes, start, timestamp))
+			case dto.MetricType_SUMMARY:
+				if m.Summary == nil {
+					m.Summary = &summary{}
+				}
+				point := summaryDataPoint{
+					Attributes:        attributes,
+					StartTimeUnixNano: start,
+					TimeUnixNano:      timestamp,
+					Count:             strconv.FormatUint(sample.GetSummary().GetSampleCount(), 10),
+					Sum:               sample.GetSummary().GetSampleSum(),
+				}
+				for _, q := range sample.GetSummary().GetQuantile() {
+					// Quantiles without observations are NaN.
+					if !math.IsNaN(q.GetValue()) {
+						point.QuantileValues = append(point.QuantileValues, quantileValue{Quantile: q.GetQuantile(), Value: q.GetValue()})
+					}
+				}
+				m.Summary.DataPoints = append(m.Summary.DataPoints, point)
+			}
+		}
+		if m.Sum != nil || m.Gauge != nil || m.Histogram != nil || m.Summary != nil {
+			metrics = append(metrics, m)
+		}
+	}
+
+	var attributes []keyValue
+	for key, value := range e.opts.Resource {
+		attributes = append(attributes, keyValue{Key: key, Value: anyValue{StringValue: value}})
+	}
+	return exportRequest{ResourceMetrics: []resourceMetrics{{
+		Resource:     resource{Attributes: sortedAttributes(attributes)},
+		ScopeMetrics: []scopeMetrics{{Scope: scope{Name: "github.com/gke-labs/gateway-api-reference-implementation"}, Metrics: metrics}},
+	}}}
+}
+
+// histogramPoint converts a Prometheus histogram, whose buckets count the
+// observations up to their bound, to an OTLP one, whose buckets count those
+// since the previous bound, with the last bucket counting those above every
+// bound.
+func histogramPoint(h *dto.Histogram, attributes []keyValue, start, timestamp string) histogramDataPoint {
+	point := histogramDataPoint{
+		Attributes:        attributes,
+		StartTimeUnixNano: start,
+		TimeUnixNano:      timestamp,
+		Count:             strconv.FormatUint(h.GetSampleCount(), 10),
+		Sum:               h.GetSampleSum(),
+		ExplicitBounds:    []float64{},
+	}
+	var previous uint64
+	for _, bucket := range h.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), 1) {
+			continue
+		}
+		point.ExplicitBounds = append(point.ExplicitBounds, bucket.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(bucket.GetCumulativeCount()-previous, 10))
+		previous = bucket.GetCumulativeCount()
+	}
+	point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(h.GetSampleCount()-previous, 10))
+	return point
+}
+
+func labels(pairs []*dto.LabelPair) []keyValue {
+	var attributes []keyValue
+	for _, pair := range pairs {
+		attributes = append(attributes, keyValue{Key: pair.GetName(), Value: anyValue{StringValue: pair.GetValue()}})
+	}
+	return attributes
+}
+
+func sortedAttributes(attributes []keyValue) []keyValue {
+	slices.SortFunc(attributes, func(a, b keyValue) int { return strings.Compare(a.Key, b.Key) })
+	return attributes
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// ParseHeaders parses headers given as comma-separated name=value pairs, as
+// OTEL_EXPORTER_OTLP_HEADERS gives them.
+func ParseHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			return nil, fmt.Errorf("header must be given as name=value, got %q", pair)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestExport(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests_total", Help: "Requests."}, []string{"code"})
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency_seconds", Buckets: []float64{0.1, 1}})
+	unset := prometheus.NewGauge(prometheus.GaugeOpts{Name: "unset"})
+	registry.MustRegister(requests, latency, unset)
+	requests.WithLabelValues("200").Add(3)
+	for _, v := range []float64{0.05, 0.5, 0.7, 5} {
+		latency.Observe(v)
+	}
+	unset.Set(math.NaN())
+
+	var received exportRequest
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("unable to decode request: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	e := NewMetricsExporter(MetricsExporterOptions{
+		Endpoint: server.URL + "/v1/metrics",
+		Headers:  map[string]string{"Authorization": "Bearer token"},
+		Gatherer: registry,
+		Resource: map[string]string{"service.name": "gari"},
+	})
+	if err := e.Export(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authorization != "Bearer token" {
+		t.Errorf("expected the headers to be set, got %q", authorization)
+	}
+	if len(received.ResourceMetrics) != 1 {
+		t.Fatalf("expected one resource, got %+v", received)
+	}
+	rm := received.ResourceMetrics[0]
+	if expected := []keyValue{{Key: "service.name", Value: anyValue{StringValue: "gari"}}}; !reflect.DeepEqual(rm.Resource.Attributes, expected) {
+		t.Errorf("expected resource attributes %v, got %v", expected, rm.Resource.Attributes)
+	}
+	metrics := map[string]metric{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	if _, ok := metrics["unset"]; ok {
+		t.Errorf("expected NaN gauges to be skipped")
+	}
+
+	counter := metrics["requests_total"].Sum
+	if counter == nil || !counter.IsMonotonic || counter.AggregationTemporality != aggregationTemporalityCumulative ||
+		len(counter.DataPoints) != 1 || counter.DataPoints[0].AsDouble != 3 || counter.DataPoints[0].Attributes[0].Value.StringValue != "200" {
+		t.Errorf("expected a cumulative monotonic sum of 3 for code 200, got %+v", counter)
+	}
+	h := metrics["latency_seconds"].Histogram
+	if h == nil || len(h.DataPoints) != 1 {
+		t.Fatalf("expected a histogram, got %+v", metrics["latency_seconds"])
+	}
+	point := h.DataPoints[0]
+	if point.Count != "4" || !reflect.DeepEqual(point.ExplicitBounds, []float64{0.1, 1}) || !reflect.DeepEqual(point.BucketCounts, []string{"1", "2", "1"}) {
+		t.Errorf("expected 4 observations in buckets [1 2 1], got %+v", point)
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders("Authorization=Bearer abc=, x-tenant = a ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := map[string]string{"Authorization": "Bearer abc=", "x-tenant": "a"}; !reflect.DeepEqual(headers, expected) {
+		t.Errorf("expected %v, got %v", expected, headers)
+	}
+	if _, err := ParseHeaders("Authorization"); err == nil {
+		t.Errorf("expected a header without a value to be rejected")
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AccessLogFormat selects how access log entries are written.
+type AccessLogFormat string
+
+const (
+	// AccessLogFormatText writes entries as the key/value pairs of the
+	// fields they are configured with.
+	AccessLogFormatText AccessLogFormat = "Text"
+	// AccessLogFormatCommon writes entries in the Common Log Format, for
+	// pipelines that ingest the logs of web servers. The fields entries are
+	// configured with do not apply.
+	AccessLogFormatCommon AccessLogFormat = "Common"
+)
+
+// AccessLogFormats lists the valid access log formats.
+var AccessLogFormats = []AccessLogFormat{AccessLogFormatText, AccessLogFormatCommon}
+
+// AccessLogFields lists the fields access log entries can be configured
+// with.
+var AccessLogFields = []string{
+	"method", "host", "path", "protocol", "status", "bytes", "duration",
+	"route", "rule", "traceparent", "trace_id", "span_id", "remote_addr", "user_agent",
+}
+
+// DefaultAccessLogFields are the fields of access log entries, in order,
+// unless Options sets others.
+var DefaultAccessLogFields = []string{"method", "host", "path", "status", "duration", "route", "rule", "traceparent", "trace_id", "span_id"}
+
+// accessLogEntry holds what the access log records of a served request.
+type accessLogEntry struct {
+	request  *http.Request
+	status   int
+	bytes    int64
+	start    time.Time
+	duration time.Duration
+	route    string
+	rule     string
+}
+
+// field returns the value of the named field of the entry.
+func (e *accessLogEntry) field(name string) any {
+	switch name {
+	case "method":
+		return e.request.Method
+	case "host":
+		return e.request.Host
+	case "path":
+		return e.request.URL.Path
+	case "protocol":
+		return e.request.Proto
+	case "status":
+		return e.status
+	case "bytes":
+		return e.bytes
+	case "duration":
+		return e.duration.String()
+	case "route":
+		return e.route
+	case "rule":
+		return e.rule
+	case "traceparent":
+		return e.request.Header.Get(traceparentHeader)
+	case "trace_id":
+		traceID, _ := traceIDs(e.request)
+		return traceID
+	case "span_id":
+		_, spanID := traceIDs(e.request)
+		return spanID
+	case "remote_addr":
+		return e.request.RemoteAddr
+	case "user_agent":
+		return e.request.UserAgent()
+	}
+	return nil
+}
+
+// accessLogger writes access log entries with the configured format and
+// fields.
+type accessLogger struct {
+	format AccessLogFormat
+	fields []string
+	// static holds the key/value pairs of the static fields, sorted by key.
+	static []any
+	// logger writes Text entries, and output Common Log Format entries.
+	logger logr.Logger
+	output io.Writer
+}
+
+func newAccessLogger(opts Options) *accessLogger {
+	l := &accessLogger{
+		format: cmp.Or(opts.AccessLogFormat, AccessLogFormatText),
+		fields: opts.AccessLogFields,
+		logger: log.Log.WithName("access"),
+		output: opts.AccessLog,
+	}
+	if l.fields == nil {
+		l.fields = DefaultAccessLogFields
+	}
+	for _, key := range slices.Sorted(maps.Keys(opts.AccessLogStaticFields)) {
+		l.static = append(l.static, key, opts.AccessLogStaticFields[key])
+	}
+	if opts.AccessLog != nil {
+		l.logger = textlogger.NewLogger(textlogger.NewConfig(textlogger.Output(opts.AccessLog))).WithName("access")
+	} else {
+		l.output = os.Stdout
+	}
+	return l
+}
+
+// write writes the entry to the access log.
+func (l *accessLogger) write(entry *accessLogEntry) {
+	if l.format == AccessLogFormatCommon {
+		_, _ = io.WriteString(l.output, commonLogLine(entry))
+		return
+	}
+	keysAndValues := make([]any, 0, 2*len(l.fields)+len(l.static))
+	for _, name := range l.fields {
+		keysAndValues = append(keysAndValues, name, entry.field(name))
+	}
+	l.logger.Info("Request served", append(keysAndValues, l.static...)...)
+}
+
+// commonLogLine formats the entry in the Common Log Format, with the path of
+// the request without its query, which may hold credentials.
+func commonLogLine(entry *accessLogEntry) string {
+	host, _, err := net.SplitHostPort(entry.request.RemoteAddr)
+	if err != nil || host == "" {
+		host = "-"
+	}
+	bytes := "-"
+	if entry.bytes > 0 {
+		bytes = strconv.FormatInt(entry.bytes, 10)
+	}
+	return fmt.Sprintf("%s - - [%s] %q %d %s\n", host, entry.start.Format("02/Jan/2006:15:04:05 -0700"),
+		entry.request.Method+" "+entry.request.URL.Path+" "+entry.request.Proto, entry.status, bytes)
+}
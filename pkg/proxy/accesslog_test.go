@@ -0,0 +1,64 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessLogFormats(t *testing.T) {
+	newEntry := func() *accessLogEntry {
+		r := httptest.NewRequest("GET", "/path?token=secret", nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+		return &accessLogEntry{
+			request:  r,
+			status:   200,
+			bytes:    42,
+			start:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			duration: time.Second,
+			route:    "default/route",
+		}
+	}
+
+	var output bytes.Buffer
+	newAccessLogger(Options{
+		AccessLog:             &output,
+		AccessLogFields:       []string{"status", "path", "bytes"},
+		AccessLogStaticFields: map[string]string{"zone": "a", "cluster": "prod"},
+	}).write(newEntry())
+	if line, expected := output.String(), `"Request served" logger="access" status=200 path="/path" bytes=42 cluster="prod" zone="a"`; !strings.Contains(line, expected) {
+		t.Errorf("expected the entry to hold %s, got %q", expected, line)
+	}
+
+	// The trace and span IDs are taken from the trace context, to follow the
+	// request to its trace.
+	output.Reset()
+	entry := newEntry()
+	entry.request.Header.Set(traceparentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	newAccessLogger(Options{AccessLog: &output, AccessLogFields: []string{"trace_id", "span_id"}}).write(entry)
+	if line, expected := output.String(), `trace_id="0af7651916cd43dd8448eb211c80319c" span_id="b7ad6b7169203331"`; !strings.Contains(line, expected) {
+		t.Errorf("expected the entry to hold %s, got %q", expected, line)
+	}
+
+	output.Reset()
+	newAccessLogger(Options{AccessLog: &output, AccessLogFormat: AccessLogFormatCommon}).write(newEntry())
+	if line, expected := output.String(), "10.0.0.1 - - [02/Jan/2026:03:04:05 +0000] \"GET /path HTTP/1.1\" 200 42\n"; line != expected {
+		t.Errorf("expected %q, got %q", expected, line)
+	}
+}
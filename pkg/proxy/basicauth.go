@@ -0,0 +1,123 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordScheme is the prefix identifying how an htpasswd entry hashes its
+// password.
+type PasswordScheme string
+
+const (
+	// PasswordSchemeBcrypt marks a bcrypt hash, as produced by "htpasswd -B".
+	PasswordSchemeBcrypt PasswordScheme = "$2"
+	// PasswordSchemeSHA marks a password digest produced by "htpasswd -s".
+	// These unsalted SHA-1 digests are only accepted for compatibility.
+	PasswordSchemeSHA PasswordScheme = "{SHA}"
+)
+
+// PasswordHash is the hashed password of an htpasswd entry.
+type PasswordHash struct {
+	Scheme PasswordScheme
+	// Hash is the bcrypt hash as written in the htpasswd file, or the decoded
+	// SHA-1 digest.
+	Hash []byte
+}
+
+// BasicAuth holds the computed state for HTTP Basic authentication on a route.
+type BasicAuth struct {
+	Realm string
+	// Users maps each username to its hashed password.
+	Users map[string]*PasswordHash
+}
+
+// ParseHtpasswd parses htpasswd-formatted data containing "user:hash"
+// entries, where hashes are bcrypt ($2a$, $2b$ or $2y$) or, as a legacy
+// format, {SHA}. Blank lines and lines starting with '#' are ignored.
+func ParseHtpasswd(data []byte) (map[string]*PasswordHash, error) {
+	users := map[string]*PasswordHash{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok || user == "" {
+			return nil, fmt.Errorf("line %d: expected user:hash", lineNumber)
+		}
+		switch {
+		case strings.HasPrefix(hash, string(PasswordSchemeBcrypt)):
+			// Cost parses the whole hash, so it rejects malformed entries.
+			if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+				return nil, fmt.Errorf("line %d: invalid bcrypt hash for user %q: %w", lineNumber, user, err)
+			}
+			users[user] = &PasswordHash{Scheme: PasswordSchemeBcrypt, Hash: []byte(hash)}
+		case strings.HasPrefix(hash, string(PasswordSchemeSHA)):
+			digest, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(hash, string(PasswordSchemeSHA)))
+			if err != nil || len(digest) != sha1.Size {
+				return nil, fmt.Errorf("line %d: invalid {SHA} digest for user %q", lineNumber, user)
+			}
+			users[user] = &PasswordHash{Scheme: PasswordSchemeSHA, Hash: digest}
+		default:
+			return nil, fmt.Errorf("line %d: unsupported hash for user %q, only bcrypt and {SHA} are supported", lineNumber, user)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Authenticate reports whether the request carries valid Basic credentials.
+func (a *BasicAuth) Authenticate(r *http.Request) bool {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	hash, found := a.Users[user]
+	if !found || hash == nil {
+		return false
+	}
+	switch hash.Scheme {
+	case PasswordSchemeBcrypt:
+		return bcrypt.CompareHashAndPassword(hash.Hash, []byte(password)) == nil
+	case PasswordSchemeSHA:
+		got := sha1.Sum([]byte(password))
+		return subtle.ConstantTimeCompare(got[:], hash.Hash) == 1
+	default:
+		return false
+	}
+}
+
+// Challenge writes a 401 response asking the client for Basic credentials.
+func (a *BasicAuth) Challenge(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", a.Realm))
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Generated with: htpasswd -nbs admin secret; the bcrypt hash of ops, with
+// the same password, with crypt(3) and a fixed salt.
+const testHtpasswd = `
+# staging users
+admin:{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=
+ops:$2y$04$saltsaltsaltsaltsaltsOh6eq3EFJkUrLtlyPfsES9Dguki/0EY.
+`
+
+func TestBasicAuth(t *testing.T) {
+	users, err := ParseHtpasswd([]byte(testHtpasswd))
+	if err != nil {
+		t.Fatalf("unexpected error parsing htpasswd: %v", err)
+	}
+	auth := &BasicAuth{Realm: "staging", Users: users}
+
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		noAuth   bool
+		expected bool
+	}{
+		{name: "valid credentials", user: "admin", password: "secret", expected: true},
+		{name: "wrong password", user: "admin", password: "wrong", expected: false},
+		{name: "valid bcrypt credentials", user: "ops", password: "secret", expected: true},
+		{name: "wrong bcrypt password", user: "ops", password: "wrong", expected: false},
+		{name: "unknown user", user: "guest", password: "secret", expected: false},
+		{name: "missing credentials", noAuth: true, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if !tt.noAuth {
+				r.SetBasicAuth(tt.user, tt.password)
+			}
+			if actual := auth.Authenticate(r); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseHtpasswdErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "missing separator", data: "admin"},
+		{name: "truncated bcrypt hash", data: "admin:$2y$05$abcdefghijklmnopqrstuv"},
+		{name: "apr1 hash", data: "admin:$apr1$salt$abcdefghijklmnopqrstuv"},
+		{name: "invalid digest", data: "admin:{SHA}not-base64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseHtpasswd([]byte(tt.data)); err == nil {
+				t.Errorf("expected error for %q", tt.data)
+			}
+		})
+	}
+}
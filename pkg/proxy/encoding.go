@@ -0,0 +1,171 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// Routes are encoded as JSON to be sent to standalone proxies. Most of their
+// state is plain data; the compiled parts below are encoded as the sources
+// they were compiled from, and compiled again when decoded.
+
+// MarshalJSON encodes the regular expression of a header match as its
+// source.
+func (m HeaderMatch) MarshalJSON() ([]byte, error) {
+	type plain HeaderMatch
+	var regex string
+	if m.MatchRegularExpressionValue != nil {
+		regex = m.MatchRegularExpressionValue.String()
+	}
+	return json.Marshal(struct {
+		plain
+		MatchRegularExpressionValue string `json:",omitempty"`
+	}{plain(m), regex})
+}
+
+func (m *HeaderMatch) UnmarshalJSON(data []byte) error {
+	type plain HeaderMatch
+	var decoded struct {
+		plain
+		MatchRegularExpressionValue string `json:",omitempty"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*m = HeaderMatch(decoded.plain)
+	if decoded.MatchRegularExpressionValue != "" {
+		regex, err := regexp.Compile(decoded.MatchRegularExpressionValue)
+		if err != nil {
+			return fmt.Errorf("header %s: %w", m.Name, err)
+		}
+		m.MatchRegularExpressionValue = regex
+	}
+	return nil
+}
+
+// headerTransformConfig is the encoded form of a HeaderTransform.
+type headerTransformConfig struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	When  string `json:"when,omitempty"`
+}
+
+// MarshalJSON encodes a header transform as the expressions it was compiled
+// from, which requires it to have been built by NewHeaderTransform.
+func (t HeaderTransform) MarshalJSON() ([]byte, error) {
+	if t.value == "" {
+		return nil, fmt.Errorf("header %s: transform was not built from expressions", t.Name)
+	}
+	return json.Marshal(headerTransformConfig{Name: t.Name, Value: t.value, When: t.when})
+}
+
+func (t *HeaderTransform) UnmarshalJSON(data []byte) error {
+	var config headerTransformConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	transform, err := NewHeaderTransform(config.Name, config.Value, config.When)
+	if err != nil {
+		return err
+	}
+	*t = transform
+	return nil
+}
+
+// hookConfig is the encoded form of a RequestHook: a WebAssembly plugin, or
+// the error of an extension that could not be loaded.
+type hookConfig struct {
+	Wasm  *wasmConfig `json:"wasm,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+type wasmConfig struct {
+	Name   string `json:"name"`
+	Module []byte `json:"module"`
+}
+
+// MarshalJSON encodes the hooks of a rule as their configuration. Only the
+// hooks built by this package can be encoded.
+func (rule RouteRule) MarshalJSON() ([]byte, error) {
+	type plain RouteRule
+	hooks := make([]hookConfig, 0, len(rule.Hooks))
+	for _, hook := range rule.Hooks {
+		switch h := hook.(type) {
+		case *WasmPlugin:
+			hooks = append(hooks, hookConfig{Wasm: &wasmConfig{Name: h.name, Module: h.wasm}})
+		case *unresolvedHook:
+			hooks = append(hooks, hookConfig{Error: h.err.Error()})
+		default:
+			return nil, fmt.Errorf("rule %s: hook %T cannot be encoded", rule.Name, hook)
+		}
+	}
+	return json.Marshal(struct {
+		plain
+		Hooks []hookConfig `json:",omitempty"`
+	}{plain(rule), hooks})
+}
+
+func (rule *RouteRule) UnmarshalJSON(data []byte) error {
+	type plain RouteRule
+	var decoded struct {
+		plain
+		Hooks []hookConfig `json:",omitempty"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*rule = RouteRule(decoded.plain)
+	rule.Hooks = nil
+	for _, config := range decoded.Hooks {
+		rule.Hooks = append(rule.Hooks, config.hook())
+	}
+	return nil
+}
+
+// decodedPlugins caches the WebAssembly plugins compiled from decoded routes
+// by name and module digest, since every update of a route carries its
+// modules again.
+var decodedPlugins sync.Map
+
+// hook returns the RequestHook described by c. As when an extension cannot
+// be loaded by the controller, a plugin that fails to compile fails the
+// requests it should have handled.
+func (c hookConfig) hook() RequestHook {
+	if c.Wasm == nil {
+		return NewUnresolvedHook(errors.New(c.Error))
+	}
+	digest := sha256.Sum256(c.Wasm.Module)
+	key := c.Wasm.Name + "@" + hex.EncodeToString(digest[:])
+	if plugin, ok := decodedPlugins.Load(key); ok {
+		return plugin.(*WasmPlugin)
+	}
+	plugin, err := NewWasmPlugin(context.Background(), c.Wasm.Name, c.Wasm.Module)
+	if err != nil {
+		return NewUnresolvedHook(err)
+	}
+	actual, loaded := decodedPlugins.LoadOrStore(key, plugin)
+	if loaded {
+		_ = plugin.Close(context.Background())
+	}
+	return actual.(*WasmPlugin)
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouteEncoding(t *testing.T) {
+	wasm, err := os.ReadFile("testdata/set_header.wasm")
+	if err != nil {
+		t.Fatalf("unable to read plugin: %v", err)
+	}
+	plugin, err := NewWasmPlugin(t.Context(), "set-header", wasm)
+	if err != nil {
+		t.Fatalf("unable to load plugin: %v", err)
+	}
+	defer plugin.Close(t.Context())
+	transform, err := NewHeaderTransform("X-Route", `route.name`, `request.method == "GET"`)
+	if err != nil {
+		t.Fatalf("unable to compile transform: %v", err)
+	}
+
+	route := HTTPRoute{
+		Namespace:         "default",
+		Name:              "route",
+		CreationTimestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Hostnames:         []string{"example.com"},
+		Rules: []RouteRule{{
+			Name: "rule",
+			Matches: []RouteMatch{{
+				Path:    &PathMatch{Type: PathMatchTypePathPrefix, Value: "/api"},
+				Headers: []HeaderMatch{{Type: "RegularExpression", Name: "X-Version", MatchRegularExpressionValue: regexp.MustCompile(`^v[0-9]+$`)}},
+			}},
+			Backends: []WeightedBackend{{Backend: Backend{Host: "backend", Port: 8080}, Weight: 1}},
+			Hooks:    []RequestHook{plugin, NewUnresolvedHook(errors.New("ConfigMap not found"))},
+			Timeout:  time.Second,
+		}},
+		Transform: &Transform{RequestHeaders: []HeaderTransform{transform}},
+		Draining:  true,
+	}
+
+	data, err := json.Marshal([]HTTPRoute{route})
+	if err != nil {
+		t.Fatalf("unable to encode route: %v", err)
+	}
+	var decoded []HTTPRoute
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unable to decode route: %v", err)
+	}
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(decoded))
+	}
+	actual := decoded[0]
+	if !actual.CreationTimestamp.Equal(route.CreationTimestamp) || actual.Hostnames[0] != "example.com" || !actual.Draining {
+		t.Errorf("expected the plain fields to be kept, got %+v", actual)
+	}
+	rule := actual.Rules[0]
+	if regex := rule.Matches[0].Headers[0].MatchRegularExpressionValue; regex == nil || regex.String() != `^v[0-9]+$` {
+		t.Errorf("expected the header regular expression to be compiled again, got %v", regex)
+	}
+	if rule.Timeout != time.Second || rule.Backends[0].Host != "backend" {
+		t.Errorf("expected the rule's timeout and backends to be kept, got %+v", rule)
+	}
+	if ht := actual.Transform.RequestHeaders[0]; ht.Name != "X-Route" || ht.Value == nil || ht.When == nil {
+		t.Errorf("expected the transform to be compiled again, got %+v", ht)
+	}
+	if len(rule.Hooks) != 2 {
+		t.Fatalf("expected 2 hooks, got %d", len(rule.Hooks))
+	}
+	if _, ok := rule.Hooks[0].(*WasmPlugin); !ok {
+		t.Errorf("expected a WebAssembly plugin, got %T", rule.Hooks[0])
+	}
+	if _, err := rule.Hooks[1].HandleRequest(nil, RouteMetadata{}); err == nil || err.Error() != "ConfigMap not found" {
+		t.Errorf("expected the unresolved extension to keep failing requests, got %v", err)
+	}
+
+	// Plugins are compiled once, however many times they are decoded.
+	var again []HTTPRoute
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("unable to decode route: %v", err)
+	}
+	if again[0].Rules[0].Hooks[0] != rule.Hooks[0] {
+		t.Errorf("expected the decoded plugin to be reused")
+	}
+
+	// Transforms not built from expressions cannot be sent.
+	route.Transform = &Transform{RequestHeaders: []HeaderTransform{{Name: "X-Route", Value: transform.Value}}}
+	if _, err := json.Marshal(route); err == nil || !strings.Contains(err.Error(), "not built from expressions") {
+		t.Errorf("expected an error encoding a transform without expressions, got %v", err)
+	}
+}
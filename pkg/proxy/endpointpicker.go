@@ -0,0 +1,435 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// EndpointPicker selects the endpoint of an InferencePool that serves a
+// request by calling the pool's endpoint picker, which implements the Envoy
+// external processing protocol as specified by the Gateway API Inference
+// Extension.
+type EndpointPicker struct {
+	// Address is the host:port of the endpoint picker's gRPC service.
+	Address string
+	// FailOpen forwards requests to one of Endpoints when the endpoint picker
+	// cannot be reached, instead of failing them.
+	FailOpen bool
+	// Endpoints are the host:port addresses of the pool's ready endpoints.
+	Endpoints []string
+}
+
+const (
+	// destinationEndpointHeader is set by the endpoint picker to the address
+	// of the endpoint that should serve the request.
+	destinationEndpointHeader = "x-gateway-destination-endpoint"
+
+	processMethod = "/envoy.service.ext_proc.v3.ExternalProcessor/Process"
+
+	// maxPickerBodySize bounds the request bodies sent to the endpoint
+	// picker, which needs the whole body to read the requested model.
+	maxPickerBodySize = 16 << 20
+
+	pickerTimeout = 10 * time.Second
+)
+
+// pickerRejection is an immediate response sent by the endpoint picker, for
+// example when the pool has no capacity for the request.
+type pickerRejection struct {
+	status int
+	body   []byte
+}
+
+func (e *pickerRejection) Error() string {
+	return fmt.Sprintf("endpoint picker rejected the request with status %d", e.status)
+}
+
+// pickerCredentials secures connections to endpoint pickers. Endpoint pickers
+// serve a self-signed certificate by default, so, like other implementations
+// of the protocol, the proxy does not verify it.
+var pickerCredentials = credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})
+
+// pickerConns caches a connection per endpoint picker address.
+var pickerConns sync.Map
+
+func pickerConn(address string) (*grpc.ClientConn, error) {
+	if conn, ok := pickerConns.Load(address); ok {
+		return conn.(*grpc.ClientConn), nil
+	}
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(pickerCredentials))
+	if err != nil {
+		return nil, err
+	}
+	actual, loaded := pickerConns.LoadOrStore(address, conn)
+	if loaded {
+		_ = conn.Close()
+	}
+	return actual.(*grpc.ClientConn), nil
+}
+
+// fallback returns a random endpoint of the pool if the picker fails open.
+func (e *EndpointPicker) fallback() string {
+	if !e.FailOpen || len(e.Endpoints) == 0 {
+		return ""
+	}
+	return e.Endpoints[rand.IntN(len(e.Endpoints))]
+}
+
+// pick asks the endpoint picker for the endpoint that should serve the
+// request. The headers and body set by the endpoint picker are applied to the
+// request.
+func (e *EndpointPicker) pick(r *http.Request) (string, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPickerBodySize+1))
+	// The part read is put back ahead of the rest, so that a body too large
+	// to be sent to the picker is still forwarded whole if it fails open.
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxPickerBodySize {
+		return "", fmt.Errorf("request body exceeds %d bytes", maxPickerBodySize)
+	}
+
+	conn, err := pickerConn(e.Address)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), pickerTimeout)
+	defer cancel()
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}, processMethod, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return "", err
+	}
+
+	endOfStream := len(body) == 0
+	if err := stream.SendMsg(encodeRequestHeaders(r, endOfStream)); err != nil {
+		return "", err
+	}
+	if !endOfStream {
+		if err := stream.SendMsg(encodeRequestBody(body)); err != nil {
+			return "", err
+		}
+	}
+
+	headers := map[string]string{}
+	var mutatedBody []byte
+	bodyMutated := false
+	for {
+		var msg []byte
+		if err := stream.RecvMsg(&msg); err != nil {
+			return "", err
+		}
+		resp, err := decodeProcessingResponse(msg)
+		if err != nil {
+			return "", err
+		}
+		if resp.immediate != nil {
+			return "", resp.immediate
+		}
+		maps.Copy(headers, resp.setHeaders)
+		if resp.bodyMutated {
+			mutatedBody = append(mutatedBody, resp.body...)
+			bodyMutated = true
+		}
+		if (endOfStream && resp.requestHeaders) || (resp.requestBody && resp.bodyDone) {
+			break
+		}
+	}
+	_ = stream.CloseSend()
+
+	endpoint, _, _ := strings.Cut(headers[destinationEndpointHeader], ",")
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return "", errors.New("endpoint picker returned no endpoint")
+	}
+	for name, value := range headers {
+		if !strings.HasPrefix(name, ":") {
+			r.Header.Set(name, value)
+		}
+	}
+	if bodyMutated {
+		r.Body = io.NopCloser(bytes.NewReader(mutatedBody))
+		r.ContentLength = int64(len(mutatedBody))
+		r.Header.Set("Content-Length", fmt.Sprint(len(mutatedBody)))
+	}
+	return endpoint, nil
+}
+
+// rawCodec passes pre-encoded protobuf messages through gRPC, since the
+// external processing API types are not vendored.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*b = slices.Clone(data)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func appendBool(b []byte, num protowire.Number, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, 1)
+}
+
+// encodeRequestHeaders encodes a ProcessingRequest carrying the request
+// headers, including the HTTP/2 pseudo-headers Envoy would send.
+func encodeRequestHeaders(r *http.Request, endOfStream bool) []byte {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	values := [][2]string{
+		{":authority", r.Host},
+		{":method", r.Method},
+		{":path", r.URL.RequestURI()},
+		{":scheme", scheme},
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Header)) {
+		for _, value := range r.Header[name] {
+			values = append(values, [2]string{strings.ToLower(name), value})
+		}
+	}
+
+	var headerMap []byte
+	for _, v := range values {
+		// HeaderValue{key: 1, raw_value: 3}
+		var hv []byte
+		hv = protowire.AppendTag(hv, 1, protowire.BytesType)
+		hv = protowire.AppendString(hv, v[0])
+		hv = protowire.AppendTag(hv, 3, protowire.BytesType)
+		hv = protowire.AppendString(hv, v[1])
+		headerMap = appendMessage(headerMap, 1, hv)
+	}
+	// HttpHeaders{headers: 1, end_of_stream: 3}
+	httpHeaders := appendMessage(nil, 1, headerMap)
+	httpHeaders = appendBool(httpHeaders, 3, endOfStream)
+	// ProcessingRequest{request_headers: 2}
+	return appendMessage(nil, 2, httpHeaders)
+}
+
+// encodeRequestBody encodes a ProcessingRequest carrying the whole request
+// body.
+func encodeRequestBody(body []byte) []byte {
+	// HttpBody{body: 1, end_of_stream: 2}
+	httpBody := appendMessage(nil, 1, body)
+	httpBody = appendBool(httpBody, 2, true)
+	// ProcessingRequest{request_body: 4}
+	return appendMessage(nil, 4, httpBody)
+}
+
+// processingResponse holds the parts of a ProcessingResponse the proxy acts
+// on.
+type processingResponse struct {
+	requestHeaders bool
+	requestBody    bool
+	setHeaders     map[string]string
+	body           []byte
+	bodyMutated    bool
+	// bodyDone is false for streamed body chunks other than the last one.
+	bodyDone  bool
+	immediate *pickerRejection
+}
+
+// forEachField calls fn with each field of an encoded message. Varint fields
+// are passed as v, length-delimited fields as data; other fields are skipped.
+func forEachField(b []byte, fn func(num protowire.Number, data []byte, v uint64) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch typ {
+		case protowire.BytesType:
+			data, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			if err := fn(num, data, 0); err != nil {
+				return err
+			}
+			b = b[n:]
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			if err := fn(num, nil, v); err != nil {
+				return err
+			}
+			b = b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return nil
+}
+
+func decodeProcessingResponse(msg []byte) (*processingResponse, error) {
+	resp := &processingResponse{setHeaders: map[string]string{}, bodyDone: true}
+	err := forEachField(msg, func(num protowire.Number, data []byte, _ uint64) error {
+		switch num {
+		case 1: // request_headers: HeadersResponse{response: 1}
+			resp.requestHeaders = true
+			return forEachField(data, func(num protowire.Number, data []byte, _ uint64) error {
+				if num != 1 {
+					return nil
+				}
+				return resp.decodeCommonResponse(data)
+			})
+		case 3: // request_body: BodyResponse{response: 1}
+			resp.requestBody = true
+			return forEachField(data, func(num protowire.Number, data []byte, _ uint64) error {
+				if num != 1 {
+					return nil
+				}
+				return resp.decodeCommonResponse(data)
+			})
+		case 7: // immediate_response
+			return resp.decodeImmediateResponse(data)
+		}
+		return nil
+	})
+	return resp, err
+}
+
+// decodeCommonResponse decodes CommonResponse{header_mutation: 2,
+// body_mutation: 3}.
+func (resp *processingResponse) decodeCommonResponse(msg []byte) error {
+	return forEachField(msg, func(num protowire.Number, data []byte, _ uint64) error {
+		switch num {
+		case 2: // HeaderMutation{set_headers: 1}
+			return forEachField(data, func(num protowire.Number, data []byte, _ uint64) error {
+				if num != 1 {
+					return nil
+				}
+				// HeaderValueOption{header: 1}
+				return forEachField(data, func(num protowire.Number, data []byte, _ uint64) error {
+					if num != 1 {
+						return nil
+					}
+					key, value, err := decodeHeaderValue(data)
+					if err != nil {
+						return err
+					}
+					resp.setHeaders[strings.ToLower(key)] = value
+					return nil
+				})
+			})
+		case 3: // BodyMutation{body: 1, streamed_response: 3}
+			return forEachField(data, func(num protowire.Number, data []byte, _ uint64) error {
+				switch num {
+				case 1:
+					resp.body = append(resp.body, data...)
+					resp.bodyMutated = true
+				case 3: // StreamedBodyResponse{body: 1, end_of_stream: 2}
+					resp.bodyDone = false
+					return forEachField(data, func(num protowire.Number, data []byte, v uint64) error {
+						switch num {
+						case 1:
+							resp.body = append(resp.body, data...)
+							resp.bodyMutated = true
+						case 2:
+							resp.bodyDone = v != 0
+						}
+						return nil
+					})
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+}
+
+// decodeHeaderValue decodes HeaderValue{key: 1, value: 2, raw_value: 3}.
+func decodeHeaderValue(msg []byte) (key, value string, err error) {
+	err = forEachField(msg, func(num protowire.Number, data []byte, _ uint64) error {
+		switch num {
+		case 1:
+			key = string(data)
+		case 2, 3:
+			value = string(data)
+		}
+		return nil
+	})
+	return key, value, err
+}
+
+// decodeImmediateResponse decodes ImmediateResponse{status: 1, body: 3},
+// where status is HttpStatus{code: 1}.
+func (resp *processingResponse) decodeImmediateResponse(msg []byte) error {
+	resp.immediate = &pickerRejection{status: http.StatusServiceUnavailable}
+	return forEachField(msg, func(num protowire.Number, data []byte, _ uint64) error {
+		switch num {
+		case 1:
+			return forEachField(data, func(num protowire.Number, _ []byte, v uint64) error {
+				if num == 1 && v != 0 {
+					resp.immediate.status = int(v)
+				}
+				return nil
+			})
+		case 3:
+			resp.immediate.body = slices.Clone(data)
+		}
+		return nil
+	})
+}
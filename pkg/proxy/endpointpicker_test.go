@@ -0,0 +1,196 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// startEndpointPicker serves the external processing protocol over TLS,
+// calling respond once the request body has been received.
+func startEndpointPicker(t *testing.T, respond func(stream grpc.ServerStream) error) string {
+	t.Helper()
+	certServer := httptest.NewUnstartedServer(nil)
+	certServer.StartTLS()
+	cert := certServer.TLS.Certificates[0]
+	certServer.Close()
+
+	s := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "envoy.service.ext_proc.v3.ExternalProcessor",
+		HandlerType: (*any)(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "Process",
+			ServerStreams: true,
+			ClientStreams: true,
+			Handler: func(_ any, stream grpc.ServerStream) error {
+				for {
+					var msg []byte
+					if err := stream.RecvMsg(&msg); err != nil {
+						return nil
+					}
+					isBody := false
+					_ = forEachField(msg, func(num protowire.Number, _ []byte, _ uint64) error {
+						isBody = isBody || num == 4
+						return nil
+					})
+					if isBody {
+						if err := respond(stream); err != nil {
+							return err
+						}
+					}
+				}
+			},
+		}},
+	}, nil)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+// commonResponse encodes a CommonResponse setting a header and replacing the
+// body.
+func commonResponse(name, value, body string) []byte {
+	var hv []byte
+	hv = protowire.AppendTag(hv, 1, protowire.BytesType)
+	hv = protowire.AppendString(hv, name)
+	hv = protowire.AppendTag(hv, 3, protowire.BytesType)
+	hv = protowire.AppendString(hv, value)
+	headerMutation := appendMessage(nil, 1, appendMessage(nil, 1, hv))
+	common := appendMessage(nil, 2, headerMutation)
+	if body != "" {
+		common = appendMessage(common, 3, appendMessage(nil, 1, []byte(body)))
+	}
+	return common
+}
+
+func TestEndpointPicker(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Model", r.Header.Get("X-Gateway-Model-Name"))
+		_, _ = w.Write(body)
+	}))
+	defer backend.Close()
+	u, err := url.Parse(backend.URL)
+	if err != nil {
+		t.Fatalf("unable to parse backend URL: %v", err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatalf("unable to parse backend port: %v", err)
+	}
+
+	picking := startEndpointPicker(t, func(stream grpc.ServerStream) error {
+		// HeadersResponse selecting the backend, then BodyResponse
+		// rewriting the model.
+		headers := appendMessage(nil, 1, appendMessage(nil, 1, commonResponse(destinationEndpointHeader, u.Host, "")))
+		if err := stream.SendMsg(headers); err != nil {
+			return err
+		}
+		return stream.SendMsg(appendMessage(nil, 3, appendMessage(nil, 1, commonResponse("x-gateway-model-name", "llama-lora", `{"model":"llama-lora"}`))))
+	})
+	rejecting := startEndpointPicker(t, func(stream grpc.ServerStream) error {
+		// ImmediateResponse{status: {code: 429}}
+		status := protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), http.StatusTooManyRequests)
+		return stream.SendMsg(appendMessage(nil, 7, appendMessage(nil, 1, status)))
+	})
+	unreachable := "127.0.0.1:1"
+	oversized := `{"model":"llama","prompt":"` + strings.Repeat("a", maxPickerBodySize) + `"}`
+
+	tests := []struct {
+		name          string
+		picker        EndpointPicker
+		body          string
+		expected      int
+		expectedBody  string
+		expectedModel string
+	}{
+		{
+			name:          "picked endpoint",
+			picker:        EndpointPicker{Address: picking},
+			expected:      http.StatusOK,
+			expectedBody:  `{"model":"llama-lora"}`,
+			expectedModel: "llama-lora",
+		},
+		{
+			name:     "rejected",
+			picker:   EndpointPicker{Address: rejecting},
+			expected: http.StatusTooManyRequests,
+		},
+		{
+			name:         "fail open",
+			picker:       EndpointPicker{Address: unreachable, FailOpen: true, Endpoints: []string{u.Host}},
+			expected:     http.StatusOK,
+			expectedBody: `{"model":"llama"}`,
+		},
+		{
+			name:         "fail open with a body too large to pick for",
+			picker:       EndpointPicker{Address: picking, FailOpen: true, Endpoints: []string{u.Host}},
+			body:         oversized,
+			expected:     http.StatusOK,
+			expectedBody: oversized,
+		},
+		{
+			name:     "fail closed",
+			picker:   EndpointPicker{Address: unreachable, Endpoints: []string{u.Host}},
+			expected: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProxy(Options{})
+			p.UpdateRoutes([]HTTPRoute{{
+				Namespace: "default",
+				Name:      "route",
+				Rules: []RouteRule{{
+					Backends: []WeightedBackend{{Backend: Backend{Port: int32(port), EndpointPicker: &tt.picker}, Weight: 1}},
+				}},
+			}})
+			rec := httptest.NewRecorder()
+			body := tt.body
+			if body == "" {
+				body = `{"model":"llama"}`
+			}
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/v1/completions", strings.NewReader(body))
+			p.ServeHTTP(rec, req)
+			if rec.Code != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, rec.Code)
+			}
+			if tt.expectedBody != "" && rec.Body.String() != tt.expectedBody {
+				t.Errorf("expected a body of %d bytes, got %d bytes", len(tt.expectedBody), rec.Body.Len())
+			}
+			if actual := rec.Header().Get("X-Model"); actual != tt.expectedModel {
+				t.Errorf("expected model %v, got %v", tt.expectedModel, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// WeightedBackend is one of the backends of a rule, with the filters that run
+// only for requests forwarded to it.
+type WeightedBackend struct {
+	Backend
+	// Weight is the share of requests forwarded to the backend, relative to
+	// the weights of the other backends of the rule.
+	Weight  int32
+	Filters Filters
+	// Invalid is set for a backendRef that could not be resolved. The
+	// requests picked for it are answered with 500, and its Backend is
+	// empty.
+	Invalid bool `json:",omitempty"`
+}
+
+// Header is a header name and value.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// HeaderModifier sets, adds and removes headers.
+type HeaderModifier struct {
+	Set    []Header
+	Add    []Header
+	Remove []string
+}
+
+func (m *HeaderModifier) apply(header http.Header) {
+	if m == nil {
+		return
+	}
+	for _, h := range m.Set {
+		header.Set(h.Name, h.Value)
+	}
+	for _, h := range m.Add {
+		header.Add(h.Name, h.Value)
+	}
+	for _, name := range m.Remove {
+		header.Del(name)
+	}
+}
+
+// Mirror sends a copy of a share of requests to a backend.
+type Mirror struct {
+	Backend Backend
+	// Fraction is the share of requests mirrored, between 0 and 1.
+	Fraction float64
+}
+
+// Filters modify the requests forwarded to a backend and their responses.
+type Filters struct {
+	RequestHeaders  *HeaderModifier
+	ResponseHeaders *HeaderModifier
+	Mirrors         []Mirror
+}
+
+// mirrorTimeout bounds the time spent on a mirrored request, whose response
+// is discarded.
+const mirrorTimeout = 10 * time.Second
+
+// maxMirrorBodySize bounds the request body buffered for mirroring; requests
+// with a larger body are not mirrored.
+const maxMirrorBodySize = 1 << 20
+
+// pickBackend returns one of the rule's backends, chosen at random in
+// proportion to their weights, or nil if all weights are zero.
+func (rule *RouteRule) pickBackend() *WeightedBackend {
+	var total int64
+	for _, b := range rule.Backends {
+		total += int64(b.Weight)
+	}
+	if total == 0 {
+		return nil
+	}
+	n := rand.Int64N(total)
+	for i := range rule.Backends {
+		n -= int64(rule.Backends[i].Weight)
+		if n < 0 {
+			return &rule.Backends[i]
+		}
+	}
+	return nil
+}
+
+// mirror sends copies of the request to the mirrors. The request body, if
+// any, is buffered up to maxMirrorBodySize, and the request is not mirrored
+// if it is larger or cannot be read. Either way, what was read is put back in
+// front of the rest of the body, so that the whole body is still forwarded to
+// the backend.
+func mirror(r *http.Request, mirrors []Mirror) {
+	var targets []Backend
+	for _, m := range mirrors {
+		if rand.Float64() < m.Fraction {
+			targets = append(targets, m.Backend)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(r.Body, maxMirrorBodySize+1))
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "unable to read request body for mirroring")
+			return
+		}
+		if len(body) > maxMirrorBodySize {
+			log.FromContext(r.Context()).V(2).Info("Request body too large to mirror", "limit", maxMirrorBodySize)
+			return
+		}
+	}
+	for _, target := range targets {
+		scheme := "http"
+		if target.TLS != nil {
+			scheme = "https"
+		}
+		url := fmt.Sprintf("%s://%s%s", scheme, target.address(), r.URL.RequestURI())
+		header := r.Header.Clone()
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), mirrorTimeout)
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, r.Method, url, bytes.NewReader(body))
+			if err != nil {
+				log.FromContext(r.Context()).Error(err, "unable to build mirrored request", "target", url)
+				return
+			}
+			req.Header = header
+			req.Host = r.Host
+			resp, err := backendTransport(target.TLS).RoundTrip(req)
+			if err != nil {
+				log.FromContext(r.Context()).V(2).Info("Mirrored request failed", "target", url, "error", err.Error())
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}()
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackendFilters(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Env", r.Header.Get("X-Env"))
+		w.Header().Set("X-Internal", "secret")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer backend.Close()
+	mirrored := make(chan string, 1)
+	mirrorBackend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mirrored <- string(body)
+	}))
+	defer mirrorBackend.Close()
+
+	selected := WeightedBackend{
+		Backend: testBackend(t, backend),
+		Weight:  1,
+		Filters: Filters{
+			RequestHeaders:  &HeaderModifier{Set: []Header{{Name: "X-Env", Value: "canary"}}},
+			ResponseHeaders: &HeaderModifier{Remove: []string{"X-Internal"}},
+			Mirrors:         []Mirror{{Backend: testBackend(t, mirrorBackend), Fraction: 1}},
+		},
+	}
+	// Never selected, so its filters must not apply.
+	unselected := WeightedBackend{
+		Backend: testBackend(t, backend),
+		Weight:  0,
+		Filters: Filters{RequestHeaders: &HeaderModifier{Set: []Header{{Name: "X-Env", Value: "stable"}}}},
+	}
+
+	p := NewProxy(Options{})
+	p.UpdateRoutes([]HTTPRoute{{Rules: []RouteRule{{Backends: []WeightedBackend{unselected, selected}}}}})
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload")))
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected %v, got %v", http.StatusNoContent, w.Code)
+	}
+	if actual := w.Header().Get("X-Seen-Env"); actual != "canary" {
+		t.Errorf("expected request header canary, got %q", actual)
+	}
+	if actual := w.Header().Get("X-Internal"); actual != "" {
+		t.Errorf("expected response header to be removed, got %q", actual)
+	}
+	select {
+	case body := <-mirrored:
+		if body != "payload" {
+			t.Errorf("expected mirrored body payload, got %q", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the request to be mirrored")
+	}
+}
+
+func TestMirrorLargeBody(t *testing.T) {
+	mirrored := make(chan struct{}, 1)
+	mirrorBackend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrored <- struct{}{}
+	}))
+	defer mirrorBackend.Close()
+
+	payload := strings.Repeat("x", maxMirrorBodySize+10)
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	mirror(r, []Mirror{{Backend: testBackend(t, mirrorBackend), Fraction: 1}})
+
+	if body, err := io.ReadAll(r.Body); err != nil || string(body) != payload {
+		t.Errorf("expected the whole body to be kept for the backend, got %d bytes, %v", len(body), err)
+	}
+	select {
+	case <-mirrored:
+		t.Errorf("expected a request with a body over the limit not to be mirrored")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestPickBackendZeroWeights(t *testing.T) {
+	p := NewProxy(Options{})
+	p.UpdateRoutes([]HTTPRoute{{Rules: []RouteRule{{Backends: []WeightedBackend{{Backend: Backend{Host: "unused", Port: 80}}}}}}})
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected %v, got %v", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestInvalidBackend(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer backend.Close()
+
+	p := NewProxy(Options{})
+	p.UpdateRoutes([]HTTPRoute{{Rules: []RouteRule{{Backends: []WeightedBackend{
+		{Weight: 1, Invalid: true},
+		{Backend: testBackend(t, backend), Weight: 1},
+	}}}}})
+	codes := map[int]int{}
+	for range 200 {
+		w := httptest.NewRecorder()
+		p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		codes[w.Code]++
+	}
+	if codes[http.StatusInternalServerError] == 0 || codes[http.StatusNoContent] == 0 || len(codes) != 2 {
+		t.Errorf("expected the requests to be split between 500 and the valid backend, got %v", codes)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+)
+
+// RequestHook is an extension point invoked for each request matched by a
+// rule, before the request is forwarded to the backend.
+type RequestHook interface {
+	HandleRequest(r *http.Request, meta RouteMetadata) (*HookResult, error)
+}
+
+// RouteMetadata describes the route that matched a request.
+type RouteMetadata struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Rule      string `json:"rule"`
+	Backend   string `json:"backend"`
+}
+
+// HookResult describes the changes a RequestHook makes to a request. A nil
+// result leaves the request unchanged.
+type HookResult struct {
+	// SetHeaders are set on the request, replacing existing values.
+	SetHeaders map[string]string `json:"setHeaders,omitempty"`
+	// RemoveHeaders are removed from the request.
+	RemoveHeaders []string `json:"removeHeaders,omitempty"`
+	// Status, if non-zero, answers the request directly with this status
+	// code and Body instead of forwarding it.
+	Status int    `json:"status,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// apply updates the request with the result, reporting whether the request
+// should still be forwarded.
+func (res *HookResult) apply(w http.ResponseWriter, r *http.Request) bool {
+	if res == nil {
+		return true
+	}
+	for _, name := range res.RemoveHeaders {
+		r.Header.Del(name)
+	}
+	setHeaders(r.Header, res.SetHeaders)
+	if res.Status != 0 {
+		w.WriteHeader(res.Status)
+		_, _ = w.Write([]byte(res.Body))
+		return false
+	}
+	return true
+}
+
+// unresolvedHook stands in for an extension that could not be loaded. The
+// Gateway API requires such requests to fail rather than skip the filter.
+type unresolvedHook struct {
+	err error
+}
+
+// NewUnresolvedHook returns a RequestHook that fails every request with err.
+func NewUnresolvedHook(err error) RequestHook {
+	return &unresolvedHook{err: err}
+}
+
+func (h *unresolvedHook) HandleRequest(r *http.Request, meta RouteMetadata) (*HookResult, error) {
+	return nil, h.err
+}
@@ -0,0 +1,31 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !unix || solaris
+
+package proxy
+
+import (
+	"errors"
+	"net"
+)
+
+// Listen binds a TCP listener to addr. SO_REUSEPORT is unavailable on this
+// platform, so reusePort is an error.
+func Listen(addr string, reusePort bool) (net.Listener, error) {
+	if reusePort {
+		return nil, errors.New("SO_REUSEPORT is not supported on this platform")
+	}
+	return net.Listen("tcp", addr)
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix && !solaris
+
+package proxy
+
+import (
+	"context"
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Listen binds a TCP listener to addr. With reusePort, the socket is bound
+// with SO_REUSEPORT, so that a replacement proxy can bind the same address
+// while this one drains, and an upgrade in place does not refuse connections.
+// The kernel then spreads new connections across both, and those still
+// queued on a listener when it is closed are reset.
+func Listen(addr string, reusePort bool) (net.Listener, error) {
+	var config net.ListenConfig
+	if reusePort {
+		config.Control = func(_, _ string, conn syscall.RawConn) error {
+			var sockErr error
+			if err := conn.Control(func(fd uintptr) {
+				sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+			}); err != nil {
+				return err
+			}
+			return sockErr
+		}
+	}
+	return config.Listen(context.Background(), "tcp", addr)
+}
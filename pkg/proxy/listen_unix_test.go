@@ -0,0 +1,50 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix && !solaris
+
+package proxy
+
+import (
+	"net"
+	"testing"
+)
+
+func TestListenReusePort(t *testing.T) {
+	first, err := Listen("127.0.0.1:0", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer first.Close()
+	second, err := Listen(first.Addr().String(), true)
+	if err != nil {
+		t.Fatalf("expected a replacement to bind the same address, got %v", err)
+	}
+	defer second.Close()
+
+	// Connections are still accepted once the first listener is closed.
+	first.Close()
+	conn, err := net.Dial("tcp", first.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to connect: %v", err)
+	}
+	conn.Close()
+	if _, err := second.Accept(); err != nil {
+		t.Errorf("expected the replacement to accept the connection, got %v", err)
+	}
+
+	if _, err := Listen(second.Addr().String(), false); err == nil {
+		t.Errorf("expected binding without SO_REUSEPORT to fail")
+	}
+}
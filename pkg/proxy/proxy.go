@@ -15,12 +15,24 @@
 package proxy
 
 import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -29,6 +41,58 @@ import (
 type Backend struct {
 	Host string
 	Port int32
+	// Endpoints, if set, are the host:port addresses of the backend's ready
+	// endpoints, one of which each request is forwarded to in place of Host
+	// and Port.
+	Endpoints []string
+	// TLS, if set, makes the proxy connect to the backend over TLS.
+	TLS *BackendTLS
+	// EndpointPicker, if set, selects the endpoint each request is forwarded
+	// to, in place of Host and Port.
+	EndpointPicker *EndpointPicker
+}
+
+// address returns the host:port a request to the backend is forwarded to: a
+// random one of its endpoints, if it has any.
+func (b Backend) address() string {
+	if len(b.Endpoints) > 0 {
+		return b.Endpoints[rand.IntN(len(b.Endpoints))]
+	}
+	return net.JoinHostPort(b.Host, strconv.Itoa(int(b.Port)))
+}
+
+// BackendTLS holds the TLS settings for connecting to a backend.
+type BackendTLS struct {
+	// ServerName is sent with SNI and verified against the backend's
+	// certificate. Defaults to the backend's host when empty.
+	ServerName string
+	// CACertificates, if set, is a PEM bundle of the CAs trusted to sign the
+	// backend's certificate in place of the system roots.
+	CACertificates []byte
+}
+
+// tlsTransports caches a transport per server name and CA bundle so that TLS
+// connections to backends are reused across requests.
+var tlsTransports sync.Map
+
+// backendTransport returns the transport used to reach a backend.
+func backendTransport(backendTLS *BackendTLS) http.RoundTripper {
+	if backendTLS == nil {
+		return http.DefaultTransport
+	}
+	key := backendTLS.ServerName + "\x00" + string(backendTLS.CACertificates)
+	if t, ok := tlsTransports.Load(key); ok {
+		return t.(http.RoundTripper)
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = &tls.Config{ServerName: backendTLS.ServerName}
+	if len(backendTLS.CACertificates) > 0 {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(backendTLS.CACertificates)
+		t.TLSClientConfig.RootCAs = pool
+	}
+	actual, _ := tlsTransports.LoadOrStore(key, t)
+	return actual.(http.RoundTripper)
 }
 
 // PathMatchType defines how a path should be matched.
@@ -76,73 +140,329 @@ type RouteMatch struct {
 
 // RouteRule holds the computed state for a single rule within an HTTPRoute.
 type RouteRule struct {
+	// Name identifies the rule in access logs, metrics and hook metadata. It
+	// is the name of the HTTPRouteRule, or its index if the rule is unnamed.
+	Name    string
 	Matches []RouteMatch
-	Backend Backend
+	// Backends are picked for each request in proportion to their weights.
+	Backends []WeightedBackend
+	// Hooks run in order for each matched request before it is forwarded.
+	Hooks []RequestHook
+	// Timeout, if set, bounds the time taken to forward a request to the
+	// backend and receive its response headers.
+	Timeout time.Duration
 }
 
 // HTTPRoute holds the computed state from a Gateway API HTTPRoute object.
 type HTTPRoute struct {
-	Hostnames []string
-	Rules     []RouteRule
+	Namespace string
+	Name      string
+	// CreationTimestamp breaks ties between equally specific matches of
+	// different routes: the oldest route wins.
+	CreationTimestamp time.Time
+	Hostnames         []string
+	Rules             []RouteRule
+	// BasicAuth, if set, requires clients to authenticate before the request
+	// is forwarded to any of the route's backends.
+	BasicAuth *BasicAuth
+	// SecurityHeaders are set on every response served for the route,
+	// replacing any value sent by the backend.
+	SecurityHeaders map[string]string
+	// Transform, if set, mutates request and response headers using CEL
+	// expressions.
+	Transform *Transform
+	// Telemetry, if set, overrides DefaultTelemetry for the route.
+	Telemetry *Telemetry
+	// Listeners are the Gateway listeners the route is attached to, which
+	// the requests served for it are attributed to in metrics.
+	Listeners []RouteListener
+	// Draining, if set, closes the client connection after each response
+	// served for the route, so that clients reconnect elsewhere while the
+	// Gateways serving it are being deleted.
+	Draining bool
+	// Builtin marks the routes the controller serves on its own, such as the
+	// ACME HTTP-01 solver, whose matches take precedence over those of every
+	// other route.
+	Builtin bool
+}
+
+// RouteListener is a Gateway listener a route is attached to.
+type RouteListener struct {
+	GatewayNamespace string
+	Gateway          string
+	Listener         string
+	// Hostname is the hostname of the listener, empty if it has none.
+	Hostname string
+}
+
+// Options configures a Proxy.
+type Options struct {
+	// RedactedHeaders lists the headers whose values are hidden in logs and
+	// traces. Defaults to DefaultRedactedHeaders when nil.
+	RedactedHeaders []string
+	// AccessLog, if set, is where access log entries are written, one line
+	// each, rather than to the log of the process. Entries in the Common Log
+	// Format are written to stdout when it is not set.
+	AccessLog io.Writer
+	// AccessLogFormat is the format of access log entries. Defaults to
+	// AccessLogFormatText.
+	AccessLogFormat AccessLogFormat
+	// AccessLogFields are the fields of access log entries, in order, from
+	// AccessLogFields. Defaults to DefaultAccessLogFields when nil.
+	AccessLogFields []string
+	// AccessLogStaticFields are added to every access log entry, after
+	// AccessLogFields, such as the name of the cluster.
+	AccessLogStaticFields map[string]string
+	// MetricsRouteLabels controls the route labels of request metrics for
+	// every route, whatever their telemetry. Defaults to RouteLabelModeFull.
+	MetricsRouteLabels RouteLabelMode
+	// MaxMetricsRoutes, if positive, caps the number of routes whose request
+	// metrics carry their own labels. The requests of routes past the cap
+	// are recorded together, under the overflowRouteLabel route.
+	MaxMetricsRoutes int
 }
 
 // Proxy is a minimal implementation of a Gateway API proxy.
 type Proxy struct {
-	mu     sync.RWMutex
-	routes []HTTPRoute
+	mu       sync.RWMutex
+	routes   []HTTPRoute
+	redactor atomic.Pointer[HeaderRedactor]
+	// accessLog writes the access log entries of the routes that enable it.
+	accessLog *accessLogger
+	// routeLabels sets the route labels of request metrics.
+	routeLabels *routeLabeler
 }
 
-func NewProxy() *Proxy {
-	return &Proxy{
-		routes: []HTTPRoute{},
+func NewProxy(opts Options) *Proxy {
+	redactedHeaders := opts.RedactedHeaders
+	if redactedHeaders == nil {
+		redactedHeaders = DefaultRedactedHeaders
+	}
+	p := &Proxy{
+		routes:      []HTTPRoute{},
+		accessLog:   newAccessLogger(opts),
+		routeLabels: &routeLabeler{mode: cmp.Or(opts.MetricsRouteLabels, RouteLabelModeFull), max: opts.MaxMetricsRoutes},
 	}
+	p.redactor.Store(NewHeaderRedactor(redactedHeaders))
+	return p
+}
+
+// SetRedactedHeaders replaces the headers whose values are hidden in logs and
+// traces.
+func (p *Proxy) SetRedactedHeaders(headers []string) {
+	p.redactor.Store(NewHeaderRedactor(headers))
+}
+
+// Redactor returns the HeaderRedactor hiding the values of sensitive headers.
+func (p *Proxy) Redactor() *HeaderRedactor {
+	return p.redactor.Load()
 }
 
 func (p *Proxy) UpdateRoutes(routes []HTTPRoute) {
+	routes = slices.Clone(routes)
+	slices.SortStableFunc(routes, compareRoutes)
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.routes = routes
 }
 
+// RouteKey identifies a route served by the proxy.
+type RouteKey struct {
+	Namespace string
+	Name      string
+}
+
+// UpdateRoute serves route in place of any route with the same namespace and
+// name.
+func (p *Proxy) UpdateRoute(route HTTPRoute) {
+	p.ApplyRouteChanges(map[RouteKey]*HTTPRoute{{Namespace: route.Namespace, Name: route.Name}: &route})
+}
+
+// RemoveRoute stops serving the route with the given namespace and name.
+func (p *Proxy) RemoveRoute(namespace, name string) {
+	p.ApplyRouteChanges(map[RouteKey]*HTTPRoute{{Namespace: namespace, Name: name}: nil})
+}
+
+// ApplyRouteChanges serves each changed route in place of the route with the
+// same key, and stops serving the routes whose change is nil. The whole batch
+// is swapped in at once, so requests never see part of it. The table is
+// copied rather than changed in place, since requests in flight may still be
+// matched against the previous one.
+func (p *Proxy) ApplyRouteChanges(changes map[RouteKey]*HTTPRoute) {
+	var updated []HTTPRoute
+	for _, route := range changes {
+		if route != nil {
+			updated = append(updated, *route)
+		}
+	}
+	slices.SortFunc(updated, compareRoutes)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	routes := make([]HTTPRoute, 0, len(p.routes)+len(updated))
+	for _, route := range p.routes {
+		if _, changed := changes[RouteKey{Namespace: route.Namespace, Name: route.Name}]; changed {
+			continue
+		}
+		// Both tables are sorted, so merge the updated routes in order.
+		for len(updated) > 0 && compareRoutes(updated[0], route) < 0 {
+			routes = append(routes, updated[0])
+			updated = updated[1:]
+		}
+		routes = append(routes, route)
+	}
+	p.routes = append(routes, updated...)
+}
+
+// compareRoutes orders routes as the spec requires for ties between equally
+// specific matches, since findRoute keeps the first of them: oldest first,
+// then by namespace/name.
+func compareRoutes(a, b HTTPRoute) int {
+	if c := a.CreationTimestamp.Compare(b.CreationTimestamp); c != 0 {
+		return c
+	}
+	return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+}
+
+// Routes returns the routes currently served by the proxy.
+func (p *Proxy) Routes() []HTTPRoute {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.routes
+}
+
 func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	route, rule := p.findRoute(r)
+
+	telemetry := DefaultTelemetry
+	if route != nil && route.Telemetry != nil {
+		telemetry = *route.Telemetry
+	}
+	ensureTraceContext(r, telemetry)
+	// What is logged while serving the request carries its trace, to follow
+	// it from the logs to the trace.
+	traceID, spanID := traceIDs(r)
+	r = r.WithContext(log.IntoContext(r.Context(), log.Log.WithValues("trace_id", traceID, "span_id", spanID)))
+
+	rec := &statusRecorder{ResponseWriter: w}
+	defer p.recordRequest(rec, r, route, rule, telemetry, start)
+	if route != nil && route.Draining {
+		rec.Header().Set("Connection", "close")
+	}
+
+	if rule == nil {
+		http.Error(rec, fmt.Sprintf("No route for host %s and path %s", r.Host, r.URL.Path), http.StatusNotFound)
+		return
+	}
+
+	if route.BasicAuth != nil && !route.BasicAuth.Authenticate(r) {
+		setHeaders(rec.Header(), route.SecurityHeaders)
+		route.BasicAuth.Challenge(rec)
+		return
+	}
+	backend := rule.pickBackend()
+	if backend == nil || backend.Invalid {
+		setHeaders(rec.Header(), route.SecurityHeaders)
+		http.Error(rec, "No backend available", http.StatusInternalServerError)
+		return
+	}
+	if !p.runHooks(rec, r, route, rule, backend.Backend) {
+		return
+	}
+	if route.Transform != nil && len(route.Transform.RequestHeaders) > 0 {
+		applyHeaderTransforms(route.Transform.RequestHeaders, r.Header, requestVars(r, route))
+	}
+	if rule.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), rule.Timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+	backend.Filters.RequestHeaders.apply(r.Header)
+	mirror(r, backend.Filters.Mirrors)
+	p.forward(rec, r, backend, route, rule)
+}
+
+// findRoute returns the most specific route rule matching the request, or nil
+// if there is none.
+func (p *Proxy) findRoute(r *http.Request) (*HTTPRoute, *RouteRule) {
 	p.mu.RLock()
 	routes := p.routes
 	p.mu.RUnlock()
 
-	var bestBackend *Backend
+	var bestRoute *HTTPRoute
+	var bestRule *RouteRule
 	var bestMatch *RouteMatch
 
-	for _, route := range routes {
+	for i := range routes {
+		route := &routes[i]
 		if !p.matchHostname(route.Hostnames, r.Host) {
 			continue
 		}
 
-		for _, rule := range route.Rules {
+		for j := range route.Rules {
+			rule := &route.Rules[j]
 			for _, match := range rule.Matches {
 				m := match
 				if p.matchMatch(m, r) {
-					if p.isBetterMatch(&m, bestMatch) {
+					if p.outranks(route, &m, bestRoute, bestMatch) {
 						bestMatch = &m
-						bestBackend = &rule.Backend
+						bestRule = rule
+						bestRoute = route
 					}
 				}
 			}
 			if len(rule.Matches) == 0 {
 				// Rule with no matches always matches, but is the least specific
-				if bestBackend == nil {
-					bestBackend = &rule.Backend
+				if bestRule == nil {
+					bestRule = rule
 					bestMatch = &RouteMatch{}
+					bestRoute = route
 				}
 			}
 		}
 	}
+	return bestRoute, bestRule
+}
 
-	if bestBackend != nil {
-		p.forward(w, r, *bestBackend)
-		return
+// runHooks runs the rule's request hooks, reporting whether the request should
+// still be forwarded.
+func (p *Proxy) runHooks(w http.ResponseWriter, r *http.Request, route *HTTPRoute, rule *RouteRule, backend Backend) bool {
+	if len(rule.Hooks) == 0 {
+		return true
 	}
+	meta := RouteMetadata{
+		Namespace: route.Namespace,
+		Name:      route.Name,
+		Rule:      rule.Name,
+		Backend:   fmt.Sprintf("%s:%d", backend.Host, backend.Port),
+	}
+	for _, hook := range rule.Hooks {
+		result, err := hook.HandleRequest(r, meta)
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "request hook failed", "route", route.Namespace+"/"+route.Name, "rule", rule.Name)
+			setHeaders(w.Header(), route.SecurityHeaders)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return false
+		}
+		if result != nil && result.Status != 0 {
+			setHeaders(w.Header(), route.SecurityHeaders)
+		}
+		if !result.apply(w, r) {
+			return false
+		}
+	}
+	return true
+}
 
-	http.Error(w, fmt.Sprintf("No route for host %s and path %s", r.Host, r.URL.Path), http.StatusNotFound)
+// outranks reports whether match of route is preferred over the best match
+// found so far: built-in routes come first, then the most specific match.
+func (p *Proxy) outranks(route *HTTPRoute, match *RouteMatch, bestRoute *HTTPRoute, bestMatch *RouteMatch) bool {
+	if bestRoute != nil && route.Builtin != bestRoute.Builtin {
+		return route.Builtin
+	}
+	return p.isBetterMatch(match, bestMatch)
 }
 
 func (p *Proxy) isBetterMatch(current, best *RouteMatch) bool {
@@ -253,13 +573,65 @@ func (p *Proxy) hasPathPrefix(path, prefix string) bool {
 	return false
 }
 
-func (p *Proxy) forward(w http.ResponseWriter, r *http.Request, backend Backend) {
+func (p *Proxy) forward(w http.ResponseWriter, r *http.Request, backend *WeightedBackend, route *HTTPRoute, rule *RouteRule) {
 	target := &url.URL{
 		Scheme: "http",
-		Host:   fmt.Sprintf("%s:%d", backend.Host, backend.Port),
+		Host:   backend.address(),
+	}
+	if picker := backend.EndpointPicker; picker != nil {
+		endpoint, err := picker.pick(r)
+		if err != nil {
+			setHeaders(w.Header(), route.SecurityHeaders)
+			var rejection *pickerRejection
+			if errors.As(err, &rejection) {
+				w.WriteHeader(rejection.status)
+				_, _ = w.Write(rejection.body)
+				return
+			}
+			log.FromContext(r.Context()).Error(err, "unable to pick an endpoint", "rule", rule.Name, "endpointPicker", picker.Address)
+			if endpoint = picker.fallback(); endpoint == "" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		target.Host = endpoint
+	}
+	if backend.TLS != nil {
+		target.Scheme = "https"
 	}
 
 	proxy := httputil.NewSingleHostReverseProxy(target)
-	log.Log.Info("Forwarding request", "host", r.Host, "path", r.URL.Path, "target", target.String())
+	proxy.Transport = backendTransport(backend.TLS)
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		if route.Transform != nil && len(route.Transform.ResponseHeaders) > 0 {
+			vars := requestVars(r, route)
+			vars["response"] = map[string]any{
+				"code":    int64(resp.StatusCode),
+				"headers": flattenHeader(resp.Header),
+			}
+			applyHeaderTransforms(route.Transform.ResponseHeaders, resp.Header, vars)
+		}
+		backend.Filters.ResponseHeaders.apply(resp.Header)
+		setHeaders(resp.Header, route.SecurityHeaders)
+		return nil
+	}
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		log.FromContext(r.Context()).Error(err, "unable to forward request", "route", route.Namespace+"/"+route.Name, "rule", rule.Name, "target", target.String())
+		setHeaders(w.Header(), route.SecurityHeaders)
+		if errors.Is(err, context.DeadlineExceeded) {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	log.FromContext(r.Context()).V(2).Info("Forwarding request", "host", r.Host, "path", r.URL.Path, "route", route.Namespace+"/"+route.Name, "rule", rule.Name, "target", target.String())
+	log.FromContext(r.Context()).V(4).Info("Request headers", "headers", p.redactor.Load().Redact(r.Header))
 	proxy.ServeHTTP(w, r)
 }
+
+// setHeaders sets each of the given headers, replacing any existing values.
+func setHeaders(header http.Header, values map[string]string) {
+	for name, value := range values {
+		header.Set(name, value)
+	}
+}
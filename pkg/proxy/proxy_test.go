@@ -0,0 +1,312 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRoutePrecedenceTieBreaking(t *testing.T) {
+	match := []RouteMatch{{Path: &PathMatch{Type: PathMatchTypePathPrefix, Value: "/"}}}
+	newRoute := func(namespace, name string, created time.Time) HTTPRoute {
+		return HTTPRoute{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: created,
+			Rules:             []RouteRule{{Matches: match}},
+		}
+	}
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		routes   []HTTPRoute
+		expected string
+	}{
+		{
+			name:     "oldest route wins",
+			routes:   []HTTPRoute{newRoute("a", "new", newer), newRoute("b", "old", older)},
+			expected: "b/old",
+		},
+		{
+			name:     "namespace breaks ties",
+			routes:   []HTTPRoute{newRoute("b", "web", older), newRoute("a", "web", older)},
+			expected: "a/web",
+		},
+		{
+			name:     "name breaks ties",
+			routes:   []HTTPRoute{newRoute("a", "y", older), newRoute("a", "x", older)},
+			expected: "a/x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProxy(Options{})
+			p.UpdateRoutes(tt.routes)
+			route, _ := p.findRoute(httptest.NewRequest("GET", "/", nil))
+			if route == nil {
+				t.Fatalf("expected %v, got no route", tt.expected)
+			}
+			if actual := route.Namespace + "/" + route.Name; actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBuiltinRoutePrecedence(t *testing.T) {
+	p := NewProxy(Options{})
+	p.UpdateRoutes([]HTTPRoute{
+		{
+			Namespace: "apps",
+			Name:      "web",
+			Hostnames: []string{"www.example.com"},
+			Rules: []RouteRule{{Matches: []RouteMatch{{
+				Path: &PathMatch{Type: PathMatchTypeExact, Value: "/.well-known/acme-challenge/token"},
+			}}}},
+		},
+		{
+			Name:    "builtin/acme",
+			Builtin: true,
+			Rules: []RouteRule{{Matches: []RouteMatch{{
+				Path: &PathMatch{Type: PathMatchTypePathPrefix, Value: "/.well-known/acme-challenge/"},
+			}}}},
+		},
+	})
+
+	// The built-in route wins over a more specific match.
+	route, _ := p.findRoute(httptest.NewRequest("GET", "http://www.example.com/.well-known/acme-challenge/token", nil))
+	if route == nil || route.Name != "builtin/acme" {
+		t.Errorf("expected the built-in route, got %v", route)
+	}
+	route, _ = p.findRoute(httptest.NewRequest("GET", "http://www.example.com/", nil))
+	if route != nil {
+		t.Errorf("expected no route outside the built-in route's matches, got %v", route.Name)
+	}
+}
+
+func TestRouteDeltas(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	names := func(routes []HTTPRoute) []string {
+		var names []string
+		for _, route := range routes {
+			names = append(names, route.Namespace+"/"+route.Name)
+		}
+		return names
+	}
+
+	p := NewProxy(Options{})
+	p.UpdateRoutes([]HTTPRoute{
+		{Namespace: "a", Name: "new", CreationTimestamp: newer},
+		{Namespace: "a", Name: "old", CreationTimestamp: older},
+	})
+	before := p.Routes()
+
+	p.UpdateRoute(HTTPRoute{Namespace: "b", Name: "old", CreationTimestamp: older})
+	p.UpdateRoute(HTTPRoute{Namespace: "a", Name: "new", CreationTimestamp: newer, Hostnames: []string{"example.com"}})
+	if expected, actual := []string{"a/old", "b/old", "a/new"}, names(p.Routes()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if hostnames := p.Routes()[2].Hostnames; !reflect.DeepEqual(hostnames, []string{"example.com"}) {
+		t.Errorf("expected the updated route to replace the existing one, got hostnames %v", hostnames)
+	}
+
+	p.RemoveRoute("a", "old")
+	p.RemoveRoute("a", "missing")
+	if expected, actual := []string{"b/old", "a/new"}, names(p.Routes()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if expected, actual := []string{"a/old", "a/new"}, names(before); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the previous table to be left unchanged, got %v", actual)
+	}
+
+	p.ApplyRouteChanges(map[RouteKey]*HTTPRoute{
+		{Namespace: "b", Name: "old"}: nil,
+		{Namespace: "c", Name: "new"}: {Namespace: "c", Name: "new", CreationTimestamp: newer},
+		{Namespace: "a", Name: "old"}: {Namespace: "a", Name: "old", CreationTimestamp: older},
+	})
+	if expected, actual := []string{"a/old", "a/new", "c/new"}, names(p.Routes()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestRuleTimeout(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer backend.Close()
+	u, err := url.Parse(backend.URL)
+	if err != nil {
+		t.Fatalf("unable to parse backend URL: %v", err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatalf("unable to parse backend port: %v", err)
+	}
+
+	p := NewProxy(Options{})
+	p.UpdateRoutes([]HTTPRoute{{
+		Namespace: "default",
+		Name:      "route",
+		Rules: []RouteRule{{
+			Backends: []WeightedBackend{{Backend: Backend{Host: u.Hostname(), Port: int32(port)}, Weight: 1}},
+			Timeout:  50 * time.Millisecond,
+		}},
+	}})
+
+	tests := []struct {
+		path     string
+		expected int
+	}{
+		{path: "/fast", expected: http.StatusNoContent},
+		{path: "/slow", expected: http.StatusGatewayTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com"+tt.path, nil))
+			if rec.Code != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, rec.Code)
+			}
+		})
+	}
+}
+
+func TestBackendEndpoints(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer backend.Close()
+	u, err := url.Parse(backend.URL)
+	if err != nil {
+		t.Fatalf("unable to parse backend URL: %v", err)
+	}
+
+	// The endpoints are forwarded to in place of the backend's host, which
+	// does not resolve.
+	p := NewProxy(Options{})
+	p.UpdateRoutes([]HTTPRoute{{
+		Namespace: "default",
+		Name:      "route",
+		Rules: []RouteRule{{
+			Backends: []WeightedBackend{{Backend: Backend{Host: "web.default.svc.invalid", Port: 80, Endpoints: []string{u.Host}}, Weight: 1}},
+		}},
+	}})
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected the request to reach an endpoint, got %v", rec.Code)
+	}
+}
+
+func TestBackendTLS(t *testing.T) {
+	backend := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer backend.Close()
+	u, err := url.Parse(backend.URL)
+	if err != nil {
+		t.Fatalf("unable to parse backend URL: %v", err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatalf("unable to parse backend port: %v", err)
+	}
+
+	// The test server's certificate is valid for example.com.
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: backend.Certificate().Raw})
+	trusted := &BackendTLS{ServerName: "example.com", CACertificates: ca}
+	if backendTransport(trusted) != backendTransport(&BackendTLS{ServerName: "example.com", CACertificates: ca}) {
+		t.Fatalf("expected the cached transport to be reused")
+	}
+
+	tests := []struct {
+		name     string
+		tls      *BackendTLS
+		expected int
+	}{
+		{name: "trusted certificate", tls: trusted, expected: http.StatusNoContent},
+		{name: "wrong server name", tls: &BackendTLS{ServerName: "untrusted.test", CACertificates: ca}, expected: http.StatusBadGateway},
+		{name: "untrusted certificate", tls: &BackendTLS{ServerName: "example.com"}, expected: http.StatusBadGateway},
+		{name: "plain HTTP to a TLS backend", expected: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProxy(Options{})
+			p.UpdateRoutes([]HTTPRoute{{
+				Namespace: "default",
+				Name:      "route",
+				Rules: []RouteRule{{
+					Backends: []WeightedBackend{{Backend: Backend{Host: u.Hostname(), Port: int32(port), TLS: tt.tls}, Weight: 1}},
+				}},
+			}})
+			rec := httptest.NewRecorder()
+			p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+			if rec.Code != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, rec.Code)
+			}
+		})
+	}
+}
+
+func TestDrainingRouteClosesConnections(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer backend.Close()
+	u, err := url.Parse(backend.URL)
+	if err != nil {
+		t.Fatalf("unable to parse backend URL: %v", err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		t.Fatalf("unable to parse backend port: %v", err)
+	}
+
+	p := NewProxy(Options{})
+	route := HTTPRoute{
+		Namespace: "default",
+		Name:      "route",
+		Rules: []RouteRule{{
+			Backends: []WeightedBackend{{Backend: Backend{Host: u.Hostname(), Port: int32(port)}, Weight: 1}},
+		}},
+	}
+	for _, draining := range []bool{false, true} {
+		route.Draining = draining
+		p.UpdateRoute(route)
+		rec := httptest.NewRecorder()
+		p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("draining=%v: expected the request to be served, got %v", draining, rec.Code)
+		}
+		if closed := rec.Header().Get("Connection") == "close"; closed != draining {
+			t.Errorf("draining=%v: expected the connection to be closed only while draining, got Connection %q", draining, rec.Header().Get("Connection"))
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RedactedValue replaces the value of sensitive headers in logs and traces.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedHeaders lists the headers that carry credentials in common
+// deployments and are redacted unless configured otherwise.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// HeaderRedactor hides the values of sensitive headers before they are
+// written to access logs, debug dumps, or trace attributes.
+type HeaderRedactor struct {
+	names map[string]bool
+}
+
+// NewHeaderRedactor returns a HeaderRedactor for the given header names, which
+// are matched case-insensitively.
+func NewHeaderRedactor(names []string) *HeaderRedactor {
+	r := &HeaderRedactor{names: map[string]bool{}}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			r.names[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+	return r
+}
+
+// IsRedacted reports whether the value of the named header must be hidden.
+func (r *HeaderRedactor) IsRedacted(name string) bool {
+	return r.names[http.CanonicalHeaderKey(name)]
+}
+
+// Value returns the value to record for the named header.
+func (r *HeaderRedactor) Value(name, value string) string {
+	if r.IsRedacted(name) {
+		return RedactedValue
+	}
+	return value
+}
+
+// Redact returns a copy of the headers with sensitive values replaced. The
+// original headers are not modified.
+func (r *HeaderRedactor) Redact(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		if !r.IsRedacted(name) {
+			redacted[name] = append([]string(nil), values...)
+			continue
+		}
+		masked := make([]string, len(values))
+		for i := range values {
+			masked[i] = RedactedValue
+		}
+		redacted[name] = masked
+	}
+	return redacted
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestHeaderRedactor(t *testing.T) {
+	redactor := NewHeaderRedactor([]string{"authorization", " X-Api-Key ", ""})
+
+	header := http.Header{
+		"Authorization": {"Bearer token"},
+		"X-Api-Key":     {"one", "two"},
+		"Accept":        {"*/*"},
+	}
+	expected := http.Header{
+		"Authorization": {RedactedValue},
+		"X-Api-Key":     {RedactedValue, RedactedValue},
+		"Accept":        {"*/*"},
+	}
+
+	actual := redactor.Redact(header)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if header.Get("Authorization") != "Bearer token" {
+		t.Errorf("expected original headers to be unmodified, got %v", header)
+	}
+	if v := redactor.Value("x-api-key", "secret"); v != RedactedValue {
+		t.Errorf("expected %q, got %q", RedactedValue, v)
+	}
+	if v := redactor.Value("Accept", "*/*"); v != "*/*" {
+		t.Errorf("expected %q, got %q", "*/*", v)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ShutdownDelay keeps a proxy serving for a while once it is asked to stop,
+// failing its readiness check meanwhile, so that load balancers deregister it
+// before it stops accepting connections and drains those in flight.
+type ShutdownDelay struct {
+	delay    time.Duration
+	stopping atomic.Bool
+}
+
+// NewShutdownDelay returns a ShutdownDelay keeping the proxy serving for
+// delay.
+func NewShutdownDelay(delay time.Duration) *ShutdownDelay {
+	return &ShutdownDelay{delay: delay}
+}
+
+// Context returns a context that is done delay after ctx is, from which the
+// proxy is run.
+func (s *ShutdownDelay) Context(ctx context.Context) context.Context {
+	delayed, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	go func() {
+		<-ctx.Done()
+		s.stopping.Store(true)
+		if s.delay > 0 {
+			log.Log.Info("failing readiness before shutting down", "delay", s.delay)
+			time.Sleep(s.delay)
+		}
+		cancel()
+	}()
+	return delayed
+}
+
+// ReadyCheck fails once the proxy is asked to stop.
+func (s *ShutdownDelay) ReadyCheck(_ *http.Request) error {
+	if s.stopping.Load() {
+		return errors.New("shutting down")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShutdownDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewShutdownDelay(100 * time.Millisecond)
+	delayed := s.Context(ctx)
+	if err := s.ReadyCheck(nil); err != nil {
+		t.Errorf("expected the proxy to be ready before shutting down, got %v", err)
+	}
+
+	cancel()
+	stopped := time.Now()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.ReadyCheck(nil) == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the ready check to fail once asked to stop")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if delayed.Err() != nil {
+		t.Errorf("expected the proxy to keep running during the delay")
+	}
+	<-delayed.Done()
+	if elapsed := time.Since(stopped); elapsed < 100*time.Millisecond {
+		t.Errorf("expected the proxy to stop after the delay, stopped after %v", elapsed)
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	mathrand "math/rand/v2"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// MetricsDetail controls how much detail is recorded in request metrics.
+type MetricsDetail string
+
+const (
+	MetricsDetailFull  MetricsDetail = "Full"
+	MetricsDetailBasic MetricsDetail = "Basic"
+	MetricsDetailNone  MetricsDetail = "None"
+)
+
+// TraceSampler decides which requests are marked as sampled in their trace
+// context.
+type TraceSampler string
+
+const (
+	// TraceSamplerAlways samples every request, overriding the decision of
+	// callers that did not sample theirs.
+	TraceSamplerAlways TraceSampler = "Always"
+	// TraceSamplerRatio samples TraceSamplingPercent of the traces, deciding
+	// from their trace ID whatever their caller decided, so that every proxy
+	// a trace goes through makes the same decision.
+	TraceSamplerRatio TraceSampler = "Ratio"
+	// TraceSamplerParentBased keeps the decision of callers, and samples
+	// TraceSamplingPercent of the traces the proxy starts. It is the sampler
+	// used when none is set.
+	TraceSamplerParentBased TraceSampler = "ParentBased"
+)
+
+// TraceSamplers lists the valid trace samplers.
+var TraceSamplers = []TraceSampler{TraceSamplerAlways, TraceSamplerRatio, TraceSamplerParentBased}
+
+// Telemetry holds the computed telemetry settings for a route.
+type Telemetry struct {
+	AccessLog bool `json:"accessLog"`
+	// TraceSampler decides which requests are sampled, or
+	// TraceSamplerParentBased if empty.
+	TraceSampler TraceSampler `json:"traceSampler,omitempty"`
+	// TraceSamplingPercent is the percentage of traces sampled by the Ratio
+	// and ParentBased samplers.
+	TraceSamplingPercent int32         `json:"traceSamplingPercent"`
+	Metrics              MetricsDetail `json:"metrics"`
+}
+
+// DefaultTelemetry applies to routes without a TelemetryPolicy and to
+// requests that match no route. Its trace sampling may be changed before the
+// proxy serves, to sample fewer traces by default.
+var DefaultTelemetry = Telemetry{
+	AccessLog:            true,
+	TraceSampler:         TraceSamplerParentBased,
+	TraceSamplingPercent: 100,
+	Metrics:              MetricsDetailFull,
+}
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gari_proxy_requests_total",
+		Help: "Number of requests handled by the proxy.",
+	}, []string{"namespace", "route", "rule", "gateway_namespace", "gateway", "listener", "code"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gari_proxy_request_duration_seconds",
+		Help:    "Time taken by the proxy to serve a request.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"namespace", "route", "rule", "gateway_namespace", "gateway", "listener"})
+)
+
+// Metrics of the proxy as a whole, without per-route labels, for horizontal
+// autoscaling on the traffic of each proxy pod. They are recorded whatever
+// the metrics detail of the routes.
+var (
+	podRequestsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gari_proxy_pod_requests_total",
+		Help: "Number of requests handled by this proxy, across all routes.",
+	})
+
+	podRequestLatency = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "gari_proxy_pod_request_latency_seconds",
+		Help:       "Time taken by this proxy to serve a request over the last minute, across all routes.",
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+		MaxAge:     time.Minute,
+	})
+
+	activeConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gari_proxy_active_connections",
+		Help: "Number of client connections open to this proxy.",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(requestsTotal, requestDuration, podRequestsTotal, podRequestLatency, activeConnections)
+}
+
+// RegisterRuntimeMetrics registers the Go runtime and process metrics, such as
+// goroutines, GC pauses, heap and open file descriptors, which show how the
+// proxy behaves under load. controller-runtime registers the same collectors
+// when its controllers are linked in, so they may already be registered; this
+// must be called after package initialization for that to be detected.
+func RegisterRuntimeMetrics() {
+	for _, c := range []prometheus.Collector{
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewGoCollector(collectors.WithGoCollectorRuntimeMetrics(collectors.MetricsAll)),
+	} {
+		if err := ctrlmetrics.Registry.Register(c); err != nil && !errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+			panic(err)
+		}
+	}
+}
+
+// ConnState counts the client connections open to the proxy in
+// gari_proxy_active_connections. It is set as the ConnState of the
+// http.Server serving the proxy.
+func ConnState(_ net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		activeConnections.Inc()
+	case http.StateHijacked, http.StateClosed:
+		activeConnections.Dec()
+	}
+}
+
+// statusRecorder captures the status code and the size of the body written
+// to a ResponseWriter.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer, for
+// example to flush streamed responses.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// recordRequest writes the access log entry and metrics for a served request.
+func (p *Proxy) recordRequest(rec *statusRecorder, r *http.Request, route *HTTPRoute, rule *RouteRule, telemetry Telemetry, start time.Time) {
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	duration := time.Since(start)
+
+	var namespace, name, ruleName string
+	if route != nil {
+		namespace, name = route.Namespace, route.Name
+	}
+	if rule != nil {
+		ruleName = rule.Name
+	}
+
+	if telemetry.AccessLog {
+		p.accessLog.write(&accessLogEntry{
+			request:  r,
+			status:   status,
+			bytes:    rec.bytes,
+			start:    start,
+			duration: duration,
+			route:    namespace + "/" + name,
+			rule:     ruleName,
+		})
+	}
+
+	podRequestsTotal.Inc()
+	podRequestLatency.Observe(duration.Seconds())
+
+	switch telemetry.Metrics {
+	case MetricsDetailNone:
+		return
+	case MetricsDetailBasic:
+		namespace, name, ruleName = "", "", ""
+	default:
+		namespace, name, ruleName = p.routeLabels.labels(namespace, name, ruleName)
+	}
+	// Requests are attributed to their listener whatever the route labels,
+	// since Gateways are far fewer than routes.
+	var listener RouteListener
+	if route != nil {
+		if l := route.listenerFor(r.Host); l != nil {
+			listener = *l
+		}
+	}
+	requestsTotal.WithLabelValues(namespace, name, ruleName, listener.GatewayNamespace, listener.Gateway, listener.Listener, strconv.Itoa(status)).Inc()
+	requestDuration.WithLabelValues(namespace, name, ruleName, listener.GatewayNamespace, listener.Gateway, listener.Listener).Observe(duration.Seconds())
+}
+
+// listenerFor returns the listener of the route a request for host was
+// received on: the one with the most specific hostname matching host, an
+// exact hostname before a wildcard and a wildcard before none, as Gateways
+// match listeners.
+func (r *HTTPRoute) listenerFor(host string) *RouteListener {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	var best *RouteListener
+	rank := func(l *RouteListener) int {
+		switch {
+		case l.Hostname == "":
+			return 0
+		case strings.HasPrefix(l.Hostname, "*."):
+			return len(l.Hostname)
+		default:
+			return math.MaxInt
+		}
+	}
+	for i := range r.Listeners {
+		l := &r.Listeners[i]
+		matches := l.Hostname == "" || l.Hostname == host ||
+			strings.HasPrefix(l.Hostname, "*.") && strings.HasSuffix(host, l.Hostname[1:])
+		if matches && (best == nil || rank(l) > rank(best)) {
+			best = l
+		}
+	}
+	return best
+}
+
+// RouteLabelMode controls the route labels of request metrics, to bound the
+// number of series of clusters with many routes.
+type RouteLabelMode string
+
+const (
+	// RouteLabelModeFull labels metrics with the namespace and name of the
+	// route and the name of the rule.
+	RouteLabelModeFull RouteLabelMode = "Full"
+	// RouteLabelModeHashed labels metrics with the namespace of the route, and
+	// a short hash of its name and of the name of the rule, keeping the
+	// series of each route apart with labels of bounded length.
+	RouteLabelModeHashed RouteLabelMode = "Hashed"
+	// RouteLabelModeNone records metrics without route labels, as the Basic
+	// metrics detail does, whatever the telemetry of the routes.
+	RouteLabelModeNone RouteLabelMode = "None"
+)
+
+// RouteLabelModes lists the valid route label modes.
+var RouteLabelModes = []RouteLabelMode{RouteLabelModeFull, RouteLabelModeHashed, RouteLabelModeNone}
+
+// overflowRouteLabel is the route label of the requests of routes past
+// Options.MaxMetricsRoutes.
+const overflowRouteLabel = "_overflow"
+
+// routeLabeler computes the route labels of request metrics.
+type routeLabeler struct {
+	mode RouteLabelMode
+	max  int
+
+	mu sync.Mutex
+	// tracked holds the routes with labels of their own, up to max. Routes
+	// are never untracked, since their series stay in the registry.
+	tracked map[RouteKey]struct{}
+}
+
+// labels returns the namespace, route and rule labels of the requests served
+// by the route with the given namespace and name, and rule.
+func (l *routeLabeler) labels(namespace, name, rule string) (string, string, string) {
+	if l.mode == RouteLabelModeNone {
+		return "", "", ""
+	}
+	if l.max > 0 && name != "" {
+		key := RouteKey{Namespace: namespace, Name: name}
+		l.mu.Lock()
+		_, tracked := l.tracked[key]
+		if !tracked && len(l.tracked) >= l.max {
+			l.mu.Unlock()
+			return "", overflowRouteLabel, ""
+		}
+		if l.tracked == nil {
+			l.tracked = map[RouteKey]struct{}{}
+		}
+		l.tracked[key] = struct{}{}
+		l.mu.Unlock()
+	}
+	if l.mode == RouteLabelModeHashed && name != "" {
+		name = labelHash(namespace + "/" + name)
+		if rule != "" {
+			rule = labelHash(rule)
+		}
+	}
+	return namespace, name, rule
+}
+
+// labelHash returns a short hash of value, for a label of bounded length.
+func labelHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:6])
+}
+
+// traceparentHeader carries the W3C trace context.
+const traceparentHeader = "traceparent"
+
+var traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// traceIDs returns the trace ID and the span ID of the W3C trace context of
+// r, or empty strings if it carries none.
+func traceIDs(r *http.Request) (traceID, spanID string) {
+	traceparent := r.Header.Get(traceparentHeader)
+	if !traceparentPattern.MatchString(traceparent) {
+		return "", ""
+	}
+	return traceparent[3:35], traceparent[36:52]
+}
+
+// ensureTraceContext starts a new trace for requests that do not carry a
+// valid W3C trace context, and marks the trace as sampled or not according to
+// the route's sampler. The ParentBased sampler leaves existing trace contexts
+// untouched so that the caller's sampling decision is respected.
+func ensureTraceContext(r *http.Request, telemetry Telemetry) {
+	parentBased := telemetry.TraceSampler == "" || telemetry.TraceSampler == TraceSamplerParentBased
+	traceparent := r.Header.Get(traceparentHeader)
+	if !traceparentPattern.MatchString(traceparent) {
+		traceID := make([]byte, 16)
+		spanID := make([]byte, 8)
+		_, _ = rand.Read(traceID)
+		_, _ = rand.Read(spanID)
+		var sampled bool
+		if parentBased {
+			sampled = mathrand.Int32N(100) < telemetry.TraceSamplingPercent
+		} else {
+			sampled = sampleTrace(traceID, telemetry)
+		}
+		flags := "00"
+		if sampled {
+			flags = "01"
+		}
+		r.Header.Set(traceparentHeader, fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(traceID), hex.EncodeToString(spanID), flags))
+		return
+	}
+	if parentBased {
+		return
+	}
+
+	// The sampled flag is the lowest bit of the trace flags, the other bits
+	// are kept.
+	traceID, _ := hex.DecodeString(traceparent[3:35])
+	flags, _ := strconv.ParseUint(traceparent[53:], 16, 8)
+	if sampleTrace(traceID, telemetry) {
+		flags |= 1
+	} else {
+		flags &^= 1
+	}
+	r.Header.Set(traceparentHeader, fmt.Sprintf("%s%02x", traceparent[:53], flags))
+}
+
+// sampleTrace returns whether the trace with the given ID is sampled by the
+// Always or Ratio sampler. The Ratio sampler compares the last 8 bytes of the
+// trace ID, which are random, to the sampling percentage, as the
+// TraceIDRatioBased sampler of OpenTelemetry does.
+func sampleTrace(traceID []byte, telemetry Telemetry) bool {
+	switch {
+	case telemetry.TraceSampler == TraceSamplerAlways || telemetry.TraceSamplingPercent >= 100:
+		return true
+	case telemetry.TraceSamplingPercent <= 0:
+		return false
+	}
+	bound := uint64(telemetry.TraceSamplingPercent) * (math.MaxUint64 / 100)
+	return binary.BigEndian.Uint64(traceID[8:]) < bound
+}
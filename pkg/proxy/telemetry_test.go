@@ -0,0 +1,219 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestEnsureTraceContext(t *testing.T) {
+	const existing = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	tests := []struct {
+		name        string
+		traceparent string
+		sampler     TraceSampler
+		percent     int32
+		expected    string
+	}{
+		{
+			name:        "existing context is kept",
+			traceparent: existing,
+			percent:     0,
+			expected:    existing,
+		},
+		{
+			name:     "new trace sampled",
+			percent:  100,
+			expected: "-01",
+		},
+		{
+			name:     "new trace not sampled",
+			percent:  0,
+			expected: "-00",
+		},
+		{
+			name:        "invalid context is replaced",
+			traceparent: "garbage",
+			percent:     100,
+			expected:    "-01",
+		},
+		{
+			name:        "always sampler overrides the caller",
+			traceparent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00",
+			sampler:     TraceSamplerAlways,
+			expected:    existing,
+		},
+		{
+			name:        "ratio sampler overrides the caller",
+			traceparent: existing,
+			sampler:     TraceSamplerRatio,
+			percent:     0,
+			expected:    "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00",
+		},
+		{
+			name:        "ratio sampler decides from the trace ID",
+			traceparent: "00-0af7651916cd43dd0000000000000001-b7ad6b7169203331-02",
+			sampler:     TraceSamplerRatio,
+			percent:     1,
+			expected:    "00-0af7651916cd43dd0000000000000001-b7ad6b7169203331-03",
+		},
+		{
+			name:     "ratio sampler starts traces",
+			sampler:  TraceSamplerRatio,
+			percent:  100,
+			expected: "-01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.traceparent != "" {
+				r.Header.Set(traceparentHeader, tt.traceparent)
+			}
+			ensureTraceContext(r, Telemetry{TraceSampler: tt.sampler, TraceSamplingPercent: tt.percent})
+
+			actual := r.Header.Get(traceparentHeader)
+			if !traceparentPattern.MatchString(actual) || !strings.HasSuffix(actual, tt.expected) {
+				t.Errorf("expected traceparent ending in %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAccessLogOutput(t *testing.T) {
+	var output bytes.Buffer
+	p := NewProxy(Options{AccessLog: &output})
+	rec := &statusRecorder{ResponseWriter: httptest.NewRecorder()}
+	r := httptest.NewRequest("GET", "/path", nil)
+	p.recordRequest(rec, r, &HTTPRoute{Namespace: "default", Name: "route"}, nil, Telemetry{AccessLog: true}, time.Now())
+	if line := output.String(); !strings.Contains(line, `"Request served"`) || !strings.Contains(line, `route="default/route"`) {
+		t.Errorf("expected the access log entry to be written to the output, got %q", line)
+	}
+}
+
+func TestPodMetrics(t *testing.T) {
+	p := NewProxy(Options{})
+	requests := testutil.ToFloat64(podRequestsTotal)
+
+	// Requests count towards the metrics of the pod even when their routes
+	// record no metrics of their own.
+	rec := &statusRecorder{ResponseWriter: httptest.NewRecorder()}
+	r := httptest.NewRequest("GET", "/", nil)
+	p.recordRequest(rec, r, nil, nil, Telemetry{Metrics: MetricsDetailNone}, time.Now())
+	if actual := testutil.ToFloat64(podRequestsTotal); actual != requests+1 {
+		t.Errorf("expected %v requests, got %v", requests+1, actual)
+	}
+
+	connections := testutil.ToFloat64(activeConnections)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actual := testutil.ToFloat64(activeConnections); actual != connections+1 {
+			t.Errorf("expected %v active connections, got %v", connections+1, actual)
+		}
+	}))
+	server.Config.ConnState = ConnState
+	server.Start()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	server.Close()
+	if actual := testutil.ToFloat64(activeConnections); actual != connections {
+		t.Errorf("expected %v active connections once closed, got %v", connections, actual)
+	}
+}
+
+func TestRegisterRuntimeMetrics(t *testing.T) {
+	RegisterRuntimeMetrics()
+	// Registering again, as when controller-runtime registered them first,
+	// is not an error.
+	RegisterRuntimeMetrics()
+
+	families, err := ctrlmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := map[string]bool{}
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	expected := []string{"go_goroutines", "go_gc_duration_seconds", "go_memstats_heap_inuse_bytes"}
+	if runtime.GOOS == "linux" {
+		expected = append(expected, "process_open_fds", "process_resident_memory_bytes")
+	}
+	for _, name := range expected {
+		if !names[name] {
+			t.Errorf("expected %s to be exported", name)
+		}
+	}
+}
+
+func TestRouteLabels(t *testing.T) {
+	capped := &routeLabeler{mode: RouteLabelModeFull, max: 1}
+	if ns, route, rule := capped.labels("default", "a", "r"); ns != "default" || route != "a" || rule != "r" {
+		t.Errorf("expected the labels of the first route, got %q %q %q", ns, route, rule)
+	}
+	if ns, route, rule := capped.labels("default", "b", "r"); ns != "" || route != overflowRouteLabel || rule != "" {
+		t.Errorf("expected routes past the cap to be recorded together, got %q %q %q", ns, route, rule)
+	}
+	if _, route, _ := capped.labels("default", "a", "r"); route != "a" {
+		t.Errorf("expected tracked routes to keep their labels, got %q", route)
+	}
+
+	hashed := &routeLabeler{mode: RouteLabelModeHashed}
+	ns, route, rule := hashed.labels("default", "a", "r")
+	if ns != "default" || len(route) != 12 || route == "a" || len(rule) != 12 {
+		t.Errorf("expected the route and rule to be hashed, got %q %q %q", ns, route, rule)
+	}
+	if _, other, _ := hashed.labels("other", "a", "r"); other == route {
+		t.Errorf("expected routes of different namespaces to get different hashes")
+	}
+
+	none := &routeLabeler{mode: RouteLabelModeNone}
+	if ns, route, rule := none.labels("default", "a", "r"); ns != "" || route != "" || rule != "" {
+		t.Errorf("expected no route labels, got %q %q %q", ns, route, rule)
+	}
+}
+
+func TestListenerFor(t *testing.T) {
+	route := &HTTPRoute{Listeners: []RouteListener{
+		{Gateway: "gw", Listener: "any"},
+		{Gateway: "gw", Listener: "wildcard", Hostname: "*.example.com"},
+		{Gateway: "gw", Listener: "exact", Hostname: "a.example.com"},
+	}}
+	for host, expected := range map[string]string{
+		"a.example.com:8080": "exact",
+		"b.example.com":      "wildcard",
+		"other.test":         "any",
+	} {
+		if l := route.listenerFor(host); l == nil || l.Listener != expected {
+			t.Errorf("expected %s to be received on %s, got %v", host, expected, l)
+		}
+	}
+	if l := (&HTTPRoute{}).listenerFor("a.example.com"); l != nil {
+		t.Errorf("expected no listener for a route without listeners, got %v", l)
+	}
+}
@@ -0,0 +1,14 @@
+;; A minimal plugin that adds an "X-Plugin: hello" header to every request.
+;; Compiled to set_header.wasm with: wat2wasm set_header.wat
+(module
+  (memory (export "memory") 1)
+  (data (i32.const 1024) "{\22setHeaders\22:{\22X-Plugin\22:\22hello\22}}")
+  ;; Requests are written after the result; this plugin does not read them.
+  (func (export "gari_alloc") (param i32) (result i32)
+    i32.const 4096)
+  (func (export "gari_on_request") (param i32 i32) (result i64)
+    i64.const 1024
+    i64.const 32
+    i64.shl
+    i64.const 35
+    i64.or))
@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var (
+	celEnvOnce sync.Once
+	celEnv     *cel.Env
+	celEnvErr  error
+)
+
+// transformEnv returns the CEL environment in which transform expressions are
+// compiled. See the TransformPolicy API for the variables it declares.
+func transformEnv() (*cel.Env, error) {
+	celEnvOnce.Do(func() {
+		celEnv, celEnvErr = cel.NewEnv(
+			cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+			cel.Variable("route", cel.MapType(cel.StringType, cel.StringType)),
+			cel.Variable("response", cel.MapType(cel.StringType, cel.DynType)),
+		)
+	})
+	return celEnv, celEnvErr
+}
+
+// HeaderTransform sets a header to the result of a compiled CEL expression.
+type HeaderTransform struct {
+	Name  string
+	Value cel.Program
+	// When, if set, must evaluate to true for the header to be set.
+	When cel.Program
+
+	// value and when are the expressions Value and When were compiled from,
+	// kept so that the transform can be sent to standalone proxies.
+	value, when string
+}
+
+// Transform holds the compiled header transforms for a route.
+type Transform struct {
+	RequestHeaders  []HeaderTransform
+	ResponseHeaders []HeaderTransform
+}
+
+// NewHeaderTransform compiles the expressions of a header transform. when may
+// be empty.
+func NewHeaderTransform(name, value, when string) (HeaderTransform, error) {
+	t := HeaderTransform{Name: name, value: value, when: when}
+	var err error
+	if t.Value, err = compileCEL(value, cel.StringType); err != nil {
+		return HeaderTransform{}, fmt.Errorf("header %s value: %w", name, err)
+	}
+	if when != "" {
+		if t.When, err = compileCEL(when, cel.BoolType); err != nil {
+			return HeaderTransform{}, fmt.Errorf("header %s when: %w", name, err)
+		}
+	}
+	return t, nil
+}
+
+func compileCEL(expr string, outputType *cel.Type) (cel.Program, error) {
+	env, err := transformEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if !ast.OutputType().IsExactType(outputType) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression must evaluate to %s, got %s", outputType, ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// applyHeaderTransforms sets the headers whose conditions hold. Evaluation
+// errors are logged and the affected header is left unchanged.
+func applyHeaderTransforms(transforms []HeaderTransform, header http.Header, vars map[string]any) {
+	for _, t := range transforms {
+		if t.When != nil {
+			out, _, err := t.When.Eval(vars)
+			if err != nil {
+				log.Log.Error(err, "unable to evaluate transform condition", "header", t.Name)
+				continue
+			}
+			if matched, ok := out.Value().(bool); !ok || !matched {
+				continue
+			}
+		}
+		out, _, err := t.Value.Eval(vars)
+		if err != nil {
+			log.Log.Error(err, "unable to evaluate transform value", "header", t.Name)
+			continue
+		}
+		value, ok := out.Value().(string)
+		if !ok {
+			log.Log.Error(nil, "transform value is not a string", "header", t.Name)
+			continue
+		}
+		header.Set(t.Name, value)
+	}
+}
+
+// requestVars returns the CEL variables describing a request.
+func requestVars(r *http.Request, route *HTTPRoute) map[string]any {
+	query := map[string]string{}
+	for name, values := range r.URL.Query() {
+		query[name] = values[0]
+	}
+	return map[string]any{
+		"request": map[string]any{
+			"method":  r.Method,
+			"host":    r.Host,
+			"path":    r.URL.Path,
+			"headers": flattenHeader(r.Header),
+			"query":   query,
+		},
+		"route": map[string]string{
+			"namespace": route.Namespace,
+			"name":      route.Name,
+		},
+		"response": map[string]any{},
+	}
+}
+
+// flattenHeader returns the first value of each header, keyed by the header
+// name in lower case.
+func flattenHeader(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for name, values := range header {
+		if len(values) > 0 {
+			flat[strings.ToLower(name)] = values[0]
+		}
+	}
+	return flat
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderTransforms(t *testing.T) {
+	tier, err := NewHeaderTransform("X-Tier", `request.path.startsWith("/premium") ? "gold" : "standard"`, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user, err := NewHeaderTransform("X-User", `request.headers["x-forwarded-user"]`, `"x-forwarded-user" in request.headers`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	routeName, err := NewHeaderTransform("X-Route", `route.namespace + "/" + route.name`, `request.query["debug"] == "1"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transforms := []HeaderTransform{tier, user, routeName}
+	route := &HTTPRoute{Namespace: "default", Name: "shop"}
+
+	tests := []struct {
+		name     string
+		target   string
+		header   http.Header
+		expected map[string]string
+	}{
+		{
+			name:     "premium path",
+			target:   "/premium/item",
+			expected: map[string]string{"X-Tier": "gold", "X-User": "", "X-Route": ""},
+		},
+		{
+			name:     "conditions hold",
+			target:   "/item?debug=1",
+			header:   http.Header{"X-Forwarded-User": {"alice"}},
+			expected: map[string]string{"X-Tier": "standard", "X-User": "alice", "X-Route": "default/shop"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for name, values := range tt.header {
+				r.Header[name] = values
+			}
+			applyHeaderTransforms(transforms, r.Header, requestVars(r, route))
+			for name, expected := range tt.expected {
+				if actual := r.Header.Get(name); actual != expected {
+					t.Errorf("expected %s %q, got %q", name, expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestNewHeaderTransformErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		when  string
+	}{
+		{name: "syntax error", value: `request.path +`},
+		{name: "value is not a string", value: `request.path == "/"`},
+		{name: "condition is not a bool", value: `"x"`, when: `1 + 1`},
+		{name: "unknown variable", value: `claims.tier`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHeaderTransform("X-Test", tt.value, tt.when); err == nil {
+				t.Errorf("expected error compiling %q", tt.value)
+			}
+		})
+	}
+}
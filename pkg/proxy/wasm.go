@@ -0,0 +1,137 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// Functions a WebAssembly plugin must export.
+//
+// The proxy calls wasmAllocFunction(size) to obtain a buffer in the module's
+// memory, writes the JSON-encoded wasmRequest into it and then calls
+// wasmOnRequestFunction(ptr, size). That function returns the location of a
+// JSON-encoded HookResult packed as (ptr << 32 | size), or 0 to leave the
+// request unchanged.
+const (
+	wasmAllocFunction     = "gari_alloc"
+	wasmOnRequestFunction = "gari_on_request"
+)
+
+// wasmRequest is the view of a request passed to a WebAssembly plugin.
+type wasmRequest struct {
+	Method  string              `json:"method"`
+	Host    string              `json:"host"`
+	Path    string              `json:"path"`
+	Headers map[string][]string `json:"headers"`
+	Route   RouteMetadata       `json:"route"`
+}
+
+// WasmPlugin is a RequestHook backed by a WebAssembly module. A fresh module
+// instance is created for every request so that plugins cannot share state
+// between requests.
+type WasmPlugin struct {
+	name     string
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+	// wasm is the module the plugin was compiled from, kept so that the
+	// plugin can be sent to standalone proxies.
+	wasm []byte
+}
+
+// NewWasmPlugin compiles the given WebAssembly module. Modules run on the
+// wazero interpreter and may import WASI.
+func NewWasmPlugin(ctx context.Context, name string, wasm []byte) (*WasmPlugin, error) {
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfigInterpreter())
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("instantiating WASI: %w", err)
+	}
+	compiled, err := runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("compiling plugin %s: %w", name, err)
+	}
+	for _, fn := range []string{wasmAllocFunction, wasmOnRequestFunction} {
+		if _, ok := compiled.ExportedFunctions()[fn]; !ok {
+			runtime.Close(ctx)
+			return nil, fmt.Errorf("plugin %s does not export %s", name, fn)
+		}
+	}
+	return &WasmPlugin{name: name, runtime: runtime, compiled: compiled, wasm: wasm}, nil
+}
+
+// Close releases the resources held by the plugin.
+func (p *WasmPlugin) Close(ctx context.Context) error {
+	return p.runtime.Close(ctx)
+}
+
+func (p *WasmPlugin) HandleRequest(r *http.Request, meta RouteMetadata) (*HookResult, error) {
+	ctx := r.Context()
+
+	input, err := json.Marshal(wasmRequest{
+		Method:  r.Method,
+		Host:    r.Host,
+		Path:    r.URL.Path,
+		Headers: r.Header,
+		Route:   meta,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Reactor modules, such as those built by TinyGo, initialize themselves
+	// in _initialize rather than _start.
+	config := wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize")
+	mod, err := p.runtime.InstantiateModule(ctx, p.compiled, config)
+	if err != nil {
+		return nil, fmt.Errorf("instantiating plugin %s: %w", p.name, err)
+	}
+	defer mod.Close(ctx)
+
+	results, err := mod.ExportedFunction(wasmAllocFunction).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %s: %w", p.name, wasmAllocFunction, err)
+	}
+	ptr := uint32(results[0])
+	if !mod.Memory().Write(ptr, input) {
+		return nil, fmt.Errorf("plugin %s: allocated buffer is out of range", p.name)
+	}
+
+	results, err = mod.ExportedFunction(wasmOnRequestFunction).Call(ctx, uint64(ptr), uint64(len(input)))
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %s: %w", p.name, wasmOnRequestFunction, err)
+	}
+	if results[0] == 0 {
+		return nil, nil
+	}
+	outPtr, outLen := uint32(results[0]>>32), uint32(results[0])
+	output, ok := mod.Memory().Read(outPtr, outLen)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s: result is out of range", p.name)
+	}
+
+	var result HookResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("plugin %s: decoding result: %w", p.name, err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestWasmPlugin(t *testing.T) {
+	wasm, err := os.ReadFile("testdata/set_header.wasm")
+	if err != nil {
+		t.Fatalf("unable to read plugin: %v", err)
+	}
+	plugin, err := NewWasmPlugin(context.Background(), "set-header", wasm)
+	if err != nil {
+		t.Fatalf("unable to load plugin: %v", err)
+	}
+	defer plugin.Close(context.Background())
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Plugin", r.Header.Get("X-Plugin"))
+	}))
+	defer backend.Close()
+
+	tests := []struct {
+		name           string
+		hook           RequestHook
+		expectedStatus int
+		expectedHeader string
+	}{
+		{
+			name:           "plugin sets request header",
+			hook:           plugin,
+			expectedStatus: http.StatusOK,
+			expectedHeader: "hello",
+		},
+		{
+			name:           "unresolved extension fails the request",
+			hook:           NewUnresolvedHook(errors.New("not found")),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProxy(Options{})
+			p.UpdateRoutes([]HTTPRoute{
+				{
+					Rules: []RouteRule{
+						{
+							Backends: []WeightedBackend{{Backend: testBackend(t, backend), Weight: 1}},
+							Hooks:    []RequestHook{tt.hook},
+						},
+					},
+				},
+			})
+
+			w := httptest.NewRecorder()
+			p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if actual := w.Header().Get("X-Seen-Plugin"); actual != tt.expectedHeader {
+				t.Errorf("expected backend to see X-Plugin %q, got %q", tt.expectedHeader, actual)
+			}
+		})
+	}
+}
+
+func testBackend(t *testing.T, server *httptest.Server) Backend {
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to parse backend address: %v", err)
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("unable to parse backend port: %v", err)
+	}
+	return Backend{Host: host, Port: int32(portNumber)}
+}
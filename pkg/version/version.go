@@ -0,0 +1,97 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package version identifies the build of the binaries. Releases set the
+// variables at build time:
+//
+//	go build -ldflags "-X github.com/gke-labs/gateway-api-reference-implementation/pkg/version.version=v1.2.3 \
+//	  -X github.com/gke-labs/gateway-api-reference-implementation/pkg/version.commit=$(git rev-parse HEAD) \
+//	  -X github.com/gke-labs/gateway-api-reference-implementation/pkg/version.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Otherwise they are taken from the build info Go records in the binary.
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/gateway-api/pkg/consts"
+)
+
+// Set at build time with -ldflags "-X".
+var (
+	version   string
+	commit    string
+	buildDate string
+)
+
+var buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gari_build_info",
+	Help: "Always 1, labelled with the version, commit and build date of the binary, and the Go and Gateway API versions.",
+}, []string{"version", "commit", "build_date", "go_version", "gateway_api_version"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(buildInfo)
+	info := Get()
+	buildInfo.WithLabelValues(info.Version, info.Commit, info.BuildDate, info.GoVersion, info.GatewayAPIVersion).Set(1)
+}
+
+// Info identifies a build.
+type Info struct {
+	// Version is the version of the binary, or "(devel)" if unknown.
+	Version string `json:"version"`
+	// Commit is the revision the binary was built from, if known.
+	Commit string `json:"commit,omitempty"`
+	// BuildDate is when the binary was built, or else when its commit was
+	// made, in RFC 3339 format, if known.
+	BuildDate         string `json:"buildDate,omitempty"`
+	GoVersion         string `json:"goVersion"`
+	GatewayAPIVersion string `json:"gatewayAPIVersion"`
+}
+
+// Get returns the Info of the running binary.
+func Get() Info {
+	info := Info{
+		Version:           version,
+		Commit:            commit,
+		BuildDate:         buildDate,
+		GoVersion:         runtime.Version(),
+		GatewayAPIVersion: consts.BundleVersion,
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && build.Main.Version != "" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "(devel)"
+	}
+	return info
+}
+
+// KeysAndValues returns info as logr key-value pairs, for the logs of a
+// binary starting.
+func (info Info) KeysAndValues() []any {
+	return []any{"version", info.Version, "commit", info.Commit, "buildDate", info.BuildDate, "goVersion", info.GoVersion}
+}
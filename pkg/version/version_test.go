@@ -0,0 +1,52 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGet(t *testing.T) {
+	info := Get()
+	if info.Version == "" || info.GoVersion == "" || info.GatewayAPIVersion == "" {
+		t.Errorf("expected the version to be known, got %+v", info)
+	}
+
+	version, commit, buildDate = "v1.2.3", "abc123", "2026-01-02T03:04:05Z"
+	defer func() { version, commit, buildDate = "", "", "" }()
+	info = Get()
+	if info.Version != "v1.2.3" || info.Commit != "abc123" || info.BuildDate != "2026-01-02T03:04:05Z" {
+		t.Errorf("expected the values set at build time to take precedence, got %+v", info)
+	}
+}
+
+func TestBuildInfoMetric(t *testing.T) {
+	if count := testutil.CollectAndCount(buildInfo); count != 1 {
+		t.Fatalf("expected one build_info series, got %d", count)
+	}
+	info := Get()
+	expected := `
+# HELP gari_build_info Always 1, labelled with the version, commit and build date of the binary, and the Go and Gateway API versions.
+# TYPE gari_build_info gauge
+gari_build_info{build_date="` + info.BuildDate + `",commit="` + info.Commit + `",gateway_api_version="` + info.GatewayAPIVersion +
+		`",go_version="` + info.GoVersion + `",version="` + info.Version + `"} 1
+`
+	if err := testutil.CollectAndCompare(buildInfo, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhookcert provisions the serving certificate of the admission
+// webhooks without cert-manager. A self-signed CA and a certificate it signs
+// are kept in a Secret shared by the replicas, which each write them to the
+// webhook server's certificate directory. The CA is set as the caBundle of
+// our ValidatingWebhookConfiguration, and the certificate is renewed ahead of
+// its expiry, under the same CA so that the caBundle stays valid.
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Keys of the Secret holding the CA and the serving certificate.
+const (
+	caCertKey = "ca.crt"
+	caKeyKey  = "ca.key"
+)
+
+const (
+	caValidity   = 10 * 365 * 24 * time.Hour
+	certValidity = 365 * 24 * time.Hour
+	// renewBefore is how long before its expiry a certificate is renewed.
+	renewBefore = 30 * 24 * time.Hour
+	// checkInterval is how often the certificate is checked for renewal,
+	// and the files and the caBundle for drift.
+	checkInterval = time.Hour
+)
+
+// Rotator keeps the serving certificate of the webhooks valid. As a manager
+// Runnable it runs on every replica, since each serves the webhooks from its
+// own certificate directory.
+type Rotator struct {
+	// Reader reads the Secret and the webhook configuration, bypassing the
+	// cache so that the certificate can be written before the manager
+	// starts.
+	Reader client.Reader
+	// Client writes the Secret and the webhook configuration.
+	Client client.Client
+	// Secret is the Secret holding the CA and the certificate.
+	Secret types.NamespacedName
+	// DNSName is the name the webhooks are called at, that of their Service.
+	DNSName string
+	// CertDir is the certificate directory of the webhook server, to which
+	// tls.crt and tls.key are written.
+	CertDir string
+	// WebhookConfiguration is the ValidatingWebhookConfiguration whose
+	// webhooks are given the CA as caBundle. It is left alone if it does not
+	// exist.
+	WebhookConfiguration string
+
+	now func() time.Time
+}
+
+// Start renews the certificate as needed until ctx is done.
+func (r *Rotator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Ensure(ctx); err != nil {
+				log.FromContext(ctx).Error(err, "unable to renew the webhook certificate")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, since every
+// replica serves the webhooks.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Ensure issues or renews the certificate if needed, writes it to CertDir
+// and sets the caBundle of the webhook configuration. It is called before the
+// manager starts, for the webhook server to find the certificate.
+func (r *Rotator) Ensure(ctx context.Context) error {
+	secret, err := r.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(r.CertDir, corev1.TLSCertKey), secret.Data[corev1.TLSCertKey]); err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(r.CertDir, corev1.TLSPrivateKeyKey), secret.Data[corev1.TLSPrivateKeyKey]); err != nil {
+		return err
+	}
+	return r.injectCABundle(ctx, secret.Data[caCertKey])
+}
+
+// ensureSecret returns the Secret, after issuing the certificates it lacks
+// or that expire soon. Replicas racing to issue them converge on the Secret
+// of the first.
+func (r *Rotator) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.Reader.Get(ctx, r.Secret, secret)
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{Type: corev1.SecretTypeTLS}
+		secret.Namespace, secret.Name = r.Secret.Namespace, r.Secret.Name
+	} else if err != nil {
+		return nil, err
+	}
+	data, changed, err := r.issue(secret.Data)
+	if err != nil || !changed {
+		return secret, err
+	}
+
+	secret.Data = data
+	if secret.ResourceVersion == "" {
+		err = r.Client.Create(ctx, secret)
+	} else {
+		err = r.Client.Update(ctx, secret)
+	}
+	if apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err) {
+		secret = &corev1.Secret{}
+		if err := r.Reader.Get(ctx, r.Secret, secret); err != nil {
+			return nil, err
+		}
+		return secret, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.FromContext(ctx).Info("issued webhook certificate", "secret", r.Secret, "dnsName", r.DNSName)
+	return secret, nil
+}
+
+// issue returns data with a new CA if it has none valid for long enough, and
+// a new certificate if it has none for DNSName, signed by the CA and valid
+// for long enough.
+func (r *Rotator) issue(data map[string][]byte) (map[string][]byte, bool, error) {
+	now := time.Now()
+	if r.now != nil {
+		now = r.now()
+	}
+	ca, caKey, err := parseKeyPair(data[caCertKey], data[caKeyKey])
+	issueCA := err != nil || now.Add(renewBefore).After(ca.NotAfter)
+	if !issueCA {
+		cert, _, err := parseKeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey])
+		if err == nil && cert.CheckSignatureFrom(ca) == nil && cert.VerifyHostname(r.DNSName) == nil && now.Add(renewBefore).Before(cert.NotAfter) {
+			return data, false, nil
+		}
+	}
+
+	issued := map[string][]byte{}
+	if issueCA {
+		template := &x509.Certificate{
+			Subject:               pkix.Name{CommonName: "gari-webhook-ca"},
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		}
+		certPEM, keyPEM, err := newCertificate(template, now, caValidity, nil, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		issued[caCertKey], issued[caKeyKey] = certPEM, keyPEM
+		if ca, caKey, err = parseKeyPair(certPEM, keyPEM); err != nil {
+			return nil, false, err
+		}
+	} else {
+		issued[caCertKey], issued[caKeyKey] = data[caCertKey], data[caKeyKey]
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: r.DNSName},
+		DNSNames:    []string{r.DNSName},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certPEM, keyPEM, err := newCertificate(template, now, certValidity, ca, caKey)
+	if err != nil {
+		return nil, false, err
+	}
+	issued[corev1.TLSCertKey], issued[corev1.TLSPrivateKeyKey] = certPEM, keyPEM
+	return issued, true, nil
+}
+
+// newCertificate returns a certificate from template, valid for validity from
+// now, and its key, in PEM. It is signed by parent, or self-signed if parent
+// is nil.
+func newCertificate(template *x509.Certificate, now time.Time, validity time.Duration, parent *x509.Certificate, parentKey any) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template.SerialNumber = serial
+	// The certificate is valid a little before now, for the clocks of the
+	// API servers that are behind.
+	template.NotBefore = now.Add(-time.Hour)
+	template.NotAfter = now.Add(validity)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// parseKeyPair returns the certificate of a key pair in PEM, and its key.
+func parseKeyPair(certPEM, keyPEM []byte) (*x509.Certificate, any, error) {
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil, nil, errors.New("missing key pair")
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair.Leaf, pair.PrivateKey, nil
+}
+
+// injectCABundle sets caBundle as the CA bundle of every webhook of the
+// webhook configuration.
+func (r *Rotator) injectCABundle(ctx context.Context, caBundle []byte) error {
+	if r.WebhookConfiguration == "" {
+		return nil
+	}
+	var configuration admissionregistrationv1.ValidatingWebhookConfiguration
+	if err := r.Reader.Get(ctx, types.NamespacedName{Name: r.WebhookConfiguration}, &configuration); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Info("webhook configuration not found, its caBundle is not set", "name", r.WebhookConfiguration)
+			return nil
+		}
+		return err
+	}
+	original := configuration.DeepCopy()
+	changed := false
+	for i := range configuration.Webhooks {
+		if !bytes.Equal(configuration.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			configuration.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.Client.Patch(ctx, &configuration, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("unable to set the caBundle of %s: %w", r.WebhookConfiguration, err)
+	}
+	return nil
+}
+
+// writeFile writes data to path unless it already holds it, so that the
+// webhook server only reloads its certificate when it changes.
+func writeFile(path string, data []byte) error {
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnsure(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("unable to build scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "gari-webhooks"},
+		Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "httproutes.gari.gke-labs.dev"}, {Name: "gateways.gari.gke-labs.dev"}},
+	}).Build()
+	now := time.Now()
+	r := &Rotator{
+		Reader:               c,
+		Client:               c,
+		Secret:               types.NamespacedName{Namespace: "gari-system", Name: "gari-webhook-cert"},
+		DNSName:              "gari-webhook.gari-system.svc",
+		CertDir:              t.TempDir(),
+		WebhookConfiguration: "gari-webhooks",
+		now:                  func() time.Time { return now },
+	}
+	if err := r.Ensure(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var secret corev1.Secret
+	if err := c.Get(ctx, r.Secret, &secret); err != nil {
+		t.Fatalf("expected the certificate to be stored: %v", err)
+	}
+	pair, err := tls.LoadX509KeyPair(filepath.Join(r.CertDir, "tls.crt"), filepath.Join(r.CertDir, "tls.key"))
+	if err != nil {
+		t.Fatalf("expected the certificate to be written: %v", err)
+	}
+	if err := pair.Leaf.VerifyHostname(r.DNSName); err != nil {
+		t.Errorf("expected the certificate to be valid for the Service: %v", err)
+	}
+	var configuration admissionregistrationv1.ValidatingWebhookConfiguration
+	if err := c.Get(ctx, types.NamespacedName{Name: "gari-webhooks"}, &configuration); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, webhook := range configuration.Webhooks {
+		if !bytes.Equal(webhook.ClientConfig.CABundle, secret.Data["ca.crt"]) {
+			t.Errorf("expected the caBundle of %s to be set", webhook.Name)
+		}
+	}
+
+	// A valid certificate is kept.
+	if err := r.Ensure(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var kept corev1.Secret
+	if err := c.Get(ctx, r.Secret, &kept); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kept.ResourceVersion != secret.ResourceVersion {
+		t.Errorf("expected a valid certificate to be kept")
+	}
+
+	// A certificate about to expire is renewed under the same CA.
+	now = now.Add(certValidity - renewBefore/2)
+	if err := r.Ensure(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var renewed corev1.Secret
+	if err := c.Get(ctx, r.Secret, &renewed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Equal(renewed.Data["tls.crt"], secret.Data["tls.crt"]) || !bytes.Equal(renewed.Data["ca.crt"], secret.Data["ca.crt"]) {
+		t.Errorf("expected the certificate to be renewed under the same CA")
+	}
+	written, err := os.ReadFile(filepath.Join(r.CertDir, "tls.crt"))
+	if err != nil || !bytes.Equal(written, renewed.Data["tls.crt"]) {
+		t.Errorf("expected the renewed certificate to be written, got %v", err)
+	}
+}
@@ -15,24 +15,41 @@
 package e2e
 
 import (
+	"cmp"
+	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/gke-labs/gateway-api-reference-implementation/pkg/version"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	"sigs.k8s.io/gateway-api/conformance"
+	confv1 "sigs.k8s.io/gateway-api/conformance/apis/v1"
 	"sigs.k8s.io/gateway-api/conformance/tests"
 	"sigs.k8s.io/gateway-api/conformance/utils/suite"
+	"sigs.k8s.io/yaml"
 )
 
+// gatewayClassName is the GatewayClass the conformance tests are run against,
+// which the controller manifest creates.
+const gatewayClassName = "reference-class"
+
+// conformanceMode is the mode the conformance report is filed under; the
+// implementation has no other mode.
+const conformanceMode = "default"
+
 func TestConformance(t *testing.T) {
 	if os.Getenv("RUN_E2E") == "" {
 		t.Skip("RUN_E2E env var not set, skipping")
 	}
+	t.Parallel()
 
 	clusterName := os.Getenv("KIND_CLUSTER_NAME")
 	if clusterName == "" {
@@ -67,26 +84,94 @@ func TestConformance(t *testing.T) {
 		t.Fatalf("Error creating Kubernetes client: %v", err)
 	}
 
+	// The version reported defaults to that of the test binary; releases
+	// set VERSION to the released version.
+	implementationVersion := cmp.Or(os.Getenv("VERSION"), version.Get().Version)
+	// The supported features are read by the suite from the status of the
+	// GatewayClass, so that the tests exercise what the controller claims.
+	h.WaitForGatewayClassSupportedFeatures(gatewayClassName, 2*time.Minute)
 	cSuite, err := suite.NewConformanceTestSuite(suite.ConformanceOptions{
-		Client:                     cl,
-		GatewayClassName:           "reference-class",
-		Debug:                      true,
-		CleanupBaseResources:       true,
-		EnableAllSupportedFeatures: true,
-		ManifestFS:                 []fs.FS{conformance.Manifests},
+		Client:               cl,
+		GatewayClassName:     gatewayClassName,
+		Debug:                true,
+		CleanupBaseResources: true,
+		SkipTests:            conformanceSkipTests,
+		ManifestFS:           []fs.FS{conformance.Manifests},
+		Mode:                 conformanceMode,
+		ConformanceProfiles:  sets.New(suite.GatewayHTTPConformanceProfileName),
+		Implementation: confv1.Implementation{
+			Organization: "gke-labs",
+			Project:      "gateway-api-reference-implementation",
+			URL:          "https://github.com/gke-labs/gateway-api-reference-implementation",
+			Version:      implementationVersion,
+			Contact:      []string{"https://github.com/gke-labs/gateway-api-reference-implementation/issues/new"},
+		},
 	})
 	if err != nil {
 		t.Fatalf("error creating conformance test suite: %v", err)
 	}
 
-	selectedTests := []suite.ConformanceTest{
-		tests.HTTPRouteSimpleSameNamespace,
-		tests.HTTPRouteMatching,
-		tests.HTTPRouteExactPathMatching,
-		tests.HTTPRouteHeaderMatching,
+	// The whole suite is run; tests of unsupported features, and those in
+	// conformanceSkipTests, are skipped by the suite itself.
+	cSuite.Setup(t, tests.ConformanceTests)
+
+	if err := cSuite.Run(t, tests.ConformanceTests); err != nil {
+		t.Fatalf("error running conformance tests: %v", err)
+	}
+
+	report := writeConformanceReport(t, h, cSuite, implementationVersion)
+	checkConformanceProfile(t, report, suite.GatewayHTTPConformanceProfileName)
+}
+
+// writeConformanceReport writes the report of the conformance tests to the
+// artifacts directory, $ARTIFACTS or else .build/artifacts, named as reports
+// are filed upstream, for submission with each release, and returns it.
+func writeConformanceReport(t *testing.T, h *Harness, cSuite *suite.ConformanceTestSuite, implementationVersion string) *confv1.ConformanceReport {
+	report, err := cSuite.Report()
+	if err != nil {
+		t.Fatalf("error generating conformance report: %v", err)
+	}
+	data, err := yaml.Marshal(report)
+	if err != nil {
+		t.Fatalf("error encoding conformance report: %v", err)
 	}
 
-	cSuite.Setup(t, selectedTests)
+	dir := cmp.Or(os.Getenv("ARTIFACTS"), filepath.Join(h.GetGitRoot(), ".build", "artifacts"))
+	path := filepath.Join(dir, "conformance", fmt.Sprintf("%s-%s-%s-report.yaml", report.GatewayAPIChannel, implementationVersion, conformanceMode))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("error creating artifacts directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("error writing conformance report: %v", err)
+	}
+	t.Logf("Conformance report written to %s:\n%s", path, data)
+	return report
+}
 
-	cSuite.Run(t, selectedTests)
+// checkConformanceProfile fails the test if less than
+// minimumConformancePassRate of the tests of the named profile passed, core
+// and extended alike, and logs the pass rate to track progress.
+func checkConformanceProfile(t *testing.T, report *confv1.ConformanceReport, name suite.ConformanceProfileName) {
+	for _, profile := range report.ProfileReports {
+		if profile.Name != string(name) {
+			continue
+		}
+		stats := profile.Core.Statistics
+		if profile.Extended != nil {
+			stats.Passed += profile.Extended.Passed
+			stats.Skipped += profile.Extended.Skipped
+			stats.Failed += profile.Extended.Failed
+		}
+		total := stats.Passed + stats.Skipped + stats.Failed
+		if total == 0 {
+			t.Fatalf("no tests were run for conformance profile %s", name)
+		}
+		rate := float64(stats.Passed) / float64(total)
+		t.Logf("Conformance profile %s: %d/%d tests passed (%.1f%%): %s", name, stats.Passed, total, 100*rate, profile.Summary)
+		if rate < minimumConformancePassRate {
+			t.Errorf("expected at least %.1f%% of the tests of conformance profile %s to pass, got %.1f%%", 100*minimumConformancePassRate, name, 100*rate)
+		}
+		return
+	}
+	t.Fatalf("conformance report has no results for profile %s", name)
 }
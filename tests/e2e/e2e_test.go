@@ -25,6 +25,7 @@ func TestGatewayAPI(t *testing.T) {
 	if os.Getenv("RUN_E2E") == "" {
 		t.Skip("RUN_E2E env var not set, skipping")
 	}
+	t.Parallel()
 
 	clusterName := os.Getenv("KIND_CLUSTER_NAME")
 	if clusterName == "" {
@@ -44,15 +45,14 @@ func TestGatewayAPI(t *testing.T) {
 	h.DeployBackend()
 
 	// 4. Create Gateway API Resources
-	h.KubectlApplyContent(h.ExampleGatewayManifest())
+	host := h.Namespace() + ".example.com"
+	h.KubectlApplyContent(h.ExampleGatewayManifest(host))
 	// Give the controller some time to reconcile
 	time.Sleep(5 * time.Second)
 
 	// 5. Run Client Pod
 	clientPodName := "test-client"
-	h.DeletePod(clientPodName)
-
-	h.KubectlApplyContent(h.ClientManifest("http://gari-proxy", "example.com"))
+	h.KubectlApplyContent(h.ClientManifest("http://gari-proxy.default", host))
 	h.WaitForPodSuccess(clientPodName, 1*time.Minute)
 
 	logs := h.GetPodLogs(clientPodName)
@@ -62,7 +62,7 @@ func TestGatewayAPI(t *testing.T) {
 	if !strings.Contains(logs, "Status: 200 OK") {
 		t.Errorf("Expected 200 OK, got: %s", logs)
 	}
-	if !strings.Contains(logs, "\"hostname\":\"example.com\"") {
-		t.Errorf("Expected hostname example.com in response body, got: %s", logs)
+	if !strings.Contains(logs, "\"hostname\":\""+host+"\"") {
+		t.Errorf("Expected hostname %s in response body, got: %s", host, logs)
 	}
 }
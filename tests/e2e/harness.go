@@ -16,14 +16,20 @@ package e2e
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"net/netip"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+	"unicode"
 
+	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -31,16 +37,71 @@ import (
 type Harness struct {
 	t           *testing.T
 	clusterName string
+
+	// external is set to run against the existing cluster of the current
+	// kubeconfig, such as a GKE cluster, instead of a kind cluster. Images
+	// are then pushed to registry rather than loaded into kind.
+	external bool
+	registry string
+
+	// namespace is the namespace of the test, created by Setup, which the
+	// objects of the test are created in.
+	namespace string
 }
 
+// clusterSetup records the steps of the setup of the cluster that are done,
+// since the tests sharing the cluster may run in parallel. mu is held while a
+// step runs, so that each is done once per test binary.
+var clusterSetup struct {
+	mu   sync.Mutex
+	done map[string]bool
+	// created holds the kind clusters created, which are deleted once every
+	// test ran.
+	created []string
+}
+
+// once runs the named step of the setup of the cluster, unless it was done
+// already. A step that fails is tried again by the next test.
+func (h *Harness) once(step string, f func()) {
+	clusterSetup.mu.Lock()
+	defer clusterSetup.mu.Unlock()
+	if clusterSetup.done[step] {
+		return
+	}
+	f()
+	if clusterSetup.done == nil {
+		clusterSetup.done = map[string]bool{}
+	}
+	clusterSetup.done[step] = true
+}
+
+// NewHarness returns a harness for the kind cluster with the given name, or,
+// when E2E_EXTERNAL_CLUSTER is set, for the cluster of the current
+// kubeconfig, to which images are pushed through the registry named by
+// E2E_IMAGE_REGISTRY.
 func NewHarness(t *testing.T, clusterName string) *Harness {
 	return &Harness{
 		t:           t,
 		clusterName: clusterName,
+		external:    os.Getenv("E2E_EXTERNAL_CLUSTER") != "",
+		registry:    os.Getenv("E2E_IMAGE_REGISTRY"),
 	}
 }
 
+// Setup prepares the cluster, once for all tests, and creates the namespace
+// of the test.
 func (h *Harness) Setup() {
+	h.once("cluster", func() {
+		if h.external {
+			h.setupExternal()
+		} else {
+			h.setupKind()
+		}
+	})
+	h.createNamespace()
+}
+
+func (h *Harness) setupKind() {
 	h.t.Logf("Setting up harness for cluster %s", h.clusterName)
 	// Check if kind is installed
 	if _, err := exec.LookPath("kind"); err != nil {
@@ -64,12 +125,7 @@ func (h *Harness) Setup() {
 	if !exists {
 		h.t.Logf("Creating kind cluster %s", h.clusterName)
 		h.runCmd("kind", "create", "cluster", "--name", h.clusterName)
-		h.t.Cleanup(func() {
-			if os.Getenv("SKIP_CLEANUP") == "" {
-				h.t.Logf("Deleting kind cluster %s", h.clusterName)
-				h.runCmd("kind", "delete", "cluster", "--name", h.clusterName)
-			}
-		})
+		clusterSetup.created = append(clusterSetup.created, h.clusterName)
 	}
 
 	// Ensure we are using the correct context and namespace
@@ -80,15 +136,102 @@ func (h *Harness) Setup() {
 	h.InstallMetallb()
 }
 
+// setupExternal checks that the existing cluster of the current kubeconfig
+// can be used. The cluster is neither created nor deleted, and is expected
+// to provide LoadBalancer Services itself.
+func (h *Harness) setupExternal() {
+	h.t.Log("Setting up harness for the cluster of the current kubeconfig")
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		h.t.Fatalf("kubectl not found: %v", err)
+	}
+	if h.registry == "" {
+		h.t.Fatalf("E2E_IMAGE_REGISTRY must be set to push images to an external cluster")
+	}
+	h.t.Logf("Using context %s", strings.TrimSpace(h.runCmd("kubectl", "config", "current-context")))
+}
+
+// createNamespace creates a namespace for the test, named after it, so that
+// tests running in parallel do not share objects, and deletes it once the
+// test is done.
+func (h *Harness) createNamespace() {
+	h.namespace = testNamespace(h.t.Name())
+	h.t.Logf("Creating namespace %s", h.namespace)
+	h.runCmd("kubectl", "create", "namespace", h.namespace)
+	h.t.Cleanup(func() {
+		if os.Getenv("SKIP_CLEANUP") == "" {
+			h.t.Logf("Deleting namespace %s", h.namespace)
+			exec.Command("kubectl", "delete", "namespace", h.namespace, "--wait=false", "--ignore-not-found").Run()
+		}
+	})
+}
+
+// testNamespace returns a unique namespace name for the named test.
+func testNamespace(testName string) string {
+	name := strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if 'a' <= r && r <= 'z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '-'
+	}, testName)
+	name = strings.Trim(name[:min(len(name), 40)], "-")
+	return "e2e-" + name + "-" + rand.String(5)
+}
+
+// Namespace returns the namespace of the test.
+func (h *Harness) Namespace() string {
+	return h.namespace
+}
+
 func (h *Harness) InstallMetallb() {
 	h.t.Log("Installing Metallb")
 	h.runCmd("kubectl", "apply", "-f", "https://raw.githubusercontent.com/metallb/metallb/v0.13.12/config/manifests/metallb-native.yaml")
 	h.runCmd("kubectl", "wait", "--namespace", "metallb-system", "--for=condition=available", "deployment/controller", "--timeout=90s")
 
 	// Configure Metallb with a range of IPs from the kind network
-	h.runCmd("docker", "network", "inspect", "kind")
+	addresses, err := metallbAddressRange([]byte(h.runCmd("docker", "network", "inspect", "kind")))
+	if err != nil {
+		h.t.Fatalf("Failed to compute the Metallb address pool from the kind network: %v", err)
+	}
 
-	h.KubectlApplyContent(h.MetallbConfigManifest())
+	h.KubectlApplyContent(h.MetallbConfigManifest(addresses))
+}
+
+// metallbAddressRange returns a range of addresses for Metallb to assign from
+// the output of docker network inspect for the kind network. The range is
+// near the end of its IPv4 subnet, away from the addresses docker hands out
+// to containers from the start.
+func metallbAddressRange(inspect []byte) (string, error) {
+	var networks []struct {
+		IPAM struct {
+			Config []struct {
+				Subnet string
+			}
+		}
+	}
+	if err := json.Unmarshal(inspect, &networks); err != nil {
+		return "", fmt.Errorf("parsing docker network inspect output: %w", err)
+	}
+	for _, network := range networks {
+		for _, config := range network.IPAM.Config {
+			prefix, err := netip.ParsePrefix(config.Subnet)
+			if err != nil || !prefix.Addr().Is4() {
+				continue
+			}
+			if prefix.Bits() > 24 {
+				return "", fmt.Errorf("subnet %s is too small for an address pool", prefix)
+			}
+			// The range of a /16 is x.y.255.200-x.y.255.250.
+			base := binary.BigEndian.Uint32(prefix.Masked().Addr().AsSlice())
+			last := base | (1<<(32-prefix.Bits()) - 1)
+			return fmt.Sprintf("%s-%s", uint32Addr(last-55), uint32Addr(last-5)), nil
+		}
+	}
+	return "", fmt.Errorf("no IPv4 subnet found in the kind network")
+}
+
+func uint32Addr(v uint32) netip.Addr {
+	return netip.AddrFrom4([4]byte(binary.BigEndian.AppendUint32(nil, v)))
 }
 
 // RESTConfig returns the configuration for talking to the test kind cluster started from this harness.
@@ -117,6 +260,40 @@ func (h *Harness) DockerBuild(tag, dockerfile, context string) {
 	h.runCmd("docker", "build", "-t", tag, "-f", dockerfile, context)
 }
 
+// PublishImage makes the image built with the given tag available to the
+// cluster, loading it into kind or pushing it to the registry, and returns
+// the reference to run it from.
+func (h *Harness) PublishImage(tag string) string {
+	ref := h.image(tag)
+	if !h.external {
+		h.KindLoad(tag)
+		return ref
+	}
+	h.t.Logf("Pushing image %s as %s", tag, ref)
+	h.runCmd("docker", "tag", tag, ref)
+	h.runCmd("docker", "push", ref)
+	return ref
+}
+
+// image returns the reference to run the image with the given tag from, once
+// published.
+func (h *Harness) image(tag string) string {
+	if !h.external {
+		return tag
+	}
+	return strings.TrimSuffix(h.registry, "/") + "/" + tag
+}
+
+// imagePullPolicy returns the pull policy of the images built by the harness:
+// those loaded into kind cannot be pulled, and those pushed are pushed again
+// under the same tag on each run.
+func (h *Harness) imagePullPolicy() string {
+	if h.external {
+		return "Always"
+	}
+	return "Never"
+}
+
 func (h *Harness) KindLoad(tag string) {
 	h.t.Logf("Loading image %s into kind cluster %s", tag, h.clusterName)
 	h.runCmd("kind", "load", "docker-image", tag, "--name", h.clusterName)
@@ -138,14 +315,20 @@ func (h *Harness) KubectlApplyFile(path string) {
 	h.runCmd("kubectl", "apply", "-f", path)
 }
 
+// WaitForDeployment waits for the named deployment of the test's namespace to
+// be available.
 func (h *Harness) WaitForDeployment(name string, timeout time.Duration) {
-	h.t.Logf("Waiting for deployment %s to be ready", name)
-	h.runCmd("kubectl", "wait", "--namespace", "default", "--for=condition=available", "--timeout="+timeout.String(), "deployment/"+name)
+	h.waitForDeployment(h.namespace, name, timeout)
+}
+
+func (h *Harness) waitForDeployment(namespace, name string, timeout time.Duration) {
+	h.t.Logf("Waiting for deployment %s/%s to be ready", namespace, name)
+	h.runCmd("kubectl", "wait", "--namespace", namespace, "--for=condition=available", "--timeout="+timeout.String(), "deployment/"+name)
 }
 
 func (h *Harness) DeletePod(name string) {
 	h.t.Logf("Deleting pod %s", name)
-	exec.Command("kubectl", "delete", "pod", name, "--namespace", "default", "--ignore-not-found").Run()
+	exec.Command("kubectl", "delete", "pod", name, "--namespace", h.namespace, "--ignore-not-found").Run()
 }
 
 func (h *Harness) WaitForPodSuccess(name string, timeout time.Duration) {
@@ -156,7 +339,7 @@ func (h *Harness) WaitForPodSuccess(name string, timeout time.Duration) {
 			h.t.Fatalf("Timeout waiting for pod %s to succeed", name)
 		}
 
-		out, err := exec.Command("kubectl", "get", "pod", name, "--namespace", "default", "-o", "jsonpath={.status.phase}").Output()
+		out, err := exec.Command("kubectl", "get", "pod", name, "--namespace", h.namespace, "-o", "jsonpath={.status.phase}").Output()
 		if err == nil {
 			phase := strings.TrimSpace(string(out))
 			if phase == "Succeeded" {
@@ -170,8 +353,26 @@ func (h *Harness) WaitForPodSuccess(name string, timeout time.Duration) {
 	}
 }
 
+// WaitForGatewayClassSupportedFeatures waits for the controller to publish
+// the supported features in the status of the named GatewayClass.
+func (h *Harness) WaitForGatewayClassSupportedFeatures(name string, timeout time.Duration) {
+	h.t.Logf("Waiting for GatewayClass %s to report its supported features", name)
+	start := time.Now()
+	for {
+		if time.Since(start) > timeout {
+			h.t.Fatalf("Timeout waiting for GatewayClass %s to report its supported features", name)
+		}
+
+		out, err := exec.Command("kubectl", "get", "gatewayclass", name, "-o", "jsonpath={.status.supportedFeatures[*].name}").Output()
+		if err == nil && strings.TrimSpace(string(out)) != "" {
+			return
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
 func (h *Harness) GetPodLogs(name string) string {
-	out, err := exec.Command("kubectl", "logs", name, "--namespace", "default").Output()
+	out, err := exec.Command("kubectl", "logs", name, "--namespace", h.namespace).Output()
 	if err != nil {
 		h.t.Fatalf("Failed to get pod logs for %s: %v", name, err)
 	}
@@ -190,30 +391,37 @@ func (h *Harness) runCmd(name string, args ...string) string {
 }
 
 func (h *Harness) InstallGatewayAPI() {
-	h.t.Log("Installing Gateway API CRDs")
-	h.runCmd("kubectl", "apply", "-f", "https://github.com/kubernetes-sigs/gateway-api/releases/download/v1.1.0/standard-install.yaml")
+	h.once("gateway-api", func() {
+		h.t.Log("Installing Gateway API CRDs")
+		h.runCmd("kubectl", "apply", "-f", "https://github.com/kubernetes-sigs/gateway-api/releases/download/v1.4.1/standard-install.yaml")
+	})
 }
 
+// DeployController deploys the controller to the default namespace, once for
+// all tests.
 func (h *Harness) DeployController() {
-	h.t.Log("Deploying Controller")
-	gitRoot := h.GetGitRoot()
-	h.DockerBuild("gari-controller:e2e", filepath.Join(gitRoot, "Dockerfile"), gitRoot)
-	h.KindLoad("gari-controller:e2e")
-
-	h.KubectlApplyFile(filepath.Join(gitRoot, "k8s/controller.yaml"))
-	h.runCmd("kubectl", "set", "image", "deployment/gari-controller", "controller=gari-controller:e2e", "--namespace=default")
-	h.runCmd("kubectl", "annotate", "deployment/gari-controller", "restartedAt="+time.Now().Format(time.RFC3339), "--namespace=default", "--overwrite")
-
-	h.WaitForDeployment("gari-controller", 2*time.Minute)
+	h.once("controller", func() {
+		h.t.Log("Deploying Controller")
+		gitRoot := h.GetGitRoot()
+		h.DockerBuild("gari-controller:e2e", filepath.Join(gitRoot, "Dockerfile"), gitRoot)
+		image := h.PublishImage("gari-controller:e2e")
+
+		h.KubectlApplyFile(filepath.Join(gitRoot, "k8s/crds"))
+		h.KubectlApplyFile(filepath.Join(gitRoot, "k8s/controller.yaml"))
+		h.runCmd("kubectl", "set", "image", "deployment/gari-controller", "controller="+image, "--namespace=default")
+		h.runCmd("kubectl", "annotate", "deployment/gari-controller", "restartedAt="+time.Now().Format(time.RFC3339), "--namespace=default", "--overwrite")
+
+		h.waitForDeployment("default", "gari-controller", 2*time.Minute)
+	})
 }
 
 func (h *Harness) BackendManifest() string {
-	return `
+	return fmt.Sprintf(`
 apiVersion: apps/v1
 kind: Deployment
 metadata:
   name: backend
-  namespace: default
+  namespace: %s
 spec:
   replicas: 1
   selector:
@@ -226,8 +434,8 @@ spec:
     spec:
       containers:
       - name: toolbox
-        image: toolbox:e2e
-        imagePullPolicy: Never
+        image: %s
+        imagePullPolicy: %s
         args: ["server"]
         ports:
         - containerPort: 8080
@@ -236,18 +444,18 @@ apiVersion: v1
 kind: Service
 metadata:
   name: backend
-  namespace: default
+  namespace: %s
 spec:
   selector:
     app: backend
   ports:
   - port: 8080
     targetPort: 8080
-`
+`, h.namespace, h.image("toolbox:e2e"), h.imagePullPolicy(), h.namespace)
 }
 
-func (h *Harness) MetallbConfigManifest() string {
-	return `
+func (h *Harness) MetallbConfigManifest(addresses string) string {
+	return fmt.Sprintf(`
 apiVersion: metallb.io/v1beta1
 kind: IPAddressPool
 metadata:
@@ -255,23 +463,26 @@ metadata:
   namespace: metallb-system
 spec:
   addresses:
-  - 172.18.255.200-172.18.255.250
+  - %s
 ---
 apiVersion: metallb.io/v1beta1
 kind: L2Advertisement
 metadata:
   name: empty
   namespace: metallb-system
-`
+`, addresses)
 }
 
-func (h *Harness) ExampleGatewayManifest() string {
-	return `
+// ExampleGatewayManifest returns a Gateway and an HTTPRoute for host in the
+// test's namespace, routing to the backend. Each test uses its own host, as
+// the Gateways of every namespace are served by the same proxy.
+func (h *Harness) ExampleGatewayManifest(host string) string {
+	return fmt.Sprintf(`
 apiVersion: gateway.networking.k8s.io/v1
 kind: Gateway
 metadata:
   name: reference-gateway
-  namespace: default
+  namespace: %[1]s
 spec:
   gatewayClassName: reference-class
   listeners:
@@ -283,16 +494,16 @@ apiVersion: gateway.networking.k8s.io/v1
 kind: HTTPRoute
 metadata:
   name: test-route
-  namespace: default
+  namespace: %[1]s
 spec:
   parentRefs:
   - name: reference-gateway
-  hostnames: ["example.com"]
+  hostnames: ["%[2]s"]
   rules:
   - backendRefs:
     - name: backend
       port: 8080
-`
+`, h.namespace, host)
 }
 
 func (h *Harness) ClientManifest(url string, host string) string {
@@ -301,21 +512,25 @@ apiVersion: v1
 kind: Pod
 metadata:
   name: test-client
+  namespace: %s
 spec:
   containers:
   - name: toolbox
-    image: toolbox:e2e
-    imagePullPolicy: Never
+    image: %s
+    imagePullPolicy: %s
     command: ["/app/toolbox", "client", "%s", "%s"]
   restartPolicy: Never
-`, url, host)
+`, h.namespace, h.image("toolbox:e2e"), h.imagePullPolicy(), url, host)
 }
 
 func (h *Harness) DeployBackend() {
+	h.once("toolbox", func() {
+		gitRoot := h.GetGitRoot()
+		h.DockerBuild("toolbox:e2e", filepath.Join(gitRoot, "tests/toolbox/Dockerfile"), filepath.Join(gitRoot, "tests/toolbox"))
+		h.PublishImage("toolbox:e2e")
+	})
+
 	h.t.Log("Deploying Backend")
-	gitRoot := h.GetGitRoot()
-	h.DockerBuild("toolbox:e2e", filepath.Join(gitRoot, "tests/toolbox/Dockerfile"), filepath.Join(gitRoot, "tests/toolbox"))
-	h.KindLoad("toolbox:e2e")
 
 	h.KubectlApplyContent(h.BackendManifest())
 	h.WaitForDeployment("backend", 2*time.Minute)
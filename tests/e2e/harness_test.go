@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"testing"
+)
+
+// TestMain deletes the kind clusters created by the tests once they all ran,
+// as tests sharing a cluster may run in parallel.
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if os.Getenv("SKIP_CLEANUP") == "" {
+		for _, name := range clusterSetup.created {
+			fmt.Printf("Deleting kind cluster %s\n", name)
+			if out, err := exec.Command("kind", "delete", "cluster", "--name", name).CombinedOutput(); err != nil {
+				fmt.Printf("Failed to delete kind cluster %s: %v\n%s", name, err, out)
+			}
+		}
+	}
+	os.Exit(code)
+}
+
+func TestTestNamespace(t *testing.T) {
+	valid := regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	for _, name := range []string{"TestGatewayAPI", "TestConformance/HTTPRouteSimpleSameNamespace", "TestAVeryLongNameThatWouldNotFitInANamespaceOtherwise_Really"} {
+		namespace := testNamespace(name)
+		if len(namespace) > 63 || !valid.MatchString(namespace) {
+			t.Errorf("expected a valid namespace name for %s, got %q", name, namespace)
+		}
+	}
+	if a, b := testNamespace("TestGatewayAPI"), testNamespace("TestGatewayAPI"); a == b {
+		t.Errorf("expected unique namespaces, got %s twice", a)
+	}
+}
+
+func TestMetallbAddressRange(t *testing.T) {
+	inspect := `[{"Name": "kind", "IPAM": {"Config": [
+		{"Subnet": "fc00:f853:ccd:e793::/64"},
+		{"Subnet": "172.19.0.0/16", "Gateway": "172.19.0.1"}
+	]}}]`
+	addresses, err := metallbAddressRange([]byte(inspect))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "172.19.255.200-172.19.255.250"; addresses != expected {
+		t.Errorf("expected %s, got %s", expected, addresses)
+	}
+
+	addresses, err = metallbAddressRange([]byte(`[{"IPAM": {"Config": [{"Subnet": "10.1.2.0/24"}]}}]`))
+	if err != nil || addresses != "10.1.2.200-10.1.2.250" {
+		t.Errorf("expected 10.1.2.200-10.1.2.250, got %s, %v", addresses, err)
+	}
+
+	if _, err := metallbAddressRange([]byte(`[{"IPAM": {"Config": [{"Subnet": "fc00::/64"}]}}]`)); err == nil {
+		t.Errorf("expected an error for a network without an IPv4 subnet")
+	}
+}